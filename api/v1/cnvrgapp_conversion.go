@@ -0,0 +1,66 @@
+package v1
+
+import (
+	"github.com/AccessibleAI/cnvrg-operator/api/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// cnvrgAppConversions are the CnvrgApp fields which differ between v1 and v1beta2
+var cnvrgAppConversions = []fieldConversion{
+	secretField("token", "tokenSecretRef", "controlPlane", "hyper"),
+	legacyLimits("controlPlane", "hyper"),
+	intField("port", "controlPlane", "ldap"),
+	boolField("ssl", "controlPlane", "ldap"),
+	secretField("adminPassword", "adminPasswordSecretRef", "controlPlane", "ldap"),
+	secretField("password", "passwordSecretRef", "controlPlane", "smtp"),
+	secretField("accessKey", "accessKeySecretRef", "controlPlane", "objectStorage"),
+	secretField("secretKey", "secretKeySecretRef", "controlPlane", "objectStorage"),
+	boolField("runJobsOnSelfCluster", "controlPlane", "baseConfig"),
+	secretField("password", "passwordSecretRef", "controlPlane", "mpi", "registry"),
+	optionalRegistry("controlPlane", "mpi"),
+	secretField("password", "passwordSecretRef", "registry"),
+	secretField("clientSecret", "clientSecretRef", "sso"),
+	secretField("cookieSecret", "cookieSecretRef", "sso"),
+}
+
+var _ conversion.Convertible = &CnvrgApp{}
+
+// ConvertTo converts the CnvrgApp to the v1beta2 hub version
+func (r *CnvrgApp) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta2.CnvrgApp)
+	r.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+	restore, err := readConversionData(r)
+	if err != nil {
+		return err
+	}
+	dst.Spec = v1beta2.CnvrgAppSpec{}
+	lost, err := convertJSON(r.Spec, &dst.Spec, cnvrgAppConversions, true, restore)
+	if err != nil {
+		return err
+	}
+	dst.Status = v1beta2.Status{}
+	if _, err := convertJSON(r.Status, &dst.Status, nil, true, nil); err != nil {
+		return err
+	}
+	return writeConversionData(dst, lost)
+}
+
+// ConvertFrom converts the v1beta2 hub version to CnvrgApp
+func (r *CnvrgApp) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta2.CnvrgApp)
+	src.ObjectMeta.DeepCopyInto(&r.ObjectMeta)
+	restore, err := readConversionData(src)
+	if err != nil {
+		return err
+	}
+	r.Spec = CnvrgAppSpec{}
+	lost, err := convertJSON(src.Spec, &r.Spec, cnvrgAppConversions, false, restore)
+	if err != nil {
+		return err
+	}
+	r.Status = Status{}
+	if _, err := convertJSON(src.Status, &r.Status, nil, false, nil); err != nil {
+		return err
+	}
+	return writeConversionData(r, lost)
+}
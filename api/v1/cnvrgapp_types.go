@@ -5,6 +5,8 @@ import (
 )
 
 type CnvrgAppSpec struct {
+	// Profile selects the sizing of the components, fields set in the spec take precedence
+	Profile               Profile            `json:"profile,omitempty"`
 	ClusterDomain         string             `json:"clusterDomain,omitempty"`
 	ClusterInternalDomain string             `json:"clusterInternalDomain,omitempty"`
 	ImageHub              string             `json:"imageHub,omitempty"`
@@ -22,6 +24,10 @@ type CnvrgAppSpec struct {
 	CnvrgJobPriorityClass PriorityClass      `json:"cnvrgJobPriorityClass,omitempty"`
 	IngressCheck          IngressCheck       `json:"ingressCheck,omitempty"`
 	Cri                   CriType            `json:"cri,omitempty"`
+	Overrides             []Override         `json:"overrides,omitempty"`
+	// SecretRefsChecksum is the checksum of the values resolved from secret references, set on the effective spec,
+	// it annotates the pods consuming the credentials, so changes of the referenced secrets roll them out
+	SecretRefsChecksum string `json:"-"`
 }
 
 // +kubebuilder:object:root=true
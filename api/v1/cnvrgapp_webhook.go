@@ -0,0 +1,194 @@
+package v1
+
+import (
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"reflect"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+func (r *CnvrgApp) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(r).Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-mlops-cnvrg-io-v1-cnvrgapp,mutating=false,failurePolicy=fail,groups=mlops.cnvrg.io,resources=cnvrgapps,verbs=create;update,versions=v1,name=vcnvrgapp.mlops.cnvrg.io
+
+var _ webhook.Validator = &CnvrgApp{}
+
+func (r *CnvrgApp) ValidateCreate() error {
+	return r.validate()
+}
+
+// ValidateUpdate validates only spec changes, so updates of the metadata (e.g. finalizers removal)
+// are not blocked by specs which were created before the validation
+func (r *CnvrgApp) ValidateUpdate(old runtime.Object) error {
+	oldApp, ok := old.(*CnvrgApp)
+	if !ok {
+		return r.validate()
+	}
+	if reflect.DeepEqual(oldApp.Spec, r.Spec) {
+		return nil
+	}
+	allErrs := r.Spec.validate(field.NewPath("spec"))
+	allErrs = append(allErrs, validatePgHAUpdate(field.NewPath("spec", "dbs", "pg", "ha"), oldApp.Spec.Dbs.Pg, r.Spec.Dbs.Pg)...)
+	return r.invalid(allErrs)
+}
+
+func (r *CnvrgApp) ValidateDelete() error {
+	return nil
+}
+
+func (r *CnvrgApp) validate() error {
+	return r.invalid(r.Spec.validate(field.NewPath("spec")))
+}
+
+func (r *CnvrgApp) invalid(allErrs field.ErrorList) error {
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind("CnvrgApp").GroupKind(), r.Name, allErrs)
+}
+
+func (s *CnvrgAppSpec) validate(path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	cp, dbs := path.Child("controlPlane"), path.Child("dbs")
+	logging, monitoring := path.Child("logging"), path.Child("monitoring")
+
+	for _, size := range []struct {
+		path *field.Path
+		size string
+	}{
+		{dbs.Child("pg", "storageSize"), s.Dbs.Pg.StorageSize},
+		{dbs.Child("minio", "storageSize"), s.Dbs.Minio.StorageSize},
+		{dbs.Child("redis", "storageSize"), s.Dbs.Redis.StorageSize},
+		{dbs.Child("es", "storageSize"), s.Dbs.Es.StorageSize},
+		{dbs.Child("cvat", "pg", "storageSize"), s.Dbs.Cvat.Pg.StorageSize},
+		{dbs.Child("cvat", "redis", "storageSize"), s.Dbs.Cvat.Redis.StorageSize},
+		{logging.Child("elastalert", "storageSize"), s.Logging.Elastalert.StorageSize},
+		{monitoring.Child("prometheus", "storageSize"), s.Monitoring.Prometheus.StorageSize},
+	} {
+		allErrs = append(allErrs, validateStorageSize(size.path, size.size)...)
+	}
+
+	// the requests and limits are checked against the defaults of the profile, which fill the fields not set in the spec
+	defaults, err := DefaultCnvrgAppSpecForProfile(s.Profile)
+	if err != nil {
+		// unknown profiles are rejected by the crd schema
+		defaults = DefaultCnvrgAppSpec()
+	}
+	allErrs = append(allErrs, validateResources(s.resources(path), defaults.resources(path))...)
+
+	allErrs = append(allErrs, validateHTTPS(path.Child("networking", "https"), s.Networking.HTTPS)...)
+	allErrs = append(allErrs, validateSSO(path.Child("sso"), s.SSO)...)
+
+	// node ports are allocated only by the nodeport ingress
+	if s.Networking.Ingress.Type == NodePortIngress {
+		allErrs = append(allErrs, validateNodePorts([]nodePort{
+			{cp.Child("webapp", "nodePort"), s.ControlPlane.WebApp.Enabled, s.ControlPlane.WebApp.NodePort},
+			{dbs.Child("minio", "nodePort"), s.Dbs.Minio.Enabled, s.Dbs.Minio.NodePort},
+			{dbs.Child("es", "nodePort"), s.Dbs.Es.Enabled, s.Dbs.Es.NodePort},
+			{logging.Child("kibana", "nodePort"), s.Logging.Kibana.Enabled, s.Logging.Kibana.NodePort},
+		})...)
+	}
+
+	allErrs = append(allErrs, validateBackup(dbs.Child("pg", "backup"), s.Dbs.Pg.Backup)...)
+	allErrs = append(allErrs, validateBackup(dbs.Child("redis", "backup"), s.Dbs.Redis.Backup)...)
+	allErrs = append(allErrs, validateBackup(dbs.Child("es", "backup"), s.Dbs.Es.Backup)...)
+	allErrs = append(allErrs, validateBackup(dbs.Child("cvat", "pg", "backup"), s.Dbs.Cvat.Pg.Backup)...)
+	allErrs = append(allErrs, validateBackup(dbs.Child("cvat", "redis", "backup"), s.Dbs.Cvat.Redis.Backup)...)
+
+	allErrs = append(allErrs, validatePgHA(dbs.Child("pg", "ha"), s.Dbs.Pg)...)
+	if s.Dbs.Cvat.Pg.HA.Enabled {
+		allErrs = append(allErrs, field.Forbidden(dbs.Child("cvat", "pg", "ha", "enabled"), "ha is not supported by cvat pg"))
+	}
+	if s.Dbs.Pg.Upgrade.ConfirmedVersion < 0 {
+		allErrs = append(allErrs, field.Invalid(dbs.Child("pg", "upgrade", "confirmedVersion"), s.Dbs.Pg.Upgrade.ConfirmedVersion, "must be a pg major version"))
+	}
+	if s.Dbs.Cvat.Pg.Upgrade.ConfirmedVersion != 0 {
+		allErrs = append(allErrs, field.Forbidden(dbs.Child("cvat", "pg", "upgrade", "confirmedVersion"), "managed upgrade is not supported by cvat pg"))
+	}
+
+	allErrs = append(allErrs, validateExternalDbs([]externalDb{
+		{dbs.Child("pg"), s.Dbs.Pg.Enabled, s.Dbs.Pg.External},
+		{dbs.Child("redis"), s.Dbs.Redis.Enabled, s.Dbs.Redis.External},
+		{dbs.Child("es"), s.Dbs.Es.Enabled, s.Dbs.Es.External},
+	})...)
+	if s.Dbs.Cvat.Pg.External.Enabled {
+		allErrs = append(allErrs, field.Forbidden(dbs.Child("cvat", "pg", "external", "enabled"), "external database is not supported by cvat pg"))
+	}
+	if s.Dbs.Cvat.Redis.External.Enabled {
+		allErrs = append(allErrs, field.Forbidden(dbs.Child("cvat", "redis", "external", "enabled"), "external database is not supported by cvat redis"))
+	}
+
+	allErrs = append(allErrs, validateSecretValues([]secretValue{
+		{cp.Child("hyper", "tokenSecretRef"), s.ControlPlane.Hyper.Token, s.ControlPlane.Hyper.TokenSecretRef},
+		{cp.Child("ldap", "adminPasswordSecretRef"), s.ControlPlane.Ldap.AdminPassword, s.ControlPlane.Ldap.AdminPasswordSecretRef},
+		{cp.Child("smtp", "passwordSecretRef"), s.ControlPlane.SMTP.Password, s.ControlPlane.SMTP.PasswordSecretRef},
+		{cp.Child("objectStorage", "accessKeySecretRef"), s.ControlPlane.ObjectStorage.AccessKey, s.ControlPlane.ObjectStorage.AccessKeySecretRef},
+		{cp.Child("objectStorage", "secretKeySecretRef"), s.ControlPlane.ObjectStorage.SecretKey, s.ControlPlane.ObjectStorage.SecretKeySecretRef},
+		{cp.Child("mpi", "registry", "passwordSecretRef"), s.ControlPlane.Mpi.Registry.Password, s.ControlPlane.Mpi.Registry.PasswordSecretRef},
+		{path.Child("registry", "passwordSecretRef"), s.Registry.Password, s.Registry.PasswordSecretRef},
+		{path.Child("sso", "clientSecretRef"), s.SSO.ClientSecret, s.SSO.ClientSecretRef},
+		{path.Child("sso", "cookieSecretRef"), s.SSO.CookieSecret, s.SSO.CookieSecretRef},
+	})...)
+
+	allErrs = append(allErrs, validatePodSpecOverrides(s.podSpecs(path))...)
+	allErrs = append(allErrs, validateOverrides(path.Child("overrides"), s.Overrides)...)
+
+	if s.ControlPlane.ObjectStorage.Type == GcpObjectStorageType && s.ControlPlane.ObjectStorage.GcpSecretRef == "" {
+		allErrs = append(allErrs, field.Required(cp.Child("objectStorage", "gcpSecretRef"), "required by gcp object storage"))
+	}
+
+	return allErrs
+}
+
+// ValidatePodSpecOverrides validates the pod spec overrides of the components,
+// used by the reconciler when the validating webhook is not deployed
+func (s *CnvrgAppSpec) ValidatePodSpecOverrides() field.ErrorList {
+	return validatePodSpecOverrides(s.podSpecs(field.NewPath("spec")))
+}
+
+func (s *CnvrgAppSpec) resources(path *field.Path) []componentResources {
+	cp, dbs := path.Child("controlPlane"), path.Child("dbs")
+	logging, monitoring := path.Child("logging"), path.Child("monitoring")
+	return []componentResources{
+		{cp.Child("webapp"), s.ControlPlane.WebApp.Requests, s.ControlPlane.WebApp.Limits},
+		{cp.Child("sidekiq"), s.ControlPlane.Sidekiq.Requests, s.ControlPlane.Sidekiq.Limits},
+		{cp.Child("searchkiq"), s.ControlPlane.Searchkiq.Requests, s.ControlPlane.Searchkiq.Limits},
+		{cp.Child("systemkiq"), s.ControlPlane.Systemkiq.Requests, s.ControlPlane.Systemkiq.Limits},
+		{cp.Child("hyper"), s.ControlPlane.Hyper.Requests, s.ControlPlane.Hyper.Limits},
+		{cp.Child("cnvrgScheduler"), s.ControlPlane.CnvrgScheduler.Requests, s.ControlPlane.CnvrgScheduler.Limits},
+		{cp.Child("cnvrgClusterProvisionerOperator"), s.ControlPlane.CnvrgClusterProvisionerOperator.Requests, s.ControlPlane.CnvrgClusterProvisionerOperator.Limits},
+		{cp.Child("mpi"), s.ControlPlane.Mpi.Requests, s.ControlPlane.Mpi.Limits},
+		{dbs.Child("pg"), s.Dbs.Pg.Requests, s.Dbs.Pg.Limits},
+		{dbs.Child("minio"), s.Dbs.Minio.Requests, s.Dbs.Minio.Limits},
+		{dbs.Child("redis"), s.Dbs.Redis.Requests, s.Dbs.Redis.Limits},
+		{dbs.Child("es"), s.Dbs.Es.Requests, s.Dbs.Es.Limits},
+		{dbs.Child("cvat", "pg"), s.Dbs.Cvat.Pg.Requests, s.Dbs.Cvat.Pg.Limits},
+		{dbs.Child("cvat", "redis"), s.Dbs.Cvat.Redis.Requests, s.Dbs.Cvat.Redis.Limits},
+		{logging.Child("elastalert"), s.Logging.Elastalert.Requests, s.Logging.Elastalert.Limits},
+		{logging.Child("kibana"), s.Logging.Kibana.Requests, s.Logging.Kibana.Limits},
+		{monitoring.Child("prometheus"), s.Monitoring.Prometheus.Requests, s.Monitoring.Prometheus.Limits},
+	}
+}
+
+func (s *CnvrgAppSpec) podSpecs(path *field.Path) []podSpec {
+	cp, dbs := path.Child("controlPlane"), path.Child("dbs")
+	logging, monitoring := path.Child("logging"), path.Child("monitoring")
+	return []podSpec{
+		{cp.Child("webapp", "podSpecOverrides"), s.ControlPlane.WebApp.PodSpecOverrides},
+		{cp.Child("sidekiq", "podSpecOverrides"), s.ControlPlane.Sidekiq.PodSpecOverrides},
+		{cp.Child("hyper", "podSpecOverrides"), s.ControlPlane.Hyper.PodSpecOverrides},
+		{dbs.Child("pg", "podSpecOverrides"), s.Dbs.Pg.PodSpecOverrides},
+		{dbs.Child("minio", "podSpecOverrides"), s.Dbs.Minio.PodSpecOverrides},
+		{dbs.Child("redis", "podSpecOverrides"), s.Dbs.Redis.PodSpecOverrides},
+		{dbs.Child("es", "podSpecOverrides"), s.Dbs.Es.PodSpecOverrides},
+		{dbs.Child("cvat", "pg", "podSpecOverrides"), s.Dbs.Cvat.Pg.PodSpecOverrides},
+		{dbs.Child("cvat", "redis", "podSpecOverrides"), s.Dbs.Cvat.Redis.PodSpecOverrides},
+		{logging.Child("kibana", "podSpecOverrides"), s.Logging.Kibana.PodSpecOverrides},
+		{monitoring.Child("grafana", "podSpecOverrides"), s.Monitoring.Grafana.PodSpecOverrides},
+		{monitoring.Child("prometheus", "podSpecOverrides"), s.Monitoring.Prometheus.PodSpecOverrides},
+	}
+}
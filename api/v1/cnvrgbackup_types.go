@@ -0,0 +1,98 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BackupDatabase is a database of the CnvrgApp which is backed up and restored
+// +kubebuilder:validation:Enum=pg;redis;es;cvatPg
+type BackupDatabase string
+
+const (
+	PgBackupDatabase     BackupDatabase = "pg"
+	RedisBackupDatabase  BackupDatabase = "redis"
+	EsBackupDatabase     BackupDatabase = "es"
+	CvatPgBackupDatabase BackupDatabase = "cvatPg"
+)
+
+// BackupPhase is the phase of the last backup run or of the restore
+type BackupPhase string
+
+const (
+	BackupPending   BackupPhase = "Pending"
+	BackupRunning   BackupPhase = "Running"
+	BackupSucceeded BackupPhase = "Succeeded"
+	BackupFailed    BackupPhase = "Failed"
+)
+
+type CnvrgBackupSpec struct {
+	// CnvrgAppRef is the name of the CnvrgApp in the namespace of the backup
+	CnvrgAppRef string `json:"cnvrgAppRef"`
+	// Databases are backed up, all the enabled databases when empty
+	Databases []BackupDatabase `json:"databases,omitempty"`
+	// Schedule is the cron schedule of recurring backups, the backup runs once when empty
+	Schedule string `json:"schedule,omitempty"`
+	// Rotation is the number of kept runs of a scheduled backup
+	Rotation int `json:"rotation,omitempty"`
+	// BucketRef is the object storage secret, in the cp-object-storage format, the artifacts are uploaded to
+	BucketRef string `json:"bucketRef,omitempty"`
+	// Image is the object storage client image which uploads and downloads the artifacts
+	Image string `json:"image,omitempty"`
+}
+
+// BackupArtifact is the backup of a single database in the object storage
+type BackupArtifact struct {
+	Database BackupDatabase `json:"database"`
+	// Location is the object of the dump, e.g. s3://bucket/ns/cnvrg-backups/name/20211011120000/pg.dump,
+	// or the snapshot of es in the snapshots repository, e.g. s3://bucket/ns/cnvrg-backups/name/elasticsearch#name-20211011120000
+	Location string `json:"location"`
+	// Size in bytes
+	Size int64 `json:"size"`
+}
+
+type CnvrgBackupStatus struct {
+	// Phase of the last run
+	Phase   BackupPhase `json:"phase,omitempty"`
+	Message string      `json:"message,omitempty"`
+	// JobName is the job of the last run
+	JobName        string       `json:"jobName,omitempty"`
+	StartTime      *metav1.Time `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Artifacts of the last successful run
+	Artifacts []BackupArtifact `json:"artifacts,omitempty"`
+	// LastSuccessfulTime is the completion time of the last successful run
+	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="App",type=string,JSONPath=`.spec.cnvrgAppRef`
+// +kubebuilder:printcolumn:name="Schedule",type=string,JSONPath=`.spec.schedule`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Last Successful",type=date,JSONPath=`.status.lastSuccessfulTime`
+// +kubebuilder:subresource:status
+type CnvrgBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CnvrgBackupSpec   `json:"spec,omitempty"`
+	Status CnvrgBackupStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+type CnvrgBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CnvrgBackup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CnvrgBackup{}, &CnvrgBackupList{})
+}
+
+func DefaultCnvrgBackupSpec() CnvrgBackupSpec {
+	return CnvrgBackupSpec{
+		Rotation:  5,
+		BucketRef: "cp-object-storage",
+		Image:     "minio/mc:RELEASE.2021-06-13T17-48-22Z",
+	}
+}
@@ -0,0 +1,55 @@
+package v1
+
+import (
+	"github.com/AccessibleAI/cnvrg-operator/api/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// cnvrgInfraConversions are the CnvrgInfra fields which differ between v1 and v1beta2
+var cnvrgInfraConversions = []fieldConversion{
+	secretField("password", "passwordSecretRef", "registry"),
+	secretField("clientSecret", "clientSecretRef", "sso"),
+	secretField("cookieSecret", "cookieSecretRef", "sso"),
+}
+
+var _ conversion.Convertible = &CnvrgInfra{}
+
+// ConvertTo converts the CnvrgInfra to the v1beta2 hub version
+func (r *CnvrgInfra) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta2.CnvrgInfra)
+	r.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+	restore, err := readConversionData(r)
+	if err != nil {
+		return err
+	}
+	dst.Spec = v1beta2.CnvrgInfraSpec{}
+	lost, err := convertJSON(r.Spec, &dst.Spec, cnvrgInfraConversions, true, restore)
+	if err != nil {
+		return err
+	}
+	dst.Status = v1beta2.Status{}
+	if _, err := convertJSON(r.Status, &dst.Status, nil, true, nil); err != nil {
+		return err
+	}
+	return writeConversionData(dst, lost)
+}
+
+// ConvertFrom converts the v1beta2 hub version to CnvrgInfra
+func (r *CnvrgInfra) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta2.CnvrgInfra)
+	src.ObjectMeta.DeepCopyInto(&r.ObjectMeta)
+	restore, err := readConversionData(src)
+	if err != nil {
+		return err
+	}
+	r.Spec = CnvrgInfraSpec{}
+	lost, err := convertJSON(src.Spec, &r.Spec, cnvrgInfraConversions, false, restore)
+	if err != nil {
+		return err
+	}
+	r.Status = Status{}
+	if _, err := convertJSON(src.Status, &r.Status, nil, false, nil); err != nil {
+		return err
+	}
+	return writeConversionData(r, lost)
+}
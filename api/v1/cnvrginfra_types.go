@@ -27,6 +27,10 @@ type CnvrgInfraSpec struct {
 	CnvrgAppPriorityClass PriorityClass        `json:"cnvrgAppPriorityClass,omitempty"`
 	CnvrgJobPriorityClass PriorityClass        `json:"cnvrgJobPriorityClass,omitempty"`
 	Cri                   CriType              `json:"cri,omitempty"`
+	Overrides             []Override           `json:"overrides,omitempty"`
+	// SecretRefsChecksum is the checksum of the values resolved from secret references, set on the effective spec,
+	// it annotates the pods consuming the credentials, so changes of the referenced secrets roll them out
+	SecretRefsChecksum string `json:"-"`
 }
 
 // +kubebuilder:object:root=true
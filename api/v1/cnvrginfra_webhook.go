@@ -0,0 +1,99 @@
+package v1
+
+import (
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"reflect"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+func (r *CnvrgInfra) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(r).Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-mlops-cnvrg-io-v1-cnvrginfra,mutating=false,failurePolicy=fail,groups=mlops.cnvrg.io,resources=cnvrginfras,verbs=create;update,versions=v1,name=vcnvrginfra.mlops.cnvrg.io
+
+var _ webhook.Validator = &CnvrgInfra{}
+
+func (r *CnvrgInfra) ValidateCreate() error {
+	return r.validate()
+}
+
+// ValidateUpdate validates only spec changes, so updates of the metadata (e.g. finalizers removal)
+// are not blocked by specs which were created before the validation
+func (r *CnvrgInfra) ValidateUpdate(old runtime.Object) error {
+	if oldInfra, ok := old.(*CnvrgInfra); ok && reflect.DeepEqual(oldInfra.Spec, r.Spec) {
+		return nil
+	}
+	return r.validate()
+}
+
+func (r *CnvrgInfra) ValidateDelete() error {
+	return nil
+}
+
+func (r *CnvrgInfra) validate() error {
+	allErrs := r.Spec.validate(field.NewPath("spec"))
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind("CnvrgInfra").GroupKind(), r.Name, allErrs)
+}
+
+func (s *CnvrgInfraSpec) validate(path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	dbs, monitoring := path.Child("dbs"), path.Child("monitoring")
+
+	allErrs = append(allErrs, validateStorageSize(dbs.Child("redis", "storageSize"), s.Dbs.Redis.StorageSize)...)
+	allErrs = append(allErrs, validateStorageSize(monitoring.Child("prometheus", "storageSize"), s.Monitoring.Prometheus.StorageSize)...)
+	allErrs = append(allErrs, validateStorageSize(path.Child("capsule", "storageSize"), s.Capsule.StorageSize)...)
+
+	defaults := DefaultCnvrgInfraSpec()
+	allErrs = append(allErrs, validateResources(s.resources(path), defaults.resources(path))...)
+
+	allErrs = append(allErrs, validateHTTPS(path.Child("networking", "https"), s.Networking.HTTPS)...)
+	allErrs = append(allErrs, validateSSO(path.Child("sso"), s.SSO)...)
+	allErrs = append(allErrs, validateBackup(dbs.Child("redis", "backup"), s.Dbs.Redis.Backup)...)
+	if s.Dbs.Redis.External.Enabled {
+		allErrs = append(allErrs, field.Forbidden(dbs.Child("redis", "external", "enabled"), "external database is supported by cnvrgApp only"))
+	}
+	allErrs = append(allErrs, validateSecretValues([]secretValue{
+		{path.Child("registry", "passwordSecretRef"), s.Registry.Password, s.Registry.PasswordSecretRef},
+		{path.Child("sso", "clientSecretRef"), s.SSO.ClientSecret, s.SSO.ClientSecretRef},
+		{path.Child("sso", "cookieSecretRef"), s.SSO.CookieSecret, s.SSO.CookieSecretRef},
+	})...)
+	allErrs = append(allErrs, validatePodSpecOverrides(s.podSpecs(path))...)
+	allErrs = append(allErrs, validateOverrides(path.Child("overrides"), s.Overrides)...)
+
+	return allErrs
+}
+
+// ValidatePodSpecOverrides validates the pod spec overrides of the components,
+// used by the reconciler when the validating webhook is not deployed
+func (s *CnvrgInfraSpec) ValidatePodSpecOverrides() field.ErrorList {
+	return validatePodSpecOverrides(s.podSpecs(field.NewPath("spec")))
+}
+
+func (s *CnvrgInfraSpec) resources(path *field.Path) []componentResources {
+	dbs, monitoring, storage := path.Child("dbs"), path.Child("monitoring"), path.Child("storage")
+	return []componentResources{
+		{dbs.Child("redis"), s.Dbs.Redis.Requests, s.Dbs.Redis.Limits},
+		{monitoring.Child("prometheus"), s.Monitoring.Prometheus.Requests, s.Monitoring.Prometheus.Limits},
+		{path.Child("logging", "fluentbit"), s.Logging.Fluentbit.Requests, s.Logging.Fluentbit.Limits},
+		{storage.Child("hostpath"), s.Storage.Hostpath.Requests, s.Storage.Hostpath.Limits},
+		{storage.Child("nfs"), s.Storage.Nfs.Requests, s.Storage.Nfs.Limits},
+		{path.Child("capsule"), s.Capsule.Requests, s.Capsule.Limits},
+	}
+}
+
+func (s *CnvrgInfraSpec) podSpecs(path *field.Path) []podSpec {
+	dbs, monitoring := path.Child("dbs"), path.Child("monitoring")
+	return []podSpec{
+		{dbs.Child("redis", "podSpecOverrides"), s.Dbs.Redis.PodSpecOverrides},
+		{path.Child("logging", "fluentbit", "podSpecOverrides"), s.Logging.Fluentbit.PodSpecOverrides},
+		{monitoring.Child("grafana", "podSpecOverrides"), s.Monitoring.Grafana.PodSpecOverrides},
+		{monitoring.Child("prometheus", "podSpecOverrides"), s.Monitoring.Prometheus.PodSpecOverrides},
+	}
+}
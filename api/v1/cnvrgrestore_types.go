@@ -0,0 +1,59 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type CnvrgRestoreSpec struct {
+	// CnvrgAppRef is the name of the CnvrgApp in the namespace of the restore
+	CnvrgAppRef string `json:"cnvrgAppRef"`
+	// BackupRef is the name of the CnvrgBackup, the artifacts of its last successful run are restored
+	BackupRef string `json:"backupRef"`
+	// Databases are restored, all the backed up databases when empty
+	Databases []BackupDatabase `json:"databases,omitempty"`
+	// Image is the object storage client image which downloads the artifacts
+	Image string `json:"image,omitempty"`
+}
+
+type CnvrgRestoreStatus struct {
+	Phase          BackupPhase  `json:"phase,omitempty"`
+	Message        string       `json:"message,omitempty"`
+	JobName        string       `json:"jobName,omitempty"`
+	StartTime      *metav1.Time `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Artifacts are restored, copied from the backup when the restore starts
+	Artifacts []BackupArtifact `json:"artifacts,omitempty"`
+	// Quiesced are the deployments scaled down during the restore
+	Quiesced []string `json:"quiesced,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="App",type=string,JSONPath=`.spec.cnvrgAppRef`
+// +kubebuilder:printcolumn:name="Backup",type=string,JSONPath=`.spec.backupRef`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.message`
+// +kubebuilder:subresource:status
+type CnvrgRestore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CnvrgRestoreSpec   `json:"spec,omitempty"`
+	Status CnvrgRestoreStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+type CnvrgRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CnvrgRestore `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CnvrgRestore{}, &CnvrgRestoreList{})
+}
+
+func DefaultCnvrgRestoreSpec() CnvrgRestoreSpec {
+	return CnvrgRestoreSpec{
+		Image: DefaultCnvrgBackupSpec().Image,
+	}
+}
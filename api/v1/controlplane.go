@@ -1,5 +1,7 @@
 package v1
 
+import corev1 "k8s.io/api/core/v1"
+
 // +kubebuilder:validation:Enum=minio;aws;azure;gcp
 type ObjectStorageType string
 
@@ -51,15 +53,17 @@ type WebApp struct {
 	FailureThreshold        int                   `json:"failureThreshold,omitempty"`
 	OauthProxy              OauthProxyServiceConf `json:"oauthProxy,omitempty"`
 	Hpa                     Hpa                   `json:"hpa,omitempty"`
+	PodSpecOverrides        PodSpecOverrides      `json:"podSpecOverrides,omitempty"`
 }
 
 type Sidekiq struct {
-	Enabled  bool     `json:"enabled,omitempty"`
-	Split    bool     `json:"split,omitempty"`
-	Requests Requests `json:"requests,omitempty"`
-	Limits   Limits   `json:"limits,omitempty"`
-	Replicas int      `json:"replicas,omitempty"`
-	Hpa      Hpa      `json:"hpa,omitempty"`
+	Enabled          bool             `json:"enabled,omitempty"`
+	Split            bool             `json:"split,omitempty"`
+	Requests         Requests         `json:"requests,omitempty"`
+	Limits           Limits           `json:"limits,omitempty"`
+	Replicas         int              `json:"replicas,omitempty"`
+	Hpa              Hpa              `json:"hpa,omitempty"`
+	PodSpecOverrides PodSpecOverrides `json:"podSpecOverrides,omitempty"`
 }
 
 type Searchkiq struct {
@@ -86,19 +90,21 @@ type CnvrgRouter struct {
 }
 
 type Hyper struct {
-	Enabled                 bool     `json:"enabled,omitempty"`
-	Image                   string   `json:"image,omitempty"`
-	Port                    int      `json:"port,omitempty"`
-	Replicas                int      `json:"replicas,omitempty"`
-	NodePort                int      `json:"nodePort,omitempty"`
-	SvcName                 string   `json:"svcName,omitempty"`
-	Token                   string   `json:"token,omitempty"`
-	Requests                Requests `json:"requests,omitempty"`
-	Limits                  Limits   `json:"limits,omitempty"`
-	CPULimit                string   `json:"cpuLimit,omitempty"`
-	MemoryLimit             string   `json:"memoryLimit,omitempty"`
-	ReadinessPeriodSeconds  int      `json:"readinessPeriodSeconds,omitempty"`
-	ReadinessTimeoutSeconds int      `json:"readinessTimeoutSeconds,omitempty"`
+	Enabled                 bool                      `json:"enabled,omitempty"`
+	Image                   string                    `json:"image,omitempty"`
+	Port                    int                       `json:"port,omitempty"`
+	Replicas                int                       `json:"replicas,omitempty"`
+	NodePort                int                       `json:"nodePort,omitempty"`
+	SvcName                 string                    `json:"svcName,omitempty"`
+	Token                   string                    `json:"token,omitempty"`
+	TokenSecretRef          *corev1.SecretKeySelector `json:"tokenSecretRef,omitempty"`
+	Requests                Requests                  `json:"requests,omitempty"`
+	Limits                  Limits                    `json:"limits,omitempty"`
+	CPULimit                string                    `json:"cpuLimit,omitempty"`
+	MemoryLimit             string                    `json:"memoryLimit,omitempty"`
+	ReadinessPeriodSeconds  int                       `json:"readinessPeriodSeconds,omitempty"`
+	ReadinessTimeoutSeconds int                       `json:"readinessTimeoutSeconds,omitempty"`
+	PodSpecOverrides        PodSpecOverrides          `json:"podSpecOverrides,omitempty"`
 }
 
 type CnvrgScheduler struct {
@@ -117,44 +123,49 @@ type CnvrgClusterProvisionerOperator struct {
 }
 
 type Registry struct {
-	Name     string `json:"name,omitempty"`
-	URL      string `json:"url,omitempty"`
-	User     string `json:"user,omitempty"`
-	Password string `json:"password,omitempty"`
+	Name              string                    `json:"name,omitempty"`
+	URL               string                    `json:"url,omitempty"`
+	User              string                    `json:"user,omitempty"`
+	Password          string                    `json:"password,omitempty"`
+	PasswordSecretRef *corev1.SecretKeySelector `json:"passwordSecretRef,omitempty"`
 }
 
 type Ldap struct {
-	Enabled       bool   `json:"enabled,omitempty"`
-	Host          string `json:"host,omitempty"`
-	Port          string `json:"port,omitempty"`
-	Account       string `json:"account,omitempty"`
-	Base          string `json:"base,omitempty"`
-	AdminUser     string `json:"adminUser,omitempty"`
-	AdminPassword string `json:"adminPassword,omitempty"`
-	Ssl           string `json:"ssl,omitempty"`
+	Enabled                bool                      `json:"enabled,omitempty"`
+	Host                   string                    `json:"host,omitempty"`
+	Port                   string                    `json:"port,omitempty"`
+	Account                string                    `json:"account,omitempty"`
+	Base                   string                    `json:"base,omitempty"`
+	AdminUser              string                    `json:"adminUser,omitempty"`
+	AdminPassword          string                    `json:"adminPassword,omitempty"`
+	AdminPasswordSecretRef *corev1.SecretKeySelector `json:"adminPasswordSecretRef,omitempty"`
+	Ssl                    string                    `json:"ssl,omitempty"`
 }
 
 type SMTP struct {
-	Server            string `json:"server,omitempty"`
-	Port              int    `json:"port,omitempty"`
-	Username          string `json:"username,omitempty"`
-	Password          string `json:"password,omitempty"`
-	Domain            string `json:"domain,omitempty"`
-	OpensslVerifyMode string `json:"opensslVerifyMode,omitempty"`
-	Sender            string `json:"sender,omitempty"`
+	Server            string                    `json:"server,omitempty"`
+	Port              int                       `json:"port,omitempty"`
+	Username          string                    `json:"username,omitempty"`
+	Password          string                    `json:"password,omitempty"`
+	PasswordSecretRef *corev1.SecretKeySelector `json:"passwordSecretRef,omitempty"`
+	Domain            string                    `json:"domain,omitempty"`
+	OpensslVerifyMode string                    `json:"opensslVerifyMode,omitempty"`
+	Sender            string                    `json:"sender,omitempty"`
 }
 
 type ObjectStorage struct {
-	Type             ObjectStorageType `json:"type,omitempty"`
-	Bucket           string            `json:"bucket,omitempty"`
-	Region           string            `json:"region,omitempty"`
-	AccessKey        string            `json:"accessKey,omitempty"`
-	SecretKey        string            `json:"secretKey,omitempty"`
-	Endpoint         string            `json:"endpoint,omitempty"`
-	AzureAccountName string            `json:"azureAccountName,omitempty"`
-	AzureContainer   string            `json:"azureContainer,omitempty"`
-	GcpProject       string            `json:"gcpProject,omitempty"`
-	GcpSecretRef     string            `json:"gcpSecretRef,omitempty"`
+	Type               ObjectStorageType         `json:"type,omitempty"`
+	Bucket             string                    `json:"bucket,omitempty"`
+	Region             string                    `json:"region,omitempty"`
+	AccessKey          string                    `json:"accessKey,omitempty"`
+	AccessKeySecretRef *corev1.SecretKeySelector `json:"accessKeySecretRef,omitempty"`
+	SecretKey          string                    `json:"secretKey,omitempty"`
+	SecretKeySecretRef *corev1.SecretKeySelector `json:"secretKeySecretRef,omitempty"`
+	Endpoint           string                    `json:"endpoint,omitempty"`
+	AzureAccountName   string                    `json:"azureAccountName,omitempty"`
+	AzureContainer     string                    `json:"azureContainer,omitempty"`
+	GcpProject         string                    `json:"gcpProject,omitempty"`
+	GcpSecretRef       string                    `json:"gcpSecretRef,omitempty"`
 }
 
 type BaseConfig struct {
@@ -0,0 +1,283 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ConversionDataAnnotation keeps the values which have no representation in the other api version,
+// so converting v1 -> v1beta2 -> v1 (and v1beta2 -> v1 -> v1beta2) is lossless
+const ConversionDataAnnotation = "mlops.cnvrg.io/conversion-data"
+
+// conversionData holds the source values lost by a conversion, by field conversion key
+type conversionData map[string]map[string]interface{}
+
+// fieldConversion converts the keys of the object at the parent path between v1 and v1beta2 (json values),
+// keys which depend on each other are converted together, e.g. hyper limits and the legacy cpuLimit/memoryLimit.
+// The conversion functions must not modify their input
+type fieldConversion struct {
+	parent []string
+	keys   []string
+	up     func(fields map[string]interface{}) map[string]interface{}
+	down   func(fields map[string]interface{}) map[string]interface{}
+}
+
+func (f fieldConversion) key() string {
+	return fmt.Sprintf("%s[%s]", strings.Join(f.parent, "."), strings.Join(f.keys, ","))
+}
+
+// get returns the converted keys of the spec, absent keys are omitted
+func (f fieldConversion) get(spec map[string]interface{}) map[string]interface{} {
+	fields := map[string]interface{}{}
+	parent, found, err := unstructured.NestedMap(spec, f.parent...)
+	if !found || err != nil {
+		return fields
+	}
+	for _, k := range f.keys {
+		if v, ok := parent[k]; ok {
+			fields[k] = v
+		}
+	}
+	return fields
+}
+
+// set replaces the converted keys of the spec
+func (f fieldConversion) set(spec map[string]interface{}, fields map[string]interface{}) error {
+	parent, found, err := unstructured.NestedMap(spec, f.parent...)
+	if err != nil {
+		return err
+	}
+	if !found {
+		if len(fields) == 0 {
+			return nil
+		}
+		parent = map[string]interface{}{}
+	}
+	for _, k := range f.keys {
+		delete(parent, k)
+		if v, ok := fields[k]; ok {
+			parent[k] = v
+		}
+	}
+	return unstructured.SetNestedMap(spec, parent, f.parent...)
+}
+
+// convertFields applies the field conversions on the spec, up is v1 -> v1beta2.
+// restore holds the target values lost by the previous conversion in the opposite direction,
+// they are restored if the source values haven't changed since.
+// Returns the source values which can't be recovered from the target
+func convertFields(spec map[string]interface{}, conversions []fieldConversion, up bool, restore conversionData) (conversionData, error) {
+	lost := conversionData{}
+	for i := range conversions {
+		f := conversions[i]
+		to, back := f.up, f.down
+		if !up {
+			// down conversion runs in reverse order, nested fields are converted after their parent
+			f = conversions[len(conversions)-1-i]
+			to, back = f.down, f.up
+		}
+		source := f.get(spec)
+		target := to(source)
+		if stashed, ok := restore[f.key()]; ok && reflect.DeepEqual(back(stashed), source) {
+			target = stashed
+		}
+		if !reflect.DeepEqual(back(target), source) {
+			lost[f.key()] = source
+		}
+		if err := f.set(spec, target); err != nil {
+			return nil, err
+		}
+	}
+	return lost, nil
+}
+
+// convertJSON converts src to dst through their json representation, applying the field conversions
+func convertJSON(src, dst interface{}, conversions []fieldConversion, up bool, restore conversionData) (conversionData, error) {
+	b, err := json.Marshal(src)
+	if err != nil {
+		return nil, err
+	}
+	obj := map[string]interface{}{}
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return nil, err
+	}
+	lost, err := convertFields(obj, conversions, up, restore)
+	if err != nil {
+		return nil, err
+	}
+	if b, err = json.Marshal(obj); err != nil {
+		return nil, err
+	}
+	return lost, json.Unmarshal(b, dst)
+}
+
+func readConversionData(obj metav1.Object) (conversionData, error) {
+	data := conversionData{}
+	value, ok := obj.GetAnnotations()[ConversionDataAnnotation]
+	if !ok {
+		return data, nil
+	}
+	if err := json.Unmarshal([]byte(value), &data); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %v", ConversionDataAnnotation, err)
+	}
+	return data, nil
+}
+
+// writeConversionData replaces the conversion data of the converted object, the annotation is removed when nothing was lost
+func writeConversionData(obj metav1.Object, data conversionData) error {
+	annotations := obj.GetAnnotations()
+	delete(annotations, ConversionDataAnnotation)
+	if len(data) > 0 {
+		b, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[ConversionDataAnnotation] = string(b)
+	}
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	obj.SetAnnotations(annotations)
+	return nil
+}
+
+// secretField converts a plaintext credential and its secret reference to v1beta2 SecretValue
+func secretField(key, refKey string, parent ...string) fieldConversion {
+	return fieldConversion{
+		parent: parent,
+		keys:   []string{key, refKey},
+		up: func(fields map[string]interface{}) map[string]interface{} {
+			out := map[string]interface{}{}
+			secret := map[string]interface{}{}
+			if value, ok := fields[key].(string); ok && value != "" {
+				secret["value"] = value
+			}
+			if ref, ok := fields[refKey]; ok {
+				secret["secretKeyRef"] = ref
+			}
+			if len(secret) > 0 {
+				out[key] = secret
+			}
+			return out
+		},
+		down: func(fields map[string]interface{}) map[string]interface{} {
+			out := map[string]interface{}{}
+			if secret, ok := fields[key].(map[string]interface{}); ok {
+				if value, ok := secret["value"].(string); ok && value != "" {
+					out[key] = value
+				}
+				if ref, ok := secret["secretKeyRef"]; ok {
+					out[refKey] = ref
+				}
+			}
+			return out
+		},
+	}
+}
+
+// intField converts a numeric string to v1beta2 int
+func intField(key string, parent ...string) fieldConversion {
+	return fieldConversion{
+		parent: parent,
+		keys:   []string{key},
+		up: func(fields map[string]interface{}) map[string]interface{} {
+			out := map[string]interface{}{}
+			if value, ok := fields[key].(string); ok {
+				if n, err := strconv.Atoi(value); err == nil && n != 0 {
+					out[key] = float64(n)
+				}
+			}
+			return out
+		},
+		down: func(fields map[string]interface{}) map[string]interface{} {
+			out := map[string]interface{}{}
+			if n, ok := fields[key].(float64); ok && n != 0 {
+				out[key] = strconv.Itoa(int(n))
+			}
+			return out
+		},
+	}
+}
+
+// boolField converts a "true"/"false" string to v1beta2 bool
+func boolField(key string, parent ...string) fieldConversion {
+	return fieldConversion{
+		parent: parent,
+		keys:   []string{key},
+		up: func(fields map[string]interface{}) map[string]interface{} {
+			out := map[string]interface{}{}
+			if value, ok := fields[key].(string); ok {
+				if b, err := strconv.ParseBool(value); err == nil && b {
+					out[key] = true
+				}
+			}
+			return out
+		},
+		down: func(fields map[string]interface{}) map[string]interface{} {
+			out := map[string]interface{}{}
+			if b, ok := fields[key].(bool); ok && b {
+				out[key] = "true"
+			}
+			return out
+		},
+	}
+}
+
+// legacyLimits folds the legacy cpuLimit/memoryLimit into the limits,
+// the limits take precedence when both are set
+func legacyLimits(parent ...string) fieldConversion {
+	return fieldConversion{
+		parent: parent,
+		keys:   []string{"limits", "cpuLimit", "memoryLimit"},
+		up: func(fields map[string]interface{}) map[string]interface{} {
+			limits := map[string]interface{}{}
+			if l, ok := fields["limits"].(map[string]interface{}); ok {
+				limits = runtime.DeepCopyJSONValue(l).(map[string]interface{})
+			}
+			for legacy, key := range map[string]string{"cpuLimit": "cpu", "memoryLimit": "memory"} {
+				if value, ok := fields[legacy].(string); ok && value != "" && limits[key] == nil {
+					limits[key] = value
+				}
+			}
+			return map[string]interface{}{"limits": limits}
+		},
+		down: func(fields map[string]interface{}) map[string]interface{} {
+			out := map[string]interface{}{"limits": map[string]interface{}{}}
+			if l, ok := fields["limits"]; ok {
+				out["limits"] = l
+			}
+			return out
+		},
+	}
+}
+
+// optionalRegistry converts a registry to v1beta2 optional registry, the empty registry is unset
+func optionalRegistry(parent ...string) fieldConversion {
+	return fieldConversion{
+		parent: parent,
+		keys:   []string{"registry"},
+		up: func(fields map[string]interface{}) map[string]interface{} {
+			out := map[string]interface{}{}
+			if registry, ok := fields["registry"].(map[string]interface{}); ok && len(registry) > 0 {
+				out["registry"] = registry
+			}
+			return out
+		},
+		down: func(fields map[string]interface{}) map[string]interface{} {
+			out := map[string]interface{}{"registry": map[string]interface{}{}}
+			if registry, ok := fields["registry"]; ok {
+				out["registry"] = registry
+			}
+			return out
+		},
+	}
+}
@@ -24,58 +24,95 @@ type Pg struct {
 	CredsRef           string            `json:"credsRef,omitempty"`
 	PvcName            string            `json:"pvcName,omitempty"`
 	Backup             Backup            `json:"backup,omitempty"`
+	PodSpecOverrides   PodSpecOverrides  `json:"podSpecOverrides,omitempty"`
+	HA                 PgHA              `json:"ha,omitempty"`
+	Upgrade            PgUpgrade         `json:"upgrade,omitempty"`
+	External           ExternalDb        `json:"external,omitempty"`
+}
+
+// PgHA runs pg as a patroni cluster, a primary and streaming replicas with automated failover
+type PgHA struct {
+	Enabled  bool   `json:"enabled,omitempty"`
+	Replicas int    `json:"replicas,omitempty"`
+	Image    string `json:"image,omitempty"`
+}
+
+// PgUpgrade configures the managed upgrade between pg major versions
+type PgUpgrade struct {
+	// ConfirmedVersion confirms the upgrade to the major version, the pvc of the previous version is removed once confirmed
+	ConfirmedVersion int `json:"confirmedVersion,omitempty"`
+}
+
+// ExternalDb connects the control plane to a managed database instead of the in-cluster one
+type ExternalDb struct {
+	Enabled bool   `json:"enabled,omitempty"`
+	Host    string `json:"host,omitempty"`
+	Port    int    `json:"port,omitempty"`
+	TLS     bool   `json:"tls,omitempty"`
+	// SecretRef is the secret with the database credentials, the username and password keys, and the database key for pg
+	SecretRef string `json:"secretRef,omitempty"`
+	// Username, Password and Database are resolved from the secret on the effective spec, they are never stored
+	Username string `json:"-"`
+	Password string `json:"-"`
+	Database string `json:"-"`
 }
 
 type Minio struct {
-	Enabled        bool              `json:"enabled,omitempty"`
-	ServiceAccount string            `json:"serviceAccount,omitempty"`
-	Replicas       int               `json:"replicas,omitempty"`
-	Image          string            `json:"image,omitempty"`
-	Port           int               `json:"port,omitempty"`
-	StorageSize    string            `json:"storageSize,omitempty"`
-	SvcName        string            `json:"svcName,omitempty"`
-	NodePort       int               `json:"nodePort,omitempty"`
-	StorageClass   string            `json:"storageClass,omitempty"`
-	Requests       Requests          `json:"requests,omitempty"`
-	Limits         Limits            `json:"limits,omitempty"`
-	SharedStorage  SharedStorage     `json:"sharedStorage,omitempty"`
-	NodeSelector   map[string]string `json:"nodeSelector,omitempty"`
-	PvcName        string            `json:"pvcName,omitempty"`
+	Enabled          bool              `json:"enabled,omitempty"`
+	ServiceAccount   string            `json:"serviceAccount,omitempty"`
+	Replicas         int               `json:"replicas,omitempty"`
+	Image            string            `json:"image,omitempty"`
+	Port             int               `json:"port,omitempty"`
+	StorageSize      string            `json:"storageSize,omitempty"`
+	SvcName          string            `json:"svcName,omitempty"`
+	NodePort         int               `json:"nodePort,omitempty"`
+	StorageClass     string            `json:"storageClass,omitempty"`
+	Requests         Requests          `json:"requests,omitempty"`
+	Limits           Limits            `json:"limits,omitempty"`
+	SharedStorage    SharedStorage     `json:"sharedStorage,omitempty"`
+	NodeSelector     map[string]string `json:"nodeSelector,omitempty"`
+	PvcName          string            `json:"pvcName,omitempty"`
+	PodSpecOverrides PodSpecOverrides  `json:"podSpecOverrides,omitempty"`
 }
 
 type Redis struct {
-	Enabled        bool              `json:"enabled,omitempty"`
-	ServiceAccount string            `json:"serviceAccount,omitempty"`
-	Image          string            `json:"image,omitempty"`
-	SvcName        string            `json:"svcName,omitempty"`
-	Port           int               `json:"port,omitempty"`
-	StorageSize    string            `json:"storageSize,omitempty"`
-	StorageClass   string            `json:"storageClass,omitempty"`
-	Requests       Requests          `json:"requests,omitempty"`
-	Limits         Limits            `json:"limits,omitempty"`
-	NodeSelector   map[string]string `json:"nodeSelector,omitempty"`
-	CredsRef       string            `json:"credsRef,omitempty"`
-	PvcName        string            `json:"pvcName,omitempty"`
-	Backup         Backup            `json:"backup,omitempty"`
+	Enabled          bool              `json:"enabled,omitempty"`
+	ServiceAccount   string            `json:"serviceAccount,omitempty"`
+	Image            string            `json:"image,omitempty"`
+	SvcName          string            `json:"svcName,omitempty"`
+	Port             int               `json:"port,omitempty"`
+	StorageSize      string            `json:"storageSize,omitempty"`
+	StorageClass     string            `json:"storageClass,omitempty"`
+	Requests         Requests          `json:"requests,omitempty"`
+	Limits           Limits            `json:"limits,omitempty"`
+	NodeSelector     map[string]string `json:"nodeSelector,omitempty"`
+	CredsRef         string            `json:"credsRef,omitempty"`
+	PvcName          string            `json:"pvcName,omitempty"`
+	Backup           Backup            `json:"backup,omitempty"`
+	PodSpecOverrides PodSpecOverrides  `json:"podSpecOverrides,omitempty"`
+	External         ExternalDb        `json:"external,omitempty"`
 }
 
 type Es struct {
-	Enabled        bool              `json:"enabled,omitempty"`
-	ServiceAccount string            `json:"serviceAccount,omitempty"`
-	Image          string            `json:"image,omitempty"`
-	Port           int               `json:"port,omitempty"`
-	StorageSize    string            `json:"storageSize,omitempty"`
-	SvcName        string            `json:"svcName,omitempty"`
-	NodePort       int               `json:"nodePort,omitempty"`
-	StorageClass   string            `json:"storageClass,omitempty"`
-	Requests       Requests          `json:"requests,omitempty"`
-	Limits         Limits            `json:"limits,omitempty"`
-	JavaOpts       string            `json:"javaOpts,omitempty"`
-	PatchEsNodes   bool              `json:"patchEsNodes,omitempty"`
-	NodeSelector   map[string]string `json:"nodeSelector,omitempty"`
-	CredsRef       string            `json:"credsRef,omitempty"`
-	PvcName        string            `json:"pvcName,omitempty"`
-	CleanupPolicy  CleanupPolicy     `json:"cleanupPolicy,omitempty"`
+	Enabled          bool              `json:"enabled,omitempty"`
+	ServiceAccount   string            `json:"serviceAccount,omitempty"`
+	Image            string            `json:"image,omitempty"`
+	Port             int               `json:"port,omitempty"`
+	StorageSize      string            `json:"storageSize,omitempty"`
+	SvcName          string            `json:"svcName,omitempty"`
+	NodePort         int               `json:"nodePort,omitempty"`
+	StorageClass     string            `json:"storageClass,omitempty"`
+	Requests         Requests          `json:"requests,omitempty"`
+	Limits           Limits            `json:"limits,omitempty"`
+	JavaOpts         string            `json:"javaOpts,omitempty"`
+	PatchEsNodes     bool              `json:"patchEsNodes,omitempty"`
+	NodeSelector     map[string]string `json:"nodeSelector,omitempty"`
+	CredsRef         string            `json:"credsRef,omitempty"`
+	PvcName          string            `json:"pvcName,omitempty"`
+	CleanupPolicy    CleanupPolicy     `json:"cleanupPolicy,omitempty"`
+	Backup           Backup            `json:"backup,omitempty"`
+	PodSpecOverrides PodSpecOverrides  `json:"podSpecOverrides,omitempty"`
+	External         ExternalDb        `json:"external,omitempty"`
 }
 
 type CleanupPolicy struct {
@@ -165,6 +202,15 @@ var pgDefault = Pg{
 		Rotation:  5,
 		Period:    "24h",
 	},
+	HA: PgHA{
+		Enabled:  false,
+		Replicas: 3,
+		Image:    "registry.opensource.zalan.do/acid/spilo-12:2.1-p1",
+	},
+	External: ExternalDb{
+		Enabled: false,
+		Port:    5432,
+	},
 }
 
 var redisDefault = Redis{
@@ -186,6 +232,17 @@ var redisDefault = Redis{
 		Cpu:    "100m",
 		Memory: "200Mi",
 	},
+	Backup: Backup{
+		Enabled:   false,
+		BucketRef: "cp-object-storage",
+		CredsRef:  "redis-creds",
+		Rotation:  5,
+		Period:    "24h",
+	},
+	External: ExternalDb{
+		Enabled: false,
+		Port:    6379,
+	},
 }
 
 var esDefault = Es{
@@ -210,11 +267,22 @@ var esDefault = Es{
 	CredsRef:     "es-creds",
 	PvcName:      "es-storage",
 	CleanupPolicy: CleanupPolicy{
-		All: "3d",
-		App: "30d",
-		Jobs: "14d",
+		All:       "3d",
+		App:       "30d",
+		Jobs:      "14d",
 		Endpoints: "1825d",
 	},
+	Backup: Backup{
+		Enabled:   false,
+		BucketRef: "cp-object-storage",
+		CredsRef:  "es-creds",
+		Rotation:  5,
+		Period:    "24h",
+	},
+	External: ExternalDb{
+		Enabled: false,
+		Port:    9200,
+	},
 }
 
 var appDbsDefaults = AppDbs{
@@ -8,12 +8,13 @@ type AppInstance struct {
 }
 
 type Fluentbit struct {
-	Enabled      bool              `json:"enabled,omitempty"`
-	Image        string            `json:"image,omitempty"`
-	Requests     Requests          `json:"requests,omitempty"`
-	Limits       Limits            `json:"limits,omitempty"`
-	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
-	LogsMounts   map[string]string `json:"logsMounts,omitempty"`
+	Enabled          bool              `json:"enabled,omitempty"`
+	Image            string            `json:"image,omitempty"`
+	Requests         Requests          `json:"requests,omitempty"`
+	Limits           Limits            `json:"limits,omitempty"`
+	NodeSelector     map[string]string `json:"nodeSelector,omitempty"`
+	LogsMounts       map[string]string `json:"logsMounts,omitempty"`
+	PodSpecOverrides PodSpecOverrides  `json:"podSpecOverrides,omitempty"`
 }
 
 type Elastalert struct {
@@ -33,16 +34,17 @@ type Elastalert struct {
 }
 
 type Kibana struct {
-	Enabled        bool                  `json:"enabled,omitempty"`
-	ServiceAccount string                `json:"serviceAccount,omitempty"`
-	SvcName        string                `json:"svcName,omitempty"`
-	Port           int                   `json:"port,omitempty"`
-	Image          string                `json:"image,omitempty"`
-	NodePort       int                   `json:"nodePort,omitempty"`
-	Requests       Requests              `json:"requests,omitempty"`
-	Limits         Limits                `json:"limits,omitempty"`
-	OauthProxy     OauthProxyServiceConf `json:"oauthProxy,omitempty"`
-	CredsRef       string                `json:"credsRef,omitempty"`
+	Enabled          bool                  `json:"enabled,omitempty"`
+	ServiceAccount   string                `json:"serviceAccount,omitempty"`
+	SvcName          string                `json:"svcName,omitempty"`
+	Port             int                   `json:"port,omitempty"`
+	Image            string                `json:"image,omitempty"`
+	NodePort         int                   `json:"nodePort,omitempty"`
+	Requests         Requests              `json:"requests,omitempty"`
+	Limits           Limits                `json:"limits,omitempty"`
+	OauthProxy       OauthProxyServiceConf `json:"oauthProxy,omitempty"`
+	CredsRef         string                `json:"credsRef,omitempty"`
+	PodSpecOverrides PodSpecOverrides      `json:"podSpecOverrides,omitempty"`
 }
 
 type CnvrgAppLogging struct {
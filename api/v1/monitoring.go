@@ -23,6 +23,7 @@ type Prometheus struct {
 	CredsRef            string            `json:"credsRef,omitempty"`
 	UpstreamRef         string            `json:"upstreamRef,omitempty"`
 	NodeSelector        map[string]string `json:"nodeSelector,omitempty"`
+	PodSpecOverrides    PodSpecOverrides  `json:"podSpecOverrides,omitempty"`
 }
 
 type NodeExporter struct {
@@ -38,13 +39,14 @@ type KubeStateMetrics struct {
 }
 
 type Grafana struct {
-	Enabled    bool                  `json:"enabled,omitempty"`
-	Image      string                `json:"image,omitempty"`
-	SvcName    string                `json:"svcName,omitempty"`
-	Port       int                   `json:"port,omitempty"`
-	NodePort   int                   `json:"nodePort,omitempty"`
-	OauthProxy OauthProxyServiceConf `json:"oauthProxy,omitempty"`
-	CredsRef   string                `json:"credsRef,omitempty"`
+	Enabled          bool                  `json:"enabled,omitempty"`
+	Image            string                `json:"image,omitempty"`
+	SvcName          string                `json:"svcName,omitempty"`
+	Port             int                   `json:"port,omitempty"`
+	NodePort         int                   `json:"nodePort,omitempty"`
+	OauthProxy       OauthProxyServiceConf `json:"oauthProxy,omitempty"`
+	CredsRef         string                `json:"credsRef,omitempty"`
+	PodSpecOverrides PodSpecOverrides      `json:"podSpecOverrides,omitempty"`
 }
 
 type DefaultServiceMonitors struct {
@@ -0,0 +1,74 @@
+package v1
+
+import (
+	"fmt"
+	jsonpatch "github.com/evanphx/json-patch"
+	"sigs.k8s.io/yaml"
+)
+
+type PatchType string
+
+const (
+	StrategicMergePatchType PatchType = "strategic"
+	JSONPatchType           PatchType = "json"
+)
+
+// Override is a user patch applied to the rendered object matched by kind and name
+type Override struct {
+	// +kubebuilder:validation:MinLength=1
+	Kind string `json:"kind"`
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// strategic (strategic merge patch, default) or json (JSON6902 patch)
+	// +kubebuilder:validation:Enum=strategic;json
+	Type PatchType `json:"type,omitempty"`
+	// patch in yaml or json format
+	// +kubebuilder:validation:MinLength=1
+	Patch string `json:"patch"`
+}
+
+func (o Override) GetType() PatchType {
+	if o.Type == "" {
+		return StrategicMergePatchType
+	}
+	return o.Type
+}
+
+// PatchJSON returns the patch converted to json
+func (o Override) PatchJSON() ([]byte, error) {
+	patch, err := yaml.YAMLToJSON([]byte(o.Patch))
+	if err != nil {
+		return nil, fmt.Errorf("override %s/%s: can't parse patch: %v", o.Kind, o.Name, err)
+	}
+	return patch, nil
+}
+
+// Validate checks the patch syntax, it doesn't check the patch can be applied on the rendered object
+func (o Override) Validate() error {
+	patch, err := o.PatchJSON()
+	if err != nil {
+		return err
+	}
+	switch o.GetType() {
+	case StrategicMergePatchType:
+		var p map[string]interface{}
+		if err := yaml.Unmarshal(patch, &p); err != nil {
+			return fmt.Errorf("override %s/%s: strategic merge patch must be an object: %v", o.Kind, o.Name, err)
+		}
+	case JSONPatchType:
+		if _, err := jsonpatch.DecodePatch(patch); err != nil {
+			return fmt.Errorf("override %s/%s: invalid json patch: %v", o.Kind, o.Name, err)
+		}
+	default:
+		return fmt.Errorf("override %s/%s: unsupported patch type: %s", o.Kind, o.Name, o.Type)
+	}
+	return nil
+}
+
+func (a *CnvrgApp) GetOverrides() []Override {
+	return a.Spec.Overrides
+}
+
+func (i *CnvrgInfra) GetOverrides() []Override {
+	return i.Spec.Overrides
+}
@@ -0,0 +1,25 @@
+package v1
+
+import corev1 "k8s.io/api/core/v1"
+
+// PodSpecOverrides are generic pod level settings of a workload component,
+// the env, volume mounts and image pull policy are applied to the main container
+type PodSpecOverrides struct {
+	// replace the blanket toleration added by tenancy when set
+	Tolerations               []corev1.Toleration               `json:"tolerations,omitempty"`
+	Affinity                  *corev1.Affinity                  `json:"affinity,omitempty"`
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+	// appended to the env of the main container
+	Env []corev1.EnvVar `json:"env,omitempty"`
+	// appended to the envFrom of the main container
+	EnvFrom []corev1.EnvFromSource `json:"envFrom,omitempty"`
+	// appended to the pod volumes
+	Volumes []corev1.Volume `json:"volumes,omitempty"`
+	// appended to the volume mounts of the main container
+	VolumeMounts   []corev1.VolumeMount `json:"volumeMounts,omitempty"`
+	PodAnnotations map[string]string    `json:"podAnnotations,omitempty"`
+	// replace the pod security context of the component
+	SecurityContext *corev1.PodSecurityContext `json:"securityContext,omitempty"`
+	// +kubebuilder:validation:Enum=Always;Never;IfNotPresent
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+}
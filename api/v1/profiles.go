@@ -0,0 +1,285 @@
+package v1
+
+import (
+	"fmt"
+	"github.com/imdario/mergo"
+)
+
+// +kubebuilder:validation:Enum=dev;small;medium;large
+type Profile string
+
+const (
+	DevProfile    Profile = "dev"
+	SmallProfile  Profile = "small"
+	MediumProfile Profile = "medium"
+	LargeProfile  Profile = "large"
+)
+
+// Profiles lists the sizing profiles from the smallest to the largest
+var Profiles = []Profile{DevProfile, SmallProfile, MediumProfile, LargeProfile}
+
+// profiles are the sizing of each profile, only the non zero fields are applied on top of the default spec.
+// PG shared buffers are 1/4 and effective cache size 1/2 of the PG requested memory, ES heap is 1/2 of the ES requested memory
+var profiles = map[Profile]CnvrgAppSpec{
+	DevProfile: {
+		ControlPlane: ControlPlane{
+			WebApp: WebApp{
+				Replicas: 1,
+				Requests: Requests{Cpu: "200m", Memory: "1Gi"},
+				Limits:   Limits{Cpu: "2", Memory: "4Gi"},
+				Hpa:      Hpa{MaxReplicas: 2},
+			},
+			Sidekiq: Sidekiq{
+				Replicas: 1,
+				Requests: Requests{Cpu: "100m", Memory: "1Gi"},
+				Limits:   Limits{Cpu: "1", Memory: "4Gi"},
+				Hpa:      Hpa{MaxReplicas: 2},
+			},
+			Searchkiq: Searchkiq{
+				Replicas: 1,
+				Requests: Requests{Cpu: "100m", Memory: "512Mi"},
+				Limits:   Limits{Cpu: "1", Memory: "2Gi"},
+				Hpa:      Hpa{MaxReplicas: 2},
+			},
+			Systemkiq: Systemkiq{
+				Replicas: 1,
+				Requests: Requests{Cpu: "100m", Memory: "1Gi"},
+				Limits:   Limits{Cpu: "1", Memory: "4Gi"},
+				Hpa:      Hpa{MaxReplicas: 2},
+			},
+		},
+		Dbs: AppDbs{
+			Pg: Pg{
+				StorageSize:        "20Gi",
+				Requests:           Requests{Cpu: "250m", Memory: "1Gi"},
+				Limits:             Limits{Cpu: "2", Memory: "4Gi"},
+				MaxConnections:     100,
+				SharedBuffers:      "256MB",
+				EffectiveCacheSize: "512MB",
+			},
+			Redis: Redis{
+				StorageSize: "5Gi",
+				Requests:    Requests{Cpu: "100m", Memory: "200Mi"},
+				Limits:      Limits{Cpu: "500m", Memory: "1Gi"},
+			},
+			Minio: Minio{
+				StorageSize: "20Gi",
+				Requests:    Requests{Cpu: "100m", Memory: "512Mi"},
+				Limits:      Limits{Cpu: "2", Memory: "4Gi"},
+			},
+			Es: Es{
+				StorageSize: "20Gi",
+				Requests:    Requests{Cpu: "250m", Memory: "2Gi"},
+				Limits:      Limits{Cpu: "2", Memory: "4Gi"},
+				JavaOpts:    "-Xms1g -Xmx1g",
+			},
+		},
+		Monitoring: CnvrgAppMonitoring{
+			Prometheus: Prometheus{
+				StorageSize: "20Gi",
+				Requests:    Requests{Cpu: "100m", Memory: "500Mi"},
+				Limits:      Limits{Cpu: "1", Memory: "2Gi"},
+			},
+		},
+	},
+	SmallProfile: {
+		ControlPlane: ControlPlane{
+			WebApp: WebApp{
+				Replicas: 1,
+				Requests: Requests{Cpu: "500m", Memory: "2Gi"},
+				Limits:   Limits{Cpu: "2", Memory: "4Gi"},
+				Hpa:      Hpa{MaxReplicas: 3},
+			},
+			Sidekiq: Sidekiq{
+				Replicas: 1,
+				Requests: Requests{Cpu: "200m", Memory: "2Gi"},
+				Limits:   Limits{Cpu: "2", Memory: "4Gi"},
+				Hpa:      Hpa{MaxReplicas: 3},
+			},
+			Searchkiq: Searchkiq{
+				Replicas: 1,
+				Requests: Requests{Cpu: "200m", Memory: "1Gi"},
+				Limits:   Limits{Cpu: "2", Memory: "4Gi"},
+				Hpa:      Hpa{MaxReplicas: 3},
+			},
+			Systemkiq: Systemkiq{
+				Replicas: 1,
+				Requests: Requests{Cpu: "200m", Memory: "1Gi"},
+				Limits:   Limits{Cpu: "2", Memory: "4Gi"},
+				Hpa:      Hpa{MaxReplicas: 3},
+			},
+		},
+		Dbs: AppDbs{
+			Pg: Pg{
+				StorageSize:        "50Gi",
+				Requests:           Requests{Cpu: "500m", Memory: "2Gi"},
+				Limits:             Limits{Cpu: "4", Memory: "8Gi"},
+				MaxConnections:     300,
+				SharedBuffers:      "512MB",
+				EffectiveCacheSize: "1024MB",
+			},
+			Redis: Redis{
+				StorageSize: "10Gi",
+				Requests:    Requests{Cpu: "100m", Memory: "200Mi"},
+				Limits:      Limits{Cpu: "1", Memory: "2Gi"},
+			},
+			Minio: Minio{
+				StorageSize: "50Gi",
+				Requests:    Requests{Cpu: "200m", Memory: "1Gi"},
+				Limits:      Limits{Cpu: "4", Memory: "8Gi"},
+			},
+			Es: Es{
+				StorageSize: "50Gi",
+				Requests:    Requests{Cpu: "500m", Memory: "4Gi"},
+				Limits:      Limits{Cpu: "2", Memory: "8Gi"},
+				JavaOpts:    "-Xms2g -Xmx2g",
+			},
+		},
+		Monitoring: CnvrgAppMonitoring{
+			Prometheus: Prometheus{
+				StorageSize: "50Gi",
+				Requests:    Requests{Cpu: "200m", Memory: "500Mi"},
+				Limits:      Limits{Cpu: "2", Memory: "4Gi"},
+			},
+		},
+	},
+	MediumProfile: {
+		ControlPlane: ControlPlane{
+			WebApp: WebApp{
+				Replicas: 2,
+				Requests: Requests{Cpu: "1", Memory: "4Gi"},
+				Limits:   Limits{Cpu: "4", Memory: "8Gi"},
+				Hpa:      Hpa{MaxReplicas: 5},
+			},
+			Sidekiq: Sidekiq{
+				Replicas: 2,
+				Requests: Requests{Cpu: "500m", Memory: "3750Mi"},
+				Limits:   Limits{Cpu: "2", Memory: "8Gi"},
+				Hpa:      Hpa{MaxReplicas: 5},
+			},
+			Searchkiq: Searchkiq{
+				Replicas: 1,
+				Requests: Requests{Cpu: "200m", Memory: "1Gi"},
+				Limits:   Limits{Cpu: "2", Memory: "8Gi"},
+				Hpa:      Hpa{MaxReplicas: 5},
+			},
+			Systemkiq: Systemkiq{
+				Replicas: 1,
+				Requests: Requests{Cpu: "300m", Memory: "2Gi"},
+				Limits:   Limits{Cpu: "2", Memory: "8Gi"},
+				Hpa:      Hpa{MaxReplicas: 5},
+			},
+		},
+		Dbs: AppDbs{
+			Pg: Pg{
+				StorageSize:        "100Gi",
+				Requests:           Requests{Cpu: "2", Memory: "8Gi"},
+				Limits:             Limits{Cpu: "8", Memory: "32Gi"},
+				MaxConnections:     500,
+				SharedBuffers:      "2048MB",
+				EffectiveCacheSize: "4096MB",
+			},
+			Redis: Redis{
+				StorageSize: "10Gi",
+				Requests:    Requests{Cpu: "250m", Memory: "500Mi"},
+				Limits:      Limits{Cpu: "1", Memory: "2Gi"},
+			},
+			Minio: Minio{
+				StorageSize: "100Gi",
+				Requests:    Requests{Cpu: "500m", Memory: "2Gi"},
+				Limits:      Limits{Cpu: "8", Memory: "20Gi"},
+			},
+			Es: Es{
+				StorageSize: "100Gi",
+				Requests:    Requests{Cpu: "1", Memory: "8Gi"},
+				Limits:      Limits{Cpu: "4", Memory: "16Gi"},
+				JavaOpts:    "-Xms4g -Xmx4g",
+			},
+		},
+		Monitoring: CnvrgAppMonitoring{
+			Prometheus: Prometheus{
+				StorageSize: "100Gi",
+				Requests:    Requests{Cpu: "500m", Memory: "1Gi"},
+				Limits:      Limits{Cpu: "2", Memory: "8Gi"},
+			},
+		},
+	},
+	LargeProfile: {
+		ControlPlane: ControlPlane{
+			WebApp: WebApp{
+				Replicas: 3,
+				Requests: Requests{Cpu: "2", Memory: "8Gi"},
+				Limits:   Limits{Cpu: "8", Memory: "16Gi"},
+				Hpa:      Hpa{MaxReplicas: 10},
+			},
+			Sidekiq: Sidekiq{
+				Replicas: 3,
+				Requests: Requests{Cpu: "1", Memory: "4Gi"},
+				Limits:   Limits{Cpu: "4", Memory: "16Gi"},
+				Hpa:      Hpa{MaxReplicas: 10},
+			},
+			Searchkiq: Searchkiq{
+				Replicas: 2,
+				Requests: Requests{Cpu: "500m", Memory: "2Gi"},
+				Limits:   Limits{Cpu: "4", Memory: "8Gi"},
+				Hpa:      Hpa{MaxReplicas: 10},
+			},
+			Systemkiq: Systemkiq{
+				Replicas: 2,
+				Requests: Requests{Cpu: "500m", Memory: "2Gi"},
+				Limits:   Limits{Cpu: "4", Memory: "8Gi"},
+				Hpa:      Hpa{MaxReplicas: 10},
+			},
+		},
+		Dbs: AppDbs{
+			Pg: Pg{
+				StorageSize:        "500Gi",
+				Requests:           Requests{Cpu: "4", Memory: "16Gi"},
+				Limits:             Limits{Cpu: "16", Memory: "64Gi"},
+				MaxConnections:     1000,
+				SharedBuffers:      "4096MB",
+				EffectiveCacheSize: "8192MB",
+			},
+			Redis: Redis{
+				StorageSize: "20Gi",
+				Requests:    Requests{Cpu: "500m", Memory: "1Gi"},
+				Limits:      Limits{Cpu: "2", Memory: "4Gi"},
+			},
+			Minio: Minio{
+				StorageSize: "1Ti",
+				Requests:    Requests{Cpu: "1", Memory: "4Gi"},
+				Limits:      Limits{Cpu: "16", Memory: "32Gi"},
+			},
+			Es: Es{
+				StorageSize: "500Gi",
+				Requests:    Requests{Cpu: "2", Memory: "16Gi"},
+				Limits:      Limits{Cpu: "8", Memory: "32Gi"},
+				JavaOpts:    "-Xms8g -Xmx8g",
+			},
+		},
+		Monitoring: CnvrgAppMonitoring{
+			Prometheus: Prometheus{
+				StorageSize: "200Gi",
+				Requests:    Requests{Cpu: "1", Memory: "2Gi"},
+				Limits:      Limits{Cpu: "4", Memory: "16Gi"},
+			},
+		},
+	},
+}
+
+// DefaultCnvrgAppSpecForProfile returns the default spec with the sizing of the profile applied,
+// the empty profile returns the default spec as is
+func DefaultCnvrgAppSpecForProfile(profile Profile) (CnvrgAppSpec, error) {
+	spec := DefaultCnvrgAppSpec()
+	if profile == "" {
+		return spec, nil
+	}
+	sizing, ok := profiles[profile]
+	if !ok {
+		return spec, fmt.Errorf("unknown profile %q", profile)
+	}
+	if err := mergo.Merge(&spec, sizing, mergo.WithOverride); err != nil {
+		return spec, err
+	}
+	return spec, nil
+}
@@ -1,19 +1,23 @@
 package v1
 
+import corev1 "k8s.io/api/core/v1"
+
 type SSO struct {
-	Enabled                          bool     `json:"enabled,omitempty"`
-	Image                            string   `json:"image,omitempty"`
-	AdminUser                        string   `json:"adminUser,omitempty"`
-	Provider                         string   `json:"provider,omitempty"`
-	EmailDomain                      []string `json:"emailDomain,omitempty"`
-	ClientID                         string   `json:"clientId,omitempty"`
-	ClientSecret                     string   `json:"clientSecret,omitempty"`
-	CookieSecret                     string   `json:"cookieSecret,omitempty"`
-	AzureTenant                      string   `json:"azureTenant,omitempty"`
-	OidcIssuerURL                    string   `json:"oidcIssuerUrl,omitempty"`
-	RealmName                        string   `json:"realmName,omitempty"`
-	ServiceUrl                       string   `json:"serviceUrl,omitempty"`
-	InsecureOidcAllowUnverifiedEmail bool     `json:"insecureOidcAllowUnverifiedEmail,omitempty"`
+	Enabled                          bool                      `json:"enabled,omitempty"`
+	Image                            string                    `json:"image,omitempty"`
+	AdminUser                        string                    `json:"adminUser,omitempty"`
+	Provider                         string                    `json:"provider,omitempty"`
+	EmailDomain                      []string                  `json:"emailDomain,omitempty"`
+	ClientID                         string                    `json:"clientId,omitempty"`
+	ClientSecret                     string                    `json:"clientSecret,omitempty"`
+	ClientSecretRef                  *corev1.SecretKeySelector `json:"clientSecretRef,omitempty"`
+	CookieSecret                     string                    `json:"cookieSecret,omitempty"`
+	CookieSecretRef                  *corev1.SecretKeySelector `json:"cookieSecretRef,omitempty"`
+	AzureTenant                      string                    `json:"azureTenant,omitempty"`
+	OidcIssuerURL                    string                    `json:"oidcIssuerUrl,omitempty"`
+	RealmName                        string                    `json:"realmName,omitempty"`
+	ServiceUrl                       string                    `json:"serviceUrl,omitempty"`
+	InsecureOidcAllowUnverifiedEmail bool                      `json:"insecureOidcAllowUnverifiedEmail,omitempty"`
 }
 
 type OauthProxyServiceConf struct {
@@ -1,5 +1,7 @@
 package v1
 
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
 type OperatorStatus string
 
 const (
@@ -10,9 +12,91 @@ const (
 	StatusRemoving    OperatorStatus = "REMOVING"
 )
 
+// status condition types
+const (
+	ConditionReconciled            = "Reconciled"
+	ConditionIngressReachable      = "IngressReachable"
+	ConditionWebAppAvailable       = "WebAppAvailable"
+	ConditionSidekiqAvailable      = "SidekiqAvailable"
+	ConditionSearchkiqAvailable    = "SearchkiqAvailable"
+	ConditionSystemkiqAvailable    = "SystemkiqAvailable"
+	ConditionPgReady               = "PgReady"
+	ConditionMinioReady            = "MinioReady"
+	ConditionRedisReady            = "RedisReady"
+	ConditionEsReady               = "EsReady"
+	ConditionKibanaReady           = "KibanaReady"
+	ConditionPrometheusReady       = "PrometheusReady"
+	ConditionGrafanaReady          = "GrafanaReady"
+	ConditionFluentbitReady        = "FluentbitReady"
+	ConditionIstioReady            = "IstioReady"
+	ConditionSecretRefsResolved    = "SecretRefsResolved"
+	ConditionPgUpgraded            = "PgUpgraded"
+	ConditionExternalDbsReachable  = "ExternalDbsReachable"
+	ConditionPodSpecOverridesValid = "PodSpecOverridesValid"
+	ConditionOverridesMatched      = "OverridesMatched"
+)
+
+// status condition reasons
+const (
+	ReasonReconcileSucceeded      = "ReconcileSucceeded"
+	ReasonReconcileFailed         = "ReconcileFailed"
+	ReasonDependenciesPending     = "DependenciesPending"
+	ReasonReady                   = "Ready"
+	ReasonNotReady                = "NotReady"
+	ReasonSecretRefsResolved      = "SecretRefsResolved"
+	ReasonSecretRefMissing        = "SecretRefMissing"
+	ReasonPgUpgradeRunning        = "PgUpgradeRunning"
+	ReasonPgUpgradeFailed         = "PgUpgradeFailed"
+	ReasonPgUpgradeSucceeded      = "PgUpgradeSucceeded"
+	ReasonPgUpgradeCompleted      = "PgUpgradeCompleted"
+	ReasonPgUpgradeRolledBack     = "PgUpgradeRolledBack"
+	ReasonPreflightRunning        = "PreflightRunning"
+	ReasonPreflightSucceeded      = "PreflightSucceeded"
+	ReasonPreflightFailed         = "PreflightFailed"
+	ReasonPodSpecOverridesValid   = "PodSpecOverridesValid"
+	ReasonPodSpecOverridesInvalid = "PodSpecOverridesInvalid"
+	ReasonOverridesMatched        = "OverridesMatched"
+	ReasonOverridesUnmatched      = "OverridesUnmatched"
+)
+
+type PgUpgradePhase string
+
+const (
+	// PgUpgradeRunning the upgrade job is copying the data, pg is serving the previous version
+	PgUpgradeRunning PgUpgradePhase = "Running"
+	// PgUpgradeFailed the upgrade job failed, pg is serving the previous version
+	PgUpgradeFailed PgUpgradePhase = "Failed"
+	// PgUpgradeSucceeded pg is serving the new version, the pvc of the previous version is kept until confirmed
+	PgUpgradeSucceeded PgUpgradePhase = "Succeeded"
+	// PgUpgradeCompleted the upgrade is confirmed and the pvc of the previous version is removed
+	PgUpgradeCompleted PgUpgradePhase = "Completed"
+	// PgUpgradeRolledBack pg is serving the previous version again
+	PgUpgradeRolledBack PgUpgradePhase = "RolledBack"
+)
+
+// PgUpgradeStatus reports the last pg major version upgrade
+type PgUpgradeStatus struct {
+	Phase       PgUpgradePhase `json:"phase,omitempty"`
+	FromVersion int            `json:"fromVersion,omitempty"`
+	ToVersion   int            `json:"toVersion,omitempty"`
+	FromImage   string         `json:"fromImage,omitempty"`
+	ToImage     string         `json:"toImage,omitempty"`
+	// PvcName is the pvc of the new version data
+	PvcName string `json:"pvcName,omitempty"`
+	// PreviousPvcName is the pvc of the previous version data
+	PreviousPvcName string       `json:"previousPvcName,omitempty"`
+	Message         string       `json:"message,omitempty"`
+	StartTime       *metav1.Time `json:"startTime,omitempty"`
+	CompletionTime  *metav1.Time `json:"completionTime,omitempty"`
+}
+
 type Status struct {
 	Status         OperatorStatus  `json:"status,omitempty"`
 	Message        string          `json:"message,omitempty"`
 	Progress       int             `json:"progress,omitempty"`
 	StackReadiness map[string]bool `json:"stackReadiness,omitempty"`
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	PgUpgrade  *PgUpgradeStatus   `json:"pgUpgrade,omitempty"`
 }
@@ -0,0 +1,258 @@
+package v1
+
+import (
+	"fmt"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"regexp"
+)
+
+// backupPeriod is one of [Xs, Xm, Xh]
+var backupPeriod = regexp.MustCompile(`^[1-9][0-9]*[smh]$`)
+
+// componentResources are the requests and limits of a component by the component field path
+type componentResources struct {
+	path     *field.Path
+	requests Requests
+	limits   Limits
+}
+
+// nodePort is the node port of a component by the component field path
+type nodePort struct {
+	path    *field.Path
+	enabled bool
+	port    int
+}
+
+// secretValue is a plaintext credential and its secret key reference by the reference field path
+type secretValue struct {
+	path  *field.Path
+	value string
+	ref   *corev1.SecretKeySelector
+}
+
+// externalDb is the external database of a db component by the component field path
+type externalDb struct {
+	path     *field.Path
+	enabled  bool
+	external ExternalDb
+}
+
+// podSpec is the pod spec overrides of a component by the overrides field path
+type podSpec struct {
+	path      *field.Path
+	overrides PodSpecOverrides
+}
+
+func validateStorageSize(path *field.Path, size string) field.ErrorList {
+	if size == "" {
+		return nil
+	}
+	if _, err := resource.ParseQuantity(size); err != nil {
+		return field.ErrorList{field.Invalid(path, size, err.Error())}
+	}
+	return nil
+}
+
+// validateResources checks the requests and limits are parsable and the effective requests don't exceed the effective limits,
+// the fields not set in the spec are taken from the defaults, which are listed in the same order
+func validateResources(resources, defaults []componentResources) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, r := range resources {
+		d := defaults[i]
+		allErrs = append(allErrs, validateRequestLimit(r.path, "cpu", r.requests.Cpu, r.limits.Cpu, d.requests.Cpu, d.limits.Cpu)...)
+		allErrs = append(allErrs, validateRequestLimit(r.path, "memory", r.requests.Memory, r.limits.Memory, d.requests.Memory, d.limits.Memory)...)
+	}
+	return allErrs
+}
+
+func validateRequestLimit(path *field.Path, name, request, limit, defaultRequest, defaultLimit string) field.ErrorList {
+	var allErrs field.ErrorList
+	requestPath, limitPath := path.Child("requests", name), path.Child("limits", name)
+	requestQuantity, err := effectiveQuantity(request, defaultRequest)
+	if err != nil {
+		allErrs = append(allErrs, field.Invalid(requestPath, request, err.Error()))
+	}
+	limitQuantity, err := effectiveQuantity(limit, defaultLimit)
+	if err != nil {
+		allErrs = append(allErrs, field.Invalid(limitPath, limit, err.Error()))
+	}
+	// the defaults are consistent, only the fields set in the spec can break them
+	if requestQuantity == nil || limitQuantity == nil || requestQuantity.Cmp(*limitQuantity) <= 0 || (request == "" && limit == "") {
+		return allErrs
+	}
+	if request != "" {
+		return append(allErrs, field.Invalid(requestPath, request,
+			fmt.Sprintf("must be less than or equal to %s limit %s", name, limitQuantity.String())))
+	}
+	return append(allErrs, field.Invalid(limitPath, limit,
+		fmt.Sprintf("must be greater than or equal to the default %s request %s", name, requestQuantity.String())))
+}
+
+// effectiveQuantity parses the value set in the spec, or the default when not set
+func effectiveQuantity(value, defaultValue string) (*resource.Quantity, error) {
+	if value == "" {
+		value = defaultValue
+	}
+	if value == "" {
+		return nil, nil
+	}
+	q, err := resource.ParseQuantity(value)
+	if err != nil {
+		return nil, err
+	}
+	return &q, nil
+}
+
+func validateHTTPS(path *field.Path, https HTTPS) field.ErrorList {
+	if !https.Enabled || https.CertSecret != "" {
+		return nil
+	}
+	var allErrs field.ErrorList
+	if https.Cert == "" {
+		allErrs = append(allErrs, field.Required(path.Child("cert"), "certSecret or cert and key are required when https is enabled"))
+	}
+	if https.Key == "" {
+		allErrs = append(allErrs, field.Required(path.Child("key"), "certSecret or cert and key are required when https is enabled"))
+	}
+	return allErrs
+}
+
+func validateSSO(path *field.Path, sso SSO) field.ErrorList {
+	if sso.Enabled && sso.ClientID == "" {
+		return field.ErrorList{field.Required(path.Child("clientId"), "required when sso is enabled")}
+	}
+	return nil
+}
+
+func validateBackup(path *field.Path, backup Backup) field.ErrorList {
+	if backup.Period != "" && !backupPeriod.MatchString(backup.Period) {
+		return field.ErrorList{field.Invalid(path.Child("period"), backup.Period, "must be one of Xs, Xm or Xh, e.g. 24h")}
+	}
+	return nil
+}
+
+// validatePgHA checks the pg ha cluster has a replica to fail over to
+func validatePgHA(path *field.Path, pg Pg) field.ErrorList {
+	if !pg.HA.Enabled {
+		return nil
+	}
+	var allErrs field.ErrorList
+	if pg.HA.Replicas < 2 {
+		allErrs = append(allErrs, field.Invalid(path.Child("replicas"), pg.HA.Replicas, "at least 2 replicas are required when ha is enabled"))
+	}
+	if pg.HugePages.Enabled {
+		allErrs = append(allErrs, field.Forbidden(path.Child("enabled"), "hugePages are not supported when ha is enabled"))
+	}
+	return allErrs
+}
+
+// validatePgHAUpdate rejects switching an existing pg between the single instance and the ha modes,
+// the new mode bootstraps an empty database instead of the data of the running pg
+func validatePgHAUpdate(path *field.Path, old, pg Pg) field.ErrorList {
+	if !old.Enabled || !pg.Enabled || old.HA.Enabled == pg.HA.Enabled {
+		return nil
+	}
+	return field.ErrorList{field.Forbidden(path.Child("enabled"),
+		"pg can't be switched between the single instance and the ha modes, the new mode starts with an empty database")}
+}
+
+// validateExternalDbs checks the external databases can be connected, and don't run along the in-cluster database
+func validateExternalDbs(dbs []externalDb) field.ErrorList {
+	var allErrs field.ErrorList
+	for _, db := range dbs {
+		if !db.external.Enabled {
+			continue
+		}
+		path := db.path.Child("external")
+		if db.enabled {
+			allErrs = append(allErrs, field.Forbidden(path.Child("enabled"), fmt.Sprintf("the external database replaces the in-cluster database, %s must be disabled", db.path.Child("enabled"))))
+		}
+		if db.external.Host == "" {
+			allErrs = append(allErrs, field.Required(path.Child("host"), "host of the external database is required"))
+		}
+		if db.external.Port < 0 || db.external.Port > 65535 {
+			allErrs = append(allErrs, field.Invalid(path.Child("port"), db.external.Port, "must be a valid port number"))
+		}
+		if db.external.SecretRef == "" {
+			allErrs = append(allErrs, field.Required(path.Child("secretRef"), "secret with the credentials of the external database is required"))
+		}
+	}
+	return allErrs
+}
+
+// validateNodePorts checks the node ports of the enabled components don't collide
+func validateNodePorts(ports []nodePort) field.ErrorList {
+	var allErrs field.ErrorList
+	used := map[int]*field.Path{}
+	for _, p := range ports {
+		if !p.enabled || p.port == 0 {
+			continue
+		}
+		if other, ok := used[p.port]; ok {
+			allErrs = append(allErrs, field.Invalid(p.path, p.port, fmt.Sprintf("collides with %s", other)))
+			continue
+		}
+		used[p.port] = p.path
+	}
+	return allErrs
+}
+
+// validateSecretValues checks each credential is given either in plaintext or by a complete secret key reference
+func validateSecretValues(values []secretValue) field.ErrorList {
+	var allErrs field.ErrorList
+	for _, v := range values {
+		if v.ref == nil {
+			continue
+		}
+		if v.value != "" {
+			allErrs = append(allErrs, field.Forbidden(v.path, "can't be set together with the plaintext value"))
+		}
+		if v.ref.Name == "" {
+			allErrs = append(allErrs, field.Required(v.path.Child("name"), "secret name is required"))
+		}
+		if v.ref.Key == "" {
+			allErrs = append(allErrs, field.Required(v.path.Child("key"), "secret key is required"))
+		}
+	}
+	return allErrs
+}
+
+// validatePodSpecOverrides checks the overrides which the crd schema doesn't, the large core types (e.g. volumes) are stored as is
+func validatePodSpecOverrides(specs []podSpec) field.ErrorList {
+	var allErrs field.ErrorList
+	for _, s := range specs {
+		for i, env := range s.overrides.Env {
+			if env.Name == "" {
+				allErrs = append(allErrs, field.Required(s.path.Child("env").Index(i).Child("name"), "env name is required"))
+			}
+		}
+		for i, volume := range s.overrides.Volumes {
+			if volume.Name == "" {
+				allErrs = append(allErrs, field.Required(s.path.Child("volumes").Index(i).Child("name"), "volume name is required"))
+			}
+		}
+		for i, mount := range s.overrides.VolumeMounts {
+			if mount.Name == "" {
+				allErrs = append(allErrs, field.Required(s.path.Child("volumeMounts").Index(i).Child("name"), "volume name is required"))
+			}
+			if mount.MountPath == "" {
+				allErrs = append(allErrs, field.Required(s.path.Child("volumeMounts").Index(i).Child("mountPath"), "mount path is required"))
+			}
+		}
+	}
+	return allErrs
+}
+
+// validateOverrides checks the syntax of the overrides patches, patches which don't match a rendered object
+// are reported by the reconciler in the OverridesMatched condition
+func validateOverrides(path *field.Path, overrides []Override) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, o := range overrides {
+		if err := o.Validate(); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Index(i).Child("patch"), o.Patch, err.Error()))
+		}
+	}
+	return allErrs
+}
@@ -20,6 +20,8 @@ limitations under the License.
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -73,6 +75,21 @@ func (in *Backup) DeepCopy() *Backup {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupArtifact) DeepCopyInto(out *BackupArtifact) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupArtifact.
+func (in *BackupArtifact) DeepCopy() *BackupArtifact {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupArtifact)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BaseConfig) DeepCopyInto(out *BaseConfig) {
 	*out = *in
@@ -257,7 +274,7 @@ func (in *CnvrgAppSpec) DeepCopyInto(out *CnvrgAppSpec) {
 		}
 	}
 	in.ControlPlane.DeepCopyInto(&out.ControlPlane)
-	out.Registry = in.Registry
+	in.Registry.DeepCopyInto(&out.Registry)
 	in.Dbs.DeepCopyInto(&out.Dbs)
 	in.Networking.DeepCopyInto(&out.Networking)
 	in.Logging.DeepCopyInto(&out.Logging)
@@ -267,6 +284,11 @@ func (in *CnvrgAppSpec) DeepCopyInto(out *CnvrgAppSpec) {
 	out.CnvrgAppPriorityClass = in.CnvrgAppPriorityClass
 	out.CnvrgJobPriorityClass = in.CnvrgJobPriorityClass
 	out.IngressCheck = in.IngressCheck
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]Override, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CnvrgAppSpec.
@@ -279,6 +301,117 @@ func (in *CnvrgAppSpec) DeepCopy() *CnvrgAppSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CnvrgBackup) DeepCopyInto(out *CnvrgBackup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CnvrgBackup.
+func (in *CnvrgBackup) DeepCopy() *CnvrgBackup {
+	if in == nil {
+		return nil
+	}
+	out := new(CnvrgBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CnvrgBackup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CnvrgBackupList) DeepCopyInto(out *CnvrgBackupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CnvrgBackup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CnvrgBackupList.
+func (in *CnvrgBackupList) DeepCopy() *CnvrgBackupList {
+	if in == nil {
+		return nil
+	}
+	out := new(CnvrgBackupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CnvrgBackupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CnvrgBackupSpec) DeepCopyInto(out *CnvrgBackupSpec) {
+	*out = *in
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]BackupDatabase, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CnvrgBackupSpec.
+func (in *CnvrgBackupSpec) DeepCopy() *CnvrgBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CnvrgBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CnvrgBackupStatus) DeepCopyInto(out *CnvrgBackupStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Artifacts != nil {
+		in, out := &in.Artifacts, &out.Artifacts
+		*out = make([]BackupArtifact, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastSuccessfulTime != nil {
+		in, out := &in.LastSuccessfulTime, &out.LastSuccessfulTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CnvrgBackupStatus.
+func (in *CnvrgBackupStatus) DeepCopy() *CnvrgBackupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CnvrgBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CnvrgClusterProvisionerOperator) DeepCopyInto(out *CnvrgClusterProvisionerOperator) {
 	*out = *in
@@ -442,7 +575,7 @@ func (in *CnvrgInfraSpec) DeepCopyInto(out *CnvrgInfraSpec) {
 	in.Monitoring.DeepCopyInto(&out.Monitoring)
 	in.Networking.DeepCopyInto(&out.Networking)
 	in.Logging.DeepCopyInto(&out.Logging)
-	out.Registry = in.Registry
+	in.Registry.DeepCopyInto(&out.Registry)
 	in.Storage.DeepCopyInto(&out.Storage)
 	in.Dbs.DeepCopyInto(&out.Dbs)
 	in.SSO.DeepCopyInto(&out.SSO)
@@ -466,6 +599,11 @@ func (in *CnvrgInfraSpec) DeepCopyInto(out *CnvrgInfraSpec) {
 	out.Capsule = in.Capsule
 	out.CnvrgAppPriorityClass = in.CnvrgAppPriorityClass
 	out.CnvrgJobPriorityClass = in.CnvrgJobPriorityClass
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]Override, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CnvrgInfraSpec.
@@ -478,6 +616,118 @@ func (in *CnvrgInfraSpec) DeepCopy() *CnvrgInfraSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CnvrgRestore) DeepCopyInto(out *CnvrgRestore) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CnvrgRestore.
+func (in *CnvrgRestore) DeepCopy() *CnvrgRestore {
+	if in == nil {
+		return nil
+	}
+	out := new(CnvrgRestore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CnvrgRestore) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CnvrgRestoreList) DeepCopyInto(out *CnvrgRestoreList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CnvrgRestore, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CnvrgRestoreList.
+func (in *CnvrgRestoreList) DeepCopy() *CnvrgRestoreList {
+	if in == nil {
+		return nil
+	}
+	out := new(CnvrgRestoreList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CnvrgRestoreList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CnvrgRestoreSpec) DeepCopyInto(out *CnvrgRestoreSpec) {
+	*out = *in
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]BackupDatabase, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CnvrgRestoreSpec.
+func (in *CnvrgRestoreSpec) DeepCopy() *CnvrgRestoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CnvrgRestoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CnvrgRestoreStatus) DeepCopyInto(out *CnvrgRestoreStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Artifacts != nil {
+		in, out := &in.Artifacts, &out.Artifacts
+		*out = make([]BackupArtifact, len(*in))
+		copy(*out, *in)
+	}
+	if in.Quiesced != nil {
+		in, out := &in.Quiesced, &out.Quiesced
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CnvrgRestoreStatus.
+func (in *CnvrgRestoreStatus) DeepCopy() *CnvrgRestoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CnvrgRestoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CnvrgRouter) DeepCopyInto(out *CnvrgRouter) {
 	*out = *in
@@ -544,17 +794,17 @@ func (in *ConsistentHash) DeepCopy() *ConsistentHash {
 func (in *ControlPlane) DeepCopyInto(out *ControlPlane) {
 	*out = *in
 	in.WebApp.DeepCopyInto(&out.WebApp)
-	out.Sidekiq = in.Sidekiq
+	in.Sidekiq.DeepCopyInto(&out.Sidekiq)
 	out.Searchkiq = in.Searchkiq
 	out.Systemkiq = in.Systemkiq
-	out.Hyper = in.Hyper
+	in.Hyper.DeepCopyInto(&out.Hyper)
 	out.CnvrgScheduler = in.CnvrgScheduler
 	out.CnvrgClusterProvisionerOperator = in.CnvrgClusterProvisionerOperator
 	out.CnvrgRouter = in.CnvrgRouter
 	in.BaseConfig.DeepCopyInto(&out.BaseConfig)
-	out.Ldap = in.Ldap
-	out.SMTP = in.SMTP
-	out.ObjectStorage = in.ObjectStorage
+	in.Ldap.DeepCopyInto(&out.Ldap)
+	in.SMTP.DeepCopyInto(&out.SMTP)
+	in.ObjectStorage.DeepCopyInto(&out.ObjectStorage)
 	in.Mpi.DeepCopyInto(&out.Mpi)
 }
 
@@ -659,6 +909,9 @@ func (in *Es) DeepCopyInto(out *Es) {
 		}
 	}
 	out.CleanupPolicy = in.CleanupPolicy
+	out.Backup = in.Backup
+	in.PodSpecOverrides.DeepCopyInto(&out.PodSpecOverrides)
+	out.External = in.External
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Es.
@@ -671,6 +924,21 @@ func (in *Es) DeepCopy() *Es {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalDb) DeepCopyInto(out *ExternalDb) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalDb.
+func (in *ExternalDb) DeepCopy() *ExternalDb {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalDb)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Fluentbit) DeepCopyInto(out *Fluentbit) {
 	*out = *in
@@ -690,6 +958,7 @@ func (in *Fluentbit) DeepCopyInto(out *Fluentbit) {
 			(*out)[key] = val
 		}
 	}
+	in.PodSpecOverrides.DeepCopyInto(&out.PodSpecOverrides)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Fluentbit.
@@ -724,6 +993,7 @@ func (in *Gpu) DeepCopy() *Gpu {
 func (in *Grafana) DeepCopyInto(out *Grafana) {
 	*out = *in
 	in.OauthProxy.DeepCopyInto(&out.OauthProxy)
+	in.PodSpecOverrides.DeepCopyInto(&out.PodSpecOverrides)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Grafana.
@@ -838,8 +1108,14 @@ func (in *HugePages) DeepCopy() *HugePages {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Hyper) DeepCopyInto(out *Hyper) {
 	*out = *in
+	if in.TokenSecretRef != nil {
+		in, out := &in.TokenSecretRef, &out.TokenSecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 	out.Requests = in.Requests
 	out.Limits = in.Limits
+	in.PodSpecOverrides.DeepCopyInto(&out.PodSpecOverrides)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Hyper.
@@ -941,6 +1217,7 @@ func (in *Kibana) DeepCopyInto(out *Kibana) {
 	out.Requests = in.Requests
 	out.Limits = in.Limits
 	in.OauthProxy.DeepCopyInto(&out.OauthProxy)
+	in.PodSpecOverrides.DeepCopyInto(&out.PodSpecOverrides)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Kibana.
@@ -978,6 +1255,11 @@ func (in *KubeStateMetrics) DeepCopy() *KubeStateMetrics {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Ldap) DeepCopyInto(out *Ldap) {
 	*out = *in
+	if in.AdminPasswordSecretRef != nil {
+		in, out := &in.AdminPasswordSecretRef, &out.AdminPasswordSecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ldap.
@@ -1033,6 +1315,7 @@ func (in *Minio) DeepCopyInto(out *Minio) {
 			(*out)[key] = val
 		}
 	}
+	in.PodSpecOverrides.DeepCopyInto(&out.PodSpecOverrides)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Minio.
@@ -1055,7 +1338,7 @@ func (in *Mpi) DeepCopyInto(out *Mpi) {
 			(*out)[key] = val
 		}
 	}
-	out.Registry = in.Registry
+	in.Registry.DeepCopyInto(&out.Registry)
 	out.Requests = in.Requests
 	out.Limits = in.Limits
 }
@@ -1152,6 +1435,16 @@ func (in *OauthProxyServiceConf) DeepCopy() *OauthProxyServiceConf {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ObjectStorage) DeepCopyInto(out *ObjectStorage) {
 	*out = *in
+	if in.AccessKeySecretRef != nil {
+		in, out := &in.AccessKeySecretRef, &out.AccessKeySecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretKeySecretRef != nil {
+		in, out := &in.SecretKeySecretRef, &out.SecretKeySecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectStorage.
@@ -1164,6 +1457,21 @@ func (in *ObjectStorage) DeepCopy() *ObjectStorage {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Override) DeepCopyInto(out *Override) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Override.
+func (in *Override) DeepCopy() *Override {
+	if in == nil {
+		return nil
+	}
+	out := new(Override)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Pg) DeepCopyInto(out *Pg) {
 	*out = *in
@@ -1178,6 +1486,10 @@ func (in *Pg) DeepCopyInto(out *Pg) {
 		}
 	}
 	out.Backup = in.Backup
+	in.PodSpecOverrides.DeepCopyInto(&out.PodSpecOverrides)
+	out.HA = in.HA
+	out.Upgrade = in.Upgrade
+	out.External = in.External
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Pg.
@@ -1190,6 +1502,133 @@ func (in *Pg) DeepCopy() *Pg {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgHA) DeepCopyInto(out *PgHA) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgHA.
+func (in *PgHA) DeepCopy() *PgHA {
+	if in == nil {
+		return nil
+	}
+	out := new(PgHA)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgUpgrade) DeepCopyInto(out *PgUpgrade) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgUpgrade.
+func (in *PgUpgrade) DeepCopy() *PgUpgrade {
+	if in == nil {
+		return nil
+	}
+	out := new(PgUpgrade)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgUpgradeStatus) DeepCopyInto(out *PgUpgradeStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgUpgradeStatus.
+func (in *PgUpgradeStatus) DeepCopy() *PgUpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PgUpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodSpecOverrides) DeepCopyInto(out *PodSpecOverrides) {
+	*out = *in
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]corev1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EnvFrom != nil {
+		in, out := &in.EnvFrom, &out.EnvFrom
+		*out = make([]corev1.EnvFromSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]corev1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VolumeMounts != nil {
+		in, out := &in.VolumeMounts, &out.VolumeMounts
+		*out = make([]corev1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PodAnnotations != nil {
+		in, out := &in.PodAnnotations, &out.PodAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(corev1.PodSecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodSpecOverrides.
+func (in *PodSpecOverrides) DeepCopy() *PodSpecOverrides {
+	if in == nil {
+		return nil
+	}
+	out := new(PodSpecOverrides)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PriorityClass) DeepCopyInto(out *PriorityClass) {
 	*out = *in
@@ -1217,6 +1656,7 @@ func (in *Prometheus) DeepCopyInto(out *Prometheus) {
 			(*out)[key] = val
 		}
 	}
+	in.PodSpecOverrides.DeepCopyInto(&out.PodSpecOverrides)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Prometheus.
@@ -1287,6 +1727,8 @@ func (in *Redis) DeepCopyInto(out *Redis) {
 		}
 	}
 	out.Backup = in.Backup
+	in.PodSpecOverrides.DeepCopyInto(&out.PodSpecOverrides)
+	out.External = in.External
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Redis.
@@ -1302,6 +1744,11 @@ func (in *Redis) DeepCopy() *Redis {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Registry) DeepCopyInto(out *Registry) {
 	*out = *in
+	if in.PasswordSecretRef != nil {
+		in, out := &in.PasswordSecretRef, &out.PasswordSecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Registry.
@@ -1332,6 +1779,11 @@ func (in *Requests) DeepCopy() *Requests {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SMTP) DeepCopyInto(out *SMTP) {
 	*out = *in
+	if in.PasswordSecretRef != nil {
+		in, out := &in.PasswordSecretRef, &out.PasswordSecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SMTP.
@@ -1352,6 +1804,16 @@ func (in *SSO) DeepCopyInto(out *SSO) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ClientSecretRef != nil {
+		in, out := &in.ClientSecretRef, &out.ClientSecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CookieSecretRef != nil {
+		in, out := &in.CookieSecretRef, &out.CookieSecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SSO.
@@ -1404,6 +1866,7 @@ func (in *Sidekiq) DeepCopyInto(out *Sidekiq) {
 	out.Requests = in.Requests
 	out.Limits = in.Limits
 	out.Hpa = in.Hpa
+	in.PodSpecOverrides.DeepCopyInto(&out.PodSpecOverrides)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Sidekiq.
@@ -1426,6 +1889,18 @@ func (in *Status) DeepCopyInto(out *Status) {
 			(*out)[key] = val
 		}
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PgUpgrade != nil {
+		in, out := &in.PgUpgrade, &out.PgUpgrade
+		*out = new(PgUpgradeStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Status.
@@ -1495,6 +1970,7 @@ func (in *WebApp) DeepCopyInto(out *WebApp) {
 	out.Limits = in.Limits
 	in.OauthProxy.DeepCopyInto(&out.OauthProxy)
 	out.Hpa = in.Hpa
+	in.PodSpecOverrides.DeepCopyInto(&out.PodSpecOverrides)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebApp.
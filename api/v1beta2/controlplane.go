@@ -0,0 +1,199 @@
+package v1beta2
+
+// +kubebuilder:validation:Enum=minio;aws;azure;gcp
+type ObjectStorageType string
+
+const (
+	MinioObjectStorageType ObjectStorageType = "minio"
+	AwsObjectStorageType   ObjectStorageType = "aws"
+	AzureObjectStorageType ObjectStorageType = "azure"
+	GcpObjectStorageType   ObjectStorageType = "gcp"
+)
+
+type ConsistentHash struct {
+	Key   string `json:"key,omitempty"`
+	Value string `json:"value,omitempty"`
+}
+
+type SharedStorage struct {
+	Enabled        bool           `json:"enabled,omitempty"`
+	ConsistentHash ConsistentHash `json:"consistentHash,omitempty"`
+}
+
+type Limits struct {
+	Cpu    string `json:"cpu,omitempty"`
+	Memory string `json:"memory,omitempty"`
+}
+
+type Hpa struct {
+	Enabled     bool `json:"enabled,omitempty"`
+	Utilization int  `json:"utilization,omitempty"`
+	MaxReplicas int  `json:"maxReplicas,omitempty"`
+}
+
+type Requests struct {
+	Cpu    string `json:"cpu,omitempty"`
+	Memory string `json:"memory,omitempty"`
+}
+
+type WebApp struct {
+	Replicas                int                   `json:"replicas,omitempty"`
+	Enabled                 bool                  `json:"enabled,omitempty"`
+	Port                    int                   `json:"port,omitempty"`
+	Requests                Requests              `json:"requests,omitempty"`
+	Limits                  Limits                `json:"limits,omitempty"`
+	SvcName                 string                `json:"svcName,omitempty"`
+	NodePort                int                   `json:"nodePort,omitempty"`
+	PassengerMaxPoolSize    int                   `json:"passengerMaxPoolSize,omitempty"`
+	InitialDelaySeconds     int                   `json:"initialDelaySeconds,omitempty"`
+	ReadinessPeriodSeconds  int                   `json:"readinessPeriodSeconds,omitempty"`
+	ReadinessTimeoutSeconds int                   `json:"readinessTimeoutSeconds,omitempty"`
+	FailureThreshold        int                   `json:"failureThreshold,omitempty"`
+	OauthProxy              OauthProxyServiceConf `json:"oauthProxy,omitempty"`
+	Hpa                     Hpa                   `json:"hpa,omitempty"`
+	PodSpecOverrides        PodSpecOverrides      `json:"podSpecOverrides,omitempty"`
+}
+
+type Sidekiq struct {
+	Enabled          bool             `json:"enabled,omitempty"`
+	Split            bool             `json:"split,omitempty"`
+	Requests         Requests         `json:"requests,omitempty"`
+	Limits           Limits           `json:"limits,omitempty"`
+	Replicas         int              `json:"replicas,omitempty"`
+	Hpa              Hpa              `json:"hpa,omitempty"`
+	PodSpecOverrides PodSpecOverrides `json:"podSpecOverrides,omitempty"`
+}
+
+type Searchkiq struct {
+	Enabled  bool     `json:"enabled,omitempty"`
+	Requests Requests `json:"requests,omitempty"`
+	Limits   Limits   `json:"limits,omitempty"`
+	Replicas int      `json:"replicas,omitempty"`
+	Hpa      Hpa      `json:"hpa,omitempty"`
+}
+
+type Systemkiq struct {
+	Enabled  bool     `json:"enabled,omitempty"`
+	Requests Requests `json:"requests,omitempty"`
+	Limits   Limits   `json:"limits,omitempty"`
+	Replicas int      `json:"replicas,omitempty"`
+	Hpa      Hpa      `json:"hpa,omitempty"`
+}
+
+type CnvrgRouter struct {
+	Enabled  bool   `json:"enabled,omitempty"`
+	Image    string `json:"image,omitempty"`
+	SvcName  string `json:"svcName,omitempty"`
+	NodePort int    `json:"nodePort,omitempty"`
+}
+
+type Hyper struct {
+	Enabled                 bool             `json:"enabled,omitempty"`
+	Image                   string           `json:"image,omitempty"`
+	Port                    int              `json:"port,omitempty"`
+	Replicas                int              `json:"replicas,omitempty"`
+	NodePort                int              `json:"nodePort,omitempty"`
+	SvcName                 string           `json:"svcName,omitempty"`
+	Token                   *SecretValue     `json:"token,omitempty"`
+	Requests                Requests         `json:"requests,omitempty"`
+	Limits                  Limits           `json:"limits,omitempty"`
+	ReadinessPeriodSeconds  int              `json:"readinessPeriodSeconds,omitempty"`
+	ReadinessTimeoutSeconds int              `json:"readinessTimeoutSeconds,omitempty"`
+	PodSpecOverrides        PodSpecOverrides `json:"podSpecOverrides,omitempty"`
+}
+
+type CnvrgScheduler struct {
+	Enabled  bool     `json:"enabled,omitempty"`
+	Requests Requests `json:"requests,omitempty"`
+	Limits   Limits   `json:"limits,omitempty"`
+	Replicas int      `json:"replicas,omitempty"`
+}
+
+type CnvrgClusterProvisionerOperator struct {
+	Enabled     bool     `json:"enabled,omitempty"`
+	Requests    Requests `json:"requests,omitempty"`
+	Limits      Limits   `json:"limits,omitempty"`
+	Image       string   `json:"image,omitempty"`
+	AwsCredsRef string   `json:"awsCredsRef,omitempty"`
+}
+
+type Registry struct {
+	Name     string       `json:"name,omitempty"`
+	URL      string       `json:"url,omitempty"`
+	User     string       `json:"user,omitempty"`
+	Password *SecretValue `json:"password,omitempty"`
+}
+
+type Ldap struct {
+	Enabled       bool         `json:"enabled,omitempty"`
+	Host          string       `json:"host,omitempty"`
+	Port          int          `json:"port,omitempty"`
+	Account       string       `json:"account,omitempty"`
+	Base          string       `json:"base,omitempty"`
+	AdminUser     string       `json:"adminUser,omitempty"`
+	AdminPassword *SecretValue `json:"adminPassword,omitempty"`
+	Ssl           bool         `json:"ssl,omitempty"`
+}
+
+type SMTP struct {
+	Server            string       `json:"server,omitempty"`
+	Port              int          `json:"port,omitempty"`
+	Username          string       `json:"username,omitempty"`
+	Password          *SecretValue `json:"password,omitempty"`
+	Domain            string       `json:"domain,omitempty"`
+	OpensslVerifyMode string       `json:"opensslVerifyMode,omitempty"`
+	Sender            string       `json:"sender,omitempty"`
+}
+
+type ObjectStorage struct {
+	Type             ObjectStorageType `json:"type,omitempty"`
+	Bucket           string            `json:"bucket,omitempty"`
+	Region           string            `json:"region,omitempty"`
+	AccessKey        *SecretValue      `json:"accessKey,omitempty"`
+	SecretKey        *SecretValue      `json:"secretKey,omitempty"`
+	Endpoint         string            `json:"endpoint,omitempty"`
+	AzureAccountName string            `json:"azureAccountName,omitempty"`
+	AzureContainer   string            `json:"azureContainer,omitempty"`
+	GcpProject       string            `json:"gcpProject,omitempty"`
+	GcpSecretRef     string            `json:"gcpSecretRef,omitempty"`
+}
+
+type BaseConfig struct {
+	JobsStorageClass     string            `json:"jobsStorageClass,omitempty"`
+	FeatureFlags         map[string]string `json:"featureFlags,omitempty"`
+	SentryURL            string            `json:"sentryUrl,omitempty"`
+	RunJobsOnSelfCluster bool              `json:"runJobsOnSelfCluster,omitempty"`
+	AgentCustomTag       string            `json:"agentCustomTag,omitempty"`
+	Intercom             string            `json:"intercom,omitempty"`
+	CnvrgJobUID          string            `json:"cnvrgJobUid,omitempty"`
+	CnvrgJobRbacStrict   bool              `json:"cnvrgJobRbacStrict,omitempty"`
+	CnvrgPrivilegedJob   bool              `json:"cnvrgPrivilegedJob,omitempty"`
+}
+
+type ControlPlane struct {
+	Image                           string                          `json:"image,omitempty"`
+	WebApp                          WebApp                          `json:"webapp,omitempty"`
+	Sidekiq                         Sidekiq                         `json:"sidekiq,omitempty"`
+	Searchkiq                       Searchkiq                       `json:"searchkiq,omitempty"`
+	Systemkiq                       Systemkiq                       `json:"systemkiq,omitempty"`
+	Hyper                           Hyper                           `json:"hyper,omitempty"`
+	CnvrgScheduler                  CnvrgScheduler                  `json:"cnvrgScheduler,omitempty"`
+	CnvrgClusterProvisionerOperator CnvrgClusterProvisionerOperator `json:"cnvrgClusterProvisionerOperator,omitempty"`
+	CnvrgRouter                     CnvrgRouter                     `json:"cnvrgRouter,omitempty"`
+	BaseConfig                      BaseConfig                      `json:"baseConfig,omitempty"`
+	Ldap                            Ldap                            `json:"ldap,omitempty"`
+	SMTP                            SMTP                            `json:"smtp,omitempty"`
+	ObjectStorage                   ObjectStorage                   `json:"objectStorage,omitempty"`
+	Mpi                             Mpi                             `json:"mpi,omitempty"`
+}
+
+type Mpi struct {
+	Enabled              bool              `json:"enabled,omitempty"`
+	Image                string            `json:"image,omitempty"`
+	KubectlDeliveryImage string            `json:"kubectlDeliveryImage,omitempty"`
+	ExtraArgs            map[string]string `json:"extraArgs,omitempty"`
+	// Registry of the mpi images, the default mpi registry is used when unset
+	Registry *Registry `json:"registry,omitempty"`
+	Requests Requests  `json:"requests,omitempty"`
+	Limits   Limits    `json:"limits,omitempty"`
+}
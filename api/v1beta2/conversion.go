@@ -0,0 +1,7 @@
+package v1beta2
+
+// Hub marks CnvrgApp v1beta2 as the conversion hub, the other versions convert to and from it
+func (*CnvrgApp) Hub() {}
+
+// Hub marks CnvrgInfra v1beta2 as the conversion hub
+func (*CnvrgInfra) Hub() {}
@@ -0,0 +1,10 @@
+package v1beta2
+
+// +kubebuilder:validation:Enum=docker;containerd;cri-o;""
+type CriType string
+
+const (
+	CriTypeDocker     CriType = "docker"
+	CriTypeContainerd CriType = "containerd"
+	CriTypeCrio       CriType = "cri-o"
+)
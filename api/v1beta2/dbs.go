@@ -0,0 +1,137 @@
+package v1beta2
+
+type HugePages struct {
+	Enabled bool   `json:"enabled,omitempty"`
+	Size    string `json:"size,omitempty"`
+	Memory  string `json:"memory,omitempty"`
+}
+
+type Pg struct {
+	Enabled            bool              `json:"enabled,omitempty"`
+	ServiceAccount     string            `json:"serviceAccount,omitempty"`
+	Image              string            `json:"image,omitempty"`
+	Port               int               `json:"port,omitempty"`
+	StorageSize        string            `json:"storageSize,omitempty"`
+	SvcName            string            `json:"svcName,omitempty"`
+	StorageClass       string            `json:"storageClass,omitempty"`
+	Requests           Requests          `json:"requests,omitempty"`
+	Limits             Limits            `json:"limits,omitempty"`
+	MaxConnections     int               `json:"maxConnections,omitempty"`
+	SharedBuffers      string            `json:"sharedBuffers,omitempty"`      // https://github.com/sclorg/postgresql-container/tree/generated/12
+	EffectiveCacheSize string            `json:"effectiveCacheSize,omitempty"` // https://github.com/sclorg/postgresql-container/tree/generated/12
+	HugePages          HugePages         `json:"hugePages,omitempty"`
+	NodeSelector       map[string]string `json:"nodeSelector,omitempty"`
+	CredsRef           string            `json:"credsRef,omitempty"`
+	PvcName            string            `json:"pvcName,omitempty"`
+	Backup             Backup            `json:"backup,omitempty"`
+	PodSpecOverrides   PodSpecOverrides  `json:"podSpecOverrides,omitempty"`
+	HA                 PgHA              `json:"ha,omitempty"`
+	Upgrade            PgUpgrade         `json:"upgrade,omitempty"`
+	External           ExternalDb        `json:"external,omitempty"`
+}
+
+// PgHA runs pg as a patroni cluster, a primary and streaming replicas with automated failover
+type PgHA struct {
+	Enabled  bool   `json:"enabled,omitempty"`
+	Replicas int    `json:"replicas,omitempty"`
+	Image    string `json:"image,omitempty"`
+}
+
+// PgUpgrade configures the managed upgrade between pg major versions
+type PgUpgrade struct {
+	// ConfirmedVersion confirms the upgrade to the major version, the pvc of the previous version is removed once confirmed
+	ConfirmedVersion int `json:"confirmedVersion,omitempty"`
+}
+
+// ExternalDb connects the control plane to a managed database instead of the in-cluster one
+type ExternalDb struct {
+	Enabled bool   `json:"enabled,omitempty"`
+	Host    string `json:"host,omitempty"`
+	Port    int    `json:"port,omitempty"`
+	TLS     bool   `json:"tls,omitempty"`
+	// SecretRef is the secret with the database credentials, the username and password keys, and the database key for pg
+	SecretRef string `json:"secretRef,omitempty"`
+}
+
+type Minio struct {
+	Enabled          bool              `json:"enabled,omitempty"`
+	ServiceAccount   string            `json:"serviceAccount,omitempty"`
+	Replicas         int               `json:"replicas,omitempty"`
+	Image            string            `json:"image,omitempty"`
+	Port             int               `json:"port,omitempty"`
+	StorageSize      string            `json:"storageSize,omitempty"`
+	SvcName          string            `json:"svcName,omitempty"`
+	NodePort         int               `json:"nodePort,omitempty"`
+	StorageClass     string            `json:"storageClass,omitempty"`
+	Requests         Requests          `json:"requests,omitempty"`
+	Limits           Limits            `json:"limits,omitempty"`
+	SharedStorage    SharedStorage     `json:"sharedStorage,omitempty"`
+	NodeSelector     map[string]string `json:"nodeSelector,omitempty"`
+	PvcName          string            `json:"pvcName,omitempty"`
+	PodSpecOverrides PodSpecOverrides  `json:"podSpecOverrides,omitempty"`
+}
+
+type Redis struct {
+	Enabled          bool              `json:"enabled,omitempty"`
+	ServiceAccount   string            `json:"serviceAccount,omitempty"`
+	Image            string            `json:"image,omitempty"`
+	SvcName          string            `json:"svcName,omitempty"`
+	Port             int               `json:"port,omitempty"`
+	StorageSize      string            `json:"storageSize,omitempty"`
+	StorageClass     string            `json:"storageClass,omitempty"`
+	Requests         Requests          `json:"requests,omitempty"`
+	Limits           Limits            `json:"limits,omitempty"`
+	NodeSelector     map[string]string `json:"nodeSelector,omitempty"`
+	CredsRef         string            `json:"credsRef,omitempty"`
+	PvcName          string            `json:"pvcName,omitempty"`
+	Backup           Backup            `json:"backup,omitempty"`
+	PodSpecOverrides PodSpecOverrides  `json:"podSpecOverrides,omitempty"`
+	External         ExternalDb        `json:"external,omitempty"`
+}
+
+type Es struct {
+	Enabled          bool              `json:"enabled,omitempty"`
+	ServiceAccount   string            `json:"serviceAccount,omitempty"`
+	Image            string            `json:"image,omitempty"`
+	Port             int               `json:"port,omitempty"`
+	StorageSize      string            `json:"storageSize,omitempty"`
+	SvcName          string            `json:"svcName,omitempty"`
+	NodePort         int               `json:"nodePort,omitempty"`
+	StorageClass     string            `json:"storageClass,omitempty"`
+	Requests         Requests          `json:"requests,omitempty"`
+	Limits           Limits            `json:"limits,omitempty"`
+	JavaOpts         string            `json:"javaOpts,omitempty"`
+	PatchEsNodes     bool              `json:"patchEsNodes,omitempty"`
+	NodeSelector     map[string]string `json:"nodeSelector,omitempty"`
+	CredsRef         string            `json:"credsRef,omitempty"`
+	PvcName          string            `json:"pvcName,omitempty"`
+	CleanupPolicy    CleanupPolicy     `json:"cleanupPolicy,omitempty"`
+	Backup           Backup            `json:"backup,omitempty"`
+	PodSpecOverrides PodSpecOverrides  `json:"podSpecOverrides,omitempty"`
+	External         ExternalDb        `json:"external,omitempty"`
+}
+
+type CleanupPolicy struct {
+	All       string `json:"all,omitempty"`
+	App       string `json:"app,omitempty"`
+	Jobs      string `json:"jobs,omitempty"`
+	Endpoints string `json:"endpoints,omitempty"`
+}
+
+type AppDbs struct {
+	Pg    Pg    `json:"pg,omitempty"`
+	Redis Redis `json:"redis,omitempty"`
+	Minio Minio `json:"minio,omitempty"`
+	Es    Es    `json:"es,omitempty"`
+	Cvat  Cvat  `json:"cvat,omitempty"`
+}
+
+type Cvat struct {
+	Enabled bool  `json:"enabled,omitempty"`
+	Pg      Pg    `json:"pg,omitempty"`
+	Redis   Redis `json:"redis,omitempty"`
+}
+
+type InfraDbs struct {
+	Redis Redis `json:"redis,omitempty"`
+}
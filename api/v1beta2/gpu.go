@@ -0,0 +1,22 @@
+package v1beta2
+
+type NvidiaDp struct {
+	Enabled bool   `json:"enabled,omitempty"`
+	Image   string `json:"image,omitempty"`
+}
+
+type HabanaDp struct {
+	Enabled bool   `json:"enabled,omitempty"`
+	Image   string `json:"image,omitempty"`
+}
+
+type MetaGpuDp struct {
+	Enabled bool   `json:"enabled,omitempty"`
+	Image   string `json:"image,omitempty"`
+}
+
+type Gpu struct {
+	NvidiaDp  NvidiaDp  `json:"nvidiaDp,omitempty"`
+	HabanaDp  HabanaDp  `json:"habanaDp,omitempty"`
+	MetaGpuDp MetaGpuDp `json:"metaGpuDp,omitempty"`
+}
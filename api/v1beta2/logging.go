@@ -0,0 +1,57 @@
+package v1beta2
+
+type AppInstance struct {
+	SpecName string
+	SpecNs   string
+	EsUser   string
+	EsPass   string
+}
+
+type Fluentbit struct {
+	Enabled          bool              `json:"enabled,omitempty"`
+	Image            string            `json:"image,omitempty"`
+	Requests         Requests          `json:"requests,omitempty"`
+	Limits           Limits            `json:"limits,omitempty"`
+	NodeSelector     map[string]string `json:"nodeSelector,omitempty"`
+	LogsMounts       map[string]string `json:"logsMounts,omitempty"`
+	PodSpecOverrides PodSpecOverrides  `json:"podSpecOverrides,omitempty"`
+}
+
+type Elastalert struct {
+	Enabled        bool              `json:"enabled,omitempty"`
+	Image          string            `json:"image,omitempty"`
+	AuthProxyImage string            `json:"authProxyImage,omitempty"`
+	CredsRef       string            `json:"credsRef,omitempty"`
+	Port           int               `json:"port,omitempty"`
+	NodePort       int               `json:"nodePort,omitempty"`
+	StorageSize    string            `json:"storageSize,omitempty"`
+	SvcName        string            `json:"svcName,omitempty"`
+	StorageClass   string            `json:"storageClass,omitempty"`
+	Requests       Requests          `json:"requests,omitempty"`
+	Limits         Limits            `json:"limits,omitempty"`
+	NodeSelector   map[string]string `json:"nodeSelector,omitempty"`
+	PvcName        string            `json:"pvcName,omitempty"`
+}
+
+type Kibana struct {
+	Enabled          bool                  `json:"enabled,omitempty"`
+	ServiceAccount   string                `json:"serviceAccount,omitempty"`
+	SvcName          string                `json:"svcName,omitempty"`
+	Port             int                   `json:"port,omitempty"`
+	Image            string                `json:"image,omitempty"`
+	NodePort         int                   `json:"nodePort,omitempty"`
+	Requests         Requests              `json:"requests,omitempty"`
+	Limits           Limits                `json:"limits,omitempty"`
+	OauthProxy       OauthProxyServiceConf `json:"oauthProxy,omitempty"`
+	CredsRef         string                `json:"credsRef,omitempty"`
+	PodSpecOverrides PodSpecOverrides      `json:"podSpecOverrides,omitempty"`
+}
+
+type CnvrgAppLogging struct {
+	Elastalert Elastalert `json:"elastalert,omitempty"`
+	Kibana     Kibana     `json:"kibana,omitempty"`
+}
+
+type CnvrgInfraLogging struct {
+	Fluentbit Fluentbit `json:"fluentbit,omitempty"`
+}
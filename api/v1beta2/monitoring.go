@@ -0,0 +1,89 @@
+package v1beta2
+
+type PrometheusOperator struct {
+	Enabled                       bool   `json:"enabled,omitempty"`
+	OperatorImage                 string `json:"operatorImage,omitempty"`
+	PrometheusConfigReloaderImage string `json:"prometheusConfigReloaderImage,omitempty"`
+	KubeRbacProxyImage            string `json:"kubeRbacProxyImage,omitempty"`
+}
+
+type Prometheus struct {
+	Enabled             bool              `json:"enabled,omitempty"`
+	Image               string            `json:"image,omitempty"`
+	Replicas            int               `json:"replicas,omitempty"`
+	BasicAuthProxyImage string            `json:"basicAuthProxyImage,omitempty"`
+	Requests            Requests          `json:"requests,omitempty"`
+	Limits              Limits            `json:"limits,omitempty"`
+	SvcName             string            `json:"svcName,omitempty"`
+	Port                int               `json:"port,omitempty"`
+	NodePort            int               `json:"nodePort,omitempty"`
+	Retention           string            `json:"retention,omitempty"`
+	StorageSize         string            `json:"storageSize,omitempty"`
+	StorageClass        string            `json:"storageClass,omitempty"`
+	CredsRef            string            `json:"credsRef,omitempty"`
+	UpstreamRef         string            `json:"upstreamRef,omitempty"`
+	NodeSelector        map[string]string `json:"nodeSelector,omitempty"`
+	PodSpecOverrides    PodSpecOverrides  `json:"podSpecOverrides,omitempty"`
+}
+
+type NodeExporter struct {
+	Enabled bool              `json:"enabled,omitempty"`
+	Image   string            `json:"image,omitempty"`
+	Labels  map[string]string `json:"labels,omitempty"`
+}
+
+type KubeStateMetrics struct {
+	Enabled bool              `json:"enabled,omitempty"`
+	Image   string            `json:"image,omitempty"`
+	Labels  map[string]string `json:"labels,omitempty"`
+}
+
+type Grafana struct {
+	Enabled          bool                  `json:"enabled,omitempty"`
+	Image            string                `json:"image,omitempty"`
+	SvcName          string                `json:"svcName,omitempty"`
+	Port             int                   `json:"port,omitempty"`
+	NodePort         int                   `json:"nodePort,omitempty"`
+	OauthProxy       OauthProxyServiceConf `json:"oauthProxy,omitempty"`
+	CredsRef         string                `json:"credsRef,omitempty"`
+	PodSpecOverrides PodSpecOverrides      `json:"podSpecOverrides,omitempty"`
+}
+
+type DefaultServiceMonitors struct {
+	Enabled bool              `json:"enabled,omitempty"`
+	Labels  map[string]string `json:"labels,omitempty"`
+}
+
+type DcgmExporter struct {
+	Enabled bool   `json:"enabled,omitempty"`
+	Image   string `json:"image,omitempty"`
+}
+
+type HabanaExporter struct {
+	Enabled   bool   `json:"enabled,omitempty"`
+	Image     string `json:"image,omitempty"`
+	HlmlImage string `json:"hlmlImage,omitempty"`
+}
+
+type CnvrgIdleMetricsExporter struct {
+	Enabled bool              `json:"enabled,omitempty"`
+	Labels  map[string]string `json:"labels,omitempty"`
+}
+
+type CnvrgInfraMonitoring struct {
+	PrometheusOperator       PrometheusOperator       `json:"prometheusOperator,omitempty"`
+	Prometheus               Prometheus               `json:"prometheus,omitempty"`
+	NodeExporter             NodeExporter             `json:"nodeExporter,omitempty"`
+	KubeStateMetrics         KubeStateMetrics         `json:"kubeStateMetrics,omitempty"`
+	Grafana                  Grafana                  `json:"grafana,omitempty"`
+	DcgmExporter             DcgmExporter             `json:"dcgmExporter,omitempty"`
+	HabanaExporter           HabanaExporter           `json:"habanaExporter,omitempty"`
+	DefaultServiceMonitors   DefaultServiceMonitors   `json:"defaultServiceMonitors,omitempty"`
+	CnvrgIdleMetricsExporter CnvrgIdleMetricsExporter `json:"cnvrgIdleMetricsExporter,omitempty"`
+}
+
+type CnvrgAppMonitoring struct {
+	Prometheus               Prometheus               `json:"prometheus,omitempty"`
+	Grafana                  Grafana                  `json:"grafana,omitempty"`
+	CnvrgIdleMetricsExporter CnvrgIdleMetricsExporter `json:"cnvrgIdleMetricsExporter,omitempty"`
+}
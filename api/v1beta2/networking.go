@@ -0,0 +1,59 @@
+package v1beta2
+
+// +kubebuilder:validation:Enum=istio;ingress;openshift;nodeport
+type IngressType string
+
+const (
+	IstioIngress     IngressType = "istio"
+	NginxIngress     IngressType = "ingress"
+	OpenShiftIngress IngressType = "openshift"
+	NodePortIngress  IngressType = "nodeport"
+)
+
+type Istio struct {
+	Enabled               bool              `json:"enabled,omitempty"`
+	OperatorImage         string            `json:"operatorImage,omitempty"`
+	PilotImage            string            `json:"pilotImage,omitempty"`
+	ProxyImage            string            `json:"proxyImage,omitempty"`
+	IngressSvcExtraPorts  []int             `json:"ingressSvcExtraPorts,omitempty"`
+	ExternalIP            []string          `json:"externalIp,omitempty"`
+	LBSourceRanges        []string          `json:"lbSourceRanges,omitempty"`
+	IngressSvcAnnotations map[string]string `json:"ingressSvcAnnotations,omitempty"`
+}
+
+type Ingress struct {
+	Type            IngressType `json:"type,omitempty"`
+	Timeout         string      `json:"timeout,omitempty"`
+	RetriesAttempts int         `json:"retriesAttempts,omitempty"`
+	PerTryTimeout   string      `json:"perTryTimeout,omitempty"`
+	IstioGwEnabled  bool        `json:"istioGwEnabled,omitempty"`
+	IstioGwName     string      `json:"istioGwName,omitempty"`
+}
+
+type HTTPS struct {
+	Enabled    bool   `json:"enabled,omitempty"`
+	Cert       string `json:"cert,omitempty"`
+	Key        string `json:"key,omitempty"`
+	CertSecret string `json:"certSecret,omitempty"`
+}
+
+type CnvrgAppNetworking struct {
+	Ingress Ingress `json:"ingress,omitempty"`
+	HTTPS   HTTPS   `json:"https,omitempty"`
+	Proxy   Proxy   `json:"proxy,omitempty"`
+}
+
+type CnvrgInfraNetworking struct {
+	Ingress Ingress `json:"ingress,omitempty"`
+	HTTPS   HTTPS   `json:"https,omitempty"`
+	Istio   Istio   `json:"istio,omitempty"`
+	Proxy   Proxy   `json:"proxy,omitempty"`
+}
+
+type Proxy struct {
+	Enabled    bool     `json:"enabled,omitempty"`
+	ConfigRef  string   `json:"configRef,omitempty"`
+	HttpProxy  []string `json:"httpProxy,omitempty"`
+	HttpsProxy []string `json:"httpsProxy,omitempty"`
+	NoProxy    []string `json:"noProxy,omitempty"`
+}
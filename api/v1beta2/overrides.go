@@ -0,0 +1,22 @@
+package v1beta2
+
+type PatchType string
+
+const (
+	StrategicMergePatchType PatchType = "strategic"
+	JSONPatchType           PatchType = "json"
+)
+
+// Override is a user patch applied to the rendered object matched by kind and name
+type Override struct {
+	// +kubebuilder:validation:MinLength=1
+	Kind string `json:"kind"`
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// strategic (strategic merge patch, default) or json (JSON6902 patch)
+	// +kubebuilder:validation:Enum=strategic;json
+	Type PatchType `json:"type,omitempty"`
+	// patch in yaml or json format
+	// +kubebuilder:validation:MinLength=1
+	Patch string `json:"patch"`
+}
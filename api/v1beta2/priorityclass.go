@@ -0,0 +1,7 @@
+package v1beta2
+
+type PriorityClass struct {
+	Name        string `json:"name"`
+	Value       int32  `json:"value"`
+	Description string `json:"description"`
+}
@@ -0,0 +1,11 @@
+package v1beta2
+
+// +kubebuilder:validation:Enum=dev;small;medium;large
+type Profile string
+
+const (
+	DevProfile    Profile = "dev"
+	SmallProfile  Profile = "small"
+	MediumProfile Profile = "medium"
+	LargeProfile  Profile = "large"
+)
@@ -0,0 +1,6 @@
+package v1beta2
+
+type ConfigReloader struct {
+	Enabled bool   `json:"enabled,omitempty"`
+	Image   string `json:"image,omitempty"`
+}
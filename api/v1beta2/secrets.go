@@ -0,0 +1,9 @@
+package v1beta2
+
+import corev1 "k8s.io/api/core/v1"
+
+// SecretValue is a credential given either as a plain value or as a reference to a key of a secret
+type SecretValue struct {
+	Value        string                    `json:"value,omitempty"`
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+}
@@ -0,0 +1,5 @@
+package v1beta2
+
+type IngressCheck struct {
+	Enabled bool `json:"enabled,omitempty"`
+}
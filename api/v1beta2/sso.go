@@ -0,0 +1,22 @@
+package v1beta2
+
+type SSO struct {
+	Enabled                          bool         `json:"enabled,omitempty"`
+	Image                            string       `json:"image,omitempty"`
+	AdminUser                        string       `json:"adminUser,omitempty"`
+	Provider                         string       `json:"provider,omitempty"`
+	EmailDomain                      []string     `json:"emailDomain,omitempty"`
+	ClientID                         string       `json:"clientId,omitempty"`
+	ClientSecret                     *SecretValue `json:"clientSecret,omitempty"`
+	CookieSecret                     *SecretValue `json:"cookieSecret,omitempty"`
+	AzureTenant                      string       `json:"azureTenant,omitempty"`
+	OidcIssuerURL                    string       `json:"oidcIssuerUrl,omitempty"`
+	RealmName                        string       `json:"realmName,omitempty"`
+	ServiceUrl                       string       `json:"serviceUrl,omitempty"`
+	InsecureOidcAllowUnverifiedEmail bool         `json:"insecureOidcAllowUnverifiedEmail,omitempty"`
+}
+
+type OauthProxyServiceConf struct {
+	SkipAuthRegex        []string `json:"skipAuthRegex,omitempty"`
+	TokenValidationRegex []string `json:"tokenValidationRegex,omitempty"`
+}
@@ -0,0 +1,31 @@
+package v1beta2
+
+type Storage struct {
+	Hostpath Hostpath `json:"hostpath,omitempty"`
+	Nfs      Nfs      `json:"nfs,omitempty"`
+}
+
+type Hostpath struct {
+	Enabled          bool              `json:"enabled,omitempty"`
+	Image            string            `json:"image,omitempty"`
+	Path             string            `json:"path,omitempty"`
+	StorageClassName string            `json:"storageClassName,omitempty"`
+	Requests         Requests          `json:"requests,omitempty"`
+	Limits           Limits            `json:"limits,omitempty"`
+	ReclaimPolicy    string            `json:"reclaimPolicy,omitempty"`
+	DefaultSc        bool              `json:"defaultSc,omitempty"`
+	NodeSelector     map[string]string `json:"nodeSelector,omitempty"`
+}
+
+type Nfs struct {
+	Enabled          bool     `json:"enabled,omitempty"`
+	Image            string   `json:"image,omitempty"`
+	Provisioner      string   `json:"provisioner,omitempty"`
+	StorageClassName string   `json:"storageClassName,omitempty"`
+	Server           string   `json:"server,omitempty"`
+	Path             string   `json:"path,omitempty"`
+	Requests         Requests `json:"requests,omitempty"`
+	Limits           Limits   `json:"limits,omitempty"`
+	ReclaimPolicy    string   `json:"reclaimPolicy,omitempty"`
+	DefaultSc        bool     `json:"defaultSc,omitempty"`
+}
@@ -0,0 +1,7 @@
+package v1beta2
+
+type Tenancy struct {
+	Enabled bool   `json:"enabled,omitempty"`
+	Key     string `json:"key,omitempty"`
+	Value   string `json:"value,omitempty"`
+}
@@ -0,0 +1,1770 @@
+//go:build !ignore_autogenerated
+// +build !ignore_autogenerated
+
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta2
+
+import (
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppDbs) DeepCopyInto(out *AppDbs) {
+	*out = *in
+	in.Pg.DeepCopyInto(&out.Pg)
+	in.Redis.DeepCopyInto(&out.Redis)
+	in.Minio.DeepCopyInto(&out.Minio)
+	in.Es.DeepCopyInto(&out.Es)
+	in.Cvat.DeepCopyInto(&out.Cvat)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppDbs.
+func (in *AppDbs) DeepCopy() *AppDbs {
+	if in == nil {
+		return nil
+	}
+	out := new(AppDbs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppInstance) DeepCopyInto(out *AppInstance) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppInstance.
+func (in *AppInstance) DeepCopy() *AppInstance {
+	if in == nil {
+		return nil
+	}
+	out := new(AppInstance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Backup) DeepCopyInto(out *Backup) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Backup.
+func (in *Backup) DeepCopy() *Backup {
+	if in == nil {
+		return nil
+	}
+	out := new(Backup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BaseConfig) DeepCopyInto(out *BaseConfig) {
+	*out = *in
+	if in.FeatureFlags != nil {
+		in, out := &in.FeatureFlags, &out.FeatureFlags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BaseConfig.
+func (in *BaseConfig) DeepCopy() *BaseConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(BaseConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Capsule) DeepCopyInto(out *Capsule) {
+	*out = *in
+	out.Requests = in.Requests
+	out.Limits = in.Limits
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Capsule.
+func (in *Capsule) DeepCopy() *Capsule {
+	if in == nil {
+		return nil
+	}
+	out := new(Capsule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CleanupPolicy) DeepCopyInto(out *CleanupPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CleanupPolicy.
+func (in *CleanupPolicy) DeepCopy() *CleanupPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(CleanupPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CnvrgApp) DeepCopyInto(out *CnvrgApp) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CnvrgApp.
+func (in *CnvrgApp) DeepCopy() *CnvrgApp {
+	if in == nil {
+		return nil
+	}
+	out := new(CnvrgApp)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CnvrgApp) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CnvrgAppList) DeepCopyInto(out *CnvrgAppList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CnvrgApp, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CnvrgAppList.
+func (in *CnvrgAppList) DeepCopy() *CnvrgAppList {
+	if in == nil {
+		return nil
+	}
+	out := new(CnvrgAppList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CnvrgAppList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CnvrgAppLogging) DeepCopyInto(out *CnvrgAppLogging) {
+	*out = *in
+	in.Elastalert.DeepCopyInto(&out.Elastalert)
+	in.Kibana.DeepCopyInto(&out.Kibana)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CnvrgAppLogging.
+func (in *CnvrgAppLogging) DeepCopy() *CnvrgAppLogging {
+	if in == nil {
+		return nil
+	}
+	out := new(CnvrgAppLogging)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CnvrgAppMonitoring) DeepCopyInto(out *CnvrgAppMonitoring) {
+	*out = *in
+	in.Prometheus.DeepCopyInto(&out.Prometheus)
+	in.Grafana.DeepCopyInto(&out.Grafana)
+	in.CnvrgIdleMetricsExporter.DeepCopyInto(&out.CnvrgIdleMetricsExporter)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CnvrgAppMonitoring.
+func (in *CnvrgAppMonitoring) DeepCopy() *CnvrgAppMonitoring {
+	if in == nil {
+		return nil
+	}
+	out := new(CnvrgAppMonitoring)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CnvrgAppNetworking) DeepCopyInto(out *CnvrgAppNetworking) {
+	*out = *in
+	out.Ingress = in.Ingress
+	out.HTTPS = in.HTTPS
+	in.Proxy.DeepCopyInto(&out.Proxy)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CnvrgAppNetworking.
+func (in *CnvrgAppNetworking) DeepCopy() *CnvrgAppNetworking {
+	if in == nil {
+		return nil
+	}
+	out := new(CnvrgAppNetworking)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CnvrgAppSpec) DeepCopyInto(out *CnvrgAppSpec) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.ControlPlane.DeepCopyInto(&out.ControlPlane)
+	in.Registry.DeepCopyInto(&out.Registry)
+	in.Dbs.DeepCopyInto(&out.Dbs)
+	in.Networking.DeepCopyInto(&out.Networking)
+	in.Logging.DeepCopyInto(&out.Logging)
+	in.Monitoring.DeepCopyInto(&out.Monitoring)
+	in.SSO.DeepCopyInto(&out.SSO)
+	out.Tenancy = in.Tenancy
+	out.CnvrgAppPriorityClass = in.CnvrgAppPriorityClass
+	out.CnvrgJobPriorityClass = in.CnvrgJobPriorityClass
+	out.IngressCheck = in.IngressCheck
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]Override, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CnvrgAppSpec.
+func (in *CnvrgAppSpec) DeepCopy() *CnvrgAppSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CnvrgAppSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CnvrgClusterProvisionerOperator) DeepCopyInto(out *CnvrgClusterProvisionerOperator) {
+	*out = *in
+	out.Requests = in.Requests
+	out.Limits = in.Limits
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CnvrgClusterProvisionerOperator.
+func (in *CnvrgClusterProvisionerOperator) DeepCopy() *CnvrgClusterProvisionerOperator {
+	if in == nil {
+		return nil
+	}
+	out := new(CnvrgClusterProvisionerOperator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CnvrgIdleMetricsExporter) DeepCopyInto(out *CnvrgIdleMetricsExporter) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CnvrgIdleMetricsExporter.
+func (in *CnvrgIdleMetricsExporter) DeepCopy() *CnvrgIdleMetricsExporter {
+	if in == nil {
+		return nil
+	}
+	out := new(CnvrgIdleMetricsExporter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CnvrgInfra) DeepCopyInto(out *CnvrgInfra) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CnvrgInfra.
+func (in *CnvrgInfra) DeepCopy() *CnvrgInfra {
+	if in == nil {
+		return nil
+	}
+	out := new(CnvrgInfra)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CnvrgInfra) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CnvrgInfraList) DeepCopyInto(out *CnvrgInfraList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CnvrgInfra, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CnvrgInfraList.
+func (in *CnvrgInfraList) DeepCopy() *CnvrgInfraList {
+	if in == nil {
+		return nil
+	}
+	out := new(CnvrgInfraList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CnvrgInfraList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CnvrgInfraLogging) DeepCopyInto(out *CnvrgInfraLogging) {
+	*out = *in
+	in.Fluentbit.DeepCopyInto(&out.Fluentbit)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CnvrgInfraLogging.
+func (in *CnvrgInfraLogging) DeepCopy() *CnvrgInfraLogging {
+	if in == nil {
+		return nil
+	}
+	out := new(CnvrgInfraLogging)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CnvrgInfraMonitoring) DeepCopyInto(out *CnvrgInfraMonitoring) {
+	*out = *in
+	out.PrometheusOperator = in.PrometheusOperator
+	in.Prometheus.DeepCopyInto(&out.Prometheus)
+	in.NodeExporter.DeepCopyInto(&out.NodeExporter)
+	in.KubeStateMetrics.DeepCopyInto(&out.KubeStateMetrics)
+	in.Grafana.DeepCopyInto(&out.Grafana)
+	out.DcgmExporter = in.DcgmExporter
+	out.HabanaExporter = in.HabanaExporter
+	in.DefaultServiceMonitors.DeepCopyInto(&out.DefaultServiceMonitors)
+	in.CnvrgIdleMetricsExporter.DeepCopyInto(&out.CnvrgIdleMetricsExporter)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CnvrgInfraMonitoring.
+func (in *CnvrgInfraMonitoring) DeepCopy() *CnvrgInfraMonitoring {
+	if in == nil {
+		return nil
+	}
+	out := new(CnvrgInfraMonitoring)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CnvrgInfraNetworking) DeepCopyInto(out *CnvrgInfraNetworking) {
+	*out = *in
+	out.Ingress = in.Ingress
+	out.HTTPS = in.HTTPS
+	in.Istio.DeepCopyInto(&out.Istio)
+	in.Proxy.DeepCopyInto(&out.Proxy)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CnvrgInfraNetworking.
+func (in *CnvrgInfraNetworking) DeepCopy() *CnvrgInfraNetworking {
+	if in == nil {
+		return nil
+	}
+	out := new(CnvrgInfraNetworking)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CnvrgInfraSpec) DeepCopyInto(out *CnvrgInfraSpec) {
+	*out = *in
+	in.Monitoring.DeepCopyInto(&out.Monitoring)
+	in.Networking.DeepCopyInto(&out.Networking)
+	in.Logging.DeepCopyInto(&out.Logging)
+	in.Registry.DeepCopyInto(&out.Registry)
+	in.Storage.DeepCopyInto(&out.Storage)
+	in.Dbs.DeepCopyInto(&out.Dbs)
+	in.SSO.DeepCopyInto(&out.SSO)
+	out.Gpu = in.Gpu
+	out.Tenancy = in.Tenancy
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	out.ConfigReloader = in.ConfigReloader
+	out.Capsule = in.Capsule
+	out.CnvrgAppPriorityClass = in.CnvrgAppPriorityClass
+	out.CnvrgJobPriorityClass = in.CnvrgJobPriorityClass
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]Override, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CnvrgInfraSpec.
+func (in *CnvrgInfraSpec) DeepCopy() *CnvrgInfraSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CnvrgInfraSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CnvrgRouter) DeepCopyInto(out *CnvrgRouter) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CnvrgRouter.
+func (in *CnvrgRouter) DeepCopy() *CnvrgRouter {
+	if in == nil {
+		return nil
+	}
+	out := new(CnvrgRouter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CnvrgScheduler) DeepCopyInto(out *CnvrgScheduler) {
+	*out = *in
+	out.Requests = in.Requests
+	out.Limits = in.Limits
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CnvrgScheduler.
+func (in *CnvrgScheduler) DeepCopy() *CnvrgScheduler {
+	if in == nil {
+		return nil
+	}
+	out := new(CnvrgScheduler)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigReloader) DeepCopyInto(out *ConfigReloader) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigReloader.
+func (in *ConfigReloader) DeepCopy() *ConfigReloader {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigReloader)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConsistentHash) DeepCopyInto(out *ConsistentHash) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConsistentHash.
+func (in *ConsistentHash) DeepCopy() *ConsistentHash {
+	if in == nil {
+		return nil
+	}
+	out := new(ConsistentHash)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControlPlane) DeepCopyInto(out *ControlPlane) {
+	*out = *in
+	in.WebApp.DeepCopyInto(&out.WebApp)
+	in.Sidekiq.DeepCopyInto(&out.Sidekiq)
+	out.Searchkiq = in.Searchkiq
+	out.Systemkiq = in.Systemkiq
+	in.Hyper.DeepCopyInto(&out.Hyper)
+	out.CnvrgScheduler = in.CnvrgScheduler
+	out.CnvrgClusterProvisionerOperator = in.CnvrgClusterProvisionerOperator
+	out.CnvrgRouter = in.CnvrgRouter
+	in.BaseConfig.DeepCopyInto(&out.BaseConfig)
+	in.Ldap.DeepCopyInto(&out.Ldap)
+	in.SMTP.DeepCopyInto(&out.SMTP)
+	in.ObjectStorage.DeepCopyInto(&out.ObjectStorage)
+	in.Mpi.DeepCopyInto(&out.Mpi)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlane.
+func (in *ControlPlane) DeepCopy() *ControlPlane {
+	if in == nil {
+		return nil
+	}
+	out := new(ControlPlane)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Cvat) DeepCopyInto(out *Cvat) {
+	*out = *in
+	in.Pg.DeepCopyInto(&out.Pg)
+	in.Redis.DeepCopyInto(&out.Redis)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Cvat.
+func (in *Cvat) DeepCopy() *Cvat {
+	if in == nil {
+		return nil
+	}
+	out := new(Cvat)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DcgmExporter) DeepCopyInto(out *DcgmExporter) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DcgmExporter.
+func (in *DcgmExporter) DeepCopy() *DcgmExporter {
+	if in == nil {
+		return nil
+	}
+	out := new(DcgmExporter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DefaultServiceMonitors) DeepCopyInto(out *DefaultServiceMonitors) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefaultServiceMonitors.
+func (in *DefaultServiceMonitors) DeepCopy() *DefaultServiceMonitors {
+	if in == nil {
+		return nil
+	}
+	out := new(DefaultServiceMonitors)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Elastalert) DeepCopyInto(out *Elastalert) {
+	*out = *in
+	out.Requests = in.Requests
+	out.Limits = in.Limits
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Elastalert.
+func (in *Elastalert) DeepCopy() *Elastalert {
+	if in == nil {
+		return nil
+	}
+	out := new(Elastalert)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Es) DeepCopyInto(out *Es) {
+	*out = *in
+	out.Requests = in.Requests
+	out.Limits = in.Limits
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	out.CleanupPolicy = in.CleanupPolicy
+	out.Backup = in.Backup
+	in.PodSpecOverrides.DeepCopyInto(&out.PodSpecOverrides)
+	out.External = in.External
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Es.
+func (in *Es) DeepCopy() *Es {
+	if in == nil {
+		return nil
+	}
+	out := new(Es)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalDb) DeepCopyInto(out *ExternalDb) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalDb.
+func (in *ExternalDb) DeepCopy() *ExternalDb {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalDb)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Fluentbit) DeepCopyInto(out *Fluentbit) {
+	*out = *in
+	out.Requests = in.Requests
+	out.Limits = in.Limits
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.LogsMounts != nil {
+		in, out := &in.LogsMounts, &out.LogsMounts
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.PodSpecOverrides.DeepCopyInto(&out.PodSpecOverrides)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Fluentbit.
+func (in *Fluentbit) DeepCopy() *Fluentbit {
+	if in == nil {
+		return nil
+	}
+	out := new(Fluentbit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Gpu) DeepCopyInto(out *Gpu) {
+	*out = *in
+	out.NvidiaDp = in.NvidiaDp
+	out.HabanaDp = in.HabanaDp
+	out.MetaGpuDp = in.MetaGpuDp
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Gpu.
+func (in *Gpu) DeepCopy() *Gpu {
+	if in == nil {
+		return nil
+	}
+	out := new(Gpu)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Grafana) DeepCopyInto(out *Grafana) {
+	*out = *in
+	in.OauthProxy.DeepCopyInto(&out.OauthProxy)
+	in.PodSpecOverrides.DeepCopyInto(&out.PodSpecOverrides)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Grafana.
+func (in *Grafana) DeepCopy() *Grafana {
+	if in == nil {
+		return nil
+	}
+	out := new(Grafana)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPS) DeepCopyInto(out *HTTPS) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPS.
+func (in *HTTPS) DeepCopy() *HTTPS {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HabanaDp) DeepCopyInto(out *HabanaDp) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HabanaDp.
+func (in *HabanaDp) DeepCopy() *HabanaDp {
+	if in == nil {
+		return nil
+	}
+	out := new(HabanaDp)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HabanaExporter) DeepCopyInto(out *HabanaExporter) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HabanaExporter.
+func (in *HabanaExporter) DeepCopy() *HabanaExporter {
+	if in == nil {
+		return nil
+	}
+	out := new(HabanaExporter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Hostpath) DeepCopyInto(out *Hostpath) {
+	*out = *in
+	out.Requests = in.Requests
+	out.Limits = in.Limits
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Hostpath.
+func (in *Hostpath) DeepCopy() *Hostpath {
+	if in == nil {
+		return nil
+	}
+	out := new(Hostpath)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Hpa) DeepCopyInto(out *Hpa) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Hpa.
+func (in *Hpa) DeepCopy() *Hpa {
+	if in == nil {
+		return nil
+	}
+	out := new(Hpa)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HugePages) DeepCopyInto(out *HugePages) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HugePages.
+func (in *HugePages) DeepCopy() *HugePages {
+	if in == nil {
+		return nil
+	}
+	out := new(HugePages)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Hyper) DeepCopyInto(out *Hyper) {
+	*out = *in
+	if in.Token != nil {
+		in, out := &in.Token, &out.Token
+		*out = new(SecretValue)
+		(*in).DeepCopyInto(*out)
+	}
+	out.Requests = in.Requests
+	out.Limits = in.Limits
+	in.PodSpecOverrides.DeepCopyInto(&out.PodSpecOverrides)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Hyper.
+func (in *Hyper) DeepCopy() *Hyper {
+	if in == nil {
+		return nil
+	}
+	out := new(Hyper)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InfraDbs) DeepCopyInto(out *InfraDbs) {
+	*out = *in
+	in.Redis.DeepCopyInto(&out.Redis)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InfraDbs.
+func (in *InfraDbs) DeepCopy() *InfraDbs {
+	if in == nil {
+		return nil
+	}
+	out := new(InfraDbs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Ingress) DeepCopyInto(out *Ingress) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ingress.
+func (in *Ingress) DeepCopy() *Ingress {
+	if in == nil {
+		return nil
+	}
+	out := new(Ingress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressCheck) DeepCopyInto(out *IngressCheck) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressCheck.
+func (in *IngressCheck) DeepCopy() *IngressCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Istio) DeepCopyInto(out *Istio) {
+	*out = *in
+	if in.IngressSvcExtraPorts != nil {
+		in, out := &in.IngressSvcExtraPorts, &out.IngressSvcExtraPorts
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExternalIP != nil {
+		in, out := &in.ExternalIP, &out.ExternalIP
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LBSourceRanges != nil {
+		in, out := &in.LBSourceRanges, &out.LBSourceRanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IngressSvcAnnotations != nil {
+		in, out := &in.IngressSvcAnnotations, &out.IngressSvcAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Istio.
+func (in *Istio) DeepCopy() *Istio {
+	if in == nil {
+		return nil
+	}
+	out := new(Istio)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Kibana) DeepCopyInto(out *Kibana) {
+	*out = *in
+	out.Requests = in.Requests
+	out.Limits = in.Limits
+	in.OauthProxy.DeepCopyInto(&out.OauthProxy)
+	in.PodSpecOverrides.DeepCopyInto(&out.PodSpecOverrides)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Kibana.
+func (in *Kibana) DeepCopy() *Kibana {
+	if in == nil {
+		return nil
+	}
+	out := new(Kibana)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeStateMetrics) DeepCopyInto(out *KubeStateMetrics) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeStateMetrics.
+func (in *KubeStateMetrics) DeepCopy() *KubeStateMetrics {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeStateMetrics)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Ldap) DeepCopyInto(out *Ldap) {
+	*out = *in
+	if in.AdminPassword != nil {
+		in, out := &in.AdminPassword, &out.AdminPassword
+		*out = new(SecretValue)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ldap.
+func (in *Ldap) DeepCopy() *Ldap {
+	if in == nil {
+		return nil
+	}
+	out := new(Ldap)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Limits) DeepCopyInto(out *Limits) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Limits.
+func (in *Limits) DeepCopy() *Limits {
+	if in == nil {
+		return nil
+	}
+	out := new(Limits)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetaGpuDp) DeepCopyInto(out *MetaGpuDp) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetaGpuDp.
+func (in *MetaGpuDp) DeepCopy() *MetaGpuDp {
+	if in == nil {
+		return nil
+	}
+	out := new(MetaGpuDp)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Minio) DeepCopyInto(out *Minio) {
+	*out = *in
+	out.Requests = in.Requests
+	out.Limits = in.Limits
+	out.SharedStorage = in.SharedStorage
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.PodSpecOverrides.DeepCopyInto(&out.PodSpecOverrides)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Minio.
+func (in *Minio) DeepCopy() *Minio {
+	if in == nil {
+		return nil
+	}
+	out := new(Minio)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Mpi) DeepCopyInto(out *Mpi) {
+	*out = *in
+	if in.ExtraArgs != nil {
+		in, out := &in.ExtraArgs, &out.ExtraArgs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Registry != nil {
+		in, out := &in.Registry, &out.Registry
+		*out = new(Registry)
+		(*in).DeepCopyInto(*out)
+	}
+	out.Requests = in.Requests
+	out.Limits = in.Limits
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Mpi.
+func (in *Mpi) DeepCopy() *Mpi {
+	if in == nil {
+		return nil
+	}
+	out := new(Mpi)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Nfs) DeepCopyInto(out *Nfs) {
+	*out = *in
+	out.Requests = in.Requests
+	out.Limits = in.Limits
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Nfs.
+func (in *Nfs) DeepCopy() *Nfs {
+	if in == nil {
+		return nil
+	}
+	out := new(Nfs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeExporter) DeepCopyInto(out *NodeExporter) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeExporter.
+func (in *NodeExporter) DeepCopy() *NodeExporter {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeExporter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NvidiaDp) DeepCopyInto(out *NvidiaDp) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NvidiaDp.
+func (in *NvidiaDp) DeepCopy() *NvidiaDp {
+	if in == nil {
+		return nil
+	}
+	out := new(NvidiaDp)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OauthProxyServiceConf) DeepCopyInto(out *OauthProxyServiceConf) {
+	*out = *in
+	if in.SkipAuthRegex != nil {
+		in, out := &in.SkipAuthRegex, &out.SkipAuthRegex
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TokenValidationRegex != nil {
+		in, out := &in.TokenValidationRegex, &out.TokenValidationRegex
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OauthProxyServiceConf.
+func (in *OauthProxyServiceConf) DeepCopy() *OauthProxyServiceConf {
+	if in == nil {
+		return nil
+	}
+	out := new(OauthProxyServiceConf)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectStorage) DeepCopyInto(out *ObjectStorage) {
+	*out = *in
+	if in.AccessKey != nil {
+		in, out := &in.AccessKey, &out.AccessKey
+		*out = new(SecretValue)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretKey != nil {
+		in, out := &in.SecretKey, &out.SecretKey
+		*out = new(SecretValue)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectStorage.
+func (in *ObjectStorage) DeepCopy() *ObjectStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Override) DeepCopyInto(out *Override) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Override.
+func (in *Override) DeepCopy() *Override {
+	if in == nil {
+		return nil
+	}
+	out := new(Override)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Pg) DeepCopyInto(out *Pg) {
+	*out = *in
+	out.Requests = in.Requests
+	out.Limits = in.Limits
+	out.HugePages = in.HugePages
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	out.Backup = in.Backup
+	in.PodSpecOverrides.DeepCopyInto(&out.PodSpecOverrides)
+	out.HA = in.HA
+	out.Upgrade = in.Upgrade
+	out.External = in.External
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Pg.
+func (in *Pg) DeepCopy() *Pg {
+	if in == nil {
+		return nil
+	}
+	out := new(Pg)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgHA) DeepCopyInto(out *PgHA) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgHA.
+func (in *PgHA) DeepCopy() *PgHA {
+	if in == nil {
+		return nil
+	}
+	out := new(PgHA)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgUpgrade) DeepCopyInto(out *PgUpgrade) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgUpgrade.
+func (in *PgUpgrade) DeepCopy() *PgUpgrade {
+	if in == nil {
+		return nil
+	}
+	out := new(PgUpgrade)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgUpgradeStatus) DeepCopyInto(out *PgUpgradeStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgUpgradeStatus.
+func (in *PgUpgradeStatus) DeepCopy() *PgUpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PgUpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodSpecOverrides) DeepCopyInto(out *PodSpecOverrides) {
+	*out = *in
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]v1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EnvFrom != nil {
+		in, out := &in.EnvFrom, &out.EnvFrom
+		*out = make([]v1.EnvFromSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]v1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VolumeMounts != nil {
+		in, out := &in.VolumeMounts, &out.VolumeMounts
+		*out = make([]v1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PodAnnotations != nil {
+		in, out := &in.PodAnnotations, &out.PodAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(v1.PodSecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodSpecOverrides.
+func (in *PodSpecOverrides) DeepCopy() *PodSpecOverrides {
+	if in == nil {
+		return nil
+	}
+	out := new(PodSpecOverrides)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PriorityClass) DeepCopyInto(out *PriorityClass) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PriorityClass.
+func (in *PriorityClass) DeepCopy() *PriorityClass {
+	if in == nil {
+		return nil
+	}
+	out := new(PriorityClass)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Prometheus) DeepCopyInto(out *Prometheus) {
+	*out = *in
+	out.Requests = in.Requests
+	out.Limits = in.Limits
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.PodSpecOverrides.DeepCopyInto(&out.PodSpecOverrides)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Prometheus.
+func (in *Prometheus) DeepCopy() *Prometheus {
+	if in == nil {
+		return nil
+	}
+	out := new(Prometheus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusOperator) DeepCopyInto(out *PrometheusOperator) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusOperator.
+func (in *PrometheusOperator) DeepCopy() *PrometheusOperator {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusOperator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Proxy) DeepCopyInto(out *Proxy) {
+	*out = *in
+	if in.HttpProxy != nil {
+		in, out := &in.HttpProxy, &out.HttpProxy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.HttpsProxy != nil {
+		in, out := &in.HttpsProxy, &out.HttpsProxy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NoProxy != nil {
+		in, out := &in.NoProxy, &out.NoProxy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Proxy.
+func (in *Proxy) DeepCopy() *Proxy {
+	if in == nil {
+		return nil
+	}
+	out := new(Proxy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Redis) DeepCopyInto(out *Redis) {
+	*out = *in
+	out.Requests = in.Requests
+	out.Limits = in.Limits
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	out.Backup = in.Backup
+	in.PodSpecOverrides.DeepCopyInto(&out.PodSpecOverrides)
+	out.External = in.External
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Redis.
+func (in *Redis) DeepCopy() *Redis {
+	if in == nil {
+		return nil
+	}
+	out := new(Redis)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Registry) DeepCopyInto(out *Registry) {
+	*out = *in
+	if in.Password != nil {
+		in, out := &in.Password, &out.Password
+		*out = new(SecretValue)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Registry.
+func (in *Registry) DeepCopy() *Registry {
+	if in == nil {
+		return nil
+	}
+	out := new(Registry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Requests) DeepCopyInto(out *Requests) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Requests.
+func (in *Requests) DeepCopy() *Requests {
+	if in == nil {
+		return nil
+	}
+	out := new(Requests)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SMTP) DeepCopyInto(out *SMTP) {
+	*out = *in
+	if in.Password != nil {
+		in, out := &in.Password, &out.Password
+		*out = new(SecretValue)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SMTP.
+func (in *SMTP) DeepCopy() *SMTP {
+	if in == nil {
+		return nil
+	}
+	out := new(SMTP)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SSO) DeepCopyInto(out *SSO) {
+	*out = *in
+	if in.EmailDomain != nil {
+		in, out := &in.EmailDomain, &out.EmailDomain
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClientSecret != nil {
+		in, out := &in.ClientSecret, &out.ClientSecret
+		*out = new(SecretValue)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CookieSecret != nil {
+		in, out := &in.CookieSecret, &out.CookieSecret
+		*out = new(SecretValue)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SSO.
+func (in *SSO) DeepCopy() *SSO {
+	if in == nil {
+		return nil
+	}
+	out := new(SSO)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Searchkiq) DeepCopyInto(out *Searchkiq) {
+	*out = *in
+	out.Requests = in.Requests
+	out.Limits = in.Limits
+	out.Hpa = in.Hpa
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Searchkiq.
+func (in *Searchkiq) DeepCopy() *Searchkiq {
+	if in == nil {
+		return nil
+	}
+	out := new(Searchkiq)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretValue) DeepCopyInto(out *SecretValue) {
+	*out = *in
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretValue.
+func (in *SecretValue) DeepCopy() *SecretValue {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretValue)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SharedStorage) DeepCopyInto(out *SharedStorage) {
+	*out = *in
+	out.ConsistentHash = in.ConsistentHash
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SharedStorage.
+func (in *SharedStorage) DeepCopy() *SharedStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(SharedStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Sidekiq) DeepCopyInto(out *Sidekiq) {
+	*out = *in
+	out.Requests = in.Requests
+	out.Limits = in.Limits
+	out.Hpa = in.Hpa
+	in.PodSpecOverrides.DeepCopyInto(&out.PodSpecOverrides)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Sidekiq.
+func (in *Sidekiq) DeepCopy() *Sidekiq {
+	if in == nil {
+		return nil
+	}
+	out := new(Sidekiq)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Status) DeepCopyInto(out *Status) {
+	*out = *in
+	if in.StackReadiness != nil {
+		in, out := &in.StackReadiness, &out.StackReadiness
+		*out = make(map[string]bool, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PgUpgrade != nil {
+		in, out := &in.PgUpgrade, &out.PgUpgrade
+		*out = new(PgUpgradeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Status.
+func (in *Status) DeepCopy() *Status {
+	if in == nil {
+		return nil
+	}
+	out := new(Status)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Storage) DeepCopyInto(out *Storage) {
+	*out = *in
+	in.Hostpath.DeepCopyInto(&out.Hostpath)
+	out.Nfs = in.Nfs
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Storage.
+func (in *Storage) DeepCopy() *Storage {
+	if in == nil {
+		return nil
+	}
+	out := new(Storage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Systemkiq) DeepCopyInto(out *Systemkiq) {
+	*out = *in
+	out.Requests = in.Requests
+	out.Limits = in.Limits
+	out.Hpa = in.Hpa
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Systemkiq.
+func (in *Systemkiq) DeepCopy() *Systemkiq {
+	if in == nil {
+		return nil
+	}
+	out := new(Systemkiq)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Tenancy) DeepCopyInto(out *Tenancy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Tenancy.
+func (in *Tenancy) DeepCopy() *Tenancy {
+	if in == nil {
+		return nil
+	}
+	out := new(Tenancy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebApp) DeepCopyInto(out *WebApp) {
+	*out = *in
+	out.Requests = in.Requests
+	out.Limits = in.Limits
+	in.OauthProxy.DeepCopyInto(&out.OauthProxy)
+	out.Hpa = in.Hpa
+	in.PodSpecOverrides.DeepCopyInto(&out.PodSpecOverrides)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebApp.
+func (in *WebApp) DeepCopy() *WebApp {
+	if in == nil {
+		return nil
+	}
+	out := new(WebApp)
+	in.DeepCopyInto(out)
+	return out
+}
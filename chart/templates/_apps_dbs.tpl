@@ -4,10 +4,22 @@ dbs:
     enabled: {{ .Values.dbs.cvat.enabled }}
     pg:
       enabled: {{ .Values.dbs.cvat.enabled }}
+      backup:
+        enabled: {{.Values.backup.enabled}}
+        rotation: {{.Values.backup.rotation}}
+        period: {{.Values.backup.period}}
     redis:
       enabled: {{ .Values.dbs.cvat.enabled }}
   es:
-    enabled: {{ .Values.dbs.es.enabled }}
+    enabled: {{ and .Values.dbs.es.enabled (not .Values.dbs.es.external.enabled) }}
+    {{- if .Values.dbs.es.external.enabled }}
+    external:
+      enabled: true
+      host: "{{ .Values.dbs.es.external.host }}"
+      port: {{ .Values.dbs.es.external.port }}
+      tls: {{ .Values.dbs.es.external.tls }}
+      secretRef: "{{ .Values.dbs.es.external.secretRef }}"
+    {{- end }}
     storageSize: {{ .Values.dbs.es.storageSize }}
     storageClass: "{{ .Values.dbs.es.storageClass }}"
     patchEsNodes: {{ .Values.dbs.es.patchEsNodes }}
@@ -22,6 +34,10 @@ dbs:
         app: {{.Values.dbs.es.cleanupPolicy.app}}
         jobs: {{.Values.dbs.es.cleanupPolicy.jobs}}
         endpoints: {{.Values.dbs.es.cleanupPolicy.endpoints}}
+    backup:
+      enabled: {{ and .Values.backup.enabled .Values.dbs.es.backup.enabled }}
+      rotation: {{.Values.backup.rotation}}
+      period: {{.Values.backup.period}}
   {{- if and (eq .Values.controlPlane.objectStorage.endpoint "") (eq .Values.controlPlane.objectStorage.type "minio")}}
   minio:
     enabled: {{ .Values.dbs.minio.enabled }}
@@ -35,7 +51,15 @@ dbs:
     {{- end }}
   {{- end }}
   pg:
-    enabled: {{ .Values.dbs.pg.enabled }}
+    enabled: {{ and .Values.dbs.pg.enabled (not .Values.dbs.pg.external.enabled) }}
+    {{- if .Values.dbs.pg.external.enabled }}
+    external:
+      enabled: true
+      host: "{{ .Values.dbs.pg.external.host }}"
+      port: {{ .Values.dbs.pg.external.port }}
+      tls: {{ .Values.dbs.pg.external.tls }}
+      secretRef: "{{ .Values.dbs.pg.external.secretRef }}"
+    {{- end }}
     storageSize: {{ .Values.dbs.pg.storageSize }}
     storageClass: "{{ .Values.dbs.pg.storageClass }}"
     {{- if .Values.dbs.pg.nodeSelector }}
@@ -48,13 +72,26 @@ dbs:
       enabled: {{ .Values.dbs.pg.hugePages.enabled }}
       size: {{ .Values.dbs.pg.hugePages.size }}
       memory: "{{ .Values.dbs.pg.hugePages.memory }}"
+    {{- if .Values.dbs.pg.ha.enabled }}
+    ha:
+      enabled: true
+      replicas: {{ .Values.dbs.pg.ha.replicas }}
+    {{- end }}
     backup:
       enabled: {{.Values.backup.enabled}}
       rotation: {{.Values.backup.rotation}}
       period: {{.Values.backup.period}}
   {{- if eq .Values.spec "ccp"  }}
   redis:
-    enabled: {{ .Values.dbs.redis.enabled }}
+    enabled: {{ and .Values.dbs.redis.enabled (not .Values.dbs.redis.external.enabled) }}
+    {{- if .Values.dbs.redis.external.enabled }}
+    external:
+      enabled: true
+      host: "{{ .Values.dbs.redis.external.host }}"
+      port: {{ .Values.dbs.redis.external.port }}
+      tls: {{ .Values.dbs.redis.external.tls }}
+      secretRef: "{{ .Values.dbs.redis.external.secretRef }}"
+    {{- end }}
     storageSize: {{ .Values.dbs.redis.storageSize }}
     storageClass: "{{ .Values.dbs.redis.storageClass }}"
     {{- if .Values.dbs.redis.nodeSelector }}
@@ -63,6 +100,10 @@ dbs:
       {{$key}}: {{$value}}
     {{- end }}
     {{- end }}
+    backup:
+      enabled: {{.Values.backup.enabled}}
+      rotation: {{.Values.backup.rotation}}
+      period: {{.Values.backup.period}}
   {{- end }}
 
 {{- end }}
\ No newline at end of file
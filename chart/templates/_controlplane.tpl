@@ -23,10 +23,12 @@ controlPlane:
     enabled: {{ .Values.controlPlane.cnvrgRouter.enabled }}
     image: {{ .Values.controlPlane.cnvrgRouter.image }}
   objectStorage:
-    accessKey: "{{ .Values.controlPlane.objectStorage.accessKey }}"
+    accessKey:
+      value: "{{ .Values.controlPlane.objectStorage.accessKey }}"
     bucket: "{{ .Values.controlPlane.objectStorage.bucket }}"
     region: "{{ .Values.controlPlane.objectStorage.region }}"
-    secretKey: "{{ .Values.controlPlane.objectStorage.secretKey }}"
+    secretKey:
+      value: "{{ .Values.controlPlane.objectStorage.secretKey }}"
     type: {{ .Values.controlPlane.objectStorage.type }}
     endpoint: "{{ .Values.controlPlane.objectStorage.endpoint }}"
     azureAccountName: "{{ .Values.controlPlane.objectStorage.azureAccountName}}"
@@ -48,7 +50,8 @@ controlPlane:
     server: "{{ .Values.controlPlane.smtp.server }}"
     port: {{ .Values.controlPlane.smtp.port }}
     username: "{{ .Values.controlPlane.smtp.username }}"
-    password: "{{ .Values.controlPlane.smtp.password }}"
+    password:
+      value: "{{ .Values.controlPlane.smtp.password }}"
     domain: "{{ .Values.controlPlane.smtp.domain }}"
     opensslVerifyMode: "{{ .Values.controlPlane.smtp.opensslVerifyMode }}"
     sender: "{{ .Values.controlPlane.smtp.sender }}"
@@ -72,7 +75,8 @@ controlPlane:
     registry:
       url: "{{ .Values.controlPlane.mpi.registry.url }}"
       user: "{{ .Values.controlPlane.mpi.registry.user }}"
-      password: "{{ .Values.controlPlane.mpi.registry.password }}"
+      password:
+        value: "{{ .Values.controlPlane.mpi.registry.password }}"
     {{- if .Values.controlPlane.mpi.extraArgs }}
     extraArgs:
     {{- range $key, $value := .Values.controlPlane.mpi.extraArgs }}
@@ -84,10 +88,11 @@ controlPlane:
   ldap:
     enabled: {{ .Values.controlPlane.ldap.enabled }}
     host: "{{ .Values.controlPlane.ldap.host }}"
-    port: "{{ .Values.controlPlane.ldap.port }}"
+    port: {{ .Values.controlPlane.ldap.port | default 0 | int }}
     account: "{{ .Values.controlPlane.ldap.account }}"
     base: "{{ .Values.controlPlane.ldap.base }}"
     adminUser: "{{ .Values.controlPlane.ldap.adminUser }}"
-    adminPassword: "{{ .Values.controlPlane.ldap.adminPassword }}"
-    ssl: "{{ .Values.controlPlane.ldap.ssl }}"
+    adminPassword:
+      value: "{{ .Values.controlPlane.ldap.adminPassword }}"
+    ssl: {{ eq (toString .Values.controlPlane.ldap.ssl) "true" }}
 {{- end }}
\ No newline at end of file
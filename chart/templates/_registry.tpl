@@ -2,5 +2,6 @@
 registry:
   url: "{{.Values.registry.url}}"
   user: "{{.Values.registry.user}}"
-  password: "{{.Values.registry.password}}"
+  password:
+    value: "{{.Values.registry.password}}"
 {{- end }}
\ No newline at end of file
@@ -10,8 +10,10 @@ sso:
   {{- end }}
   {{- end }}
   clientId: "{{ .Values.sso.clientId }}"
-  clientSecret: "{{ .Values.sso.clientSecret }}"
+  clientSecret:
+    value: "{{ .Values.sso.clientSecret }}"
   azureTenant: "{{ .Values.sso.azureTenant }}"
   oidcIssuerUrl: "{{ .Values.sso.oidcIssuerUrl }}"
-  cookieSecret: {{ randAlphaNum 16 | quote }}
+  cookieSecret:
+    value: {{ randAlphaNum 16 | quote }}
 {{- end }}
\ No newline at end of file
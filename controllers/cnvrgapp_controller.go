@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	mlopsv1 "github.com/AccessibleAI/cnvrg-operator/api/v1"
+	"github.com/AccessibleAI/cnvrg-operator/pkg/backup"
 	"github.com/AccessibleAI/cnvrg-operator/pkg/controlplane"
 	"github.com/AccessibleAI/cnvrg-operator/pkg/dbs"
 	"github.com/AccessibleAI/cnvrg-operator/pkg/desired"
@@ -13,15 +14,11 @@ import (
 	"github.com/AccessibleAI/cnvrg-operator/pkg/monitoring"
 	"github.com/AccessibleAI/cnvrg-operator/pkg/networking"
 	"github.com/AccessibleAI/cnvrg-operator/pkg/registry"
-	"github.com/Dimss/crypt/apr1_crypt"
 	"github.com/go-logr/logr"
 	"github.com/imdario/mergo"
 	"github.com/markbates/pkger"
 	"github.com/spf13/viper"
-	"gopkg.in/d4l3k/messagediff.v1"
 	"io/ioutil"
-	v1apps "k8s.io/api/apps/v1"
-	v1batch "k8s.io/api/batch/v1"
 	v1core "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -32,13 +29,15 @@ import (
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
 	"path/filepath"
-	"reflect"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 	"strconv"
 	"strings"
 	"time"
@@ -53,73 +52,110 @@ type CnvrgAppReconciler struct {
 	Scheme   *runtime.Scheme
 }
 
-var appLog logr.Logger
-
 // +kubebuilder:rbac:groups=mlops.cnvrg.io,resources=cnvrgapps,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=mlops.cnvrg.io,resources=cnvrgapps/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=*,resources=*,verbs=*
 
 func (r *CnvrgAppReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	// the logger is request scoped, reconciles of different objects run concurrently
+	log := r.Log.WithValues("name", req.NamespacedName)
+	ctx = logf.IntoContext(ctx, log)
+	log.Info("starting cnvrgapp reconciliation")
 
-	appLog = r.Log.WithValues("name", req.NamespacedName)
-	appLog.Info("starting cnvrgapp reconciliation")
-
-	// sync specs between actual and defaults
-	equal, err := r.syncCnvrgAppSpec(req.NamespacedName)
+	app, err := r.getCnvrgAppSpec(ctx, req.NamespacedName)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
-	if !equal {
-		return ctrl.Result{Requeue: true}, nil // specs are not equals -> reconcile
+	if app == nil {
+		return ctrl.Result{}, nil // probably spec was deleted, no need to reconcile
 	}
 
-	// specs are synced, proceed reconcile
-	cnvrgApp, err := r.getCnvrgAppSpec(req.NamespacedName)
+	// the defaults are applied in memory, updates (e.g. finalizers) are done on the stored spec
+	infra, err := r.getCnvrgInfra(ctx)
 	if err != nil {
-		return ctrl.Result{}, err
+		log.Error(err, "can't get cnvrg infra")
 	}
-	if cnvrgApp == nil {
-		return ctrl.Result{}, nil // probably spec was deleted, no need to reconcile
+	cnvrgApp, err := effectiveCnvrgApp(ctx, app, infra, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
 	}
 
 	// setup finalizer
-	if cnvrgApp.ObjectMeta.DeletionTimestamp.IsZero() {
-		if !containsString(cnvrgApp.ObjectMeta.Finalizers, CnvrgappFinalizer) {
-			cnvrgApp.ObjectMeta.Finalizers = append(cnvrgApp.ObjectMeta.Finalizers, CnvrgappFinalizer)
-			if err := r.Update(ctx, cnvrgApp); err != nil {
-				appLog.Error(err, "failed to add finalizer")
+	if app.ObjectMeta.DeletionTimestamp.IsZero() {
+		if !containsString(app.ObjectMeta.Finalizers, CnvrgappFinalizer) {
+			app.ObjectMeta.Finalizers = append(app.ObjectMeta.Finalizers, CnvrgappFinalizer)
+			if err := r.Update(ctx, app); err != nil {
+				log.Error(err, "failed to add finalizer")
 				return ctrl.Result{}, err
 			}
 		}
 	} else {
-		if containsString(cnvrgApp.ObjectMeta.Finalizers, CnvrgappFinalizer) {
-			r.updateStatusMessage(mlopsv1.Status{Status: mlopsv1.StatusRemoving, Message: "removing cnvrg spec"}, cnvrgApp)
-			if err := r.cleanup(cnvrgApp); err != nil {
+		if containsString(app.ObjectMeta.Finalizers, CnvrgappFinalizer) {
+			r.updateStatusMessage(ctx, mlopsv1.Status{Status: mlopsv1.StatusRemoving, Message: "removing cnvrg spec"}, cnvrgApp)
+			if err := r.cleanup(ctx, cnvrgApp); err != nil {
 				return ctrl.Result{}, err
 			}
-			cnvrgApp.ObjectMeta.Finalizers = removeString(cnvrgApp.ObjectMeta.Finalizers, CnvrgappFinalizer)
 			err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
-				if err := r.Update(ctx, cnvrgApp); err != nil {
-					cnvrgApp, err := r.getCnvrgAppSpec(req.NamespacedName)
-					if err != nil {
-						appLog.Error(err, "error getting cnvrgapp for finalizer cleanup")
-						return err
-					}
-					cnvrgApp.ObjectMeta.Finalizers = removeString(cnvrgApp.ObjectMeta.Finalizers, CnvrgappFinalizer)
-					return r.Update(ctx, cnvrgApp)
+				app, err := r.getCnvrgAppSpec(ctx, req.NamespacedName)
+				if err != nil {
+					log.Error(err, "error getting cnvrgapp for finalizer cleanup")
+					return err
 				}
-				return err
+				if app == nil {
+					return nil
+				}
+				app.ObjectMeta.Finalizers = removeString(app.ObjectMeta.Finalizers, CnvrgappFinalizer)
+				return r.Update(ctx, app)
 			})
 			if err != nil {
-				appLog.Info("error in removing finalizer")
+				log.Info("error in removing finalizer")
 				return ctrl.Result{}, err
 			}
+			deleteReadinessMetrics(cnvrgApp)
 		}
 		return ctrl.Result{}, nil
 	}
+	saveEffectiveSpec(ctx, r.Client, r.Scheme, cnvrgApp, cnvrgApp.Namespace, redactedCnvrgAppSpec(cnvrgApp))
+
+	// the secret references are resolved after the effective spec is saved, so the credentials are not dumped
+	missing, err := resolveSecretRefs(ctx, r.Client, cnvrgApp.Namespace, appSecretRefs(cnvrgApp))
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	secretRefsResolved := secretRefsCondition(missing)
+	if len(missing) > 0 {
+		err := fmt.Errorf("can't resolve secret references: %s", secretRefsResolved.Message)
+		log.Error(err, "missing secret references")
+		r.updateStatusMessage(ctx, mlopsv1.Status{Status: mlopsv1.StatusError, Message: err.Error(), Progress: -1, Conditions: []metav1.Condition{secretRefsResolved, reconciledCondition(nil, err)}}, cnvrgApp)
+		// the referenced secrets are watched, the cnvrgApp is reconciled again once they are created
+		return ctrl.Result{}, nil
+	}
+	cnvrgApp.Spec.SecretRefsChecksum = secretRefsChecksum(appSecretRefs(cnvrgApp))
+	conditions := []metav1.Condition{secretRefsResolved}
+
+	// the validating webhook rejects invalid pod spec overrides, without it they are reported by the reconciler
+	if !viper.GetBool("enable-webhooks") {
+		overridesValid := podSpecOverridesCondition(cnvrgApp.Spec.ValidatePodSpecOverrides())
+		if overridesValid.Status == metav1.ConditionFalse {
+			err := fmt.Errorf("invalid pod spec overrides: %s", overridesValid.Message)
+			log.Error(err, "invalid pod spec overrides")
+			r.updateStatusMessage(ctx, mlopsv1.Status{Status: mlopsv1.StatusError, Message: err.Error(), Progress: -1, Conditions: []metav1.Condition{overridesValid, reconciledCondition(nil, err)}}, cnvrgApp)
+			// the cnvrgApp is reconciled again once the spec is fixed
+			return ctrl.Result{}, nil
+		}
+		conditions = append(conditions, overridesValid)
+	}
+
+	// the pg upgrade decides which pg version is served, hence it runs before the manifests are rendered
+	if err := r.reconcilePgUpgrade(ctx, cnvrgApp); err != nil {
+		log.Error(err, "pg upgrade error")
+		r.updateStatusMessage(ctx, mlopsv1.Status{Status: mlopsv1.StatusError, Message: err.Error(), Progress: -1, Conditions: []metav1.Condition{reconciledCondition(nil, err)}}, cnvrgApp)
+		return ctrl.Result{}, err
+	}
+	pgUpgradeSpec(cnvrgApp)
 
 	// check if enabled control plane workloads are all in ready status
-	ready, percentageReady, stackReadiness, err := r.getControlPlaneReadinessStatus(cnvrgApp)
+	ready, percentageReady, stackReadiness, err := r.getControlPlaneReadinessStatus(ctx, cnvrgApp)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -133,74 +169,95 @@ func (r *CnvrgAppReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		Message:        fmt.Sprintf("reconciling... (%d%%)", percentageReady),
 		Progress:       percentageReady,
 		StackReadiness: stackReadiness}
-	r.updateStatusMessage(s, cnvrgApp)
+	r.updateStatusMessage(ctx, s, cnvrgApp)
 
 	// apply spec manifests
-	if err := r.applyManifests(cnvrgApp); err != nil {
+	// the overrides are matched while the manifests are rendered
+	matches := desired.NewOverrideMatches()
+	pending, err := r.applyManifests(desired.WithOverrideMatches(ctx, matches), cnvrgApp)
+	if err != nil {
 		return ctrl.Result{}, err
 	}
+	reconciled := reconciledCondition(pending, nil)
+	// the pending components aren't rendered, their overrides are matched once they are applied
+	if len(pending) == 0 {
+		conditions = append(conditions, overridesMatchedCondition(matches.Unmatched(cnvrgApp.Spec.Overrides)))
+	}
 
 	// get control plan readiness
-	ready, percentageReady, stackReadiness, err = r.getControlPlaneReadinessStatus(cnvrgApp)
+	ready, percentageReady, stackReadiness, err = r.getControlPlaneReadinessStatus(ctx, cnvrgApp)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 	statusMsg := fmt.Sprintf("successfully reconciled, ready (%d%%)", percentageReady)
-	appLog.Info(statusMsg)
+	log.Info(statusMsg)
 
-	if ready { // ura, done
+	if ready && len(pending) == 0 { // ura, done
 		s := mlopsv1.Status{
 			Status:         mlopsv1.StatusReady,
 			Message:        statusMsg,
 			Progress:       percentageReady,
-			StackReadiness: stackReadiness}
-		r.updateStatusMessage(s, cnvrgApp)
-		appLog.Info("stack is ready!")
+			StackReadiness: stackReadiness,
+			Conditions:     append([]metav1.Condition{reconciled}, conditions...)}
+		r.updateStatusMessage(ctx, s, cnvrgApp)
+		log.Info("stack is ready!")
 		r.recorder.Event(cnvrgApp, "Normal", "Created", fmt.Sprintf("cnvrgapp %s successfully deployed", req.NamespacedName))
 		return ctrl.Result{}, nil
 	} else { // reconcile again
+		if percentageReady == 100 {
+			percentageReady = 99
+		}
+		s := mlopsv1.Status{
+			Status:         mlopsv1.StatusReconciling,
+			Message:        fmt.Sprintf("reconciling... (%d%%)", percentageReady),
+			Progress:       percentageReady,
+			StackReadiness: stackReadiness,
+			Conditions:     append([]metav1.Condition{reconciled}, conditions...)}
+		r.updateStatusMessage(ctx, s, cnvrgApp)
 		requeueAfter, _ := time.ParseDuration("30s")
-		appLog.Info("stack not ready yet, requeuing...")
+		log.Info("stack not ready yet, requeuing...")
 		r.recorder.Event(cnvrgApp, "Normal", "Creating", fmt.Sprintf("cnvrgapp %s not ready yet, done: %d%%", req.NamespacedName, percentageReady))
 		return ctrl.Result{RequeueAfter: requeueAfter}, nil
 	}
 }
 
-func (r *CnvrgAppReconciler) getEsCredsSecret(app *mlopsv1.CnvrgApp) (user string, pass string, err error) {
+func (r *CnvrgAppReconciler) getEsCredsSecret(ctx context.Context, app *mlopsv1.CnvrgApp) (user string, pass string, err error) {
+	log := logf.FromContext(ctx)
 	user = "cnvrg"
 	namespacedName := types.NamespacedName{Name: app.Spec.Dbs.Es.CredsRef, Namespace: app.Namespace}
 	creds := v1core.Secret{ObjectMeta: metav1.ObjectMeta{Name: namespacedName.Name, Namespace: namespacedName.Namespace}}
-	if err := r.Get(context.Background(), namespacedName, &creds); err != nil && errors.IsNotFound(err) {
-		appLog.Error(err, "es-creds secret not found!")
+	if err := r.Get(ctx, namespacedName, &creds); err != nil && errors.IsNotFound(err) {
+		log.Error(err, "es-creds secret not found!")
 		return "", "", err
 	} else if err != nil {
-		appLog.Error(err, "can't check if es creds secret exists", "name", namespacedName.Name)
+		log.Error(err, "can't check if es creds secret exists", "name", namespacedName.Name)
 		return "", "", err
 	}
 
 	if _, ok := creds.Data["CNVRG_ES_USER"]; !ok {
 		err := fmt.Errorf("es creds secret %s missing require field CNVRG_ES_USER", namespacedName.Name)
-		appLog.Error(err, "missing required field")
+		log.Error(err, "missing required field")
 		return "", "", err
 	}
 
 	if _, ok := creds.Data["CNVRG_ES_PASS"]; !ok {
 		err := fmt.Errorf("es creds secret %s missing require field CNVRG_ES_PASS", namespacedName.Name)
-		appLog.Error(err, "missing required field")
+		log.Error(err, "missing required field")
 		return "", "", err
 	}
 
 	return string(creds.Data["CNVRG_ES_USER"]), string(creds.Data["CNVRG_ES_PASS"]), nil
 }
 
-func (r *CnvrgAppReconciler) getControlPlaneReadinessStatus(cnvrgApp *mlopsv1.CnvrgApp) (bool, int, map[string]bool, error) {
+func (r *CnvrgAppReconciler) getControlPlaneReadinessStatus(ctx context.Context, cnvrgApp *mlopsv1.CnvrgApp) (bool, int, map[string]bool, error) {
+	log := logf.FromContext(ctx)
 
 	readyState := make(map[string]bool)
 
 	// check ingresscheck status
 	if cnvrgApp.Spec.IngressCheck.Enabled {
 		name := types.NamespacedName{Name: "ingresscheck", Namespace: cnvrgApp.Namespace}
-		ready, err := r.CheckJobReadiness(name)
+		ready, err := checkJobReadiness(ctx, r, name)
 		if err != nil {
 			return false, 0, nil, err
 		}
@@ -210,7 +267,7 @@ func (r *CnvrgAppReconciler) getControlPlaneReadinessStatus(cnvrgApp *mlopsv1.Cn
 	// check webapp status
 	if cnvrgApp.Spec.ControlPlane.WebApp.Enabled {
 		name := types.NamespacedName{Name: cnvrgApp.Spec.ControlPlane.WebApp.SvcName, Namespace: cnvrgApp.Namespace}
-		ready, err := r.CheckDeploymentReadiness(name)
+		ready, err := checkDeploymentReadiness(ctx, r, name)
 		if err != nil {
 			return false, 0, nil, err
 		}
@@ -220,7 +277,7 @@ func (r *CnvrgAppReconciler) getControlPlaneReadinessStatus(cnvrgApp *mlopsv1.Cn
 	// check sidekiq status
 	if cnvrgApp.Spec.ControlPlane.Sidekiq.Enabled {
 		name := types.NamespacedName{Name: "sidekiq", Namespace: cnvrgApp.Namespace}
-		ready, err := r.CheckDeploymentReadiness(name)
+		ready, err := checkDeploymentReadiness(ctx, r, name)
 		if err != nil {
 			return false, 0, nil, err
 		}
@@ -230,7 +287,7 @@ func (r *CnvrgAppReconciler) getControlPlaneReadinessStatus(cnvrgApp *mlopsv1.Cn
 	// check searchkiq status
 	if cnvrgApp.Spec.ControlPlane.Searchkiq.Enabled {
 		name := types.NamespacedName{Name: "searchkiq", Namespace: cnvrgApp.Namespace}
-		ready, err := r.CheckDeploymentReadiness(name)
+		ready, err := checkDeploymentReadiness(ctx, r, name)
 		if err != nil {
 			return false, 0, nil, err
 		}
@@ -240,17 +297,29 @@ func (r *CnvrgAppReconciler) getControlPlaneReadinessStatus(cnvrgApp *mlopsv1.Cn
 	// check systemkiq status
 	if cnvrgApp.Spec.ControlPlane.Systemkiq.Enabled {
 		name := types.NamespacedName{Name: "systemkiq", Namespace: cnvrgApp.Namespace}
-		ready, err := r.CheckDeploymentReadiness(name)
+		ready, err := checkDeploymentReadiness(ctx, r, name)
 		if err != nil {
 			return false, 0, nil, err
 		}
 		readyState["systemkiq"] = ready
 	}
 
-	// check postgres status
-	if cnvrgApp.Spec.Dbs.Pg.Enabled {
+	// check postgres status, in ha mode pg is ready once there is a primary, the replicas are reported separately
+	if cnvrgApp.Spec.Dbs.Pg.Enabled && cnvrgApp.Spec.Dbs.Pg.HA.Enabled {
 		name := types.NamespacedName{Name: cnvrgApp.Spec.Dbs.Pg.SvcName, Namespace: cnvrgApp.Namespace}
-		ready, err := r.CheckDeploymentReadiness(name)
+		ready, err := checkPgPrimaryReadiness(ctx, r, name)
+		if err != nil {
+			return false, 0, nil, err
+		}
+		readyState["pg"] = ready
+		replicasReady, err := checkStatefulSetReadiness(ctx, r, name)
+		if err != nil {
+			return false, 0, nil, err
+		}
+		readyState["pgReplicas"] = replicasReady
+	} else if cnvrgApp.Spec.Dbs.Pg.Enabled {
+		name := types.NamespacedName{Name: cnvrgApp.Spec.Dbs.Pg.SvcName, Namespace: cnvrgApp.Namespace}
+		ready, err := checkDeploymentReadiness(ctx, r, name)
 		if err != nil {
 			return false, 0, nil, err
 		}
@@ -260,7 +329,7 @@ func (r *CnvrgAppReconciler) getControlPlaneReadinessStatus(cnvrgApp *mlopsv1.Cn
 	// check minio status
 	if cnvrgApp.Spec.Dbs.Minio.Enabled {
 		name := types.NamespacedName{Name: cnvrgApp.Spec.Dbs.Minio.SvcName, Namespace: cnvrgApp.Namespace}
-		ready, err := r.CheckDeploymentReadiness(name)
+		ready, err := checkDeploymentReadiness(ctx, r, name)
 		if err != nil {
 			return false, 0, nil, err
 		}
@@ -270,7 +339,7 @@ func (r *CnvrgAppReconciler) getControlPlaneReadinessStatus(cnvrgApp *mlopsv1.Cn
 	// check redis status
 	if cnvrgApp.Spec.Dbs.Redis.Enabled {
 		name := types.NamespacedName{Name: cnvrgApp.Spec.Dbs.Redis.SvcName, Namespace: cnvrgApp.Namespace}
-		ready, err := r.CheckDeploymentReadiness(name)
+		ready, err := checkDeploymentReadiness(ctx, r, name)
 		if err != nil {
 			return false, 0, nil, err
 		}
@@ -280,14 +349,14 @@ func (r *CnvrgAppReconciler) getControlPlaneReadinessStatus(cnvrgApp *mlopsv1.Cn
 	// check es status
 	if cnvrgApp.Spec.Dbs.Es.Enabled {
 		name := types.NamespacedName{Name: cnvrgApp.Spec.Dbs.Es.SvcName, Namespace: cnvrgApp.Namespace}
-		ready, err := r.CheckStatefulSetReadiness(name)
+		ready, err := checkStatefulSetReadiness(ctx, r, name)
 		if err != nil {
 			return false, 0, nil, err
 		}
 		// if es is ready, trigger fluentbit reconfiguration
 		if ready {
-			appLog.Info("es is ready, triggering fluentbit reconfiguration")
-			if err := r.addFluentbitConfiguration(cnvrgApp); err != nil {
+			log.Info("es is ready, triggering fluentbit reconfiguration")
+			if err := r.addFluentbitConfiguration(ctx, cnvrgApp); err != nil {
 				return false, 0, nil, err
 			}
 		}
@@ -297,7 +366,7 @@ func (r *CnvrgAppReconciler) getControlPlaneReadinessStatus(cnvrgApp *mlopsv1.Cn
 	// check kibana status
 	if cnvrgApp.Spec.Logging.Kibana.Enabled {
 		name := types.NamespacedName{Name: cnvrgApp.Spec.Logging.Kibana.SvcName, Namespace: cnvrgApp.Namespace}
-		ready, err := r.CheckDeploymentReadiness(name)
+		ready, err := checkDeploymentReadiness(ctx, r, name)
 		if err != nil {
 			return false, 0, nil, err
 		}
@@ -307,7 +376,7 @@ func (r *CnvrgAppReconciler) getControlPlaneReadinessStatus(cnvrgApp *mlopsv1.Cn
 	// check prometheus status
 	if cnvrgApp.Spec.Monitoring.Prometheus.Enabled {
 		name := types.NamespacedName{Name: "prometheus-cnvrg-ccp-prometheus", Namespace: cnvrgApp.Namespace}
-		ready, err := r.CheckStatefulSetReadiness(name)
+		ready, err := checkStatefulSetReadiness(ctx, r, name)
 		if err != nil {
 			return false, 0, nil, err
 		}
@@ -317,142 +386,181 @@ func (r *CnvrgAppReconciler) getControlPlaneReadinessStatus(cnvrgApp *mlopsv1.Cn
 	// check prometheus status
 	if cnvrgApp.Spec.Monitoring.Grafana.Enabled {
 		name := types.NamespacedName{Name: cnvrgApp.Spec.Monitoring.Grafana.SvcName, Namespace: cnvrgApp.Namespace}
-		ready, err := r.CheckDeploymentReadiness(name)
+		ready, err := checkDeploymentReadiness(ctx, r, name)
 		if err != nil {
 			return false, 0, nil, err
 		}
 		readyState["grafana"] = ready
 	}
 
-	percentageReady := 0
-
-	readyCount := 0
-
-	for _, ready := range readyState {
-		if ready {
-			readyCount++
-		}
-	}
-
-	if len(readyState) > 0 {
-		percentageReady = readyCount * 100 / len(readyState)
-	}
-
-	return readyCount == len(readyState), percentageReady, readyState, nil
+	ready, percentageReady := stackProgress(readyState)
+	return ready, percentageReady, readyState, nil
 }
 
-func (r *CnvrgAppReconciler) applyManifests(cnvrgApp *mlopsv1.CnvrgApp) error {
-	// registry
-	appLog.Info("applying registry")
-	registryData := desired.TemplateData{
-		Namespace: cnvrgApp.Namespace,
-		Data: map[string]interface{}{
-			"Registry":    cnvrgApp.Spec.Registry,
-			"Annotations": cnvrgApp.Spec.Annotations,
-			"Labels":      cnvrgApp.Spec.Labels,
-		},
-	}
-	if err := desired.Apply(registry.State(registryData), cnvrgApp, r.Client, r.Scheme, appLog); err != nil {
-		r.updateStatusMessage(mlopsv1.Status{Status: mlopsv1.StatusError, Message: err.Error(), Progress: -1}, cnvrgApp)
-		return err
-	}
+// applyManifests applies the components, returns the components which are waiting for their dependencies
+func (r *CnvrgAppReconciler) applyManifests(ctx context.Context, cnvrgApp *mlopsv1.CnvrgApp) ([]string, error) {
+	log := logf.FromContext(ctx)
 
-	// dbs
-	if err := r.dbsState(cnvrgApp); err != nil {
-		r.updateStatusMessage(mlopsv1.Status{Status: mlopsv1.StatusError, Message: err.Error(), Progress: -1}, cnvrgApp)
-		return err
-	}
-
-	// backups
-	if err := r.backupsState(cnvrgApp); err != nil {
-		r.updateStatusMessage(mlopsv1.Status{Status: mlopsv1.StatusError, Message: err.Error(), Progress: -1}, cnvrgApp)
-		return err
+	inventory := desired.NewInventory()
+	pending, err := applyComponents(ctx, r.appComponents(ctx, cnvrgApp), cnvrgApp, r.Client, r.Scheme, inventory)
+	if err != nil {
+		r.updateStatusMessage(ctx, mlopsv1.Status{Status: mlopsv1.StatusError, Message: err.Error(), Progress: -1, Conditions: []metav1.Condition{reconciledCondition(nil, err)}}, cnvrgApp)
+		return nil, err
 	}
 
-	// networking
-	appLog.Info("applying networking")
-	if err := desired.Apply(networking.CnvrgAppNetworkingState(cnvrgApp), cnvrgApp, r.Client, r.Scheme, appLog); err != nil {
-		r.updateStatusMessage(mlopsv1.Status{Status: mlopsv1.StatusError, Message: err.Error(), Progress: -1}, cnvrgApp)
-		return err
+	// the inventory is partial until all the components are applied, hence pruning is postponed
+	if len(pending) > 0 {
+		log.Info("components are waiting for dependencies", "components", pending)
+		return pending, nil
 	}
 
-	// logging
-	if err := r.loggingState(cnvrgApp); err != nil {
-		r.updateStatusMessage(mlopsv1.Status{Status: mlopsv1.StatusError, Message: err.Error(), Progress: -1}, cnvrgApp)
-		return err
+	// prune resources of the disabled components
+	inventoryName := types.NamespacedName{Name: cnvrgApp.Name + "-inventory", Namespace: cnvrgApp.Namespace}
+	if err := pruneComponents(ctx, inventoryName, inventory, cnvrgApp, r.Client, r.Scheme); err != nil {
+		r.updateStatusMessage(ctx, mlopsv1.Status{Status: mlopsv1.StatusError, Message: err.Error(), Progress: -1, Conditions: []metav1.Condition{reconciledCondition(nil, err)}}, cnvrgApp)
+		return nil, err
 	}
 
-	// controlplane
-	appLog.Info("applying controlplane")
-	if err := desired.Apply(controlplane.State(cnvrgApp), cnvrgApp, r.Client, r.Scheme, appLog); err != nil {
-		r.updateStatusMessage(mlopsv1.Status{Status: mlopsv1.StatusError, Message: err.Error(), Progress: -1}, cnvrgApp)
-		return err
-	}
+	return nil, nil
+}
 
-	// monitoring
-	if err := r.monitoringState(cnvrgApp); err != nil {
-		r.updateStatusMessage(mlopsv1.Status{Status: mlopsv1.StatusError, Message: err.Error(), Progress: -1}, cnvrgApp)
-		return err
+// appComponents returns the cnvrgApp components, the list is ordered by dependencies
+func (r *CnvrgAppReconciler) appComponents(ctx context.Context, app *mlopsv1.CnvrgApp) []component {
+	return []component{
+		{name: "registry", states: func() ([]*desired.State, error) {
+			return registry.State(registryData(app)), nil
+		}},
+		{name: "pg", states: func() ([]*desired.State, error) {
+			if err := checkPgModeSwitch(ctx, r, app); err != nil {
+				return nil, err
+			}
+			return append(appPgState(app), appPgUpgradeState(app)...), nil
+		}, ready: func() (bool, error) {
+			if !app.Spec.Dbs.Pg.Enabled {
+				return true, nil
+			}
+			// the dependent components are postponed until the upgraded pg is served
+			if pgUpgradeRunning(app) {
+				return false, nil
+			}
+			if app.Spec.Dbs.Pg.HA.Enabled {
+				return checkPgPrimaryReadiness(ctx, r, types.NamespacedName{Name: app.Spec.Dbs.Pg.SvcName, Namespace: app.Namespace})
+			}
+			return checkDeploymentReadiness(ctx, r, types.NamespacedName{Name: app.Spec.Dbs.Pg.SvcName, Namespace: app.Namespace})
+		}},
+		// the control plane and redis are held while a restore quiesces them
+		{name: "restore", ready: func() (bool, error) {
+			running, err := restoreRunning(ctx, r, app)
+			return !running, err
+		}},
+		{name: "redis", dependsOn: []string{"restore"}, states: func() ([]*desired.State, error) {
+			return appRedisState(app), nil
+		}, ready: func() (bool, error) {
+			if !app.Spec.Dbs.Redis.Enabled {
+				return true, nil
+			}
+			return checkDeploymentReadiness(ctx, r, types.NamespacedName{Name: app.Spec.Dbs.Redis.SvcName, Namespace: app.Namespace})
+		}},
+		{name: "minio", states: func() ([]*desired.State, error) {
+			return dbs.AppMinioState(app), nil
+		}, ready: func() (bool, error) {
+			if !app.Spec.Dbs.Minio.Enabled {
+				return true, nil
+			}
+			return checkDeploymentReadiness(ctx, r, types.NamespacedName{Name: app.Spec.Dbs.Minio.SvcName, Namespace: app.Namespace})
+		}},
+		{name: "es", states: func() ([]*desired.State, error) {
+			return appEsState(app), nil
+		}, ready: func() (bool, error) {
+			if !app.Spec.Dbs.Es.Enabled {
+				return true, nil
+			}
+			return checkStatefulSetReadiness(ctx, r, types.NamespacedName{Name: app.Spec.Dbs.Es.SvcName, Namespace: app.Namespace})
+		}},
+		{name: "cvat", states: func() ([]*desired.State, error) {
+			return dbs.AppCvatState(app), nil
+		}},
+		// the external databases are checked with their own secrets, the creds secrets (which reload the control plane)
+		// are written and the control plane is rolled out only once the external databases are reachable
+		{name: "external dbs", states: func() ([]*desired.State, error) {
+			return appPreflightState(app), nil
+		}, sync: func() error {
+			return r.syncPreflight(ctx, app)
+		}, ready: func() (bool, error) {
+			return preflightReady(ctx, r, app)
+		}},
+		{name: "external dbs creds", dependsOn: []string{"external dbs"}, states: func() ([]*desired.State, error) {
+			return externalDbsCreds(app), nil
+		}},
+		{name: "backups", dependsOn: []string{"pg", "redis", "cvat"}, states: func() ([]*desired.State, error) {
+			return redisBackupState(app), nil
+		}, sync: func() error {
+			return r.backupsState(ctx, app)
+		}},
+		{name: "networking", states: func() ([]*desired.State, error) {
+			return networking.CnvrgAppNetworkingState(app), nil
+		}},
+		{name: "logging", dependsOn: []string{"es", "external dbs creds"}, states: func() ([]*desired.State, error) {
+			return r.loggingState(ctx, app)
+		}},
+		{name: "controlplane", dependsOn: []string{"registry", "pg", "redis", "minio", "es", "external dbs creds", "restore"}, states: func() ([]*desired.State, error) {
+			return controlplane.State(app), nil
+		}},
+		{name: "monitoring secrets", states: func() ([]*desired.State, error) {
+			return r.monitoringSecretsState(ctx, app)
+		}},
+		{name: "monitoring", dependsOn: []string{"monitoring secrets"}, states: func() ([]*desired.State, error) {
+			return r.monitoringState(ctx, app)
+		}, sync: func() error {
+			return r.createGrafanaDashboards(ctx, app)
+		}},
+		{name: "ingress check", dependsOn: []string{"networking", "controlplane"}, states: func() ([]*desired.State, error) {
+			return ingresscheck.IngressCheckState(app), nil
+		}},
 	}
+}
 
-	// ingress check
-	if err := r.ingressCheckState(cnvrgApp); err != nil {
-		r.updateStatusMessage(mlopsv1.Status{Status: mlopsv1.StatusError, Message: err.Error(), Progress: -1}, cnvrgApp)
-		return err
+func registryData(app *mlopsv1.CnvrgApp) registry.Data {
+	return registry.Data{
+		Namespace:   app.Namespace,
+		Registry:    app.Spec.Registry,
+		Annotations: app.Spec.Annotations,
+		Labels:      app.Spec.Labels,
 	}
-
-	return nil
 }
 
-func (r *CnvrgAppReconciler) loggingState(app *mlopsv1.CnvrgApp) error {
+func (r *CnvrgAppReconciler) loggingState(ctx context.Context, app *mlopsv1.CnvrgApp) ([]*desired.State, error) {
+	var state []*desired.State
 
 	if app.Spec.Logging.Kibana.Enabled {
-		appLog.Info("applying kibana")
-		kibanaConfigSecretData, err := r.getKibanaConfigSecretData(app)
+		kibanaConfigSecretData, err := r.getKibanaConfigSecretData(ctx, app)
 		if err != nil {
-			r.updateStatusMessage(mlopsv1.Status{Status: mlopsv1.StatusError, Message: err.Error(), Progress: -1}, app)
-			return err
-		}
-		if err := desired.Apply(logging.KibanaConfSecret(*kibanaConfigSecretData), app, r.Client, r.Scheme, appLog); err != nil {
-			r.updateStatusMessage(mlopsv1.Status{Status: mlopsv1.StatusError, Message: err.Error(), Progress: -1}, app)
-			return err
-		}
-		if err := desired.Apply(logging.CnvrgAppKibanaState(app), app, r.Client, r.Scheme, appLog); err != nil {
-			r.updateStatusMessage(mlopsv1.Status{Status: mlopsv1.StatusError, Message: err.Error(), Progress: -1}, app)
-			return err
+			return nil, err
 		}
+		state = append(state, logging.KibanaConfSecret(*kibanaConfigSecretData)...)
+		state = append(state, logging.CnvrgAppKibanaState(app)...)
 	}
 
 	if app.Spec.Logging.Elastalert.Enabled {
-		appLog.Info("applying elastalert")
-
 		// create elastalert creds ref
-		data, err := generateElastalertCreds(app)
+		data, err := generateElastalertCreds(ctx, app)
 		if err != nil {
-			r.updateStatusMessage(mlopsv1.Status{Status: mlopsv1.StatusError, Message: err.Error(), Progress: -1}, app)
-			return err
-		}
-		if err := desired.Apply(logging.ElastCreds(data), app, r.Client, r.Scheme, appLog); err != nil {
-			r.updateStatusMessage(mlopsv1.Status{Status: mlopsv1.StatusError, Message: err.Error(), Progress: -1}, app)
-			return err
-		}
-
-		if err := desired.Apply(logging.ElastAlert(), app, r.Client, r.Scheme, appLog); err != nil {
-			r.updateStatusMessage(mlopsv1.Status{Status: mlopsv1.StatusError, Message: err.Error(), Progress: -1}, app)
-			return err
+			return nil, err
 		}
-
+		state = append(state, logging.ElastCreds(*data)...)
+		state = append(state, logging.ElastAlert()...)
 	}
 
-	return nil
+	return state, nil
 }
 
-func generateElastalertCreds(app *mlopsv1.CnvrgApp) (*desired.TemplateData, error) {
+func generateElastalertCreds(ctx context.Context, app *mlopsv1.CnvrgApp) (*logging.ElastCredsData, error) {
+	log := logf.FromContext(ctx)
 	user := "cnvrg"
-	pass := desired.RandomString()
-	passwordHash, err := apr1_crypt.New().Generate([]byte(pass), nil)
+	pass := desired.RandomString(ctx)
+	passwordHash, err := desired.PasswordHash(ctx, pass)
 	if err != nil {
-		appLog.Error(err, "error generating elastalert hash")
+		log.Error(err, "error generating elastalert hash")
 		return nil, err
 	}
 
@@ -461,291 +569,275 @@ func generateElastalertCreds(app *mlopsv1.CnvrgApp) (*desired.TemplateData, erro
 		httpSchema = "https://"
 	}
 
-	data := &desired.TemplateData{
-		Data: map[string]interface{}{
-			"Namespace":     app.Namespace,
-			"Annotations":   app.Spec.Annotations,
-			"Labels":        app.Spec.Labels,
-			"CredsRef":      app.Spec.Logging.Elastalert.CredsRef,
-			"User":          user,
-			"Pass":          pass,
-			"Htpasswd":      fmt.Sprintf("%s:%s", user, passwordHash),
-			"ElastAlertUrl": fmt.Sprintf("%s%s.%s", httpSchema, app.Spec.Logging.Elastalert.SvcName, app.Spec.ClusterDomain),
-		},
+	data := &logging.ElastCredsData{
+		Namespace:     app.Namespace,
+		Annotations:   app.Spec.Annotations,
+		Labels:        app.Spec.Labels,
+		CredsRef:      app.Spec.Logging.Elastalert.CredsRef,
+		User:          user,
+		Pass:          pass,
+		Htpasswd:      fmt.Sprintf("%s:%s", user, passwordHash),
+		ElastAlertUrl: fmt.Sprintf("%s%s.%s", httpSchema, app.Spec.Logging.Elastalert.SvcName, app.Spec.ClusterDomain),
 	}
 
 	return data, nil
 }
 
-func (r *CnvrgAppReconciler) dbsState(app *mlopsv1.CnvrgApp) error {
-
-	// dbs
-	appLog.Info("applying dbs")
+// creds secrets are not updatable, hence generated only if still doesn't exists,
+// the creds secrets of the external databases are applied by the external dbs creds component
 
-	if app.Spec.Dbs.Es.Enabled {
-		esSecretData := desired.TemplateData{
-			Data: map[string]interface{}{
-				"Namespace":   app.Namespace,
-				"CredsRef":    app.Spec.Dbs.Es.CredsRef,
-				"EsUrl":       fmt.Sprintf("%s.%s.svc:%d", app.Spec.Dbs.Es.SvcName, app.Namespace, app.Spec.Dbs.Es.Port),
-				"Annotations": app.Spec.Annotations,
-				"Labels":      app.Spec.Labels,
-			},
-		}
-		appLog.Info("trying to generate es creds (if still doesn't exists...)")
-		if err := desired.Apply(dbs.EsCreds(esSecretData), app, r.Client, r.Scheme, appLog); err != nil {
-			r.updateStatusMessage(mlopsv1.Status{Status: mlopsv1.StatusError, Message: err.Error(), Progress: -1}, app)
-			return err
-		}
+func appEsState(app *mlopsv1.CnvrgApp) []*desired.State {
+	if !app.Spec.Dbs.Es.Enabled {
+		return nil
 	}
-
-	if app.Spec.Dbs.Pg.Enabled {
-		pgSecretData := desired.TemplateData{
-			Data: map[string]interface{}{
-				"Namespace":          app.Namespace,
-				"CredsRef":           app.Spec.Dbs.Pg.CredsRef,
-				"Annotations":        app.Spec.Annotations,
-				"Labels":             app.Spec.Labels,
-				"MaxConnections":     app.Spec.Dbs.Pg.MaxConnections,
-				"SharedBuffers":      app.Spec.Dbs.Pg.SharedBuffers,
-				"EffectiveCacheSize": app.Spec.Dbs.Pg.EffectiveCacheSize,
-				"SvcName":            app.Spec.Dbs.Pg.SvcName,
-			},
-		}
-		appLog.Info("trying to generate pg creds (if still doesn't exists...)")
-		if err := desired.Apply(dbs.PgCreds(pgSecretData), app, r.Client, r.Scheme, appLog); err != nil {
-			r.updateStatusMessage(mlopsv1.Status{Status: mlopsv1.StatusError, Message: err.Error(), Progress: -1}, app)
-			return err
-		}
+	esSecretData := dbs.EsCredsData{
+		Namespace:   app.Namespace,
+		CredsRef:    app.Spec.Dbs.Es.CredsRef,
+		EsUrl:       fmt.Sprintf("%s.%s.svc:%d", app.Spec.Dbs.Es.SvcName, app.Namespace, app.Spec.Dbs.Es.Port),
+		Annotations: app.Spec.Annotations,
+		Labels:      app.Spec.Labels,
 	}
+	return append(dbs.EsCreds(esSecretData), dbs.AppEsState(app)...)
+}
 
-	if app.Spec.Dbs.Redis.Enabled {
-		redisSecretData := desired.TemplateData{
-			Data: map[string]interface{}{
-				"Namespace":   app.Namespace,
-				"Annotations": app.Spec.Annotations,
-				"Labels":      app.Spec.Labels,
-				"CredsRef":    app.Spec.Dbs.Redis.CredsRef,
-				"SvcName":     app.Spec.Dbs.Redis.SvcName,
-			},
-		}
-		appLog.Info("trying to generate redis creds (if still doesn't exists...)")
-		if err := desired.Apply(dbs.RedisCreds(redisSecretData), app, r.Client, r.Scheme, appLog); err != nil {
-			r.updateStatusMessage(mlopsv1.Status{Status: mlopsv1.StatusError, Message: err.Error(), Progress: -1}, app)
-			return err
-		}
+func appPgState(app *mlopsv1.CnvrgApp) []*desired.State {
+	if !app.Spec.Dbs.Pg.Enabled {
+		return nil
 	}
+	pgSecretData := dbs.PgCredsData{
+		Namespace:          app.Namespace,
+		CredsRef:           app.Spec.Dbs.Pg.CredsRef,
+		Annotations:        app.Spec.Annotations,
+		Labels:             app.Spec.Labels,
+		MaxConnections:     app.Spec.Dbs.Pg.MaxConnections,
+		SharedBuffers:      app.Spec.Dbs.Pg.SharedBuffers,
+		EffectiveCacheSize: app.Spec.Dbs.Pg.EffectiveCacheSize,
+		SvcName:            app.Spec.Dbs.Pg.SvcName,
+	}
+	return append(dbs.PgCreds(pgSecretData), dbs.AppPgState(app)...)
+}
 
-	if err := desired.Apply(dbs.AppDbsState(app), app, r.Client, r.Scheme, appLog); err != nil {
-		r.updateStatusMessage(mlopsv1.Status{Status: mlopsv1.StatusError, Message: err.Error(), Progress: -1}, app)
-		return err
+func appRedisState(app *mlopsv1.CnvrgApp) []*desired.State {
+	if !app.Spec.Dbs.Redis.Enabled {
+		return nil
 	}
-	return nil
+	redisSecretData := dbs.RedisCredsData{
+		Namespace:   app.Namespace,
+		Annotations: app.Spec.Annotations,
+		Labels:      app.Spec.Labels,
+		CredsRef:    app.Spec.Dbs.Redis.CredsRef,
+		SvcName:     app.Spec.Dbs.Redis.SvcName,
+	}
+	return append(dbs.RedisCreds(redisSecretData), dbs.AppRedisState(app)...)
 }
 
-func (r *CnvrgAppReconciler) backupsState(app *mlopsv1.CnvrgApp) error {
+// backupsState annotates the pg pvcs with the backup settings for capsule, redis is backed up by the redis backup
+// cron job of the backups component and es by the snapshots cron job of the es component
+func (r *CnvrgAppReconciler) backupsState(ctx context.Context, app *mlopsv1.CnvrgApp) error {
 
 	if app.Spec.Dbs.Pg.Enabled { // pg backups
-		pgPvc := v1core.PersistentVolumeClaim{}
-		pgPvcName := types.NamespacedName{Namespace: app.Namespace, Name: app.Spec.Dbs.Pg.PvcName}
-		if err := r.Get(context.Background(), pgPvcName, &pgPvc); err != nil {
-			r.updateStatusMessage(mlopsv1.Status{Status: mlopsv1.StatusError, Message: err.Error(), Progress: -1}, app)
-			return err
+		pgPvcName := app.Spec.Dbs.Pg.PvcName
+		if app.Spec.Dbs.Pg.HA.Enabled {
+			// the members pvcs are created from the stateful set claim template, the backup is annotated on the first member
+			pgPvcName = fmt.Sprintf("pgdata-%s-0", app.Spec.Dbs.Pg.SvcName)
 		}
-		if err := r.ApplyCapsuleAnnotations(app.Spec.Dbs.Pg.Backup, &pgPvc, "postgresql"); err != nil {
-			r.updateStatusMessage(mlopsv1.Status{Status: mlopsv1.StatusError, Message: err.Error(), Progress: -1}, app)
+		if err := r.annotatePvcBackup(ctx, app.Namespace, pgPvcName, app.Spec.Dbs.Pg.Backup, "postgresql"); err != nil {
 			return err
 		}
 	}
-	return nil
-}
 
-func (r *CnvrgAppReconciler) ingressCheckState(app *mlopsv1.CnvrgApp) error {
-	// apply ingress check state
-	if err := desired.Apply(ingresscheck.IngressCheckState(app), app, r.Client, r.Scheme, appLog); err != nil {
-		r.updateStatusMessage(mlopsv1.Status{Status: mlopsv1.StatusError, Message: err.Error(), Progress: -1}, app)
-		return err
+	if app.Spec.Dbs.Redis.Enabled { // redis is backed up by the redis backup cron job, capsule backups of the redis pvc are disabled
+		if err := r.annotatePvcBackup(ctx, app.Namespace, app.Spec.Dbs.Redis.PvcName, mlopsv1.Backup{}, "redis"); err != nil {
+			return err
+		}
 	}
 
+	if app.Spec.Dbs.Cvat.Enabled { // cvat pg backups
+		if err := r.annotatePvcBackup(ctx, app.Namespace, app.Spec.Dbs.Cvat.Pg.PvcName, app.Spec.Dbs.Cvat.Pg.Backup, "postgresql"); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func (r *CnvrgAppReconciler) monitoringState(app *mlopsv1.CnvrgApp) error {
-
-	// generate monitoring secrets (prometheus, prometheus upstream, and grafana data sources
-	if err := r.generateMonitoringSecrets(app); err != nil {
-		r.updateStatusMessage(mlopsv1.Status{Status: mlopsv1.StatusError, Message: err.Error(), Progress: -1}, app)
-		return err
+// redisBackupState returns the cron job which dumps the redis rdb snapshot with redis-cli --rdb, and uploads it
+// to <namespace>/redis in the bucket, the same job as the scheduled cnvrgBackup of redis
+func redisBackupState(app *mlopsv1.CnvrgApp) []*desired.State {
+	redis := app.Spec.Dbs.Redis
+	if !redis.Enabled || !redis.Backup.Enabled {
+		return nil
 	}
-	// apply app monitoring state
-	if err := desired.Apply(monitoring.AppMonitoringState(app), app, r.Client, r.Scheme, appLog); err != nil {
-		r.updateStatusMessage(mlopsv1.Status{Status: mlopsv1.StatusError, Message: err.Error(), Progress: -1}, app)
+	return backup.BackupState(backup.Data{
+		Namespace:    app.Namespace,
+		Annotations:  app.Spec.Annotations,
+		Labels:       app.Spec.Labels,
+		Name:         redis.SvcName,
+		JobName:      redis.SvcName + "-backup",
+		Schedule:     desired.BackupSchedule(redis.Backup.Period),
+		Rotation:     redis.Backup.Rotation,
+		BucketRef:    redis.Backup.BucketRef,
+		Image:        mlopsv1.DefaultCnvrgBackupSpec().Image,
+		NodeSelector: backupNodeSelector(app),
+		Tenancy:      app.Spec.Tenancy.Enabled,
+		Prefix:       app.Namespace + "/redis",
+		Redis:        redisDump(app),
+	})
+}
+
+func (r *CnvrgAppReconciler) annotatePvcBackup(ctx context.Context, namespace, name string, b mlopsv1.Backup, serviceType string) error {
+	pvc := v1core.PersistentVolumeClaim{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &pvc); err != nil {
 		return err
 	}
-
-	return nil
+	return r.ApplyCapsuleAnnotations(ctx, b, &pvc, serviceType)
 }
 
-func (r *CnvrgAppReconciler) generateMonitoringSecrets(app *mlopsv1.CnvrgApp) error {
+func (r *CnvrgAppReconciler) monitoringState(ctx context.Context, app *mlopsv1.CnvrgApp) ([]*desired.State, error) {
+	log := logf.FromContext(ctx)
+	var state []*desired.State
 
-	if app.Spec.Monitoring.Prometheus.Enabled {
-		appLog.Info("applying monitoring")
-		user := "cnvrg"
-		pass := desired.RandomString()
-		passHash, err := apr1_crypt.New().Generate([]byte(pass), nil)
+	if app.Spec.Monitoring.Grafana.Enabled {
+		// grafana datasource
+		url, user, pass, err := desired.GetPromCredsSecret(ctx, app.Spec.Monitoring.Prometheus.CredsRef, app.Namespace, r.Client, log)
 		if err != nil {
-			appLog.Error(err, "error generating prometheus hash")
-			return err
+			return nil, err
 		}
-		promSecretData := desired.TemplateData{
-			Data: map[string]interface{}{
-				"Namespace":   app.Namespace,
-				"Annotations": app.Spec.Annotations,
-				"Labels":      app.Spec.Labels,
-				"CredsRef":    app.Spec.Monitoring.Prometheus.CredsRef,
-				"User":        user,
-				"Pass":        pass,
-				"PassHash":    fmt.Sprintf("%s:%s", user, passHash),
-				"PromUrl":     fmt.Sprintf("http://%s.%s.svc:%d", app.Spec.Monitoring.Prometheus.SvcName, app.Namespace, app.Spec.Monitoring.Prometheus.Port),
-			},
+		grafanaDatasourceData := monitoring.GrafanaDSData{
+			Namespace:   app.Namespace,
+			Url:         url,
+			User:        user,
+			Pass:        pass,
+			Annotations: app.Spec.Annotations,
+			Labels:      app.Spec.Labels,
 		}
+		state = append(state, monitoring.GrafanaDSState(grafanaDatasourceData)...)
+	}
 
-		appLog.Info("trying to generate prometheus creds (if still doesn't exists...)")
-		if err := desired.Apply(monitoring.PromCreds(promSecretData), app, r.Client, r.Scheme, appLog); err != nil {
-			r.updateStatusMessage(mlopsv1.Status{Status: mlopsv1.StatusError, Message: err.Error(), Progress: -1}, app)
-			return err
-		}
+	return append(state, monitoring.AppMonitoringState(app)...), nil
+}
 
-		if err := r.createUpstreamPrometheusConfig(app); err != nil {
-			r.updateStatusMessage(mlopsv1.Status{Status: mlopsv1.StatusError, Message: err.Error(), Progress: -1}, app)
-			return err
-		}
+// monitoringSecretsState generates monitoring secrets (prometheus and prometheus upstream)
+func (r *CnvrgAppReconciler) monitoringSecretsState(ctx context.Context, app *mlopsv1.CnvrgApp) ([]*desired.State, error) {
+	log := logf.FromContext(ctx)
+
+	if !app.Spec.Monitoring.Prometheus.Enabled {
+		return nil, nil
 	}
 
-	if app.Spec.Monitoring.Grafana.Enabled {
-		// grafana dashboards
-		appLog.Info("applying grafana dashboards ")
-		if err := r.createGrafanaDashboards(app); err != nil {
-			return err
-		}
-		// grafana datasource
-		appLog.Info("applying grafana datasource")
-		url, user, pass, err := desired.GetPromCredsSecret(app.Spec.Monitoring.Prometheus.CredsRef, app.Namespace, r.Client, appLog)
-		if err != nil {
-			r.updateStatusMessage(mlopsv1.Status{Status: mlopsv1.StatusError, Message: err.Error(), Progress: -1}, app)
-			return err
-		}
-		grafanaDatasourceData := desired.TemplateData{
-			Namespace: app.Namespace,
-			Data: map[string]interface{}{
-				"Url":  url,
-				"User": user,
-				"Pass": pass,
-			},
-		}
+	user := "cnvrg"
+	pass := desired.RandomString(ctx)
+	passHash, err := desired.PasswordHash(ctx, pass)
+	if err != nil {
+		log.Error(err, "error generating prometheus hash")
+		return nil, err
+	}
+	promSecretData := monitoring.PromCredsData{
+		Namespace:   app.Namespace,
+		Annotations: app.Spec.Annotations,
+		Labels:      app.Spec.Labels,
+		CredsRef:    app.Spec.Monitoring.Prometheus.CredsRef,
+		User:        user,
+		Pass:        pass,
+		PassHash:    fmt.Sprintf("%s:%s", user, passHash),
+		PromUrl:     fmt.Sprintf("http://%s.%s.svc:%d", app.Spec.Monitoring.Prometheus.SvcName, app.Namespace, app.Spec.Monitoring.Prometheus.Port),
+	}
 
-		if err := desired.Apply(monitoring.GrafanaDSState(grafanaDatasourceData), app, r.Client, r.Scheme, appLog); err != nil {
-			r.updateStatusMessage(mlopsv1.Status{Status: mlopsv1.StatusError, Message: err.Error(), Progress: -1}, app)
-			return err
-		}
+	upstreamState, err := r.upstreamPrometheusConfigState(ctx, app)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	return append(monitoring.PromCreds(promSecretData), upstreamState...), nil
 }
 
-func (r *CnvrgAppReconciler) getCnvrgInfra() (*mlopsv1.CnvrgInfra, error) {
+func (r *CnvrgAppReconciler) getCnvrgInfra(ctx context.Context) (*mlopsv1.CnvrgInfra, error) {
+	log := logf.FromContext(ctx)
 
 	cnvrgAppInfra := &mlopsv1.CnvrgInfraList{}
 
-	if err := r.List(context.Background(), cnvrgAppInfra); err != nil {
-		appLog.Error(err, "can't list CnvrgInfra objects")
+	if err := r.List(ctx, cnvrgAppInfra); err != nil {
+		log.Error(err, "can't list CnvrgInfra objects")
 		return nil, err
 	}
 
 	if len(cnvrgAppInfra.Items) == 0 {
-		appLog.Info("no CnvrgInfra objects was deployed, skipping infra reconciler")
+		log.Info("no CnvrgInfra objects was deployed, skipping infra reconciler")
 		return nil, errors.NewNotFound(schema.GroupResource{Group: "mlops.cnvrg.io", Resource: "CnvrgInfra"}, "cnvrg-infra")
 	}
 
-	return &cnvrgAppInfra.Items[0], nil
+	return effectiveCnvrgInfra(ctx, &cnvrgAppInfra.Items[0], r.Client)
 }
 
-func (r *CnvrgAppReconciler) createUpstreamPrometheusConfig(app *mlopsv1.CnvrgApp) error {
-	infra, err := r.getCnvrgInfra()
+func (r *CnvrgAppReconciler) upstreamPrometheusConfigState(ctx context.Context, app *mlopsv1.CnvrgApp) ([]*desired.State, error) {
+	log := logf.FromContext(ctx)
+	infra, err := r.getCnvrgInfra(ctx)
 	if err != nil {
-		appLog.Error(err, "can't get cnvrgInfra object ")
-		return err
+		log.Error(err, "can't get cnvrgInfra object ")
+		return nil, err
 	}
 
-	_, user, pass, err := desired.GetPromCredsSecret(infra.Spec.Monitoring.Prometheus.CredsRef, infra.Spec.InfraNamespace, r.Client, appLog)
+	_, user, pass, err := desired.GetPromCredsSecret(ctx, infra.Spec.Monitoring.Prometheus.CredsRef, infra.Spec.InfraNamespace, r.Client, log)
 	if err != nil {
-		appLog.Error(err, "can't get cnvrgInfra prometheus creds")
-		return err
-	}
-
-	promUpstreamData := desired.TemplateData{
-		Data: map[string]interface{}{
-			"Namespace":   app.Namespace,
-			"Annotations": app.Spec.Annotations,
-			"Labels":      app.Spec.Labels,
-			"CredsRef":    app.Spec.Monitoring.Prometheus.UpstreamRef,
-			"User":        user,
-			"Pass":        pass,
-			"Upstream":    fmt.Sprintf("prometheus-operated.%s.svc:%d", infra.Spec.InfraNamespace, infra.Spec.Monitoring.Prometheus.Port),
-		},
+		log.Error(err, "can't get cnvrgInfra prometheus creds")
+		return nil, err
 	}
 
-	if err := desired.Apply(monitoring.PromUpstreamCreds(promUpstreamData), app, r.Client, r.Scheme, appLog); err != nil {
-		r.updateStatusMessage(mlopsv1.Status{Status: mlopsv1.StatusError, Message: err.Error(), Progress: -1}, app)
-		return err
+	promUpstreamData := monitoring.PromUpstreamData{
+		Namespace:   app.Namespace,
+		Annotations: app.Spec.Annotations,
+		Labels:      app.Spec.Labels,
+		CredsRef:    app.Spec.Monitoring.Prometheus.UpstreamRef,
+		User:        user,
+		Pass:        pass,
+		Upstream:    fmt.Sprintf("prometheus-operated.%s.svc:%d", infra.Spec.InfraNamespace, infra.Spec.Monitoring.Prometheus.Port),
 	}
 
-	return nil
+	return monitoring.PromUpstreamCreds(promUpstreamData), nil
 }
 
-func (r *CnvrgAppReconciler) getKibanaConfigSecretData(app *mlopsv1.CnvrgApp) (*desired.TemplateData, error) {
+func (r *CnvrgAppReconciler) getKibanaConfigSecretData(ctx context.Context, app *mlopsv1.CnvrgApp) (*logging.KibanaConfData, error) {
+	log := logf.FromContext(ctx)
 	kibanaHost := "0.0.0.0"
 	kibanaPort := strconv.Itoa(app.Spec.Logging.Kibana.Port)
-	esUser, esPass, err := r.getEsCredsSecret(app)
+	esUser, esPass, err := r.getEsCredsSecret(ctx, app)
 	if err != nil {
-		appLog.Error(err, "can't fetch es creds")
+		log.Error(err, "can't fetch es creds")
 		return nil, err
 	}
 	if app.Spec.SSO.Enabled {
 		kibanaHost = "127.0.0.1"
 		kibanaPort = "3000"
 	}
-	return &desired.TemplateData{
-		Namespace: app.Namespace,
-		Data: map[string]interface{}{
-			"Host":        kibanaHost,
-			"Port":        kibanaPort,
-			"EsHost":      fmt.Sprintf("http://%s.%s.svc:%d", app.Spec.Dbs.Es.SvcName, app.Namespace, app.Spec.Dbs.Es.Port),
-			"EsUser":      esUser,
-			"EsPass":      esPass,
-			"Annotations": app.Spec.Annotations,
-			"Labels":      app.Spec.Labels,
-		},
+	return &logging.KibanaConfData{
+		Namespace:   app.Namespace,
+		Host:        kibanaHost,
+		Port:        kibanaPort,
+		EsHost:      esHostUrl(app),
+		EsUser:      esUser,
+		EsPass:      esPass,
+		Annotations: app.Spec.Annotations,
+		Labels:      app.Spec.Labels,
 	}, nil
 
 }
 
-func (r *CnvrgAppReconciler) createGrafanaDashboards(cnvrgApp *mlopsv1.CnvrgApp) error {
+func (r *CnvrgAppReconciler) createGrafanaDashboards(ctx context.Context, cnvrgApp *mlopsv1.CnvrgApp) error {
+	log := logf.FromContext(ctx)
 
 	if !cnvrgApp.Spec.Monitoring.Grafana.Enabled {
-		appLog.Info("grafana disabled, skipping grafana deployment")
+		log.Info("grafana disabled, skipping grafana deployment")
 		return nil
 	}
 	basePath := "/pkg/monitoring/tmpl/grafana/dashboards-data/"
 	for _, dashboard := range desired.GrafanaAppDashboards {
 		f, err := pkger.Open(basePath + dashboard)
 		if err != nil {
-			appLog.Error(err, "error reading path", "path", dashboard)
+			log.Error(err, "error reading path", "path", dashboard)
 			return err
 		}
 		b, err := ioutil.ReadAll(f)
 		if err != nil {
-			appLog.Error(err, "error reading", "file", dashboard)
+			log.Error(err, "error reading", "file", dashboard)
 			return err
 		}
 		cm := &v1core.ConfigMap{
@@ -756,14 +848,14 @@ func (r *CnvrgAppReconciler) createGrafanaDashboards(cnvrgApp *mlopsv1.CnvrgApp)
 			Data: map[string]string{filepath.Base(f.Name()): string(b)},
 		}
 		if err := ctrl.SetControllerReference(cnvrgApp, cm, r.Scheme); err != nil {
-			appLog.Error(err, "error setting controller reference", "file", f.Name())
+			log.Error(err, "error setting controller reference", "file", f.Name())
 			return err
 		}
-		if err := r.Create(context.Background(), cm); err != nil && errors.IsAlreadyExists(err) {
-			appLog.V(1).Info("grafana dashboard already exists", "file", dashboard)
+		if err := r.Create(ctx, cm); err != nil && errors.IsAlreadyExists(err) {
+			log.V(1).Info("grafana dashboard already exists", "file", dashboard)
 			continue
 		} else if err != nil {
-			appLog.Error(err, "error reading", "file", dashboard)
+			log.Error(err, "error reading", "file", dashboard)
 			return err
 		}
 	}
@@ -772,8 +864,9 @@ func (r *CnvrgAppReconciler) createGrafanaDashboards(cnvrgApp *mlopsv1.CnvrgApp)
 
 }
 
-func (r *CnvrgAppReconciler) addFluentbitConfiguration(cnvrgApp *mlopsv1.CnvrgApp) error {
-	infra, err := r.getCnvrgInfra()
+func (r *CnvrgAppReconciler) addFluentbitConfiguration(ctx context.Context, cnvrgApp *mlopsv1.CnvrgApp) error {
+	log := logf.FromContext(ctx)
+	infra, err := r.getCnvrgInfra(ctx)
 	if err != nil {
 		return err
 	}
@@ -781,20 +874,20 @@ func (r *CnvrgAppReconciler) addFluentbitConfiguration(cnvrgApp *mlopsv1.CnvrgAp
 	name := types.NamespacedName{Name: mlopsv1.InfraReconcilerCm, Namespace: infra.Spec.InfraNamespace}
 	infraReconcilerCm := &v1core.ConfigMap{}
 
-	esUser, esPass, err := r.getEsCredsSecret(cnvrgApp)
+	esUser, esPass, err := r.getEsCredsSecret(ctx, cnvrgApp)
 	if err != nil {
-		appLog.Error(err, "failed to fetch es creds")
+		log.Error(err, "failed to fetch es creds")
 		return err
 	}
 
 	appInstance := mlopsv1.AppInstance{SpecName: cnvrgApp.Name, SpecNs: cnvrgApp.Namespace, EsUser: esUser, EsPass: esPass}
 	appInstanceBytes, err := json.Marshal(appInstance)
 	if err != nil {
-		appLog.Error(err, "failed to marshal app instance ")
+		log.Error(err, "failed to marshal app instance ")
 		return err
 	}
-	if err := r.Get(context.Background(), name, infraReconcilerCm); err != nil {
-		appLog.Error(err, "can't get reconciler cm", "name", name)
+	if err := r.Get(ctx, name, infraReconcilerCm); err != nil {
+		log.Error(err, "can't get reconciler cm", "name", name)
 		return err
 	}
 	if infraReconcilerCm.Data == nil {
@@ -802,53 +895,59 @@ func (r *CnvrgAppReconciler) addFluentbitConfiguration(cnvrgApp *mlopsv1.CnvrgAp
 	} else {
 		infraReconcilerCm.Data[cnvrgApp.Namespace] = string(appInstanceBytes)
 	}
-	if err := r.Update(context.Background(), infraReconcilerCm); err != nil {
-		appLog.Error(err, "can't update cm", "cm", name)
+	if err := r.Update(ctx, infraReconcilerCm); err != nil {
+		log.Error(err, "can't update cm", "cm", name)
 		return err
 	}
 	return nil
 }
 
-func (r *CnvrgAppReconciler) removeFluentbitConfiguration(cnvrgApp *mlopsv1.CnvrgApp) error {
-	infra, err := r.getCnvrgInfra()
+func (r *CnvrgAppReconciler) removeFluentbitConfiguration(ctx context.Context, cnvrgApp *mlopsv1.CnvrgApp) error {
+	log := logf.FromContext(ctx)
+	infra, err := r.getCnvrgInfra(ctx)
 	if err != nil && errors.IsNotFound(err) {
-		appLog.Info("cnvrg infra not found, skipping fluentbit cleanup")
+		log.Info("cnvrg infra not found, skipping fluentbit cleanup")
 		return nil
 	} else if err != nil {
-		appLog.Info("error getting cnvrg infra, trying reconcile again...")
+		log.Info("error getting cnvrg infra, trying reconcile again...")
 		return err
 	}
 	name := types.NamespacedName{Name: mlopsv1.InfraReconcilerCm, Namespace: infra.Spec.InfraNamespace}
 	infraReconcilerCm := &v1core.ConfigMap{}
-	if err := r.Get(context.Background(), name, infraReconcilerCm); err != nil && errors.IsNotFound(err) {
-		appLog.Info("infra reconciler configmap not found, skipping fluentbit cleanup")
+	if err := r.Get(ctx, name, infraReconcilerCm); err != nil && errors.IsNotFound(err) {
+		log.Info("infra reconciler configmap not found, skipping fluentbit cleanup")
 		return nil
 	} else if err != nil {
-		appLog.Error(err, "can't get reconciler cm", "name", name)
+		log.Error(err, "can't get reconciler cm", "name", name)
 		return err
 	}
 	delete(infraReconcilerCm.Data, cnvrgApp.Namespace)
-	if err := r.Update(context.Background(), infraReconcilerCm); err != nil {
-		appLog.Error(err, "can't update cm", "cm", name)
+	if err := r.Update(ctx, infraReconcilerCm); err != nil {
+		log.Error(err, "can't update cm", "cm", name)
 		return err
 	}
 	return nil
 }
 
-func (r *CnvrgAppReconciler) updateStatusMessage(status mlopsv1.Status, app *mlopsv1.CnvrgApp) {
+func (r *CnvrgAppReconciler) updateStatusMessage(ctx context.Context, status mlopsv1.Status, app *mlopsv1.CnvrgApp) {
+	log := logf.FromContext(ctx)
 
 	if app.Status.Status == mlopsv1.StatusRemoving {
-		appLog.Info("skipping status update, current cnvrg spec under removing status...")
+		log.Info("skipping status update, current cnvrg spec under removing status...")
 		return
 	}
 	if status.Status == mlopsv1.StatusError {
 		msg := fmt.Sprintf("%s/%s error acoured during reconcile", app.GetNamespace(), app.GetName())
 		r.recorder.Event(app, "Warning", "ReconcileError", msg)
 	}
-	ctx := context.Background()
+	// conditions are observed for the reconciled generation
+	generation := app.Generation
+	if status.StackReadiness != nil {
+		observeReadiness(app, status.Progress, status.StackReadiness)
+	}
 	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
 		name := types.NamespacedName{Namespace: app.Namespace, Name: app.Name}
-		app, err := r.getCnvrgAppSpec(name)
+		app, err := r.getCnvrgAppSpec(ctx, name)
 		if err != nil {
 			return err
 		}
@@ -859,141 +958,110 @@ func (r *CnvrgAppReconciler) updateStatusMessage(status mlopsv1.Status, app *mlo
 		}
 		if status.StackReadiness != nil {
 			app.Status.StackReadiness = status.StackReadiness
+			readinessConditions(&app.Status.Conditions, status.StackReadiness, appReadinessConditions, generation)
 		}
+		setConditions(&app.Status.Conditions, status.Conditions, generation)
 		err = r.Status().Update(ctx, app)
 		return err
 	})
 	if err != nil {
-		appLog.Error(err, "can't update status")
+		log.Error(err, "can't update status")
 	}
 
 }
 
-func (r *CnvrgAppReconciler) syncCnvrgAppSpec(name types.NamespacedName) (bool, error) {
-
-	appLog.Info("synchronizing cnvrgApp spec")
-
-	// Fetch current cnvrgApp spec
-	cnvrgApp, err := r.getCnvrgAppSpec(name)
-	if err != nil {
-		return false, err
-	}
-	if cnvrgApp == nil {
-		return false, nil // probably cnvrgapp was removed
-	}
-	appLog = r.Log.WithValues("name", name, "ns", cnvrgApp.Namespace)
-
-	// Get default cnvrgApp spec
-	desiredSpec := mlopsv1.DefaultCnvrgAppSpec()
+// desiredCnvrgAppSpec merges the cnvrgApp spec into the default spec of the cnvrgApp profile
+func desiredCnvrgAppSpec(ctx context.Context, cnvrgApp *mlopsv1.CnvrgApp, infra *mlopsv1.CnvrgInfra, clientset client.Client) (mlopsv1.CnvrgAppSpec, error) {
+	log := logf.FromContext(ctx)
 
-	infra, err := r.getCnvrgInfra()
+	// Get default cnvrgApp spec, sized by the profile
+	desiredSpec, err := mlopsv1.DefaultCnvrgAppSpecForProfile(cnvrgApp.Spec.Profile)
 	if err != nil {
-		appLog.Error(err, "can't get cnvrg infra")
-		//return false, err
+		log.Error(err, "can't apply profile", "profile", cnvrgApp.Spec.Profile)
+		return desiredSpec, err
 	}
 
-	if err := calculateAndApplyAppDefaults(cnvrgApp, &desiredSpec, infra, r.Client); err != nil {
-		appLog.Error(err, "can't calculate defaults")
-		return false, err
+	if err := calculateAndApplyAppDefaults(ctx, cnvrgApp, &desiredSpec, infra, clientset); err != nil {
+		log.Error(err, "can't calculate defaults")
+		return desiredSpec, err
 	}
 
 	// Merge current cnvrgApp spec into default spec ( make it indeed desiredSpec )
 	if err := mergo.Merge(&desiredSpec, cnvrgApp.Spec, mergo.WithOverride, mergo.WithTransformers(cnvrgSpecBoolTransformer{})); err != nil {
-		appLog.Error(err, "can't merge")
-		return false, err
-	}
-
-	if viper.GetBool("verbose") {
-
-		if diff, equal := messagediff.PrettyDiff(desiredSpec, cnvrgApp.Spec); !equal {
-			appLog.Info("diff between desiredSpec and actual")
-			appLog.Info(diff)
-		}
-
-		if diff, equal := messagediff.PrettyDiff(cnvrgApp.Spec, desiredSpec); !equal {
-			appLog.Info("diff between actual and desired")
-			appLog.Info(diff)
-		}
-
-	}
-
-	equal := reflect.DeepEqual(desiredSpec, cnvrgApp.Spec)
-	if !equal {
-		appLog.Info("states are not equals, syncing and requeuing")
-		cnvrgApp.Spec = desiredSpec
-		if err := r.Update(context.Background(), cnvrgApp); err != nil && errors.IsConflict(err) {
-			appLog.Info("conflict updating cnvrgApp object, requeue for reconciliations...")
-			return true, nil
-		} else if err != nil {
-			return false, err
-		}
-		return equal, nil
+		log.Error(err, "can't merge")
+		return desiredSpec, err
 	}
+	applyEsHeap(cnvrgApp, &desiredSpec)
 
-	appLog.Info("states are equals, no need to sync")
-	return equal, nil
+	return desiredSpec, nil
 }
 
-func (r *CnvrgAppReconciler) getCnvrgAppSpec(namespacedName types.NamespacedName) (*mlopsv1.CnvrgApp, error) {
-	ctx := context.Background()
+func (r *CnvrgAppReconciler) getCnvrgAppSpec(ctx context.Context, namespacedName types.NamespacedName) (*mlopsv1.CnvrgApp, error) {
+	log := logf.FromContext(ctx)
 	var app mlopsv1.CnvrgApp
 	if err := r.Get(ctx, namespacedName, &app); err != nil {
 		if errors.IsNotFound(err) {
-			appLog.Info("unable to fetch CnvrgApp, probably cr was deleted")
+			log.Info("unable to fetch CnvrgApp, probably cr was deleted")
 			return nil, nil
 		}
-		appLog.Error(err, "unable to fetch CnvrgApp")
+		log.Error(err, "unable to fetch CnvrgApp")
 		return nil, err
 	}
 
 	return &app, nil
 }
 
-func (r *CnvrgAppReconciler) cleanup(cnvrgApp *mlopsv1.CnvrgApp) error {
+func (r *CnvrgAppReconciler) cleanup(ctx context.Context, cnvrgApp *mlopsv1.CnvrgApp) error {
+	log := logf.FromContext(ctx)
 
-	appLog.Info("running finalizer cleanup")
+	log.Info("running finalizer cleanup")
 
 	// remove cnvrg-db-init
-	if err := r.cleanupDbInitCm(cnvrgApp); err != nil {
+	if err := r.cleanupDbInitCm(ctx, cnvrgApp); err != nil {
 		return err
 	}
 
 	// update infra reconciler cm
-	if err := r.removeFluentbitConfiguration(cnvrgApp); err != nil {
+	if err := r.removeFluentbitConfiguration(ctx, cnvrgApp); err != nil {
 		if err.Error() == "no CnvrgInfra objects was deployed, skipping infra reconciler" {
-			appLog.Info("cnvrgInfra object not found, no need to trigger infra reconciler")
+			log.Info("cnvrgInfra object not found, no need to trigger infra reconciler")
 		} else {
 			return err
 		}
 	}
 
 	// cleanup pvc
-	if err := r.cleanupPVCs(); err != nil {
+	if err := r.cleanupPVCs(ctx); err != nil {
+		return err
+	}
+
+	// cleanup patroni state
+	if err := r.cleanupPgHA(ctx, cnvrgApp); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (r *CnvrgAppReconciler) cleanupPVCs() error {
+func (r *CnvrgAppReconciler) cleanupPVCs(ctx context.Context) error {
+	log := logf.FromContext(ctx)
 	if !viper.GetBool("cleanup-pvc") {
-		appLog.Info("cleanup-pvc is false, skipping pvc deletion!")
+		log.Info("cleanup-pvc is false, skipping pvc deletion!")
 		return nil
 	}
-	appLog.Info("running pvc cleanup")
-	ctx := context.Background()
+	log.Info("running pvc cleanup")
 	pvcList := v1core.PersistentVolumeClaimList{}
 	if err := r.List(ctx, &pvcList); err != nil {
-		appLog.Error(err, "failed cleanup pvcs")
+		log.Error(err, "failed cleanup pvcs")
 		return err
 	}
 	for _, pvc := range pvcList.Items {
 		if _, ok := pvc.ObjectMeta.Labels["app"]; ok {
 			if pvc.ObjectMeta.Labels["app"] == "prometheus" || pvc.ObjectMeta.Labels["app"] == "elasticsearch" {
 				if err := r.Delete(ctx, &pvc); err != nil && errors.IsNotFound(err) {
-					appLog.Info("pvc already deleted")
+					log.Info("pvc already deleted")
 				} else if err != nil {
-					appLog.Error(err, "error deleting prometheus pvc")
+					log.Error(err, "error deleting prometheus pvc")
 					return err
 				}
 			}
@@ -1002,22 +1070,71 @@ func (r *CnvrgAppReconciler) cleanupPVCs() error {
 	return nil
 }
 
-func (r *CnvrgAppReconciler) cleanupDbInitCm(desiredSpec *mlopsv1.CnvrgApp) error {
-	appLog.Info("running cnvrg-db-init cleanup")
-	ctx := context.Background()
+// cleanupPgHA removes the patroni cluster state config maps, a leftover state prevents a new cluster
+// with the same name from bootstrapping, the members pvcs are removed only when cleanup-pvc is set
+func (r *CnvrgAppReconciler) cleanupPgHA(ctx context.Context, cnvrgApp *mlopsv1.CnvrgApp) error {
+	log := logf.FromContext(ctx)
+	if !cnvrgApp.Spec.Dbs.Pg.Enabled || !cnvrgApp.Spec.Dbs.Pg.HA.Enabled {
+		return nil
+	}
+	log.Info("running pg ha cleanup")
+	ns := client.InNamespace(cnvrgApp.Namespace)
+	scope := client.MatchingLabels{"app": cnvrgApp.Spec.Dbs.Pg.SvcName, pgScopeLabel: cnvrgApp.Spec.Dbs.Pg.SvcName}
+	if err := r.DeleteAllOf(ctx, &v1core.ConfigMap{}, ns, scope); err != nil {
+		log.Error(err, "error deleting patroni config maps")
+		return err
+	}
+	if !viper.GetBool("cleanup-pvc") {
+		return nil
+	}
+	members := client.MatchingLabels{"app": cnvrgApp.Spec.Dbs.Pg.SvcName}
+	if err := r.DeleteAllOf(ctx, &v1core.PersistentVolumeClaim{}, ns, members); err != nil {
+		log.Error(err, "error deleting pg ha pvcs")
+		return err
+	}
+	return nil
+}
+
+// checkPgModeSwitch refuses to switch an existing pg between the single instance and the ha modes,
+// the new mode would bootstrap an empty database next to the pvc of the other mode. The webhook rejects
+// the switch as well, but the webhooks are optional
+func checkPgModeSwitch(ctx context.Context, c client.Reader, app *mlopsv1.CnvrgApp) error {
+	if !app.Spec.Dbs.Pg.Enabled {
+		return nil
+	}
+	pvcName := app.Spec.Dbs.Pg.PvcName
+	if !app.Spec.Dbs.Pg.HA.Enabled {
+		pvcName = fmt.Sprintf("pgdata-%s-0", app.Spec.Dbs.Pg.SvcName)
+	}
+	pvc := &v1core.PersistentVolumeClaim{}
+	err := c.Get(ctx, types.NamespacedName{Name: pvcName, Namespace: app.Namespace}, pvc)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("spec.dbs.pg.ha.enabled: pg can't be switched between the single instance and the ha modes while the pvc %s exists, "+
+		"the new mode starts with an empty database, dump the database and remove the pvc before the switch", pvcName)
+}
+
+func (r *CnvrgAppReconciler) cleanupDbInitCm(ctx context.Context, desiredSpec *mlopsv1.CnvrgApp) error {
+	log := logf.FromContext(ctx)
+	log.Info("running cnvrg-db-init cleanup")
 	dbInitCm := &v1core.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cnvrg-db-init", Namespace: desiredSpec.Namespace}}
 	err := r.Delete(ctx, dbInitCm)
 	if err != nil && errors.IsNotFound(err) {
-		appLog.Info("no need to delete cnvrg-db-init, cm not found")
+		log.Info("no need to delete cnvrg-db-init, cm not found")
 	} else {
-		appLog.Error(err, "error deleting cnvrg-db-init")
+		log.Error(err, "error deleting cnvrg-db-init")
 		return err
 	}
 	return nil
 }
 
-func (r *CnvrgAppReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	appLog = r.Log.WithValues("initializing", "crds")
+// SetupWithManager registers the controller, ctx is the manager context, used by the watches map functions
+func (r *CnvrgAppReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
+	log := r.Log.WithValues("initializing", "crds")
 
 	appPredicate := predicate.Funcs{
 
@@ -1028,7 +1145,7 @@ func (r *CnvrgAppReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		},
 
 		UpdateFunc: func(e event.UpdateEvent) bool {
-			infraLog.V(1).Info("received update event", "objectName", e.ObjectNew.GetName())
+			log.V(1).Info("received update event", "objectName", e.ObjectNew.GetName())
 			shouldReconcile := e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration()
 			if shouldReconcile {
 				msg := fmt.Sprintf("cnvrgapp: %s/%s has been updated", e.ObjectNew.GetNamespace(), e.ObjectNew.GetName())
@@ -1040,7 +1157,7 @@ func (r *CnvrgAppReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		DeleteFunc: func(deleteEvent event.DeleteEvent) bool {
 			msg := fmt.Sprintf("cnvrgapp: %s/%s has been deleted", deleteEvent.Object.GetNamespace(), deleteEvent.Object.GetName())
 			r.recorder.Event(deleteEvent.Object, "Normal", "SuccessfulDelete", msg)
-			infraLog.V(1).Info("received delete event", "objectName", deleteEvent.Object.GetName())
+			log.V(1).Info("received delete event", "objectName", deleteEvent.Object.GetName())
 			return !deleteEvent.DeleteStateUnknown
 		},
 	}
@@ -1057,6 +1174,7 @@ func (r *CnvrgAppReconciler) SetupWithManager(mgr ctrl.Manager) error {
 				"systemkiq",
 				app.ControlPlane.WebApp.SvcName,
 				app.Dbs.Pg.SvcName,
+				app.Dbs.Pg.SvcName + "-upgrade",
 				app.Dbs.Minio.SvcName,
 				app.Dbs.Redis.SvcName,
 				app.Dbs.Es.SvcName,
@@ -1065,20 +1183,25 @@ func (r *CnvrgAppReconciler) SetupWithManager(mgr ctrl.Manager) error {
 				if containsString(healthCheckWorkloads, e.ObjectNew.GetName()) {
 					return true
 				}
+				// the preflight jobs are named by the checked configuration
+				if _, ok := e.ObjectNew.GetLabels()[preflightLabel]; ok {
+					return true
+				}
 			}
-			infraLog.V(1).Info("received update event", "objectName", e.ObjectNew.GetName())
+			log.V(1).Info("received update event", "objectName", e.ObjectNew.GetName())
 			return false
 		},
 
 		DeleteFunc: func(deleteEvent event.DeleteEvent) bool {
-			infraLog.V(1).Info("received delete event", "objectName", deleteEvent.Object.GetName())
+			log.V(1).Info("received delete event", "objectName", deleteEvent.Object.GetName())
 			return true
 		},
 	}
 	r.recorder = mgr.GetEventRecorderFor("cnvrgapp")
 	cnvrgAppController := ctrl.
 		NewControllerManagedBy(mgr).
-		For(&mlopsv1.CnvrgApp{}, builder.WithPredicates(appPredicate))
+		For(&mlopsv1.CnvrgApp{}, builder.WithPredicates(appPredicate)).
+		Watches(&source.Kind{Type: &v1core.Secret{}}, handler.EnqueueRequestsFromMapFunc(r.secretRefRequests(ctx)))
 
 	for _, v := range desired.Kinds {
 
@@ -1096,65 +1219,13 @@ func (r *CnvrgAppReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		cnvrgAppController.Owns(u, builder.WithPredicates(appOwnsPredicate))
 	}
 
-	appLog.Info(fmt.Sprintf("max concurrent reconciles: %d", viper.GetInt("max-concurrent-reconciles")))
+	log.Info(fmt.Sprintf("max concurrent reconciles: %d", viper.GetInt("max-concurrent-reconciles")))
 	return cnvrgAppController.
 		WithOptions(controller.Options{MaxConcurrentReconciles: viper.GetInt("max-concurrent-reconciles")}).
 		Complete(r)
 }
 
-func (r *CnvrgAppReconciler) CheckJobReadiness(name types.NamespacedName) (bool, error) {
-	ctx := context.Background()
-	job := &v1batch.Job{}
-
-	if err := r.Get(ctx, name, job); err != nil && errors.IsNotFound(err) {
-		return false, nil
-	} else if err != nil {
-		return false, err
-	}
-
-	if job.Status.Succeeded >= 1 {
-		return true, nil
-	}
-
-	return false, nil
-}
-
-func (r *CnvrgAppReconciler) CheckDeploymentReadiness(name types.NamespacedName) (bool, error) {
-	ctx := context.Background()
-	deployment := &v1apps.Deployment{}
-
-	if err := r.Get(ctx, name, deployment); err != nil && errors.IsNotFound(err) {
-		return false, nil
-	} else if err != nil {
-		return false, err
-	}
-
-	if deployment.Status.Replicas == deployment.Status.ReadyReplicas {
-		return true, nil
-	}
-
-	return false, nil
-}
-
-func (r *CnvrgAppReconciler) CheckStatefulSetReadiness(name types.NamespacedName) (bool, error) {
-
-	ctx := context.Background()
-	sts := &v1apps.StatefulSet{}
-
-	if err := r.Get(ctx, name, sts); err != nil && errors.IsNotFound(err) {
-		return false, nil
-	} else if err != nil {
-		return false, err
-	}
-
-	if sts.Status.Replicas == sts.Status.ReadyReplicas {
-		return true, nil
-	}
-
-	return false, nil
-}
-
-func (r *CnvrgAppReconciler) ApplyCapsuleAnnotations(b mlopsv1.Backup, pvc *v1core.PersistentVolumeClaim, serviceType string) error {
+func (r *CnvrgAppReconciler) ApplyCapsuleAnnotations(ctx context.Context, b mlopsv1.Backup, pvc *v1core.PersistentVolumeClaim, serviceType string) error {
 	if pvc.Annotations == nil {
 		pvc.Annotations = map[string]string{}
 	}
@@ -1168,7 +1239,7 @@ func (r *CnvrgAppReconciler) ApplyCapsuleAnnotations(b mlopsv1.Backup, pvc *v1co
 	pvc.Annotations["capsule.mlops.cnvrg.io/rotation"] = strconv.Itoa(b.Rotation)
 	pvc.Annotations["capsule.mlops.cnvrg.io/period"] = b.Period
 
-	if err := r.Update(context.Background(), pvc); err != nil {
+	if err := r.Update(ctx, pvc); err != nil {
 		return err
 	}
 	return nil
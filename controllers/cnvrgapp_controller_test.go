@@ -8,15 +8,23 @@ import (
 	"github.com/AccessibleAI/cnvrg-operator/pkg/networking"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/spf13/viper"
 	"github.com/teris-io/shortid"
 	v1 "k8s.io/api/apps/v1"
 	"k8s.io/api/autoscaling/v2beta1"
+	v1batch "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
 	"reflect"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
 	"sort"
 	"strings"
 	"time"
@@ -307,6 +315,108 @@ var _ = Describe("CnvrgApp controller", func() {
 			Expect(pgCreds.Data["POSTGRESQL_PASSWORD"]).Should(Equal(pgCredsAfterReconcile.Data["POSTGRESQL_PASSWORD"]))
 			Expect(k8sClient.Delete(ctx, testApp)).Should(Succeed())
 		})
+		It("PG HA", func() {
+			ns := createNs()
+			ctx := context.Background()
+			testApp := getDefaultTestAppSpec(ns)
+			testApp.Spec.Dbs.Pg.Enabled = true
+			testApp.Spec.Dbs.Pg.HA.Enabled = true
+
+			sts := v1.StatefulSet{}
+			Expect(k8sClient.Create(ctx, testApp)).Should(Succeed())
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: testApp.Spec.Dbs.Pg.SvcName, Namespace: ns}, &sts)
+				if err != nil {
+					return false
+				}
+				return true
+			}, timeout, interval).Should(BeTrue())
+			Expect(*sts.Spec.Replicas).Should(BeEquivalentTo(3))
+			Expect(sts.Spec.VolumeClaimTemplates[0].Spec.Resources.Requests.Storage().String()).Should(Equal(testApp.Spec.Dbs.Pg.StorageSize))
+
+			// the service points only at the primary
+			svc := corev1.Service{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: testApp.Spec.Dbs.Pg.SvcName, Namespace: ns}, &svc)).Should(Succeed())
+			Expect(svc.Spec.Selector).Should(HaveKeyWithValue("spilo-role", "master"))
+
+			deployment := v1.Deployment{}
+			err := k8sClient.Get(ctx, types.NamespacedName{Name: testApp.Spec.Dbs.Pg.SvcName, Namespace: ns}, &deployment)
+			Expect(errors.IsNotFound(err)).Should(BeTrue())
+			Expect(k8sClient.Delete(ctx, testApp)).Should(Succeed())
+		})
+		It("PG HA is refused next to the single instance PVC", func() {
+			ns := createNs()
+			ctx := context.Background()
+			testApp := getDefaultTestAppSpec(ns)
+			testApp.Spec.Dbs.Pg.Enabled = true
+			testApp.Spec.Dbs.Pg.HA.Enabled = true
+
+			// the pvc of the single instance pg, left by the install before the switch
+			pvc := &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: testApp.Spec.Dbs.Pg.PvcName, Namespace: ns},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(testApp.Spec.Dbs.Pg.StorageSize)},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, pvc)).Should(Succeed())
+			Expect(k8sClient.Create(ctx, testApp)).Should(Succeed())
+
+			appRes := mlopsv1.CnvrgApp{}
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: testApp.Name, Namespace: ns}, &appRes)
+				if err != nil {
+					return false
+				}
+				reconciled := meta.FindStatusCondition(appRes.Status.Conditions, mlopsv1.ConditionReconciled)
+				return reconciled != nil && reconciled.Reason == mlopsv1.ReasonReconcileFailed
+			}, timeout, interval).Should(BeTrue())
+			reconciled := meta.FindStatusCondition(appRes.Status.Conditions, mlopsv1.ConditionReconciled)
+			Expect(reconciled.Message).Should(ContainSubstring(testApp.Spec.Dbs.Pg.PvcName))
+
+			sts := v1.StatefulSet{}
+			err := k8sClient.Get(ctx, types.NamespacedName{Name: testApp.Spec.Dbs.Pg.SvcName, Namespace: ns}, &sts)
+			Expect(errors.IsNotFound(err)).Should(BeTrue())
+			Expect(k8sClient.Delete(ctx, testApp)).Should(Succeed())
+		})
+		It("PG HA readiness follows the primary", func() {
+			ctx := context.Background()
+			name := types.NamespacedName{Name: "postgres", Namespace: "cnvrg"}
+			member := func(name, role string, ready corev1.ConditionStatus) *corev1.Pod {
+				return &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "cnvrg", Labels: map[string]string{
+						"app": "postgres", "cluster-name": "postgres", "spilo-role": role,
+					}},
+					Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}}},
+				}
+			}
+
+			// replicas without a primary, e.g. during a failover
+			c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+				member("postgres-1", "replica", corev1.ConditionTrue),
+				member("postgres-2", "replica", corev1.ConditionTrue),
+			).Build()
+			ready, err := checkPgPrimaryReadiness(ctx, c, name)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ready).Should(BeFalse())
+
+			// primary which is not ready yet
+			Expect(c.Create(ctx, member("postgres-0", "master", corev1.ConditionFalse))).Should(Succeed())
+			ready, err = checkPgPrimaryReadiness(ctx, c, name)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ready).Should(BeFalse())
+
+			// ready primary
+			primary := &corev1.Pod{}
+			Expect(c.Get(ctx, types.NamespacedName{Name: "postgres-0", Namespace: "cnvrg"}, primary)).Should(Succeed())
+			primary.Status.Conditions[0].Status = corev1.ConditionTrue
+			Expect(c.Update(ctx, primary)).Should(Succeed())
+			ready, err = checkPgPrimaryReadiness(ctx, c, name)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ready).Should(BeTrue())
+		})
 
 	})
 
@@ -2303,6 +2413,684 @@ var _ = Describe("CnvrgApp controller", func() {
 
 	})
 
+	Context("Test Server Side Apply", func() {
+
+		It("Labels added by other field managers are preserved", func() {
+			ns := createNs()
+			ctx := context.Background()
+			testApp := getDefaultTestAppSpec(ns)
+			testApp.Spec.Dbs.Pg.Enabled = true
+			Expect(k8sClient.Create(ctx, testApp)).Should(Succeed())
+
+			deployment := v1.Deployment{}
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: testApp.Spec.Dbs.Pg.SvcName, Namespace: ns}, &deployment)
+				if err != nil {
+					return false
+				}
+				return true
+			}, timeout, interval).Should(BeTrue())
+			// label the deployment on behalf of another field manager
+			deployment.Labels["external"] = "true"
+			Expect(k8sClient.Update(ctx, &deployment)).Should(Succeed())
+
+			// enforce reconcile loop
+			appRes := mlopsv1.CnvrgApp{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "cnvrgapp", Namespace: ns}, &appRes)).Should(Succeed())
+			appRes.Spec.Labels = map[string]string{"foo": "bar"}
+			Expect(k8sClient.Update(ctx, &appRes)).Should(Succeed())
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: testApp.Spec.Dbs.Pg.SvcName, Namespace: ns}, &deployment)
+				if err != nil {
+					return false
+				}
+				return deployment.Labels["foo"] == "bar"
+			}, timeout, interval).Should(BeTrue())
+			Expect(deployment.Labels).Should(HaveKeyWithValue("external", "true"))
+			Expect(k8sClient.Delete(ctx, testApp)).Should(Succeed())
+		})
+
+		It("Fields removed from the spec are removed from the object", func() {
+			ns := createNs()
+			ctx := context.Background()
+			testApp := getDefaultTestAppSpec(ns)
+			testApp.Spec.Dbs.Pg.Enabled = true
+			testApp.Spec.Dbs.Pg.NodeSelector = map[string]string{"foo": "bar"}
+			Expect(k8sClient.Create(ctx, testApp)).Should(Succeed())
+
+			deployment := v1.Deployment{}
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: testApp.Spec.Dbs.Pg.SvcName, Namespace: ns}, &deployment)
+				if err != nil {
+					return false
+				}
+				return true
+			}, timeout, interval).Should(BeTrue())
+			Expect(deployment.Spec.Template.Spec.NodeSelector).Should(HaveKeyWithValue("foo", "bar"))
+
+			appRes := mlopsv1.CnvrgApp{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "cnvrgapp", Namespace: ns}, &appRes)).Should(Succeed())
+			appRes.Spec.Dbs.Pg.NodeSelector = nil
+			Expect(k8sClient.Update(ctx, &appRes)).Should(Succeed())
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: testApp.Spec.Dbs.Pg.SvcName, Namespace: ns}, &deployment)
+				if err != nil {
+					return false
+				}
+				_, ok := deployment.Spec.Template.Spec.NodeSelector["foo"]
+				return !ok
+			}, timeout, interval).Should(BeTrue())
+			Expect(k8sClient.Delete(ctx, testApp)).Should(Succeed())
+		})
+
+		It("Conflicting fields are taken over by default", func() {
+			ns := createNs()
+			ctx := context.Background()
+			testApp := getDefaultTestAppSpec(ns)
+			testApp.Spec.Dbs.Pg.Enabled = true
+			testApp.Spec.Dbs.Pg.NodeSelector = map[string]string{"foo": "bar"}
+			Expect(k8sClient.Create(ctx, testApp)).Should(Succeed())
+
+			deployment := v1.Deployment{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, types.NamespacedName{Name: testApp.Spec.Dbs.Pg.SvcName, Namespace: ns}, &deployment)
+			}, timeout, interval).Should(Succeed())
+
+			// change the node selector on behalf of another field manager
+			patch := &unstructured.Unstructured{}
+			patch.SetGroupVersionKind(v1.SchemeGroupVersion.WithKind("Deployment"))
+			patch.SetName(deployment.Name)
+			patch.SetNamespace(ns)
+			Expect(unstructured.SetNestedStringMap(patch.Object, map[string]string{"foo": "baz"}, "spec", "template", "spec", "nodeSelector")).Should(Succeed())
+			Expect(k8sClient.Patch(ctx, patch, client.Apply, client.FieldOwner("kubectl"), client.ForceOwnership)).Should(Succeed())
+
+			// enforce reconcile loop
+			appRes := mlopsv1.CnvrgApp{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "cnvrgapp", Namespace: ns}, &appRes)).Should(Succeed())
+			appRes.Spec.Labels = map[string]string{"foo": "bar"}
+			Expect(k8sClient.Update(ctx, &appRes)).Should(Succeed())
+
+			Eventually(func() string {
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: testApp.Spec.Dbs.Pg.SvcName, Namespace: ns}, &deployment); err != nil {
+					return ""
+				}
+				return deployment.Spec.Template.Spec.NodeSelector["foo"]
+			}, timeout, interval).Should(Equal("bar"))
+			Expect(k8sClient.Delete(ctx, testApp)).Should(Succeed())
+		})
+
+		It("Conflict policies are set per state", func() {
+			infra := &mlopsv1.CnvrgInfra{Spec: mlopsv1.DefaultCnvrgInfraSpec()}
+			infra.Spec.Networking.Ingress.Type = mlopsv1.IstioIngress
+			infra.Spec.Networking.Istio.Enabled = true
+			var istioInstance *desired.State
+			for _, s := range networking.InfraNetworkingState(infra) {
+				if s.GVK == desired.Kinds[desired.IstioGVK] {
+					istioInstance = s
+				}
+			}
+			Expect(istioInstance).ShouldNot(BeNil())
+			Expect(istioInstance.ConflictPolicy).Should(Equal(desired.ConflictSkip))
+			Expect(desired.DefaultConflictPolicy).Should(Equal(desired.ConflictForce))
+		})
+
+	})
+
+	Context("Test Pod Spec Overrides", func() {
+
+		It("Invalid pod spec overrides are reported by the condition", func() {
+			testApp := getDefaultTestAppSpec("overrides")
+			testApp.Spec.Dbs.Redis.PodSpecOverrides.VolumeMounts = []corev1.VolumeMount{{Name: "extra"}}
+			c := podSpecOverridesCondition(testApp.Spec.ValidatePodSpecOverrides())
+			Expect(c.Type).Should(Equal(mlopsv1.ConditionPodSpecOverridesValid))
+			Expect(c.Status).Should(Equal(metav1.ConditionFalse))
+			Expect(c.Reason).Should(Equal(mlopsv1.ReasonPodSpecOverridesInvalid))
+			Expect(c.Message).Should(ContainSubstring("spec.dbs.redis.podSpecOverrides.volumeMounts[0].mountPath"))
+
+			testApp.Spec.Dbs.Redis.PodSpecOverrides.VolumeMounts[0].MountPath = "/extra"
+			Expect(podSpecOverridesCondition(testApp.Spec.ValidatePodSpecOverrides()).Status).Should(Equal(metav1.ConditionTrue))
+		})
+
+	})
+
+	Context("Test External Databases", func() {
+
+		It("Creds secrets are written once the preflight succeeded", func() {
+			ns := createNs()
+			ctx := context.Background()
+			rdsCreds := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "rds-creds", Namespace: ns},
+				StringData: map[string]string{"username": "cnvrg", "password": "rds-pass"},
+			}
+			Expect(k8sClient.Create(ctx, rdsCreds)).Should(Succeed())
+			testApp := getDefaultTestAppSpec(ns)
+			testApp.Spec.Dbs.Pg.Enabled = false
+			testApp.Spec.Dbs.Pg.External = mlopsv1.ExternalDb{Enabled: true, Host: "pg.rds.local", Port: 5432, SecretRef: "rds-creds"}
+			Expect(k8sClient.Create(ctx, testApp)).Should(Succeed())
+
+			job := v1batch.Job{}
+			Eventually(func() error {
+				jobs := &v1batch.JobList{}
+				if err := k8sClient.List(ctx, jobs, client.InNamespace(ns), client.HasLabels{preflightLabel}); err != nil {
+					return err
+				}
+				if len(jobs.Items) != 1 {
+					return fmt.Errorf("expected a single preflight job, found %d", len(jobs.Items))
+				}
+				job = jobs.Items[0]
+				return nil
+			}, timeout, interval).Should(Succeed())
+			// the preflight reads the external secret, not the creds secret
+			Expect(job.Spec.Template.Spec.Containers[0].EnvFrom).Should(BeEmpty())
+
+			pgCreds := corev1.Secret{}
+			credsName := types.NamespacedName{Name: testApp.Spec.Dbs.Pg.CredsRef, Namespace: ns}
+			Consistently(func() bool {
+				return errors.IsNotFound(k8sClient.Get(ctx, credsName, &pgCreds))
+			}, time.Second*5, interval).Should(BeTrue())
+
+			job.Status.Succeeded = 1
+			Expect(k8sClient.Status().Update(ctx, &job)).Should(Succeed())
+			Eventually(func() error {
+				return k8sClient.Get(ctx, credsName, &pgCreds)
+			}, timeout, interval).Should(Succeed())
+			Expect(string(pgCreds.Data["POSTGRES_HOST"])).Should(Equal("pg.rds.local"))
+			Expect(k8sClient.Delete(ctx, testApp)).Should(Succeed())
+		})
+
+	})
+
+	Context("Test Conditions", func() {
+
+		It("Reconciled and readiness conditions", func() {
+			ns := createNs()
+			ctx := context.Background()
+			testApp := getDefaultTestAppSpec(ns)
+			testApp.Spec.Dbs.Pg.Enabled = true
+			Expect(k8sClient.Create(ctx, testApp)).Should(Succeed())
+
+			appRes := mlopsv1.CnvrgApp{}
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: "cnvrgapp", Namespace: ns}, &appRes)
+				if err != nil {
+					return false
+				}
+				return meta.IsStatusConditionTrue(appRes.Status.Conditions, mlopsv1.ConditionReconciled)
+			}, timeout, interval).Should(BeTrue())
+
+			reconciled := meta.FindStatusCondition(appRes.Status.Conditions, mlopsv1.ConditionReconciled)
+			Expect(reconciled.Reason).Should(Equal(mlopsv1.ReasonReconcileSucceeded))
+			Expect(reconciled.ObservedGeneration).Should(Equal(appRes.Generation))
+			Expect(meta.FindStatusCondition(appRes.Status.Conditions, mlopsv1.ConditionPgReady)).ShouldNot(BeNil())
+			// disabled components don't have conditions
+			Expect(meta.FindStatusCondition(appRes.Status.Conditions, mlopsv1.ConditionEsReady)).Should(BeNil())
+			Expect(k8sClient.Delete(ctx, testApp)).Should(Succeed())
+		})
+
+	})
+
+	Context("Test Secret References", func() {
+
+		It("SMTP password from secret reference", func() {
+			ns := createNs()
+			ctx := context.Background()
+			smtpSecret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "smtp-creds", Namespace: ns},
+				Data:       map[string][]byte{"password": []byte("smtp-pass")},
+			}
+			Expect(k8sClient.Create(ctx, smtpSecret)).Should(Succeed())
+			testApp := getDefaultTestAppSpec(ns)
+			testApp.Spec.ControlPlane.SMTP.PasswordSecretRef = &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "smtp-creds"},
+				Key:                  "password",
+			}
+			Expect(k8sClient.Create(ctx, testApp)).Should(Succeed())
+
+			secret := corev1.Secret{}
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: "cp-smtp", Namespace: ns}, &secret)
+				if err != nil {
+					return false
+				}
+				return string(secret.Data["SMTP_PASSWORD"]) == "smtp-pass"
+			}, timeout, interval).Should(BeTrue())
+
+			// changes of the referenced secret are rolled out
+			smtpSecret.Data["password"] = []byte("new-smtp-pass")
+			Expect(k8sClient.Update(ctx, smtpSecret)).Should(Succeed())
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: "cp-smtp", Namespace: ns}, &secret)
+				if err != nil {
+					return false
+				}
+				return string(secret.Data["SMTP_PASSWORD"]) == "new-smtp-pass"
+			}, timeout, interval).Should(BeTrue())
+
+			// the resolved value is never written to the CR
+			appRes := mlopsv1.CnvrgApp{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "cnvrgapp", Namespace: ns}, &appRes)).Should(Succeed())
+			Expect(appRes.Spec.ControlPlane.SMTP.Password).Should(BeEmpty())
+			Expect(k8sClient.Delete(ctx, testApp)).Should(Succeed())
+		})
+
+		It("Changes of referenced secrets roll out the consuming pods", func() {
+			ns := createNs()
+			ctx := context.Background()
+			smtpSecret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "smtp-creds", Namespace: ns},
+				Data:       map[string][]byte{"password": []byte("smtp-pass")},
+			}
+			Expect(k8sClient.Create(ctx, smtpSecret)).Should(Succeed())
+			testApp := getDefaultTestAppSpec(ns)
+			testApp.Spec.ControlPlane.WebApp.Enabled = true
+			testApp.Spec.ControlPlane.SMTP.PasswordSecretRef = &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "smtp-creds"},
+				Key:                  "password",
+			}
+			Expect(k8sClient.Create(ctx, testApp)).Should(Succeed())
+
+			checksum := func() string {
+				deployment := v1.Deployment{}
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: testApp.Spec.ControlPlane.WebApp.SvcName, Namespace: ns}, &deployment)
+				if err != nil {
+					return ""
+				}
+				return deployment.Spec.Template.Annotations["mlops.cnvrg.io/secret-refs-checksum"]
+			}
+			Eventually(checksum, timeout, interval).ShouldNot(BeEmpty())
+			initial := checksum()
+
+			smtpSecret.Data["password"] = []byte("new-smtp-pass")
+			Expect(k8sClient.Update(ctx, smtpSecret)).Should(Succeed())
+			Eventually(checksum, timeout, interval).ShouldNot(Equal(initial))
+			Expect(checksum()).ShouldNot(BeEmpty())
+			Expect(k8sClient.Delete(ctx, testApp)).Should(Succeed())
+		})
+
+		It("Secret references checksum", func() {
+			app := getDefaultTestAppSpec("cnvrg")
+			Expect(secretRefsChecksum(appSecretRefs(app))).Should(BeEmpty())
+			app.Spec.ControlPlane.SMTP.PasswordSecretRef = &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "creds"}, Key: "password",
+			}
+			app.Spec.ControlPlane.SMTP.Password = "smtp-pass"
+			checksum := secretRefsChecksum(appSecretRefs(app))
+			Expect(checksum).ShouldNot(BeEmpty())
+			app.Spec.ControlPlane.SMTP.Password = "new-smtp-pass"
+			Expect(secretRefsChecksum(appSecretRefs(app))).ShouldNot(Equal(checksum))
+		})
+
+		It("Secret references resolution", func() {
+			ctx := context.Background()
+			c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "creds", Namespace: "cnvrg"},
+				Data:       map[string][]byte{"token": []byte("hyper-token")},
+			}).Build()
+			optional := true
+			app := getDefaultTestAppSpec("cnvrg")
+			app.Spec.ControlPlane.Hyper.TokenSecretRef = &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "creds"}, Key: "token",
+			}
+			app.Spec.ControlPlane.SMTP.PasswordSecretRef = &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "creds"}, Key: "password",
+			}
+			app.Spec.SSO.ClientSecretRef = &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "sso"}, Key: "secret",
+			}
+			app.Spec.SSO.CookieSecretRef = &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "sso"}, Key: "cookie", Optional: &optional,
+			}
+
+			missing, err := resolveSecretRefs(ctx, c, "cnvrg", appSecretRefs(app))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(app.Spec.ControlPlane.Hyper.Token).Should(Equal("hyper-token"))
+			Expect(missing).Should(ConsistOf(
+				"spec.controlPlane.smtp.passwordSecretRef: key password not found in secret cnvrg/creds",
+				"spec.sso.clientSecretRef: secret cnvrg/sso not found",
+			))
+			Expect(referencesSecret(appSecretRefs(app), "sso")).Should(BeTrue())
+			Expect(referencesSecret(appSecretRefs(app), "other")).Should(BeFalse())
+		})
+
+		It("Missing secret key is reported", func() {
+			ns := createNs()
+			ctx := context.Background()
+			smtpSecret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "smtp-creds", Namespace: ns},
+				Data:       map[string][]byte{"user": []byte("smtp-user")},
+			}
+			Expect(k8sClient.Create(ctx, smtpSecret)).Should(Succeed())
+			testApp := getDefaultTestAppSpec(ns)
+			testApp.Spec.ControlPlane.SMTP.PasswordSecretRef = &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "smtp-creds"},
+				Key:                  "password",
+			}
+			Expect(k8sClient.Create(ctx, testApp)).Should(Succeed())
+
+			appRes := mlopsv1.CnvrgApp{}
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: "cnvrgapp", Namespace: ns}, &appRes)
+				if err != nil {
+					return false
+				}
+				return meta.IsStatusConditionFalse(appRes.Status.Conditions, mlopsv1.ConditionSecretRefsResolved)
+			}, timeout, interval).Should(BeTrue())
+			resolved := meta.FindStatusCondition(appRes.Status.Conditions, mlopsv1.ConditionSecretRefsResolved)
+			Expect(resolved.Reason).Should(Equal(mlopsv1.ReasonSecretRefMissing))
+			Expect(resolved.Message).Should(ContainSubstring("spec.controlPlane.smtp.passwordSecretRef"))
+
+			// adding the key resolves the reference
+			smtpSecret.Data["password"] = []byte("smtp-pass")
+			Expect(k8sClient.Update(ctx, smtpSecret)).Should(Succeed())
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: "cnvrgapp", Namespace: ns}, &appRes)
+				if err != nil {
+					return false
+				}
+				return meta.IsStatusConditionTrue(appRes.Status.Conditions, mlopsv1.ConditionSecretRefsResolved)
+			}, timeout, interval).Should(BeTrue())
+			Expect(k8sClient.Delete(ctx, testApp)).Should(Succeed())
+		})
+
+	})
+
+	Context("Test Applied Hash", func() {
+
+		It("Applied objects are annotated with the rendered manifest hash", func() {
+			ns := createNs()
+			ctx := context.Background()
+			testApp := getDefaultTestAppSpec(ns)
+			testApp.Spec.Dbs.Pg.Enabled = true
+			Expect(k8sClient.Create(ctx, testApp)).Should(Succeed())
+
+			deployment := v1.Deployment{}
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: testApp.Spec.Dbs.Pg.SvcName, Namespace: ns}, &deployment)
+				if err != nil {
+					return false
+				}
+				return true
+			}, timeout, interval).Should(BeTrue())
+			hash := deployment.Annotations[desired.AppliedHashAnnotation]
+			Expect(hash).ShouldNot(BeEmpty())
+
+			appRes := mlopsv1.CnvrgApp{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "cnvrgapp", Namespace: ns}, &appRes)).Should(Succeed())
+			appRes.Spec.Dbs.Pg.NodeSelector = map[string]string{"foo": "bar"}
+			Expect(k8sClient.Update(ctx, &appRes)).Should(Succeed())
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: testApp.Spec.Dbs.Pg.SvcName, Namespace: ns}, &deployment)
+				if err != nil {
+					return false
+				}
+				return deployment.Spec.Template.Spec.NodeSelector["foo"] == "bar"
+			}, timeout, interval).Should(BeTrue())
+			Expect(deployment.Annotations[desired.AppliedHashAnnotation]).ShouldNot(Equal(hash))
+			Expect(k8sClient.Delete(ctx, testApp)).Should(Succeed())
+		})
+
+		It("Drifted objects are updated even when the hash is unchanged", func() {
+			ns := createNs()
+			ctx := context.Background()
+			testApp := getDefaultTestAppSpec(ns)
+			testApp.Spec.Dbs.Pg.Enabled = true
+			Expect(k8sClient.Create(ctx, testApp)).Should(Succeed())
+
+			deployment := v1.Deployment{}
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: testApp.Spec.Dbs.Pg.SvcName, Namespace: ns}, &deployment)
+				if err != nil {
+					return false
+				}
+				return true
+			}, timeout, interval).Should(BeTrue())
+			image := deployment.Spec.Template.Spec.Containers[0].Image
+			deployment.Spec.Template.Spec.Containers[0].Image = "drifted:latest"
+			Expect(k8sClient.Update(ctx, &deployment)).Should(Succeed())
+
+			// enforce reconcile loop without changing the pg manifests
+			appRes := mlopsv1.CnvrgApp{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "cnvrgapp", Namespace: ns}, &appRes)).Should(Succeed())
+			appRes.Spec.Dbs.Redis.Enabled = true
+			Expect(k8sClient.Update(ctx, &appRes)).Should(Succeed())
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: testApp.Spec.Dbs.Pg.SvcName, Namespace: ns}, &deployment)
+				if err != nil {
+					return false
+				}
+				return deployment.Spec.Template.Spec.Containers[0].Image == image
+			}, timeout, interval).Should(BeTrue())
+			Expect(k8sClient.Delete(ctx, testApp)).Should(Succeed())
+		})
+
+		It("Normalized quantities are not updated", func() {
+			ns := createNs()
+			ctx := context.Background()
+			testApp := getDefaultTestAppSpec(ns)
+			testApp.Spec.Dbs.Pg.Enabled = true
+			// normalized by the api server to 2 and 4Gi
+			testApp.Spec.Dbs.Pg.Requests.Cpu = "2000m"
+			testApp.Spec.Dbs.Pg.Requests.Memory = "4096Mi"
+			Expect(k8sClient.Create(ctx, testApp)).Should(Succeed())
+
+			deployment := v1.Deployment{}
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: testApp.Spec.Dbs.Pg.SvcName, Namespace: ns}, &deployment)
+				if err != nil {
+					return false
+				}
+				return true
+			}, timeout, interval).Should(BeTrue())
+			resourceVersion := deployment.ResourceVersion
+
+			// enforce reconcile loop without changing the pg manifests
+			appRes := mlopsv1.CnvrgApp{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "cnvrgapp", Namespace: ns}, &appRes)).Should(Succeed())
+			appRes.Spec.Dbs.Redis.Enabled = true
+			Expect(k8sClient.Update(ctx, &appRes)).Should(Succeed())
+
+			redis := v1.Deployment{}
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: testApp.Spec.Dbs.Redis.SvcName, Namespace: ns}, &redis)
+				if err != nil {
+					return false
+				}
+				return true
+			}, timeout, interval).Should(BeTrue())
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: testApp.Spec.Dbs.Pg.SvcName, Namespace: ns}, &deployment)).Should(Succeed())
+			Expect(deployment.ResourceVersion).Should(Equal(resourceVersion))
+			Expect(k8sClient.Delete(ctx, testApp)).Should(Succeed())
+		})
+
+	})
+
+	Context("Test Overrides", func() {
+
+		It("Strategic merge and json patches are applied", func() {
+			ns := createNs()
+			ctx := context.Background()
+			testApp := getDefaultTestAppSpec(ns)
+			testApp.Spec.Dbs.Pg.Enabled = true
+			testApp.Spec.Overrides = []mlopsv1.Override{
+				{
+					Kind:  "Deployment",
+					Name:  testApp.Spec.Dbs.Pg.SvcName,
+					Patch: "spec:\n  template:\n    spec:\n      containers:\n      - name: postgresql\n        env:\n        - name: FOO\n          value: bar\n",
+				},
+				{
+					Kind:  "Deployment",
+					Name:  testApp.Spec.Dbs.Pg.SvcName,
+					Type:  mlopsv1.JSONPatchType,
+					Patch: `[{"op": "add", "path": "/metadata/labels/override", "value": "json"}]`,
+				},
+			}
+			Expect(k8sClient.Create(ctx, testApp)).Should(Succeed())
+
+			deployment := v1.Deployment{}
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: testApp.Spec.Dbs.Pg.SvcName, Namespace: ns}, &deployment)
+				if err != nil {
+					return false
+				}
+				return true
+			}, timeout, interval).Should(BeTrue())
+			Expect(deployment.Labels).Should(HaveKeyWithValue("override", "json"))
+			Expect(deployment.Spec.Template.Spec.Containers[0].Env).Should(ContainElement(corev1.EnvVar{Name: "FOO", Value: "bar"}))
+			// patch merges with the rendered container
+			Expect(deployment.Spec.Template.Spec.Containers[0].EnvFrom).ShouldNot(BeEmpty())
+			Expect(k8sClient.Delete(ctx, testApp)).Should(Succeed())
+		})
+
+		It("Failed override is reported in status", func() {
+			ns := createNs()
+			ctx := context.Background()
+			testApp := getDefaultTestAppSpec(ns)
+			testApp.Spec.Dbs.Pg.Enabled = true
+			testApp.Spec.Overrides = []mlopsv1.Override{
+				{
+					Kind:  "Deployment",
+					Name:  testApp.Spec.Dbs.Pg.SvcName,
+					Type:  mlopsv1.JSONPatchType,
+					Patch: `[{"op": "replace", "path": "/spec/not/exists", "value": "foo"}]`,
+				},
+			}
+			Expect(k8sClient.Create(ctx, testApp)).Should(Succeed())
+
+			appRes := mlopsv1.CnvrgApp{}
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: "cnvrgapp", Namespace: ns}, &appRes)
+				if err != nil {
+					return false
+				}
+				return appRes.Status.Status == mlopsv1.StatusError && strings.Contains(appRes.Status.Message, "failed to apply override")
+			}, timeout, interval).Should(BeTrue())
+			Expect(k8sClient.Delete(ctx, testApp)).Should(Succeed())
+		})
+
+		It("Unmatched overrides are reported by the condition", func() {
+			ns := createNs()
+			ctx := context.Background()
+			testApp := getDefaultTestAppSpec(ns)
+			testApp.Spec.Dbs.Pg.Enabled = true
+			testApp.Spec.Overrides = []mlopsv1.Override{
+				{
+					Kind:  "Deployment",
+					Name:  testApp.Spec.Dbs.Pg.SvcName,
+					Type:  mlopsv1.JSONPatchType,
+					Patch: `[{"op": "add", "path": "/metadata/labels/override", "value": "json"}]`,
+				},
+				{
+					Kind:  "Deployment",
+					Name:  "not-rendered",
+					Type:  mlopsv1.JSONPatchType,
+					Patch: `[{"op": "add", "path": "/metadata/labels/override", "value": "json"}]`,
+				},
+			}
+			Expect(k8sClient.Create(ctx, testApp)).Should(Succeed())
+
+			appRes := mlopsv1.CnvrgApp{}
+			Eventually(func() *metav1.Condition {
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: "cnvrgapp", Namespace: ns}, &appRes); err != nil {
+					return nil
+				}
+				return meta.FindStatusCondition(appRes.Status.Conditions, mlopsv1.ConditionOverridesMatched)
+			}, timeout, interval).ShouldNot(BeNil())
+			matched := meta.FindStatusCondition(appRes.Status.Conditions, mlopsv1.ConditionOverridesMatched)
+			Expect(matched.Status).Should(Equal(metav1.ConditionFalse))
+			Expect(matched.Reason).Should(Equal(mlopsv1.ReasonOverridesUnmatched))
+			Expect(matched.Message).Should(ContainSubstring("Deployment/not-rendered"))
+			Expect(matched.Message).ShouldNot(ContainSubstring("Deployment/" + testApp.Spec.Dbs.Pg.SvcName))
+			Expect(k8sClient.Delete(ctx, testApp)).Should(Succeed())
+		})
+
+		It("Invalid patch type is rejected", func() {
+			ns := createNs()
+			ctx := context.Background()
+			testApp := getDefaultTestAppSpec(ns)
+			testApp.Spec.Overrides = []mlopsv1.Override{{Kind: "Deployment", Name: "app", Type: "merge", Patch: "{}"}}
+			Expect(k8sClient.Create(ctx, testApp)).ShouldNot(Succeed())
+		})
+
+	})
+
+	Context("Test Prune", func() {
+
+		It("Disabled component resources are pruned", func() {
+			ns := createNs()
+			ctx := context.Background()
+			testApp := getDefaultTestAppSpec(ns)
+			testApp.Spec.Dbs.Minio.Enabled = true
+			Expect(k8sClient.Create(ctx, testApp)).Should(Succeed())
+
+			deployment := v1.Deployment{}
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: testApp.Spec.Dbs.Minio.SvcName, Namespace: ns}, &deployment)
+				if err != nil {
+					return false
+				}
+				return true
+			}, timeout, interval).Should(BeTrue())
+
+			appRes := mlopsv1.CnvrgApp{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "cnvrgapp", Namespace: ns}, &appRes)).Should(Succeed())
+			appRes.Spec.Dbs.Minio.Enabled = false
+			Expect(k8sClient.Update(ctx, &appRes)).Should(Succeed())
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: testApp.Spec.Dbs.Minio.SvcName, Namespace: ns}, &deployment)
+				return errors.IsNotFound(err)
+			}, timeout, interval).Should(BeTrue())
+			Expect(k8sClient.Delete(ctx, testApp)).Should(Succeed())
+		})
+
+		It("Disabled component PVCs are kept", func() {
+			ns := createNs()
+			ctx := context.Background()
+			testApp := getDefaultTestAppSpec(ns)
+			testApp.Spec.Dbs.Minio.Enabled = true
+			Expect(k8sClient.Create(ctx, testApp)).Should(Succeed())
+
+			pvc := corev1.PersistentVolumeClaim{}
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: testApp.Spec.Dbs.Minio.PvcName, Namespace: ns}, &pvc)
+				if err != nil {
+					return false
+				}
+				return true
+			}, timeout, interval).Should(BeTrue())
+
+			appRes := mlopsv1.CnvrgApp{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "cnvrgapp", Namespace: ns}, &appRes)).Should(Succeed())
+			appRes.Spec.Dbs.Minio.Enabled = false
+			Expect(k8sClient.Update(ctx, &appRes)).Should(Succeed())
+
+			deployment := v1.Deployment{}
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: testApp.Spec.Dbs.Minio.SvcName, Namespace: ns}, &deployment)
+				return errors.IsNotFound(err)
+			}, timeout, interval).Should(BeTrue())
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: testApp.Spec.Dbs.Minio.PvcName, Namespace: ns}, &pvc)).Should(Succeed())
+			Expect(pvc.DeletionTimestamp).Should(BeNil())
+
+			// the kept pvc stays in the inventory and is pruned once prune-pvc is set
+			viper.Set("prune-pvc", true)
+			defer viper.Set("prune-pvc", false)
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "cnvrgapp", Namespace: ns}, &appRes)).Should(Succeed())
+			appRes.Spec.Labels = map[string]string{"prune": "pvc"}
+			Expect(k8sClient.Update(ctx, &appRes)).Should(Succeed())
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: testApp.Spec.Dbs.Minio.PvcName, Namespace: ns}, &pvc)
+				return errors.IsNotFound(err) || (err == nil && pvc.DeletionTimestamp != nil)
+			}, timeout, interval).Should(BeTrue())
+			Expect(k8sClient.Delete(ctx, testApp)).Should(Succeed())
+		})
+
+	})
+
 	Context("Test Events", func() {
 
 		It("Simple event test", func() {
@@ -2339,6 +3127,67 @@ var _ = Describe("CnvrgApp controller", func() {
 
 	})
 
+	Context("Test Effective Spec", func() {
+		It("Defaults are not written to the stored spec", func() {
+			ns := createNs()
+			ctx := context.Background()
+			viper.Set("dump-effective-spec", true)
+			defer viper.Set("dump-effective-spec", false)
+			app := getEmptyTestAppSpec(ns)
+			app.Spec.Cri = mlopsv1.CriTypeDocker
+			app.Spec.ClusterDomain = "test.local"
+			app.Spec.Dbs.Pg.Enabled = true
+			Expect(k8sClient.Create(ctx, app)).Should(Succeed())
+
+			defaultPg := mlopsv1.DefaultCnvrgAppSpec().Dbs.Pg
+			deployment := v1.Deployment{}
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: defaultPg.SvcName, Namespace: ns}, &deployment)
+				if err != nil {
+					return false
+				}
+				return true
+			}, timeout, interval).Should(BeTrue())
+			Expect(deployment.Spec.Template.Spec.Containers[0].Image).Should(ContainSubstring(defaultPg.Image))
+
+			// the stored spec holds only the user settings
+			stored := mlopsv1.CnvrgApp{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: app.Name, Namespace: ns}, &stored)).Should(Succeed())
+			Expect(stored.Spec.Dbs.Pg.Enabled).Should(BeTrue())
+			Expect(stored.Spec.Dbs.Pg.Image).Should(BeEmpty())
+			Expect(stored.Spec.ControlPlane.WebApp.SvcName).Should(BeEmpty())
+
+			// the effective spec is exposed in the debug configmap
+			cm := corev1.ConfigMap{}
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: app.Name + "-effective-spec", Namespace: ns}, &cm)
+				if err != nil {
+					return false
+				}
+				return true
+			}, timeout, interval).Should(BeTrue())
+			Expect(cm.Data["spec.yaml"]).Should(ContainSubstring(defaultPg.Image))
+			Expect(k8sClient.Delete(ctx, app)).Should(Succeed())
+		})
+
+		It("Credentials are redacted in the effective spec", func() {
+			app := getDefaultTestAppSpec("redacted")
+			app.Spec.ControlPlane.SMTP.Password = "smtp-pass"
+			app.Spec.ControlPlane.ObjectStorage.SecretKey = "s3-secret"
+			app.Spec.SSO.CookieSecret = "cookie-secret"
+			app.Spec.Dbs.Redis.External.Password = "redis-pass"
+
+			dump, err := yaml.Marshal(redactedCnvrgAppSpec(app))
+			Expect(err).ToNot(HaveOccurred())
+			for _, secret := range []string{"smtp-pass", "s3-secret", "cookie-secret", "redis-pass"} {
+				Expect(string(dump)).ShouldNot(ContainSubstring(secret))
+			}
+			Expect(string(dump)).Should(ContainSubstring(redactedValue))
+			// the spec of the reconcile is not changed
+			Expect(app.Spec.ControlPlane.SMTP.Password).Should(Equal("smtp-pass"))
+		})
+	})
+
 })
 
 func createNs() string {
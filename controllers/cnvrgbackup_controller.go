@@ -0,0 +1,379 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	mlopsv1 "github.com/AccessibleAI/cnvrg-operator/api/v1"
+	"github.com/AccessibleAI/cnvrg-operator/pkg/backup"
+	"github.com/AccessibleAI/cnvrg-operator/pkg/desired"
+	"github.com/go-logr/logr"
+	"github.com/imdario/mergo"
+	v1batch "k8s.io/api/batch/v1"
+	v1core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	"reflect"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	"sort"
+	"time"
+)
+
+// backup and restore jobs are labeled with the name of their cnvrgBackup/cnvrgRestore, must match the job templates
+const (
+	backupLabel  = "mlops.cnvrg.io/backup"
+	restoreLabel = "mlops.cnvrg.io/restore"
+	// backupUploadContainer reports the uploaded artifacts with its termination message
+	backupUploadContainer = "upload"
+)
+
+// backupRequeue is the delay of rechecking a missing cnvrgApp or cnvrgBackup, which are not watched
+var backupRequeue = 30 * time.Second
+
+type CnvrgBackupReconciler struct {
+	client.Client
+	recorder record.EventRecorder
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=mlops.cnvrg.io,resources=cnvrgbackups,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=mlops.cnvrg.io,resources=cnvrgbackups/status,verbs=get;update;patch
+
+func (r *CnvrgBackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("name", req.NamespacedName)
+	ctx = logf.IntoContext(ctx, log)
+
+	cnvrgBackup := &mlopsv1.CnvrgBackup{}
+	if err := r.Get(ctx, req.NamespacedName, cnvrgBackup); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	status := cnvrgBackup.Status.DeepCopy()
+	spec, err := desiredCnvrgBackupSpec(cnvrgBackup)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	app, err := backupCnvrgApp(ctx, r.Client, r.Scheme, r.Log, cnvrgBackup.Namespace, spec.CnvrgAppRef)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if app == nil {
+		status.Phase = mlopsv1.BackupPending
+		status.Message = fmt.Sprintf("waiting for cnvrgapp %s", spec.CnvrgAppRef)
+		return ctrl.Result{RequeueAfter: backupRequeue}, r.updateStatus(ctx, cnvrgBackup, status)
+	}
+
+	data, err := backupData(app, cnvrgBackup.Name, spec)
+	if err != nil {
+		// the spec is invalid, the backup is reconciled again once the spec is fixed
+		status.Phase = mlopsv1.BackupFailed
+		status.Message = err.Error()
+		return ctrl.Result{}, r.updateStatus(ctx, cnvrgBackup, status)
+	}
+	if err := desired.Apply(ctx, backup.BackupState(data), cnvrgBackup, r.Client, r.Scheme, log); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.runStatus(ctx, cnvrgBackup, status); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, r.updateStatus(ctx, cnvrgBackup, status)
+}
+
+// runStatus sets the phase of the last backup run, and the artifacts of the last successful run
+func (r *CnvrgBackupReconciler) runStatus(ctx context.Context, cnvrgBackup *mlopsv1.CnvrgBackup, status *mlopsv1.CnvrgBackupStatus) error {
+	jobs := &v1batch.JobList{}
+	if err := r.List(ctx, jobs, client.InNamespace(cnvrgBackup.Namespace), client.MatchingLabels{backupLabel: cnvrgBackup.Name}); err != nil {
+		return err
+	}
+	if len(jobs.Items) == 0 {
+		status.Phase = mlopsv1.BackupPending
+		status.Message = "waiting for the backup job"
+		if cnvrgBackup.Spec.Schedule != "" {
+			status.Message = fmt.Sprintf("waiting for the first scheduled run (%s)", cnvrgBackup.Spec.Schedule)
+		}
+		return nil
+	}
+	sort.Slice(jobs.Items, func(i, j int) bool {
+		return jobs.Items[i].CreationTimestamp.Before(&jobs.Items[j].CreationTimestamp)
+	})
+
+	last := &jobs.Items[len(jobs.Items)-1]
+	status.JobName = last.Name
+	status.StartTime = last.Status.StartTime
+	status.CompletionTime = last.Status.CompletionTime
+	switch {
+	case last.Status.Succeeded > 0:
+		status.Phase = mlopsv1.BackupSucceeded
+		status.Message = fmt.Sprintf("backup job %s succeeded", last.Name)
+	case jobFailed(last):
+		status.Phase = mlopsv1.BackupFailed
+		status.Message = fmt.Sprintf("backup job %s failed, check the job logs", last.Name)
+	default:
+		status.Phase = mlopsv1.BackupRunning
+		status.Message = fmt.Sprintf("backup job %s is running", last.Name)
+	}
+
+	for i := len(jobs.Items) - 1; i >= 0; i-- {
+		job := &jobs.Items[i]
+		if job.Status.Succeeded == 0 || job.Status.CompletionTime == nil {
+			continue
+		}
+		if status.LastSuccessfulTime != nil && !status.LastSuccessfulTime.Before(job.Status.CompletionTime) {
+			break // the artifacts of the run are already reported
+		}
+		artifacts, err := r.jobArtifacts(ctx, job)
+		if err != nil {
+			return err
+		}
+		if artifacts == nil {
+			status.Message = fmt.Sprintf("the artifacts of backup job %s are not reported, the job pod is missing", job.Name)
+			break
+		}
+		status.Artifacts = artifacts
+		status.LastSuccessfulTime = job.Status.CompletionTime
+		break
+	}
+	return nil
+}
+
+// jobArtifacts returns the artifacts reported by the upload container of the succeeded job pod,
+// nil when the pod doesn't exist anymore
+func (r *CnvrgBackupReconciler) jobArtifacts(ctx context.Context, job *v1batch.Job) ([]mlopsv1.BackupArtifact, error) {
+	pods := &v1core.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(job.Namespace), client.MatchingLabels{"job-name": job.Name}); err != nil {
+		return nil, err
+	}
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != v1core.PodSucceeded {
+			continue
+		}
+		for _, c := range pod.Status.ContainerStatuses {
+			if c.Name != backupUploadContainer || c.State.Terminated == nil {
+				continue
+			}
+			artifacts := []mlopsv1.BackupArtifact{}
+			if err := json.Unmarshal([]byte(c.State.Terminated.Message), &artifacts); err != nil {
+				logf.FromContext(ctx).Error(err, "bad artifacts termination message", "pod", pod.Name, "message", c.State.Terminated.Message)
+				return nil, nil
+			}
+			return artifacts, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *CnvrgBackupReconciler) updateStatus(ctx context.Context, cnvrgBackup *mlopsv1.CnvrgBackup, status *mlopsv1.CnvrgBackupStatus) error {
+	if reflect.DeepEqual(cnvrgBackup.Status, *status) {
+		return nil
+	}
+	if cnvrgBackup.Status.Phase != status.Phase {
+		r.recorder.Event(cnvrgBackup, backupEventType(status.Phase), "Backup"+string(status.Phase), status.Message)
+	}
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest := &mlopsv1.CnvrgBackup{}
+		if err := r.Get(ctx, types.NamespacedName{Name: cnvrgBackup.Name, Namespace: cnvrgBackup.Namespace}, latest); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		latest.Status = *status
+		return r.Status().Update(ctx, latest)
+	})
+}
+
+func backupEventType(phase mlopsv1.BackupPhase) string {
+	if phase == mlopsv1.BackupFailed {
+		return "Warning"
+	}
+	return "Normal"
+}
+
+// desiredCnvrgBackupSpec merges the backup spec into the defaults, the defaults are never written back
+func desiredCnvrgBackupSpec(cnvrgBackup *mlopsv1.CnvrgBackup) (mlopsv1.CnvrgBackupSpec, error) {
+	desiredSpec := mlopsv1.DefaultCnvrgBackupSpec()
+	if err := mergo.Merge(&desiredSpec, cnvrgBackup.Spec, mergo.WithOverride); err != nil {
+		return desiredSpec, err
+	}
+	return desiredSpec, nil
+}
+
+// backupCnvrgApp returns the effective cnvrgApp of a backup or a restore, nil when the cnvrgApp doesn't exist.
+// The databases are backed up and restored with the images and the pvc of the served pg version
+func backupCnvrgApp(ctx context.Context, c client.Client, scheme *runtime.Scheme, log logr.Logger, namespace, name string) (*mlopsv1.CnvrgApp, error) {
+	appReconciler := &CnvrgAppReconciler{Client: c, Scheme: scheme, Log: log}
+	app, err := appReconciler.getCnvrgAppSpec(ctx, types.NamespacedName{Name: name, Namespace: namespace})
+	if err != nil || app == nil {
+		return nil, err
+	}
+	infra, err := appReconciler.getCnvrgInfra(ctx)
+	if err != nil && !errors.IsNotFound(err) {
+		logf.FromContext(ctx).Error(err, "can't get cnvrg infra")
+	}
+	effective, err := effectiveCnvrgApp(ctx, app, infra, c)
+	if err != nil {
+		return nil, err
+	}
+	pgUpgradeSpec(effective)
+	return effective, nil
+}
+
+// backupDatabases returns the requested databases, or all the enabled databases of the cnvrgApp
+func backupDatabases(app *mlopsv1.CnvrgApp, requested []mlopsv1.BackupDatabase) ([]mlopsv1.BackupDatabase, error) {
+	if len(requested) > 0 {
+		for _, db := range requested {
+			if err := databaseEnabled(app, db); err != nil {
+				return nil, err
+			}
+		}
+		return requested, nil
+	}
+	var databases []mlopsv1.BackupDatabase
+	for _, db := range []mlopsv1.BackupDatabase{mlopsv1.PgBackupDatabase, mlopsv1.RedisBackupDatabase, mlopsv1.EsBackupDatabase, mlopsv1.CvatPgBackupDatabase} {
+		if databaseEnabled(app, db) == nil {
+			databases = append(databases, db)
+		}
+	}
+	if len(databases) == 0 {
+		return nil, fmt.Errorf("cnvrgapp %s has no enabled databases", app.Name)
+	}
+	return databases, nil
+}
+
+// databaseEnabled checks the database is deployed by the cnvrgApp,
+// es is snapshotted with its s3 client which is configured only when the es backup is enabled
+func databaseEnabled(app *mlopsv1.CnvrgApp, db mlopsv1.BackupDatabase) error {
+	dbs := app.Spec.Dbs
+	switch db {
+	case mlopsv1.PgBackupDatabase:
+		if dbs.Pg.Enabled {
+			return nil
+		}
+	case mlopsv1.RedisBackupDatabase:
+		if dbs.Redis.Enabled {
+			return nil
+		}
+	case mlopsv1.EsBackupDatabase:
+		if dbs.Es.Enabled && !dbs.Es.Backup.Enabled {
+			return fmt.Errorf("es snapshots require spec.dbs.es.backup.enabled of cnvrgapp %s", app.Name)
+		}
+		if dbs.Es.Enabled {
+			return nil
+		}
+	case mlopsv1.CvatPgBackupDatabase:
+		if dbs.Cvat.Enabled {
+			return nil
+		}
+	default:
+		return fmt.Errorf("unknown database %s", db)
+	}
+	return fmt.Errorf("%s is not enabled in cnvrgapp %s", db, app.Name)
+}
+
+func pgDump(app *mlopsv1.CnvrgApp, db mlopsv1.BackupDatabase) backup.PgDump {
+	pg := app.Spec.Dbs.Pg
+	if db == mlopsv1.CvatPgBackupDatabase {
+		pg = app.Spec.Dbs.Cvat.Pg
+	}
+	return backup.PgDump{
+		Database: db,
+		Image:    fullImage(app.Spec.ImageHub, pg.Image),
+		CredsRef: pg.CredsRef,
+		Host:     pg.SvcName,
+		Port:     pg.Port,
+	}
+}
+
+func redisDump(app *mlopsv1.CnvrgApp) *backup.RedisDump {
+	redis := app.Spec.Dbs.Redis
+	return &backup.RedisDump{
+		Image:    fullImage(app.Spec.ImageHub, redis.Image),
+		CredsRef: redis.CredsRef,
+		Host:     redis.SvcName,
+		Port:     redis.Port,
+		PvcName:  redis.PvcName,
+	}
+}
+
+func esSnapshot(app *mlopsv1.CnvrgApp, repository string) *backup.EsSnapshot {
+	es := app.Spec.Dbs.Es
+	return &backup.EsSnapshot{
+		Image:      fullImage(app.Spec.ImageHub, es.Image),
+		CredsRef:   es.CredsRef,
+		Url:        fmt.Sprintf("http://%s:%d", es.SvcName, es.Port),
+		Repository: repository,
+	}
+}
+
+func backupNodeSelector(app *mlopsv1.CnvrgApp) map[string]string {
+	nodeSelector := map[string]string{}
+	if app.Spec.Tenancy.Enabled {
+		nodeSelector[app.Spec.Tenancy.Key] = app.Spec.Tenancy.Value
+	}
+	return nodeSelector
+}
+
+// backupData returns the backup job of the databases, the runs are uploaded to <namespace>/cnvrg-backups/<name>/<run>
+// and the es snapshots to the <namespace>/cnvrg-backups/<name>/elasticsearch repository
+func backupData(app *mlopsv1.CnvrgApp, name string, spec mlopsv1.CnvrgBackupSpec) (backup.Data, error) {
+	prefix := fmt.Sprintf("%s/cnvrg-backups/%s", app.Namespace, name)
+	data := backup.Data{
+		Namespace:    app.Namespace,
+		Annotations:  app.Spec.Annotations,
+		Labels:       app.Spec.Labels,
+		Name:         name,
+		JobName:      name + "-backup",
+		Schedule:     spec.Schedule,
+		Rotation:     spec.Rotation,
+		BucketRef:    spec.BucketRef,
+		Image:        spec.Image,
+		NodeSelector: backupNodeSelector(app),
+		Tenancy:      app.Spec.Tenancy.Enabled,
+		Prefix:       prefix,
+	}
+	databases, err := backupDatabases(app, spec.Databases)
+	if err != nil {
+		return data, err
+	}
+	for _, db := range databases {
+		switch db {
+		case mlopsv1.PgBackupDatabase, mlopsv1.CvatPgBackupDatabase:
+			data.Pg = append(data.Pg, pgDump(app, db))
+		case mlopsv1.RedisBackupDatabase:
+			data.Redis = redisDump(app)
+		case mlopsv1.EsBackupDatabase:
+			data.Es = esSnapshot(app, "cnvrg-backups-"+name)
+			data.Es.BasePath = prefix + "/elasticsearch"
+		}
+	}
+	return data, nil
+}
+
+// labelRequests maps an object to the request of the cnvrgBackup/cnvrgRestore named by its label
+func labelRequests(label string) handler.MapFunc {
+	return func(obj client.Object) []reconcile.Request {
+		name, ok := obj.GetLabels()[label]
+		if !ok {
+			return nil
+		}
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: name, Namespace: obj.GetNamespace()}}}
+	}
+}
+
+func (r *CnvrgBackupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.recorder = mgr.GetEventRecorderFor("cnvrgbackup")
+	// the jobs of scheduled backups are owned by the cron job, hence the jobs are mapped by the label
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&mlopsv1.CnvrgBackup{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&source.Kind{Type: &v1batch.Job{}}, handler.EnqueueRequestsFromMapFunc(labelRequests(backupLabel))).
+		Complete(r)
+}
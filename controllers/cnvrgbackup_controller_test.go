@@ -0,0 +1,282 @@
+package controllers
+
+import (
+	"context"
+	mlopsv1 "github.com/AccessibleAI/cnvrg-operator/api/v1"
+	"github.com/AccessibleAI/cnvrg-operator/pkg/desired"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1apps "k8s.io/api/apps/v1"
+	v1batch "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"time"
+)
+
+var _ = Describe("CnvrgBackup controller", func() {
+
+	const (
+		timeout  = time.Second * 60
+		interval = time.Millisecond * 250
+	)
+
+	getTestBackup := func(ns string) *mlopsv1.CnvrgBackup {
+		return &mlopsv1.CnvrgBackup{
+			ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: ns},
+			Spec:       mlopsv1.CnvrgBackupSpec{CnvrgAppRef: "cnvrgapp"},
+		}
+	}
+
+	Context("Test Backup", func() {
+		It("Backup of the enabled databases is a job", func() {
+			ns := createNs()
+			ctx := context.Background()
+			testApp := getDefaultTestAppSpec(ns)
+			testApp.Spec.Dbs.Pg.Enabled = true
+			testApp.Spec.Dbs.Redis.Enabled = true
+			Expect(k8sClient.Create(ctx, testApp)).Should(Succeed())
+			backup := getTestBackup(ns)
+			Expect(k8sClient.Create(ctx, backup)).Should(Succeed())
+
+			job := v1batch.Job{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, types.NamespacedName{Name: "nightly-backup", Namespace: ns}, &job)
+			}, timeout, interval).Should(Succeed())
+			Expect(job.Labels).Should(HaveKeyWithValue(backupLabel, "nightly"))
+			var initContainers []string
+			for _, c := range job.Spec.Template.Spec.InitContainers {
+				initContainers = append(initContainers, c.Name)
+			}
+			Expect(initContainers).Should(Equal([]string{"dump-pg", "dump-redis"}))
+			Expect(job.Spec.Template.Spec.Containers[0].Name).Should(Equal(backupUploadContainer))
+
+			Eventually(func() mlopsv1.BackupPhase {
+				_ = k8sClient.Get(ctx, types.NamespacedName{Name: backup.Name, Namespace: ns}, backup)
+				return backup.Status.Phase
+			}, timeout, interval).Should(Equal(mlopsv1.BackupRunning))
+			Expect(backup.Status.JobName).Should(Equal("nightly-backup"))
+			Expect(k8sClient.Delete(ctx, testApp)).Should(Succeed())
+		})
+
+		It("Scheduled backup is a cron job", func() {
+			ns := createNs()
+			ctx := context.Background()
+			testApp := getDefaultTestAppSpec(ns)
+			testApp.Spec.Dbs.Pg.Enabled = true
+			Expect(k8sClient.Create(ctx, testApp)).Should(Succeed())
+			backup := getTestBackup(ns)
+			backup.Spec.Schedule = "0 1 * * *"
+			backup.Spec.Databases = []mlopsv1.BackupDatabase{mlopsv1.PgBackupDatabase}
+			Expect(k8sClient.Create(ctx, backup)).Should(Succeed())
+
+			cronJob := &unstructured.Unstructured{}
+			cronJob.SetGroupVersionKind(desired.Kinds[desired.CronJobGVK])
+			Eventually(func() error {
+				return k8sClient.Get(ctx, types.NamespacedName{Name: "nightly-backup", Namespace: ns}, cronJob)
+			}, timeout, interval).Should(Succeed())
+			schedule, _, _ := unstructured.NestedString(cronJob.Object, "spec", "schedule")
+			Expect(schedule).Should(Equal("0 1 * * *"))
+			Expect(k8sClient.Delete(ctx, testApp)).Should(Succeed())
+		})
+
+		It("Backup of a disabled database fails", func() {
+			ns := createNs()
+			ctx := context.Background()
+			testApp := getDefaultTestAppSpec(ns)
+			testApp.Spec.Dbs.Es.Enabled = true
+			Expect(k8sClient.Create(ctx, testApp)).Should(Succeed())
+			backup := getTestBackup(ns)
+			backup.Spec.Databases = []mlopsv1.BackupDatabase{mlopsv1.EsBackupDatabase}
+			Expect(k8sClient.Create(ctx, backup)).Should(Succeed())
+
+			Eventually(func() mlopsv1.BackupPhase {
+				_ = k8sClient.Get(ctx, types.NamespacedName{Name: backup.Name, Namespace: ns}, backup)
+				return backup.Status.Phase
+			}, timeout, interval).Should(Equal(mlopsv1.BackupFailed))
+			Expect(backup.Status.Message).Should(ContainSubstring("spec.dbs.es.backup.enabled"))
+			Expect(k8sClient.Delete(ctx, testApp)).Should(Succeed())
+		})
+
+		It("Backup databases default to the enabled databases", func() {
+			testApp := getDefaultTestAppSpec("backup-ns")
+			testApp.Spec.Dbs.Pg.Enabled = true
+			testApp.Spec.Dbs.Es.Enabled = true
+			testApp.Spec.Dbs.Cvat.Enabled = true
+			databases, err := backupDatabases(testApp, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(databases).Should(Equal([]mlopsv1.BackupDatabase{mlopsv1.PgBackupDatabase, mlopsv1.CvatPgBackupDatabase}))
+
+			testApp.Spec.Dbs.Es.Backup.Enabled = true
+			data, err := backupData(testApp, "nightly", mlopsv1.DefaultCnvrgBackupSpec())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(data.Pg).Should(HaveLen(2))
+			Expect(data.Es.Repository).Should(Equal("cnvrg-backups-nightly"))
+			Expect(data.Es.BasePath).Should(Equal("backup-ns/cnvrg-backups/nightly/elasticsearch"))
+		})
+	})
+
+	Context("Test Restore", func() {
+		It("Restore waits for a successful backup", func() {
+			ns := createNs()
+			ctx := context.Background()
+			testApp := getDefaultTestAppSpec(ns)
+			Expect(k8sClient.Create(ctx, testApp)).Should(Succeed())
+			restore := &mlopsv1.CnvrgRestore{
+				ObjectMeta: metav1.ObjectMeta{Name: "restore", Namespace: ns},
+				Spec:       mlopsv1.CnvrgRestoreSpec{CnvrgAppRef: "cnvrgapp", BackupRef: "nightly"},
+			}
+			Expect(k8sClient.Create(ctx, restore)).Should(Succeed())
+
+			Eventually(func() mlopsv1.BackupPhase {
+				_ = k8sClient.Get(ctx, types.NamespacedName{Name: restore.Name, Namespace: ns}, restore)
+				return restore.Status.Phase
+			}, timeout, interval).Should(Equal(mlopsv1.BackupPending))
+			Expect(restore.Status.Message).Should(ContainSubstring("nightly"))
+			Expect(k8sClient.Delete(ctx, testApp)).Should(Succeed())
+		})
+
+		It("Restore quiesces the control plane", func() {
+			ns := createNs()
+			ctx := context.Background()
+			testApp := getDefaultTestAppSpec(ns)
+			testApp.Spec.Dbs.Pg.Enabled = true
+			Expect(k8sClient.Create(ctx, testApp)).Should(Succeed())
+
+			replicas := int32(2)
+			labels := map[string]string{"app": "sidekiq"}
+			sidekiq := &v1apps.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "sidekiq", Namespace: ns},
+				Spec: v1apps.DeploymentSpec{
+					Replicas: &replicas,
+					Selector: &metav1.LabelSelector{MatchLabels: labels},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: labels},
+						Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "sidekiq", Image: "sidekiq"}}},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, sidekiq)).Should(Succeed())
+
+			// the backup of a missing cnvrgApp is pending, the reported artifacts are kept
+			backup := getTestBackup(ns)
+			backup.Spec.CnvrgAppRef = "missing"
+			Expect(k8sClient.Create(ctx, backup)).Should(Succeed())
+			Eventually(func() mlopsv1.BackupPhase {
+				_ = k8sClient.Get(ctx, types.NamespacedName{Name: backup.Name, Namespace: ns}, backup)
+				return backup.Status.Phase
+			}, timeout, interval).Should(Equal(mlopsv1.BackupPending))
+			now := metav1.Now()
+			backup.Status.LastSuccessfulTime = &now
+			backup.Status.Artifacts = []mlopsv1.BackupArtifact{
+				{Database: mlopsv1.PgBackupDatabase, Location: "s3://cnvrg-storage/" + ns + "/cnvrg-backups/nightly/20211011120000/pg.dump", Size: 1024},
+			}
+			Expect(k8sClient.Status().Update(ctx, backup)).Should(Succeed())
+
+			restore := &mlopsv1.CnvrgRestore{
+				ObjectMeta: metav1.ObjectMeta{Name: "restore", Namespace: ns},
+				Spec:       mlopsv1.CnvrgRestoreSpec{CnvrgAppRef: "cnvrgapp", BackupRef: "nightly"},
+			}
+			Expect(k8sClient.Create(ctx, restore)).Should(Succeed())
+
+			job := v1batch.Job{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, types.NamespacedName{Name: "restore-restore", Namespace: ns}, &job)
+			}, timeout, interval).Should(Succeed())
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "sidekiq", Namespace: ns}, sidekiq)).Should(Succeed())
+			Expect(*sidekiq.Spec.Replicas).Should(BeEquivalentTo(0))
+			Expect(sidekiq.Annotations).Should(HaveKeyWithValue(quiescedReplicasAnnotation, "2"))
+
+			job.Status.Conditions = []v1batch.JobCondition{{Type: v1batch.JobFailed, Status: corev1.ConditionTrue}}
+			Expect(k8sClient.Status().Update(ctx, &job)).Should(Succeed())
+			Eventually(func() mlopsv1.BackupPhase {
+				_ = k8sClient.Get(ctx, types.NamespacedName{Name: restore.Name, Namespace: ns}, restore)
+				return restore.Status.Phase
+			}, timeout, interval).Should(Equal(mlopsv1.BackupFailed))
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "sidekiq", Namespace: ns}, sidekiq)).Should(Succeed())
+			Expect(*sidekiq.Spec.Replicas).Should(BeEquivalentTo(2))
+			Expect(k8sClient.Delete(ctx, testApp)).Should(Succeed())
+		})
+
+		It("Restore holds the control plane of the cnvrgApp", func() {
+			ns := createNs()
+			ctx := context.Background()
+			testApp := getDefaultTestAppSpec(ns)
+			testApp.Spec.Dbs.Pg.Enabled = true
+			testApp.Spec.ControlPlane.Sidekiq.Enabled = true
+			testApp.Spec.ControlPlane.Sidekiq.Split = true
+			Expect(k8sClient.Create(ctx, testApp)).Should(Succeed())
+
+			// the sidekiq deployment is applied by the cnvrgApp reconciler
+			sidekiq := &v1apps.Deployment{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, types.NamespacedName{Name: "sidekiq", Namespace: ns}, sidekiq)
+			}, timeout, interval).Should(Succeed())
+			replicas := *sidekiq.Spec.Replicas
+
+			backup := getTestBackup(ns)
+			backup.Spec.CnvrgAppRef = "missing"
+			Expect(k8sClient.Create(ctx, backup)).Should(Succeed())
+			Eventually(func() mlopsv1.BackupPhase {
+				_ = k8sClient.Get(ctx, types.NamespacedName{Name: backup.Name, Namespace: ns}, backup)
+				return backup.Status.Phase
+			}, timeout, interval).Should(Equal(mlopsv1.BackupPending))
+			now := metav1.Now()
+			backup.Status.LastSuccessfulTime = &now
+			backup.Status.Artifacts = []mlopsv1.BackupArtifact{
+				{Database: mlopsv1.PgBackupDatabase, Location: "s3://cnvrg-storage/" + ns + "/cnvrg-backups/nightly/20211011120000/pg.dump", Size: 1024},
+			}
+			Expect(k8sClient.Status().Update(ctx, backup)).Should(Succeed())
+
+			restore := &mlopsv1.CnvrgRestore{
+				ObjectMeta: metav1.ObjectMeta{Name: "restore", Namespace: ns},
+				Spec:       mlopsv1.CnvrgRestoreSpec{CnvrgAppRef: "cnvrgapp", BackupRef: "nightly"},
+			}
+			Expect(k8sClient.Create(ctx, restore)).Should(Succeed())
+			job := v1batch.Job{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, types.NamespacedName{Name: "restore-restore", Namespace: ns}, &job)
+			}, timeout, interval).Should(Succeed())
+
+			// a cnvrgApp reconcile doesn't scale the quiesced sidekiq back while the restore is running
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: testApp.Name, Namespace: ns}, testApp)).Should(Succeed())
+			testApp.Spec.Labels = map[string]string{"restore": "running"}
+			Expect(k8sClient.Update(ctx, testApp)).Should(Succeed())
+			Consistently(func() int32 {
+				_ = k8sClient.Get(ctx, types.NamespacedName{Name: "sidekiq", Namespace: ns}, sidekiq)
+				return *sidekiq.Spec.Replicas
+			}, 5*time.Second, interval).Should(BeEquivalentTo(0))
+			Expect(sidekiq.Labels).ShouldNot(HaveKey("restore"))
+
+			job.Status.Conditions = []v1batch.JobCondition{{Type: v1batch.JobFailed, Status: corev1.ConditionTrue}}
+			Expect(k8sClient.Status().Update(ctx, &job)).Should(Succeed())
+			Eventually(func() int32 {
+				_ = k8sClient.Get(ctx, types.NamespacedName{Name: "sidekiq", Namespace: ns}, sidekiq)
+				return *sidekiq.Spec.Replicas
+			}, timeout, interval).Should(Equal(replicas))
+			// the held control plane is applied once the restore is completed
+			Eventually(func() map[string]string {
+				_ = k8sClient.Get(ctx, types.NamespacedName{Name: "sidekiq", Namespace: ns}, sidekiq)
+				return sidekiq.Labels
+			}, timeout, interval).Should(HaveKeyWithValue("restore", "running"))
+			Expect(k8sClient.Delete(ctx, testApp)).Should(Succeed())
+		})
+
+		It("Restore artifacts are selected by database", func() {
+			artifacts := []mlopsv1.BackupArtifact{
+				{Database: mlopsv1.PgBackupDatabase, Location: "s3://bucket/ns/cnvrg-backups/nightly/20211011120000/pg.dump"},
+				{Database: mlopsv1.RedisBackupDatabase, Location: "s3://bucket/ns/cnvrg-backups/nightly/20211011120000/redis.rdb"},
+			}
+			selected, err := restoreArtifacts(artifacts, []mlopsv1.BackupDatabase{mlopsv1.RedisBackupDatabase})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(selected).Should(Equal(artifacts[1:]))
+			_, err = restoreArtifacts(artifacts, []mlopsv1.BackupDatabase{mlopsv1.EsBackupDatabase})
+			Expect(err).To(HaveOccurred())
+
+			testApp := getDefaultTestAppSpec("restore-ns")
+			Expect(restoreWorkloads(testApp, artifacts)).Should(ContainElement(testApp.Spec.Dbs.Redis.SvcName))
+			Expect(restoreWorkloads(testApp, artifacts[:1])).ShouldNot(ContainElement(testApp.Spec.Dbs.Redis.SvcName))
+		})
+	})
+})
@@ -17,13 +17,10 @@ import (
 	"github.com/AccessibleAI/cnvrg-operator/pkg/registry"
 	"github.com/AccessibleAI/cnvrg-operator/pkg/reloader"
 	"github.com/AccessibleAI/cnvrg-operator/pkg/storage"
-	"github.com/Dimss/crypt/apr1_crypt"
 	"github.com/go-logr/logr"
 	"github.com/imdario/mergo"
 	"github.com/markbates/pkger"
 	"github.com/spf13/viper"
-	"go.uber.org/zap"
-	"gopkg.in/d4l3k/messagediff.v1"
 	"io/ioutil"
 	v1 "k8s.io/api/core/v1"
 	v1core "k8s.io/api/core/v1"
@@ -34,15 +31,16 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
-	"os"
 	"path/filepath"
-	"reflect"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 	"sort"
 	"strings"
 	"time"
@@ -57,276 +55,379 @@ type CnvrgInfraReconciler struct {
 	Scheme   *runtime.Scheme
 }
 
-var infraLog logr.Logger
-
 // +kubebuilder:rbac:groups=mlops.cnvrg.io,resources=cnvrginfras,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=mlops.cnvrg.io,resources=cnvrginfras/status,verbs=get;update;patch
 
 func (r *CnvrgInfraReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	infraLog = r.Log.WithValues("name", req.NamespacedName)
-	infraLog.Info("starting cnvrginfra reconciliation")
+	// the logger is request scoped, reconciles of different objects run concurrently
+	log := r.Log.WithValues("name", req.NamespacedName)
+	ctx = logf.IntoContext(ctx, log)
+	log.Info("starting cnvrginfra reconciliation")
 
-	// sync specs between actual and defaults
-	equal, err := r.syncCnvrgInfraSpec(req.NamespacedName)
+	infra, err := r.getCnvrgInfraSpec(ctx, req.NamespacedName)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
-	if !equal {
-		requeueAfter, _ := time.ParseDuration("3s")
-		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	if infra == nil {
+		return ctrl.Result{}, nil // probably spec was deleted, no need to reconcile
 	}
 
-	// specs are synced, proceed reconcile
-	cnvrgInfra, err := r.getCnvrgInfraSpec(req.NamespacedName)
+	// the defaults are applied in memory, updates (e.g. finalizers) are done on the stored spec
+	cnvrgInfra, err := effectiveCnvrgInfra(ctx, infra, r.Client)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
-	if cnvrgInfra == nil {
-		return ctrl.Result{}, nil // probably spec was deleted, no need to reconcile
-	}
 
 	// setup finalizer
-	if cnvrgInfra.ObjectMeta.DeletionTimestamp.IsZero() {
-		if !containsString(cnvrgInfra.ObjectMeta.Finalizers, CnvrginfraFinalizer) {
-			cnvrgInfra.ObjectMeta.Finalizers = append(cnvrgInfra.ObjectMeta.Finalizers, CnvrginfraFinalizer)
-			if err := r.Update(ctx, cnvrgInfra); err != nil {
-				infraLog.Error(err, "failed to add finalizer")
+	if infra.ObjectMeta.DeletionTimestamp.IsZero() {
+		if !containsString(infra.ObjectMeta.Finalizers, CnvrginfraFinalizer) {
+			infra.ObjectMeta.Finalizers = append(infra.ObjectMeta.Finalizers, CnvrginfraFinalizer)
+			if err := r.Update(ctx, infra); err != nil {
+				log.Error(err, "failed to add finalizer")
 				return ctrl.Result{}, err
 			}
 		}
 	} else {
-		if containsString(cnvrgInfra.ObjectMeta.Finalizers, CnvrginfraFinalizer) {
-			r.updateStatusMessage(mlopsv1.StatusRemoving, "removing cnvrg spec", cnvrgInfra)
-			if err := r.cleanup(cnvrgInfra); err != nil {
+		if containsString(infra.ObjectMeta.Finalizers, CnvrginfraFinalizer) {
+			r.updateStatusMessage(ctx, mlopsv1.Status{Status: mlopsv1.StatusRemoving, Message: "removing cnvrg spec"}, cnvrgInfra)
+			if err := r.cleanup(ctx, cnvrgInfra); err != nil {
 				return ctrl.Result{}, err
 			}
-			cnvrgInfra.ObjectMeta.Finalizers = removeString(cnvrgInfra.ObjectMeta.Finalizers, CnvrginfraFinalizer)
 			err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
-				if err := r.Update(ctx, cnvrgInfra); err != nil {
-					cnvrgInfra, err := r.getCnvrgInfraSpec(req.NamespacedName)
-					if err != nil {
-						infraLog.Error(err, "error getting cnvrginfra for finalizer cleanup")
-						return err
-					}
-					cnvrgInfra.ObjectMeta.Finalizers = removeString(cnvrgInfra.ObjectMeta.Finalizers, CnvrginfraFinalizer)
-					return r.Update(ctx, cnvrgInfra)
+				infra, err := r.getCnvrgInfraSpec(ctx, req.NamespacedName)
+				if err != nil {
+					log.Error(err, "error getting cnvrginfra for finalizer cleanup")
+					return err
 				}
-				return err
+				if infra == nil {
+					return nil
+				}
+				infra.ObjectMeta.Finalizers = removeString(infra.ObjectMeta.Finalizers, CnvrginfraFinalizer)
+				return r.Update(ctx, infra)
 			})
 			if err != nil {
-				infraLog.Info("error in removing finalizer")
+				log.Info("error in removing finalizer")
 				return ctrl.Result{}, err
 			}
+			deleteReadinessMetrics(cnvrgInfra)
 		}
 		return ctrl.Result{}, nil
 	}
+	saveEffectiveSpec(ctx, r.Client, r.Scheme, cnvrgInfra, cnvrgInfra.Spec.InfraNamespace, redactedCnvrgInfraSpec(cnvrgInfra))
 
-	r.updateStatusMessage(mlopsv1.StatusReconciling, "reconciling", cnvrgInfra)
-
-	// apply manifests
-	if err := r.applyManifests(cnvrgInfra); err != nil {
+	// the secret references are resolved after the effective spec is saved, so the credentials are not dumped
+	missing, err := resolveSecretRefs(ctx, r.Client, cnvrgInfra.Spec.InfraNamespace, infraSecretRefs(cnvrgInfra))
+	if err != nil {
 		return ctrl.Result{}, err
 	}
+	secretRefsResolved := secretRefsCondition(missing)
+	if len(missing) > 0 {
+		err := fmt.Errorf("can't resolve secret references: %s", secretRefsResolved.Message)
+		log.Error(err, "missing secret references")
+		r.updateStatusMessage(ctx, mlopsv1.Status{Status: mlopsv1.StatusError, Message: err.Error(), Progress: -1, Conditions: []metav1.Condition{secretRefsResolved, reconciledCondition(nil, err)}}, cnvrgInfra)
+		// the referenced secrets are watched, the cnvrgInfra is reconciled again once they are created
+		return ctrl.Result{}, nil
+	}
+	cnvrgInfra.Spec.SecretRefsChecksum = secretRefsChecksum(infraSecretRefs(cnvrgInfra))
+	conditions := []metav1.Condition{secretRefsResolved}
 
-	r.updateStatusMessage(mlopsv1.StatusHealthy, "successfully reconciled", cnvrgInfra)
-	infraLog.Info("successfully reconciled")
-	return ctrl.Result{}, nil
-}
-
-func (r *CnvrgInfraReconciler) applyManifests(cnvrgInfra *mlopsv1.CnvrgInfra) error {
-
-	var reconcileResult error
+	// the validating webhook rejects invalid pod spec overrides, without it they are reported by the reconciler
+	if !viper.GetBool("enable-webhooks") {
+		overridesValid := podSpecOverridesCondition(cnvrgInfra.Spec.ValidatePodSpecOverrides())
+		if overridesValid.Status == metav1.ConditionFalse {
+			err := fmt.Errorf("invalid pod spec overrides: %s", overridesValid.Message)
+			log.Error(err, "invalid pod spec overrides")
+			r.updateStatusMessage(ctx, mlopsv1.Status{Status: mlopsv1.StatusError, Message: err.Error(), Progress: -1, Conditions: []metav1.Condition{overridesValid, reconciledCondition(nil, err)}}, cnvrgInfra)
+			// the cnvrgInfra is reconciled again once the spec is fixed
+			return ctrl.Result{}, nil
+		}
+		conditions = append(conditions, overridesValid)
+	}
 
-	// apply priority classes
-	if err := desired.Apply(priorityclass.State(), cnvrgInfra, r.Client, r.Scheme, infraLog); err != nil {
-		r.updateStatusMessage(mlopsv1.StatusError, err.Error(), cnvrgInfra)
-		reconcileResult = err
+	// check if enabled infra workloads are all in ready status
+	_, percentageReady, stackReadiness, err := r.getInfraReadinessStatus(ctx, cnvrgInfra)
+	if err != nil {
+		return ctrl.Result{}, err
 	}
+	// even if all infra workloads are ready, let operator finish the full reconcile loop
+	if percentageReady == 100 {
+		percentageReady = 99
+	}
+	s := mlopsv1.Status{
+		Status:         mlopsv1.StatusReconciling,
+		Message:        fmt.Sprintf("reconciling... (%d%%)", percentageReady),
+		Progress:       percentageReady,
+		StackReadiness: stackReadiness}
+	r.updateStatusMessage(ctx, s, cnvrgInfra)
 
-	// registry
-	infraLog.Info("applying registry")
-	registryData := desired.TemplateData{
-		Namespace: cnvrgInfra.Spec.InfraNamespace,
-		Data: map[string]interface{}{
-			"Registry":    cnvrgInfra.Spec.Registry,
-			"Annotations": cnvrgInfra.Spec.Annotations,
-			"Labels":      cnvrgInfra.Spec.Labels,
-		},
+	// apply manifests
+	// the overrides are matched while the manifests are rendered
+	matches := desired.NewOverrideMatches()
+	pending, err := r.applyManifests(desired.WithOverrideMatches(ctx, matches), cnvrgInfra)
+	if err != nil {
+		return ctrl.Result{}, err
 	}
-	if err := desired.Apply(registry.State(registryData), cnvrgInfra, r.Client, r.Scheme, infraLog); err != nil {
-		r.updateStatusMessage(mlopsv1.StatusError, err.Error(), cnvrgInfra)
-		reconcileResult = err
+	reconciled := reconciledCondition(pending, nil)
+	// the pending components aren't rendered, their overrides are matched once they are applied
+	if len(pending) == 0 {
+		conditions = append(conditions, overridesMatchedCondition(matches.Unmatched(cnvrgInfra.Spec.Overrides)))
 	}
 
-	// infra reconciler trigger configmap
-	if err := r.createInfraReconcilerTriggerCm(cnvrgInfra); err != nil {
-		r.updateStatusMessage(mlopsv1.StatusError, err.Error(), cnvrgInfra)
-		reconcileResult = err
+	// get infra readiness
+	ready, percentageReady, stackReadiness, err := r.getInfraReadinessStatus(ctx, cnvrgInfra)
+	if err != nil {
+		return ctrl.Result{}, err
 	}
 
-	// config reloader
-	infraLog.Info("applying config reloader")
-	if err := desired.Apply(reloader.State(cnvrgInfra), cnvrgInfra, r.Client, r.Scheme, infraLog); err != nil {
-		r.updateStatusMessage(mlopsv1.StatusError, err.Error(), cnvrgInfra)
-		reconcileResult = err
+	if ready && len(pending) == 0 {
+		s := mlopsv1.Status{
+			Status:         mlopsv1.StatusHealthy,
+			Message:        fmt.Sprintf("successfully reconciled, ready (%d%%)", percentageReady),
+			Progress:       percentageReady,
+			StackReadiness: stackReadiness,
+			Conditions:     append([]metav1.Condition{reconciled}, conditions...)}
+		r.updateStatusMessage(ctx, s, cnvrgInfra)
+		log.Info("successfully reconciled")
+		return ctrl.Result{}, nil
 	}
 
-	// storage
-	infraLog.Info("applying storage")
-	if err := desired.Apply(storage.State(cnvrgInfra), cnvrgInfra, r.Client, r.Scheme, infraLog); err != nil {
-		r.updateStatusMessage(mlopsv1.StatusError, err.Error(), cnvrgInfra)
-		reconcileResult = err
+	if percentageReady == 100 {
+		percentageReady = 99
 	}
+	s = mlopsv1.Status{
+		Status:         mlopsv1.StatusReconciling,
+		Message:        fmt.Sprintf("reconciling... (%d%%)", percentageReady),
+		Progress:       percentageReady,
+		StackReadiness: stackReadiness,
+		Conditions:     append([]metav1.Condition{reconciled}, conditions...)}
+	r.updateStatusMessage(ctx, s, cnvrgInfra)
+	requeueAfter, _ := time.ParseDuration("30s")
+	log.Info("infra stack not ready yet, requeuing...", "progress", percentageReady)
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
 
-	// redis
-	if cnvrgInfra.Spec.Dbs.Redis.Enabled || cnvrgInfra.Spec.SSO.Enabled {
-		redisSecretData := desired.TemplateData{
-			Data: map[string]interface{}{
-				"Namespace":   cnvrgInfra.Spec.InfraNamespace,
-				"Annotations": cnvrgInfra.Spec.Annotations,
-				"Labels":      cnvrgInfra.Spec.Labels,
-				"CredsRef":    cnvrgInfra.Spec.Dbs.Redis.CredsRef,
-				"SvcName":     cnvrgInfra.Spec.Dbs.Redis.SvcName,
-			},
-		}
-		infraLog.Info("trying to generate redis creds (if still doesn't exists...)")
-		if err := desired.Apply(dbs.RedisCreds(redisSecretData), cnvrgInfra, r.Client, r.Scheme, infraLog); err != nil {
-			r.updateStatusMessage(mlopsv1.StatusError, err.Error(), cnvrgInfra)
-			return err
+func (r *CnvrgInfraReconciler) getInfraReadinessStatus(ctx context.Context, infra *mlopsv1.CnvrgInfra) (bool, int, map[string]bool, error) {
+
+	readyState := make(map[string]bool)
+	ns := infra.Spec.InfraNamespace
+
+	type check struct {
+		key     string
+		enabled bool
+		name    string
+		ready   func(context.Context, client.Reader, types.NamespacedName) (bool, error)
+	}
+	istio := infra.Spec.Networking.Ingress.Type == mlopsv1.IstioIngress && infra.Spec.Networking.Istio.Enabled
+	checks := []check{
+		// networking
+		{"istioOperator", istio, "istio-operator", checkDeploymentReadiness},
+		{"istiod", istio, "istiod", checkDeploymentReadiness},
+		// storage provisioners
+		{"hostpath", infra.Spec.Storage.Hostpath.Enabled, "hostpath-provisioner", checkDaemonSetReadiness},
+		{"nfs", infra.Spec.Storage.Nfs.Enabled, "nfs-client-provisioner", checkDeploymentReadiness},
+		// dbs
+		{"redis", infra.Spec.Dbs.Redis.Enabled, infra.Spec.Dbs.Redis.SvcName, checkDeploymentReadiness},
+		// logging
+		{"fluentbit", infra.Spec.Logging.Fluentbit.Enabled, "cnvrg-fluentbit", checkDaemonSetReadiness},
+		// monitoring
+		{"prometheusOperator", infra.Spec.Monitoring.PrometheusOperator.Enabled, "cnvrg-prometheus-operator", checkDeploymentReadiness},
+		{"prometheus", infra.Spec.Monitoring.Prometheus.Enabled, "prometheus-cnvrg-infra-prometheus", checkStatefulSetReadiness},
+		{"grafana", infra.Spec.Monitoring.Grafana.Enabled, infra.Spec.Monitoring.Grafana.SvcName, checkDeploymentReadiness},
+		{"kubeStateMetrics", infra.Spec.Monitoring.KubeStateMetrics.Enabled, "kube-state-metrics", checkDeploymentReadiness},
+		{"nodeExporter", infra.Spec.Monitoring.NodeExporter.Enabled, "node-exporter", checkDaemonSetReadiness},
+		{"dcgmExporter", infra.Spec.Monitoring.DcgmExporter.Enabled, "dcgm-exporter", checkDaemonSetReadiness},
+		{"habanaExporter", infra.Spec.Monitoring.HabanaExporter.Enabled, "habana-exporter", checkDaemonSetReadiness},
+		// device plugins
+		{"nvidiaDp", infra.Spec.Gpu.NvidiaDp.Enabled, "nvidia-device-plugin-daemonset", checkDaemonSetReadiness},
+		{"habanaDp", infra.Spec.Gpu.HabanaDp.Enabled, "habanalabs-device-plugin-daemonset-hpu", checkDaemonSetReadiness},
+		{"metagpuDp", infra.Spec.Gpu.MetaGpuDp.Enabled, "metagpu-device-plugin", checkDaemonSetReadiness},
+		// config reloader and capsule
+		{"configReloader", infra.Spec.ConfigReloader.Enabled, "config-reloader", checkDeploymentReadiness},
+		{"capsule", infra.Spec.Capsule.Enabled, infra.Spec.Capsule.SvcName, checkDeploymentReadiness},
+	}
+
+	for _, c := range checks {
+		if !c.enabled {
+			continue
 		}
-		infraLog.Info("applying redis")
-		if err := desired.Apply(dbs.InfraDbsState(cnvrgInfra), cnvrgInfra, r.Client, r.Scheme, infraLog); err != nil {
-			r.updateStatusMessage(mlopsv1.StatusError, err.Error(), cnvrgInfra)
-			reconcileResult = err
+		ready, err := c.ready(ctx, r, types.NamespacedName{Name: c.name, Namespace: ns})
+		if err != nil {
+			return false, 0, nil, err
 		}
+		readyState[c.key] = ready
 	}
 
-	// logging
-	infraLog.Info("applying logging")
-	cnvrgApps, err := r.getCnvrgAppInstances(cnvrgInfra)
+	ready, percentageReady := stackProgress(readyState)
+	return ready, percentageReady, readyState, nil
+}
+
+// applyManifests applies the components, returns the components which are waiting for their dependencies
+func (r *CnvrgInfraReconciler) applyManifests(ctx context.Context, cnvrgInfra *mlopsv1.CnvrgInfra) ([]string, error) {
+	log := logf.FromContext(ctx)
+
+	inventory := desired.NewInventory()
+	pending, err := applyComponents(ctx, r.infraComponents(ctx, cnvrgInfra), cnvrgInfra, r.Client, r.Scheme, inventory)
 	if err != nil {
-		r.updateStatusMessage(mlopsv1.StatusError, err.Error(), cnvrgInfra)
-		reconcileResult = err
-	}
-	fluentbitData := desired.TemplateData{
-		Namespace: cnvrgInfra.Spec.InfraNamespace,
-		Data: map[string]interface{}{
-			"AppInstance":           cnvrgApps,
-			"Annotations":           cnvrgInfra.Spec.Annotations,
-			"Labels":                cnvrgInfra.Spec.Labels,
-			"ClusterInternalDomain": cnvrgInfra.Spec.ClusterInternalDomain,
-			"CriType":               cnvrgInfra.Spec.Cri,
-		},
+		r.updateStatusMessage(ctx, mlopsv1.Status{Status: mlopsv1.StatusError, Message: err.Error(), Progress: -1, Conditions: []metav1.Condition{reconciledCondition(nil, err)}}, cnvrgInfra)
+		return nil, err
+	}
+
+	// prune resources of the disabled components, only when all the components has been applied,
+	// otherwise the inventory is partial and the pending components would be pruned
+	if len(pending) > 0 {
+		log.Info("components are waiting for dependencies", "components", pending)
+		return pending, nil
 	}
-	if err := desired.Apply(logging.FluentbitConfigurationState(fluentbitData), cnvrgInfra, r.Client, r.Scheme, infraLog); err != nil {
-		r.updateStatusMessage(mlopsv1.StatusError, err.Error(), cnvrgInfra)
-		reconcileResult = err
+	inventoryName := types.NamespacedName{Name: cnvrgInfra.Name + "-inventory", Namespace: cnvrgInfra.Spec.InfraNamespace}
+	if err := pruneComponents(ctx, inventoryName, inventory, cnvrgInfra, r.Client, r.Scheme); err != nil {
+		r.updateStatusMessage(ctx, mlopsv1.Status{Status: mlopsv1.StatusError, Message: err.Error(), Progress: -1, Conditions: []metav1.Condition{reconciledCondition(nil, err)}}, cnvrgInfra)
+		return nil, err
 	}
-	if err := desired.Apply(logging.InfraLoggingState(cnvrgInfra), cnvrgInfra, r.Client, r.Scheme, infraLog); err != nil {
-		r.updateStatusMessage(mlopsv1.StatusError, err.Error(), cnvrgInfra)
-		reconcileResult = err
+
+	return nil, nil
+}
+
+func (r *CnvrgInfraReconciler) infraComponents(ctx context.Context, infra *mlopsv1.CnvrgInfra) []component {
+	return []component{
+		{name: "priority classes", states: func() ([]*desired.State, error) {
+			return priorityclass.State(), nil
+		}},
+		{name: "registry", states: func() ([]*desired.State, error) {
+			return registry.State(infraRegistryData(infra)), nil
+		}},
+		{name: "infra reconciler trigger configmap", sync: func() error {
+			return r.createInfraReconcilerTriggerCm(ctx, infra)
+		}},
+		{name: "config reloader", states: func() ([]*desired.State, error) {
+			return reloader.State(infra), nil
+		}},
+		{name: "storage", states: func() ([]*desired.State, error) {
+			return storage.State(infra), nil
+		}},
+		{name: "redis", states: func() ([]*desired.State, error) {
+			return infraRedisState(infra), nil
+		}},
+		{name: "logging", states: func() ([]*desired.State, error) {
+			return r.loggingState(ctx, infra)
+		}},
+		{name: "infra networking", states: func() ([]*desired.State, error) {
+			return networking.InfraNetworkingState(infra), nil
+		}},
+		{name: "monitoring secrets", states: func() ([]*desired.State, error) {
+			return monitoringSecretsState(ctx, infra)
+		}},
+		{name: "monitoring", dependsOn: []string{"monitoring secrets"}, states: func() ([]*desired.State, error) {
+			return r.monitoringState(ctx, infra)
+		}, sync: func() error {
+			return r.createGrafanaDashboards(ctx, infra)
+		}},
+		{name: "mpi infra", states: func() ([]*desired.State, error) {
+			return controlplane.MpiInfraState(), nil
+		}},
+		{name: "device plugins", states: func() ([]*desired.State, error) {
+			return devicePluginsState(infra), nil
+		}},
+		{name: "metagpu presence", states: func() ([]*desired.State, error) {
+			// turn on/off metagpu presence cm in each app ns based on the infra state
+			return r.metagpuPresenceState(ctx, infra)
+		}},
+		{name: "capsule", states: func() ([]*desired.State, error) {
+			return capsule.State(infra), nil
+		}},
 	}
+}
 
-	// istio
-	infraLog.Info("applying infra networking")
-	if err := desired.Apply(networking.InfraNetworkingState(cnvrgInfra), cnvrgInfra, r.Client, r.Scheme, infraLog); err != nil {
-		r.updateStatusMessage(mlopsv1.StatusError, err.Error(), cnvrgInfra)
-		reconcileResult = err
+func infraRegistryData(infra *mlopsv1.CnvrgInfra) registry.Data {
+	return registry.Data{
+		Namespace:   infra.Spec.InfraNamespace,
+		Registry:    infra.Spec.Registry,
+		Annotations: infra.Spec.Annotations,
+		Labels:      infra.Spec.Labels,
 	}
+}
 
-	// monitoring
-	infraLog.Info("applying monitoring")
-	if err := r.monitoringState(cnvrgInfra); err != nil {
-		r.updateStatusMessage(mlopsv1.StatusError, err.Error(), cnvrgInfra)
-		reconcileResult = err
+func infraRedisState(infra *mlopsv1.CnvrgInfra) []*desired.State {
+	if !infra.Spec.Dbs.Redis.Enabled && !infra.Spec.SSO.Enabled {
+		return nil
 	}
+	// redis creds secret is not updatable, hence generated only if still doesn't exists
+	redisSecretData := dbs.RedisCredsData{
+		Namespace:   infra.Spec.InfraNamespace,
+		Annotations: infra.Spec.Annotations,
+		Labels:      infra.Spec.Labels,
+		CredsRef:    infra.Spec.Dbs.Redis.CredsRef,
+		SvcName:     infra.Spec.Dbs.Redis.SvcName,
+	}
+	return append(dbs.RedisCreds(redisSecretData), dbs.InfraDbsState(infra)...)
+}
 
-	// mpi infra
-	infraLog.Info("applying mpi infra")
-	if err := desired.Apply(controlplane.MpiInfraState(), cnvrgInfra, r.Client, r.Scheme, infraLog); err != nil {
-		r.updateStatusMessage(mlopsv1.StatusError, err.Error(), cnvrgInfra)
-		reconcileResult = err
+func (r *CnvrgInfraReconciler) loggingState(ctx context.Context, infra *mlopsv1.CnvrgInfra) ([]*desired.State, error) {
+	cnvrgApps, err := r.getCnvrgAppInstances(ctx, infra)
+	if err != nil {
+		return nil, err
 	}
+	fluentbitData := logging.FluentbitData{
+		Namespace:             infra.Spec.InfraNamespace,
+		AppInstance:           cnvrgApps,
+		Annotations:           infra.Spec.Annotations,
+		Labels:                infra.Spec.Labels,
+		ClusterInternalDomain: infra.Spec.ClusterInternalDomain,
+		CriType:               infra.Spec.Cri,
+	}
+	return append(logging.FluentbitConfigurationState(fluentbitData), logging.InfraLoggingState(infra)...), nil
+}
+
+func devicePluginsState(infra *mlopsv1.CnvrgInfra) []*desired.State {
+	var state []*desired.State
 
 	// nvidia device plugin
-	if cnvrgInfra.Spec.Gpu.NvidiaDp.Enabled {
-		infraLog.Info("nvidia device plugin")
-		nvidiaDpData := desired.TemplateData{
-			Namespace: cnvrgInfra.Spec.InfraNamespace,
-			Data: map[string]interface{}{
-				"NvidiaDp":    cnvrgInfra.Spec.Gpu.NvidiaDp,
-				"Registry":    cnvrgInfra.Spec.Registry,
-				"ImageHub":    cnvrgInfra.Spec.ImageHub,
-				"Annotations": cnvrgInfra.Spec.Annotations,
-				"Labels":      cnvrgInfra.Spec.Labels,
-			},
-		}
-		if err := desired.Apply(gpu.NvidiaDpState(nvidiaDpData), cnvrgInfra, r.Client, r.Scheme, infraLog); err != nil {
-			r.updateStatusMessage(mlopsv1.StatusError, err.Error(), cnvrgInfra)
-			reconcileResult = err
+	if infra.Spec.Gpu.NvidiaDp.Enabled {
+		nvidiaDpData := gpu.NvidiaDpData{
+			Namespace:   infra.Spec.InfraNamespace,
+			NvidiaDp:    infra.Spec.Gpu.NvidiaDp,
+			Registry:    infra.Spec.Registry,
+			ImageHub:    infra.Spec.ImageHub,
+			Annotations: infra.Spec.Annotations,
+			Labels:      infra.Spec.Labels,
 		}
+		state = append(state, gpu.NvidiaDpState(nvidiaDpData)...)
 	}
 
 	// habana device plugin
-	if cnvrgInfra.Spec.Gpu.HabanaDp.Enabled {
-		infraLog.Info("habana device plugin")
-		habanaDpData := desired.TemplateData{
-			Namespace: cnvrgInfra.Spec.InfraNamespace,
-			Data: map[string]interface{}{
-				"HabanaDp":    cnvrgInfra.Spec.Gpu.HabanaDp,
-				"Registry":    cnvrgInfra.Spec.Registry,
-				"ImageHub":    cnvrgInfra.Spec.ImageHub,
-				"Annotations": cnvrgInfra.Spec.Annotations,
-				"Labels":      cnvrgInfra.Spec.Labels,
-			},
-		}
-		if err := desired.Apply(gpu.HabanaDpState(habanaDpData), cnvrgInfra, r.Client, r.Scheme, infraLog); err != nil {
-			r.updateStatusMessage(mlopsv1.StatusError, err.Error(), cnvrgInfra)
-			reconcileResult = err
+	if infra.Spec.Gpu.HabanaDp.Enabled {
+		habanaDpData := gpu.HabanaDpData{
+			Namespace:   infra.Spec.InfraNamespace,
+			HabanaDp:    infra.Spec.Gpu.HabanaDp,
+			Registry:    infra.Spec.Registry,
+			ImageHub:    infra.Spec.ImageHub,
+			Annotations: infra.Spec.Annotations,
+			Labels:      infra.Spec.Labels,
 		}
+		state = append(state, gpu.HabanaDpState(habanaDpData)...)
 	}
 
 	// metagpu device plugin
-	if cnvrgInfra.Spec.Gpu.MetaGpuDp.Enabled {
-		infraLog.Info("metagpu device plugin")
-		metagpuDpData := desired.TemplateData{
-			Namespace: cnvrgInfra.Spec.InfraNamespace,
-			Data: map[string]interface{}{
-				"Annotations": cnvrgInfra.Spec.Annotations,
-				"Labels":      cnvrgInfra.Spec.Labels,
-				"MetaGpuDp":   cnvrgInfra.Spec.Gpu.MetaGpuDp,
-				"ImageHub":    cnvrgInfra.Spec.ImageHub,
-			},
+	if infra.Spec.Gpu.MetaGpuDp.Enabled {
+		metagpuDpData := gpu.MetaGpuDpData{
+			Namespace:   infra.Spec.InfraNamespace,
+			Annotations: infra.Spec.Annotations,
+			Labels:      infra.Spec.Labels,
+			MetaGpuDp:   infra.Spec.Gpu.MetaGpuDp,
+			ImageHub:    infra.Spec.ImageHub,
 		}
-		// apply metagpu infra state
-		if err := desired.Apply(gpu.MetagpudpState(metagpuDpData), cnvrgInfra, r.Client, r.Scheme, infraLog); err != nil {
-			r.updateStatusMessage(mlopsv1.StatusError, err.Error(), cnvrgInfra)
-			reconcileResult = err
-		}
-
-	}
-	// turn on/off metagpu presence cm in each app ns based on the infra state
-	if err := r.setMetagpuPresence(cnvrgInfra); err != nil {
-		r.updateStatusMessage(mlopsv1.StatusError, err.Error(), cnvrgInfra)
-		reconcileResult = err
+		state = append(state, gpu.MetagpudpState(metagpuDpData)...)
 	}
 
-	// capsule backup service
-	infraLog.Info("applying capsule")
-	if err := desired.Apply(capsule.State(cnvrgInfra), cnvrgInfra, r.Client, r.Scheme, infraLog); err != nil {
-		r.updateStatusMessage(mlopsv1.StatusError, err.Error(), cnvrgInfra)
-		reconcileResult = err
-	}
-
-	return reconcileResult
+	return state
 }
 
-func (r *CnvrgInfraReconciler) getCnvrgAppInstances(infra *mlopsv1.CnvrgInfra) ([]mlopsv1.AppInstance, error) {
+func (r *CnvrgInfraReconciler) getCnvrgAppInstances(ctx context.Context, infra *mlopsv1.CnvrgInfra) ([]mlopsv1.AppInstance, error) {
+	log := logf.FromContext(ctx)
 
 	cmName := types.NamespacedName{Namespace: infra.Spec.InfraNamespace, Name: mlopsv1.InfraReconcilerCm}
 
 	cnvrgAppCm := &v1.ConfigMap{}
-	if err := r.Get(context.Background(), cmName, cnvrgAppCm); err != nil && errors.IsNotFound(err) {
+	if err := r.Get(ctx, cmName, cnvrgAppCm); err != nil && errors.IsNotFound(err) {
 		return nil, nil
 	} else if err != nil {
 		return nil, err
@@ -341,7 +442,7 @@ func (r *CnvrgInfraReconciler) getCnvrgAppInstances(infra *mlopsv1.CnvrgInfra) (
 	for _, key := range cmKeys {
 		var app mlopsv1.AppInstance
 		if err := json.Unmarshal([]byte(cnvrgAppCm.Data[key]), &app); err != nil {
-			infraLog.Error(err, "error decoding AppInstance")
+			log.Error(err, "error decoding AppInstance")
 			return nil, err
 		}
 		apps = append(apps, app)
@@ -349,81 +450,62 @@ func (r *CnvrgInfraReconciler) getCnvrgAppInstances(infra *mlopsv1.CnvrgInfra) (
 	return apps, nil
 }
 
-func (r *CnvrgInfraReconciler) monitoringState(infra *mlopsv1.CnvrgInfra) error {
-
-	if err := r.generateMonitoringSecrets(infra); err != nil {
-		return err
-	}
-	if err := desired.Apply(monitoring.InfraMonitoringState(infra), infra, r.Client, r.Scheme, infraLog); err != nil {
-		return err
-	}
-	return nil
-}
-
-func (r *CnvrgInfraReconciler) generateMonitoringSecrets(infra *mlopsv1.CnvrgInfra) error {
+func (r *CnvrgInfraReconciler) monitoringState(ctx context.Context, infra *mlopsv1.CnvrgInfra) ([]*desired.State, error) {
+	log := logf.FromContext(ctx)
+	var state []*desired.State
 
-	if infra.Spec.Monitoring.Prometheus.Enabled {
-		user := "cnvrg"
-		pass := desired.RandomString()
-		passHash, err := apr1_crypt.New().Generate([]byte(pass), nil)
+	if infra.Spec.Monitoring.Grafana.Enabled {
+		// grafana datasource
+		url, basicAuthUser, basicAuthPass, err := desired.GetPromCredsSecret(ctx, infra.Spec.Monitoring.Prometheus.CredsRef, infra.Spec.InfraNamespace, r.Client, log)
 		if err != nil {
-			infraLog.Error(err, "error generating prometheus hash")
-			return err
-		}
-		promSecretData := desired.TemplateData{
-			Data: map[string]interface{}{
-				"Namespace":   infra.Spec.InfraNamespace,
-				"Annotations": infra.Spec.Annotations,
-				"Labels":      infra.Spec.Labels,
-				"CredsRef":    infra.Spec.Monitoring.Prometheus.CredsRef,
-				"User":        user,
-				"Pass":        pass,
-				"PassHash":    fmt.Sprintf("%s:%s", user, passHash),
-				"PromUrl":     fmt.Sprintf("http://%s.%s.svc:%d", infra.Spec.Monitoring.Prometheus.SvcName, infra.Spec.InfraNamespace, infra.Spec.Monitoring.Prometheus.Port),
-			},
+			return nil, err
 		}
-		infraLog.Info("trying to generate prometheus creds (if still doesn't exists...)")
-		if err := desired.Apply(monitoring.PromCreds(promSecretData), infra, r.Client, r.Scheme, infraLog); err != nil {
-			r.updateStatusMessage(mlopsv1.StatusError, err.Error(), infra)
-			return err
+		grafanaDatasourceData := monitoring.GrafanaDSData{
+			Namespace:   infra.Spec.InfraNamespace,
+			Url:         url,
+			User:        basicAuthUser,
+			Pass:        basicAuthPass,
+			Annotations: infra.Spec.Annotations,
+			Labels:      infra.Spec.Labels,
 		}
+		state = append(state, monitoring.GrafanaDSState(grafanaDatasourceData)...)
 	}
 
-	if infra.Spec.Monitoring.Grafana.Enabled {
-		// grafana dashboards
-		infraLog.Info("applying grafana dashboards")
-		if err := r.createGrafanaDashboards(infra); err != nil {
-			return err
-		}
+	return append(state, monitoring.InfraMonitoringState(infra)...), nil
+}
 
-		// grafana datasource
-		infraLog.Info("applying grafana datasource")
-		url, basicAuthUser, basicAuthPass, err := desired.GetPromCredsSecret(infra.Spec.Monitoring.Prometheus.CredsRef, infra.Spec.InfraNamespace, r.Client, infraLog)
-		if err != nil {
-			return err
-		}
-		grafanaDatasourceData := desired.TemplateData{
-			Namespace: infra.Spec.InfraNamespace,
-			Data: map[string]interface{}{
-				"Url":         url,
-				"User":        basicAuthUser,
-				"Pass":        basicAuthPass,
-				"Annotations": infra.Spec.Annotations,
-				"Labels":      infra.Spec.Labels,
-			},
-		}
-		if err := desired.Apply(monitoring.GrafanaDSState(grafanaDatasourceData), infra, r.Client, r.Scheme, infraLog); err != nil {
-			return err
-		}
+func monitoringSecretsState(ctx context.Context, infra *mlopsv1.CnvrgInfra) ([]*desired.State, error) {
+	log := logf.FromContext(ctx)
+
+	if !infra.Spec.Monitoring.Prometheus.Enabled {
+		return nil, nil
 	}
 
-	return nil
+	user := "cnvrg"
+	pass := desired.RandomString(ctx)
+	passHash, err := desired.PasswordHash(ctx, pass)
+	if err != nil {
+		log.Error(err, "error generating prometheus hash")
+		return nil, err
+	}
+	promSecretData := monitoring.PromCredsData{
+		Namespace:   infra.Spec.InfraNamespace,
+		Annotations: infra.Spec.Annotations,
+		Labels:      infra.Spec.Labels,
+		CredsRef:    infra.Spec.Monitoring.Prometheus.CredsRef,
+		User:        user,
+		Pass:        pass,
+		PassHash:    fmt.Sprintf("%s:%s", user, passHash),
+		PromUrl:     fmt.Sprintf("http://%s.%s.svc:%d", infra.Spec.Monitoring.Prometheus.SvcName, infra.Spec.InfraNamespace, infra.Spec.Monitoring.Prometheus.Port),
+	}
+	return monitoring.PromCreds(promSecretData), nil
 }
 
-func (r *CnvrgInfraReconciler) createGrafanaDashboards(cnvrgInfra *mlopsv1.CnvrgInfra) error {
+func (r *CnvrgInfraReconciler) createGrafanaDashboards(ctx context.Context, cnvrgInfra *mlopsv1.CnvrgInfra) error {
+	log := logf.FromContext(ctx)
 
 	if !cnvrgInfra.Spec.Monitoring.Grafana.Enabled {
-		infraLog.Info("grafana disabled, skipping grafana deployment")
+		log.Info("grafana disabled, skipping grafana deployment")
 		return nil
 	}
 
@@ -431,12 +513,12 @@ func (r *CnvrgInfraReconciler) createGrafanaDashboards(cnvrgInfra *mlopsv1.Cnvrg
 	for _, dashboard := range desired.GrafanaInfraDashboards {
 		f, err := pkger.Open(basePath + dashboard)
 		if err != nil {
-			infraLog.Error(err, "error reading path", "path", dashboard)
+			log.Error(err, "error reading path", "path", dashboard)
 			return err
 		}
 		b, err := ioutil.ReadAll(f)
 		if err != nil {
-			infraLog.Error(err, "error reading", "file", dashboard)
+			log.Error(err, "error reading", "file", dashboard)
 			return err
 		}
 		cm := &v1core.ConfigMap{
@@ -447,14 +529,14 @@ func (r *CnvrgInfraReconciler) createGrafanaDashboards(cnvrgInfra *mlopsv1.Cnvrg
 			Data: map[string]string{filepath.Base(f.Name()): string(b)},
 		}
 		if err := ctrl.SetControllerReference(cnvrgInfra, cm, r.Scheme); err != nil {
-			infraLog.Error(err, "error setting controller reference", "file", f.Name())
+			log.Error(err, "error setting controller reference", "file", f.Name())
 			return err
 		}
-		if err := r.Create(context.Background(), cm); err != nil && errors.IsAlreadyExists(err) {
-			infraLog.V(1).Info("grafana dashboard already exists", "file", dashboard)
+		if err := r.Create(ctx, cm); err != nil && errors.IsAlreadyExists(err) {
+			log.V(1).Info("grafana dashboard already exists", "file", dashboard)
 			continue
 		} else if err != nil {
-			infraLog.Error(err, "error reading", "file", dashboard)
+			log.Error(err, "error reading", "file", dashboard)
 			return err
 		}
 	}
@@ -463,95 +545,65 @@ func (r *CnvrgInfraReconciler) createGrafanaDashboards(cnvrgInfra *mlopsv1.Cnvrg
 
 }
 
-func (r *CnvrgInfraReconciler) syncCnvrgInfraSpec(name types.NamespacedName) (bool, error) {
-
-	infraLog.Info("synchronizing cnvrgInfra spec")
-
-	// Fetch current cnvrgInfra spec
-	cnvrgInfra, err := r.getCnvrgInfraSpec(name)
-	if err != nil {
-		return false, err
-	}
-	if cnvrgInfra == nil {
-		return true, nil // all (probably) good, cnvrginfra was removed
-	}
-	infraLog = r.Log.WithValues("name", name, "ns", cnvrgInfra.Spec.InfraNamespace)
+// desiredCnvrgInfraSpec merges the cnvrgInfra spec into the default spec
+func desiredCnvrgInfraSpec(ctx context.Context, cnvrgInfra *mlopsv1.CnvrgInfra, clientset client.Client) (mlopsv1.CnvrgInfraSpec, error) {
+	log := logf.FromContext(ctx)
 
 	// Get default cnvrgInfra spec
 	desiredSpec := mlopsv1.DefaultCnvrgInfraSpec()
 
-	if err := calculateAndApplyInfraDefaults(cnvrgInfra, &desiredSpec, r.Client); err != nil {
-		infraLog.Error(err, "can't calculate defaults")
-		return false, err
+	if err := calculateAndApplyInfraDefaults(ctx, cnvrgInfra, &desiredSpec, clientset); err != nil {
+		log.Error(err, "can't calculate defaults")
+		return desiredSpec, err
 	}
 
 	// Merge current cnvrgInfra spec into default spec ( make it indeed desiredSpec )
 	if err := mergo.Merge(&desiredSpec, cnvrgInfra.Spec, mergo.WithOverride, mergo.WithTransformers(cnvrgSpecBoolTransformer{})); err != nil {
-		infraLog.Error(err, "can't merge")
-		return false, err
-	}
-
-	infraLog.V(1).Info("printing the diff between desiredSpec and actual")
-	diff, _ := messagediff.PrettyDiff(desiredSpec, cnvrgInfra.Spec)
-	infraLog.V(1).Info(diff)
-
-	// Compare desiredSpec and current cnvrgInfra spec,
-	// if they are not equal, update the cnvrgInfra spec with desiredSpec,
-	// and return true for triggering new reconciliation
-	equal := reflect.DeepEqual(desiredSpec, cnvrgInfra.Spec)
-	if !equal {
-		infraLog.Info("states are not equals, syncing and requeuing")
-		cnvrgInfra.Spec = desiredSpec
-		if err := r.Update(context.Background(), cnvrgInfra); err != nil && errors.IsConflict(err) {
-			infraLog.Error(err, "conflict updating cnvrgInfra object, requeue for reconciliations...")
-			return true, nil
-		} else if err != nil {
-			return false, err
-		}
-		return equal, nil
+		log.Error(err, "can't merge")
+		return desiredSpec, err
 	}
 
-	infraLog.Info("states are equals, no need to sync")
-	return equal, nil
+	return desiredSpec, nil
 }
 
-func (r *CnvrgInfraReconciler) getCnvrgInfraSpec(namespacedName types.NamespacedName) (*mlopsv1.CnvrgInfra, error) {
-	ctx := context.Background()
+func (r *CnvrgInfraReconciler) getCnvrgInfraSpec(ctx context.Context, namespacedName types.NamespacedName) (*mlopsv1.CnvrgInfra, error) {
+	log := logf.FromContext(ctx)
 	var cnvrgInfra mlopsv1.CnvrgInfra
 	if err := r.Get(ctx, namespacedName, &cnvrgInfra); err != nil {
 		if errors.IsNotFound(err) {
-			infraLog.Info("unable to fetch CnvrgInfra, probably cr was deleted")
+			log.Info("unable to fetch CnvrgInfra, probably cr was deleted")
 			return nil, nil
 		}
-		infraLog.Error(err, "unable to fetch CnvrgInfra")
+		log.Error(err, "unable to fetch CnvrgInfra")
 		return nil, err
 	}
 	return &cnvrgInfra, nil
 }
 
-func (r *CnvrgInfraReconciler) cleanup(cnvrgInfra *mlopsv1.CnvrgInfra) error {
+func (r *CnvrgInfraReconciler) cleanup(ctx context.Context, cnvrgInfra *mlopsv1.CnvrgInfra) error {
+	log := logf.FromContext(ctx)
 
-	infraLog.Info("running finalizer cleanup")
+	log.Info("running finalizer cleanup")
 
 	// cleanup pvc
-	if err := r.cleanupPVCs(cnvrgInfra); err != nil {
+	if err := r.cleanupPVCs(ctx, cnvrgInfra); err != nil {
 		return err
 	}
 
-	infraLog.Info("cleanup has been finished")
+	log.Info("cleanup has been finished")
 	return nil
 }
 
-func (r *CnvrgInfraReconciler) cleanupPVCs(infra *mlopsv1.CnvrgInfra) error {
+func (r *CnvrgInfraReconciler) cleanupPVCs(ctx context.Context, infra *mlopsv1.CnvrgInfra) error {
+	log := logf.FromContext(ctx)
 	if !viper.GetBool("cleanup-pvc") {
-		infraLog.Info("cleanup-pvc is false, skipping pvc deletion!")
+		log.Info("cleanup-pvc is false, skipping pvc deletion!")
 		return nil
 	}
-	infraLog.Info("running pvc cleanup")
-	ctx := context.Background()
+	log.Info("running pvc cleanup")
 	pvcList := v1core.PersistentVolumeClaimList{}
 	if err := r.List(ctx, &pvcList); err != nil {
-		infraLog.Error(err, "failed cleanup pvcs")
+		log.Error(err, "failed cleanup pvcs")
 		return err
 	}
 	for _, pvc := range pvcList.Items {
@@ -559,9 +611,9 @@ func (r *CnvrgInfraReconciler) cleanupPVCs(infra *mlopsv1.CnvrgInfra) error {
 			if _, ok := pvc.ObjectMeta.Labels["app"]; ok {
 				if pvc.ObjectMeta.Labels["app"] == "prometheus" {
 					if err := r.Delete(ctx, &pvc); err != nil && errors.IsNotFound(err) {
-						infraLog.Info("prometheus pvc already deleted")
+						log.Info("prometheus pvc already deleted")
 					} else if err != nil {
-						infraLog.Error(err, "error deleting prometheus pvc")
+						log.Error(err, "error deleting prometheus pvc")
 						return err
 					}
 				}
@@ -571,9 +623,9 @@ func (r *CnvrgInfraReconciler) cleanupPVCs(infra *mlopsv1.CnvrgInfra) error {
 	return nil
 }
 
-func (r *CnvrgInfraReconciler) cleanupIstio(cnvrgInfra *mlopsv1.CnvrgInfra) error {
-	infraLog.Info("running istio cleanup")
-	ctx := context.Background()
+func (r *CnvrgInfraReconciler) cleanupIstio(ctx context.Context, cnvrgInfra *mlopsv1.CnvrgInfra) error {
+	log := logf.FromContext(ctx)
+	log.Info("running istio cleanup")
 	istioManifests := networking.InfraNetworkingState(cnvrgInfra)
 	for _, m := range istioManifests {
 		// Make sure IstioOperator was deployed
@@ -582,26 +634,26 @@ func (r *CnvrgInfraReconciler) cleanupIstio(cnvrgInfra *mlopsv1.CnvrgInfra) erro
 				m.TemplateData = cnvrgInfra
 			}
 			if err := m.GenerateDeployable(); err != nil {
-				infraLog.Error(err, "can't make manifest deployable")
+				log.Error(err, "can't make manifest deployable")
 				return err
 			}
 			if err := r.Delete(ctx, m.Obj); err != nil {
 				if errors.IsNotFound(err) {
-					infraLog.Info("istio instance not found - probably removed previously")
+					log.Info("istio instance not found - probably removed previously")
 					return nil
 				}
 				return err
 			}
 			istioExists := true
-			infraLog.Info("wait for istio instance removal")
+			log.Info("wait for istio instance removal")
 			for istioExists {
 				err := r.Get(ctx, types.NamespacedName{Name: m.Obj.GetName(), Namespace: m.Obj.GetNamespace()}, m.Obj)
 				if err != nil && errors.IsNotFound(err) {
-					infraLog.Info("istio instance was successfully removed")
+					log.Info("istio instance was successfully removed")
 					istioExists = false
 				}
 				if istioExists {
-					infraLog.Info("istio instance still present, will sleep of 1 sec, and check again...")
+					log.Info("istio instance still present, will sleep of 1 sec, and check again...")
 				}
 				time.Sleep(1 * time.Second)
 			}
@@ -610,96 +662,81 @@ func (r *CnvrgInfraReconciler) cleanupIstio(cnvrgInfra *mlopsv1.CnvrgInfra) erro
 	return nil
 }
 
-func (r *CnvrgInfraReconciler) updateStatusMessage(status mlopsv1.OperatorStatus, message string, cnvrgInfra *mlopsv1.CnvrgInfra) {
+func (r *CnvrgInfraReconciler) updateStatusMessage(ctx context.Context, status mlopsv1.Status, cnvrgInfra *mlopsv1.CnvrgInfra) {
+	log := logf.FromContext(ctx)
 	if cnvrgInfra.Status.Status == mlopsv1.StatusRemoving {
-		infraLog.Info("skipping status update, current cnvrg spec under removing status...")
+		log.Info("skipping status update, current cnvrg spec under removing status...")
 		return
 	}
-	ctx := context.Background()
+	// conditions are observed for the reconciled generation
+	generation := cnvrgInfra.Generation
+	if status.StackReadiness != nil {
+		observeReadiness(cnvrgInfra, status.Progress, status.StackReadiness)
+	}
 	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
 		name := types.NamespacedName{Namespace: "", Name: cnvrgInfra.Name}
-		infra, err := r.getCnvrgInfraSpec(name)
+		infra, err := r.getCnvrgInfraSpec(ctx, name)
 		if err != nil {
 			return err
 		}
-		infra.Status.Status = status
-		infra.Status.Message = message
+		infra.Status.Status = status.Status
+		infra.Status.Message = status.Message
+		if status.Progress >= 0 {
+			infra.Status.Progress = status.Progress
+		}
+		if status.StackReadiness != nil {
+			infra.Status.StackReadiness = status.StackReadiness
+			readinessConditions(&infra.Status.Conditions, status.StackReadiness, infraReadinessConditions, generation)
+		}
+		setConditions(&infra.Status.Conditions, status.Conditions, generation)
 		err = r.Status().Update(ctx, infra)
 		return err
 	})
 	if err != nil {
-		infraLog.Error(err, "can't update status")
+		log.Error(err, "can't update status")
 	}
 }
 
-func (r *CnvrgInfraReconciler) setMetagpuPresence(infra *mlopsv1.CnvrgInfra) error {
-	apps, err := r.getCnvrgAppInstances(infra)
+func (r *CnvrgInfraReconciler) metagpuPresenceState(ctx context.Context, infra *mlopsv1.CnvrgInfra) ([]*desired.State, error) {
+	apps, err := r.getCnvrgAppInstances(ctx, infra)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	var state []*desired.State
 	for _, app := range apps {
-		mgDpPresence := desired.TemplateData{
-			Namespace: app.SpecNs,
-			Data: map[string]interface{}{
-				"Annotations": infra.Spec.Annotations,
-				"Labels":      infra.Spec.Labels,
-				"Enabled":     infra.Spec.Gpu.MetaGpuDp.Enabled,
-			},
-		}
-		if err := desired.Apply(gpu.MetagpudpPresenceState(mgDpPresence), infra, r.Client, r.Scheme, infraLog); err != nil {
-			return err
+		mgDpPresence := gpu.MetaGpuPresenceData{
+			Namespace:   app.SpecNs,
+			Annotations: infra.Spec.Annotations,
+			Labels:      infra.Spec.Labels,
+			Enabled:     infra.Spec.Gpu.MetaGpuDp.Enabled,
 		}
+		state = append(state, gpu.MetagpudpPresenceState(mgDpPresence)...)
 	}
-	return nil
+	return state, nil
 }
 
-func (r *CnvrgInfraReconciler) createInfraReconcilerTriggerCm(cnvrgInfra *mlopsv1.CnvrgInfra) error {
+func (r *CnvrgInfraReconciler) createInfraReconcilerTriggerCm(ctx context.Context, cnvrgInfra *mlopsv1.CnvrgInfra) error {
+	log := logf.FromContext(ctx)
 	cm := &v1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{Name: mlopsv1.InfraReconcilerCm, Namespace: cnvrgInfra.Spec.InfraNamespace},
 	}
 	if err := ctrl.SetControllerReference(cnvrgInfra, cm, r.Scheme); err != nil {
-		infraLog.Error(err, "failed to set ControllerReference", "cm", mlopsv1.InfraReconcilerCm)
+		log.Error(err, "failed to set ControllerReference", "cm", mlopsv1.InfraReconcilerCm)
 		return err
 	}
-	if err := r.Create(context.Background(), cm); err != nil && errors.IsAlreadyExists(err) {
-		infraLog.Info("already exists", "cm", mlopsv1.InfraReconcilerCm)
+	if err := r.Create(ctx, cm); err != nil && errors.IsAlreadyExists(err) {
+		log.Info("already exists", "cm", mlopsv1.InfraReconcilerCm)
 	} else if err != nil {
-		infraLog.Error(err, "error creating", "cm", mlopsv1.InfraReconcilerCm)
+		log.Error(err, "error creating", "cm", mlopsv1.InfraReconcilerCm)
 		return err
 	}
 
 	return nil
 }
 
-func (r *CnvrgInfraReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	infraLog = r.Log.WithValues("initializing", "crds")
-
-	if viper.GetBool("deploy-depended-crds") == false {
-		zap.S().Info("deploy-depended-crds is false, I hope CRDs was deployed ahead and match expected versions, if not I will fail...")
-	} else {
-
-		if viper.GetBool("own-istio-resources") {
-			err := desired.Apply(networking.IstioCrds(), &mlopsv1.CnvrgInfra{Spec: mlopsv1.DefaultCnvrgInfraSpec()}, r.Client, r.Scheme, r.Log)
-			if err != nil {
-				infraLog.Error(err, "can't apply istio CRDs")
-				os.Exit(1)
-			}
-		}
-
-		if viper.GetBool("own-prometheus-resources") {
-			err := desired.Apply(monitoring.Crds(), &mlopsv1.CnvrgInfra{Spec: mlopsv1.DefaultCnvrgInfraSpec()}, r.Client, r.Scheme, r.Log)
-			if err != nil {
-				infraLog.Error(err, "can't apply prometheus CRDs")
-				os.Exit(1)
-			}
-		}
-
-		err := desired.Apply(controlplane.Crds(), &mlopsv1.CnvrgInfra{Spec: mlopsv1.DefaultCnvrgInfraSpec()}, r.Client, r.Scheme, r.Log)
-		if err != nil {
-			infraLog.Error(err, "can't apply control plane crds")
-			os.Exit(1)
-		}
-	}
+// SetupWithManager registers the controller, ctx is the manager context, used by the watches map functions
+func (r *CnvrgInfraReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
+	log := r.Log.WithValues("initializing", "crds")
 
 	infraPredicate := predicate.Funcs{
 
@@ -710,7 +747,7 @@ func (r *CnvrgInfraReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		},
 
 		UpdateFunc: func(e event.UpdateEvent) bool {
-			infraLog.V(1).Info("received update event", "objectName", e.ObjectNew.GetName())
+			log.V(1).Info("received update event", "objectName", e.ObjectNew.GetName())
 			shouldReconcile := e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration()
 			if shouldReconcile {
 				msg := fmt.Sprintf("cnvrginfra: %s has been updated", e.ObjectNew.GetName())
@@ -722,7 +759,7 @@ func (r *CnvrgInfraReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		DeleteFunc: func(deleteEvent event.DeleteEvent) bool {
 			msg := fmt.Sprintf("cnvrginfra: %s has been deleted", deleteEvent.Object.GetName())
 			r.recorder.Event(deleteEvent.Object, "Normal", "SuccessfulDelete", msg)
-			infraLog.V(1).Info("received delete event", "objectName", deleteEvent.Object.GetName())
+			log.V(1).Info("received delete event", "objectName", deleteEvent.Object.GetName())
 			return !deleteEvent.DeleteStateUnknown
 		},
 	}
@@ -730,7 +767,7 @@ func (r *CnvrgInfraReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	infraOwnsPredicate := predicate.Funcs{
 
 		UpdateFunc: func(e event.UpdateEvent) bool {
-			infraLog.V(1).Info("received update event", "objectName", e.ObjectNew.GetName())
+			log.V(1).Info("received update event", "objectName", e.ObjectNew.GetName())
 			if e.ObjectNew.GetName() == mlopsv1.InfraReconcilerCm {
 				// Infra reconciler ConfigMap should always trigger reconcile loop
 				return true
@@ -740,14 +777,15 @@ func (r *CnvrgInfraReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		},
 
 		DeleteFunc: func(deleteEvent event.DeleteEvent) bool {
-			infraLog.V(1).Info("received delete event", "objectName", deleteEvent.Object.GetName())
+			log.V(1).Info("received delete event", "objectName", deleteEvent.Object.GetName())
 			return true
 		},
 	}
 	r.recorder = mgr.GetEventRecorderFor("cnvrginfra")
 	cnvrgInfraController := ctrl.
 		NewControllerManagedBy(mgr).
-		For(&mlopsv1.CnvrgInfra{}, builder.WithPredicates(infraPredicate))
+		For(&mlopsv1.CnvrgInfra{}, builder.WithPredicates(infraPredicate)).
+		Watches(&source.Kind{Type: &v1core.Secret{}}, handler.EnqueueRequestsFromMapFunc(r.secretRefRequests(ctx)))
 
 	for _, v := range desired.Kinds {
 
@@ -765,7 +803,7 @@ func (r *CnvrgInfraReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		cnvrgInfraController.Owns(u, builder.WithPredicates(infraOwnsPredicate))
 	}
 
-	infraLog.Info(fmt.Sprintf("max concurrent reconciles: %d", viper.GetInt("max-concurrent-reconciles")))
+	log.Info(fmt.Sprintf("max concurrent reconciles: %d", viper.GetInt("max-concurrent-reconciles")))
 	return cnvrgInfraController.
 		WithOptions(controller.Options{MaxConcurrentReconciles: viper.GetInt("max-concurrent-reconciles")}).
 		Complete(r)
@@ -15,6 +15,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 	"reflect"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sort"
 	"strings"
 	"time"
@@ -1055,6 +1056,118 @@ var _ = Describe("CnvrgInfra controller", func() {
 		})
 	})
 
+	Context("Test Operator Metrics", func() {
+		It("Operator ServiceMonitor", func() {
+			ns := createNs()
+			ctx := context.Background()
+			infra := getDefaultTestInfraSpec(ns)
+			infra.Spec.Monitoring.Prometheus.Enabled = true
+			Expect(k8sClient.Create(ctx, infra)).Should(Succeed())
+			sm := &unstructured.Unstructured{}
+			sm.SetGroupVersionKind(desired.Kinds[desired.ServiceMonitorGVK])
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: "cnvrg-operator", Namespace: ns}, sm)
+				if err != nil {
+					return false
+				}
+				return true
+			}, timeout, interval).Should(BeTrue())
+			Expect(sm.GetLabels()).Should(HaveKeyWithValue("cnvrg-infra-prometheus", fmt.Sprintf("%s-%s", ns, ns)))
+			Expect(k8sClient.Delete(ctx, infra)).Should(Succeed())
+		})
+		It("Readiness metrics", func() {
+			ns := createNs()
+			ctx := context.Background()
+			infra := getDefaultTestInfraSpec(ns)
+			infra.Spec.Logging.Fluentbit.Enabled = true
+			Expect(k8sClient.Create(ctx, infra)).Should(Succeed())
+			Eventually(func() bool {
+				families, err := metrics.Registry.Gather()
+				if err != nil {
+					return false
+				}
+				for _, f := range families {
+					if f.GetName() != "cnvrg_operator_component_ready" {
+						continue
+					}
+					for _, m := range f.GetMetric() {
+						labels := map[string]string{}
+						for _, l := range m.GetLabel() {
+							labels[l.GetName()] = l.GetValue()
+						}
+						if labels["kind"] == "CnvrgInfra" && labels["name"] == ns && labels["component"] == "fluentbit" {
+							return true
+						}
+					}
+				}
+				return false
+			}, timeout, interval).Should(BeTrue())
+			Expect(k8sClient.Delete(ctx, infra)).Should(Succeed())
+		})
+	})
+
+	Context("Test Readiness", func() {
+		It("DaemonSets readiness is tracked in the stack readiness", func() {
+			ns := createNs()
+			ctx := context.Background()
+			infra := getDefaultTestInfraSpec(ns)
+			infra.Spec.Logging.Fluentbit.Enabled = true
+			Expect(k8sClient.Create(ctx, infra)).Should(Succeed())
+
+			// daemon set status wasn't observed yet, hence fluentbit isn't ready
+			Eventually(func() bool {
+				i := mlopsv1.CnvrgInfra{}
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: ns}, &i); err != nil {
+					return false
+				}
+				ready, ok := i.Status.StackReadiness["fluentbit"]
+				return ok && !ready && i.Status.Status == mlopsv1.StatusReconciling
+			}, timeout, interval).Should(BeTrue())
+
+			ds := v1.DaemonSet{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "cnvrg-fluentbit", Namespace: ns}, &ds)).Should(Succeed())
+			ds.Status.ObservedGeneration = ds.Generation
+			Expect(k8sClient.Status().Update(ctx, &ds)).Should(Succeed())
+
+			// infra is requeued until the stack is ready
+			Eventually(func() bool {
+				i := mlopsv1.CnvrgInfra{}
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: ns}, &i); err != nil {
+					return false
+				}
+				return i.Status.StackReadiness["fluentbit"]
+			}, time.Second*90, interval).Should(BeTrue())
+			Expect(k8sClient.Delete(ctx, infra)).Should(Succeed())
+		})
+	})
+
+	Context("Test Effective Spec", func() {
+		It("Defaults are not written to the stored spec", func() {
+			ns := createNs()
+			ctx := context.Background()
+			infra := getEmptyTestInfraSpec(ns)
+			infra.Spec.Cri = mlopsv1.CriTypeDocker
+			infra.Spec.Dbs.Redis.Enabled = true
+			Expect(k8sClient.Create(ctx, infra)).Should(Succeed())
+
+			defaultRedis := mlopsv1.DefaultCnvrgInfraSpec().Dbs.Redis
+			deployment := v1.Deployment{}
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: defaultRedis.SvcName, Namespace: ns}, &deployment)
+				if err != nil {
+					return false
+				}
+				return true
+			}, timeout, interval).Should(BeTrue())
+
+			stored := mlopsv1.CnvrgInfra{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: ns}, &stored)).Should(Succeed())
+			Expect(stored.Spec.Dbs.Redis.Enabled).Should(BeTrue())
+			Expect(stored.Spec.Dbs.Redis.Image).Should(BeEmpty())
+			Expect(stored.Spec.Monitoring.Prometheus.SvcName).Should(BeEmpty())
+			Expect(k8sClient.Delete(ctx, infra)).Should(Succeed())
+		})
+	})
 })
 
 func getEmptyTestInfraSpec(ns string) *mlopsv1.CnvrgInfra {
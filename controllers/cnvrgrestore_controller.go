@@ -0,0 +1,304 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	mlopsv1 "github.com/AccessibleAI/cnvrg-operator/api/v1"
+	"github.com/AccessibleAI/cnvrg-operator/pkg/backup"
+	"github.com/AccessibleAI/cnvrg-operator/pkg/desired"
+	"github.com/go-logr/logr"
+	"github.com/imdario/mergo"
+	v1batch "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	"reflect"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	"time"
+)
+
+// CnvrgRestoreFinalizer scales the quiesced deployments back when a running restore is deleted
+const CnvrgRestoreFinalizer = "cnvrgrestore.mlops.cnvrg.io/finalizer"
+
+type CnvrgRestoreReconciler struct {
+	client.Client
+	recorder record.EventRecorder
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=mlops.cnvrg.io,resources=cnvrgrestores,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=mlops.cnvrg.io,resources=cnvrgrestores/status,verbs=get;update;patch
+
+// Reconcile restores the artifacts of the last successful run of the backup. The control plane is quiesced
+// before the restore job starts, and scaled back once the job is completed, succeeded or failed.
+// A completed restore is never run again, a new restore is created to restore again
+func (r *CnvrgRestoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("name", req.NamespacedName)
+	ctx = logf.IntoContext(ctx, log)
+
+	restore := &mlopsv1.CnvrgRestore{}
+	if err := r.Get(ctx, req.NamespacedName, restore); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !restore.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.finalize(ctx, restore)
+	}
+	if !containsString(restore.Finalizers, CnvrgRestoreFinalizer) {
+		restore.Finalizers = append(restore.Finalizers, CnvrgRestoreFinalizer)
+		if err := r.Update(ctx, restore); err != nil {
+			log.Error(err, "failed to add finalizer")
+			return ctrl.Result{}, err
+		}
+	}
+
+	status := restore.Status.DeepCopy()
+	if status.Phase == mlopsv1.BackupSucceeded || status.Phase == mlopsv1.BackupFailed {
+		return ctrl.Result{}, nil
+	}
+	spec, err := desiredCnvrgRestoreSpec(restore)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	app, err := backupCnvrgApp(ctx, r.Client, r.Scheme, r.Log, restore.Namespace, spec.CnvrgAppRef)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if app == nil && status.Phase == mlopsv1.BackupRunning {
+		return ctrl.Result{}, r.completeRestore(ctx, restore, status, mlopsv1.BackupFailed, fmt.Sprintf("cnvrgapp %s is deleted", spec.CnvrgAppRef))
+	}
+	if app == nil {
+		status.Phase = mlopsv1.BackupPending
+		status.Message = fmt.Sprintf("waiting for cnvrgapp %s", spec.CnvrgAppRef)
+		return ctrl.Result{RequeueAfter: backupRequeue}, r.updateStatus(ctx, restore, status)
+	}
+	// a running restore uses the artifacts recorded in its status, the backup may be removed meanwhile
+	cnvrgBackup := &mlopsv1.CnvrgBackup{}
+	if err := r.Get(ctx, types.NamespacedName{Name: spec.BackupRef, Namespace: restore.Namespace}, cnvrgBackup); client.IgnoreNotFound(err) != nil {
+		return ctrl.Result{}, err
+	}
+	if status.Phase != mlopsv1.BackupRunning && cnvrgBackup.Status.LastSuccessfulTime == nil {
+		status.Phase = mlopsv1.BackupPending
+		status.Message = fmt.Sprintf("waiting for a successful run of backup %s", spec.BackupRef)
+		return ctrl.Result{RequeueAfter: backupRequeue}, r.updateStatus(ctx, restore, status)
+	}
+	backupSpec, err := desiredCnvrgBackupSpec(cnvrgBackup)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if status.Phase != mlopsv1.BackupRunning {
+		artifacts, err := restoreArtifacts(cnvrgBackup.Status.Artifacts, spec.Databases)
+		if err != nil {
+			status.Phase = mlopsv1.BackupFailed
+			status.Message = fmt.Sprintf("can't restore backup %s: %s", spec.BackupRef, err)
+			return ctrl.Result{}, r.updateStatus(ctx, restore, status)
+		}
+		now := metav1.Now()
+		status.Phase = mlopsv1.BackupRunning
+		status.Message = fmt.Sprintf("restoring backup %s from %s", spec.BackupRef, cnvrgBackup.Status.LastSuccessfulTime.UTC().Format(time.RFC3339))
+		status.JobName = restore.Name + "-restore"
+		status.StartTime = &now
+		status.Artifacts = artifacts
+		status.Quiesced = restoreWorkloads(app, artifacts)
+		// the quiesced deployments are recorded before they are scaled down, so they are scaled back on any failure
+		if err := r.updateStatus(ctx, restore, status); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	data, err := restoreData(app, restore.Name, status.JobName, spec, backupSpec.BucketRef, status.Artifacts)
+	if err != nil {
+		return ctrl.Result{}, r.completeRestore(ctx, restore, status, mlopsv1.BackupFailed, err.Error())
+	}
+	if err := quiesce(ctx, r.Client, restore.Namespace, status.Quiesced); err != nil {
+		return ctrl.Result{}, err
+	}
+	down, err := scaledDown(ctx, r.Client, restore.Namespace, status.Quiesced)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !down {
+		log.Info("waiting for the quiesced deployments to scale down", "deployments", status.Quiesced)
+		return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+	}
+	if err := desired.Apply(ctx, backup.RestoreState(data), restore, r.Client, r.Scheme, log); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	job := &v1batch.Job{}
+	if err := r.Get(ctx, types.NamespacedName{Name: status.JobName, Namespace: restore.Namespace}, job); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	switch {
+	case job.Status.Succeeded > 0:
+		return ctrl.Result{}, r.completeRestore(ctx, restore, status, mlopsv1.BackupSucceeded,
+			fmt.Sprintf("backup %s is restored", spec.BackupRef))
+	case jobFailed(job):
+		return ctrl.Result{}, r.completeRestore(ctx, restore, status, mlopsv1.BackupFailed,
+			fmt.Sprintf("restore job %s failed, check the job logs, the databases may be partially restored", job.Name))
+	}
+	return ctrl.Result{}, nil
+}
+
+// completeRestore scales the quiesced deployments back and sets the final phase
+func (r *CnvrgRestoreReconciler) completeRestore(ctx context.Context, restore *mlopsv1.CnvrgRestore, status *mlopsv1.CnvrgRestoreStatus, phase mlopsv1.BackupPhase, message string) error {
+	if err := unquiesce(ctx, r.Client, restore.Namespace, status.Quiesced); err != nil {
+		return err
+	}
+	now := metav1.Now()
+	status.Phase = phase
+	status.Message = message
+	status.CompletionTime = &now
+	return r.updateStatus(ctx, restore, status)
+}
+
+// finalize scales back the deployments quiesced by a running restore
+func (r *CnvrgRestoreReconciler) finalize(ctx context.Context, restore *mlopsv1.CnvrgRestore) error {
+	if !containsString(restore.Finalizers, CnvrgRestoreFinalizer) {
+		return nil
+	}
+	if restore.Status.Phase == mlopsv1.BackupRunning {
+		if err := unquiesce(ctx, r.Client, restore.Namespace, restore.Status.Quiesced); err != nil {
+			return err
+		}
+	}
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest := &mlopsv1.CnvrgRestore{}
+		if err := r.Get(ctx, types.NamespacedName{Name: restore.Name, Namespace: restore.Namespace}, latest); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		latest.Finalizers = removeString(latest.Finalizers, CnvrgRestoreFinalizer)
+		return r.Update(ctx, latest)
+	})
+}
+
+func (r *CnvrgRestoreReconciler) updateStatus(ctx context.Context, restore *mlopsv1.CnvrgRestore, status *mlopsv1.CnvrgRestoreStatus) error {
+	if reflect.DeepEqual(restore.Status, *status) {
+		return nil
+	}
+	if restore.Status.Phase != status.Phase {
+		r.recorder.Event(restore, backupEventType(status.Phase), "Restore"+string(status.Phase), status.Message)
+	}
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest := &mlopsv1.CnvrgRestore{}
+		if err := r.Get(ctx, types.NamespacedName{Name: restore.Name, Namespace: restore.Namespace}, latest); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		latest.Status = *status
+		if err := r.Status().Update(ctx, latest); err != nil {
+			return err
+		}
+		restore.Status = *status.DeepCopy()
+		return nil
+	})
+}
+
+func desiredCnvrgRestoreSpec(restore *mlopsv1.CnvrgRestore) (mlopsv1.CnvrgRestoreSpec, error) {
+	desiredSpec := mlopsv1.DefaultCnvrgRestoreSpec()
+	if err := mergo.Merge(&desiredSpec, restore.Spec, mergo.WithOverride); err != nil {
+		return desiredSpec, err
+	}
+	return desiredSpec, nil
+}
+
+// restoreArtifacts returns the artifacts of the requested databases, or all the artifacts of the backup
+func restoreArtifacts(artifacts []mlopsv1.BackupArtifact, databases []mlopsv1.BackupDatabase) ([]mlopsv1.BackupArtifact, error) {
+	if len(artifacts) == 0 {
+		return nil, fmt.Errorf("the backup has no artifacts")
+	}
+	if len(databases) == 0 {
+		return artifacts, nil
+	}
+	var selected []mlopsv1.BackupArtifact
+	for _, db := range databases {
+		found := false
+		for _, a := range artifacts {
+			if a.Database == db {
+				selected = append(selected, a)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("the backup has no %s artifact", db)
+		}
+	}
+	return selected, nil
+}
+
+// restoreRunning checks if a restore of the cnvrgApp is running. The cnvrgApp reconciler holds the quiesced components
+// meanwhile, otherwise it applies the replicas of the quiesced deployments back while the databases are restored
+func restoreRunning(ctx context.Context, c client.Reader, app *mlopsv1.CnvrgApp) (bool, error) {
+	restores := &mlopsv1.CnvrgRestoreList{}
+	if err := c.List(ctx, restores, client.InNamespace(app.Namespace)); err != nil {
+		return false, err
+	}
+	for i := range restores.Items {
+		spec, err := desiredCnvrgRestoreSpec(&restores.Items[i])
+		if err != nil {
+			return false, err
+		}
+		if spec.CnvrgAppRef == app.Name && restores.Items[i].Status.Phase == mlopsv1.BackupRunning {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// restoreWorkloads are the control plane deployments, and redis when its pvc is restored
+func restoreWorkloads(app *mlopsv1.CnvrgApp, artifacts []mlopsv1.BackupArtifact) []string {
+	workloads := pgWorkloads(app)
+	for _, a := range artifacts {
+		if a.Database == mlopsv1.RedisBackupDatabase {
+			workloads = append(workloads, app.Spec.Dbs.Redis.SvcName)
+		}
+	}
+	return workloads
+}
+
+// restoreData returns the restore job of the artifacts, the databases must be enabled in the cnvrgApp
+func restoreData(app *mlopsv1.CnvrgApp, name, jobName string, spec mlopsv1.CnvrgRestoreSpec, bucketRef string, artifacts []mlopsv1.BackupArtifact) (backup.RestoreData, error) {
+	data := backup.RestoreData{
+		Namespace:    app.Namespace,
+		Annotations:  app.Spec.Annotations,
+		Labels:       app.Spec.Labels,
+		Name:         name,
+		JobName:      jobName,
+		BucketRef:    bucketRef,
+		Image:        spec.Image,
+		NodeSelector: backupNodeSelector(app),
+		Tenancy:      app.Spec.Tenancy.Enabled,
+	}
+	for _, a := range artifacts {
+		if err := databaseEnabled(app, a.Database); err != nil {
+			return data, err
+		}
+		switch a.Database {
+		case mlopsv1.PgBackupDatabase, mlopsv1.CvatPgBackupDatabase:
+			dump := pgDump(app, a.Database)
+			dump.Location = a.Location
+			data.Pg = append(data.Pg, dump)
+		case mlopsv1.RedisBackupDatabase:
+			data.Redis = redisDump(app)
+			data.Redis.Location = a.Location
+		case mlopsv1.EsBackupDatabase:
+			data.Es = esSnapshot(app, "cnvrg-restore-"+name)
+			data.Es.Location = a.Location
+		}
+	}
+	return data, nil
+}
+
+func (r *CnvrgRestoreReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.recorder = mgr.GetEventRecorderFor("cnvrgrestore")
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&mlopsv1.CnvrgRestore{}).
+		Watches(&source.Kind{Type: &v1batch.Job{}}, handler.EnqueueRequestsFromMapFunc(labelRequests(restoreLabel))).
+		Complete(r)
+}
@@ -0,0 +1,212 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"github.com/AccessibleAI/cnvrg-operator/pkg/desired"
+	"github.com/spf13/viper"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sync"
+	"time"
+)
+
+// component is a group of desired states applied together
+type component struct {
+	name string
+	// dependsOn are the components which must be applied and ready before the component is applied
+	dependsOn []string
+	// states are evaluated lazily, right before apply,
+	// since some of them depends on objects created by the previous components
+	states func() ([]*desired.State, error)
+	// sync runs actions which are not expressed as templates, after the states were applied
+	sync func() error
+	// ready reports if the component workloads are ready, component without ready is ready once applied
+	ready func() (bool, error)
+}
+
+func applyComponent(ctx context.Context, c component, owner v1.Object, client client.Client, schema *runtime.Scheme, inventory *desired.Inventory) error {
+	if c.states != nil {
+		states, err := c.states()
+		if err != nil {
+			return err
+		}
+		if err := desired.Apply(ctx, states, owner, client, schema, logf.FromContext(ctx)); err != nil {
+			return err
+		}
+		inventory.Add(states)
+	}
+	if c.sync != nil {
+		return c.sync()
+	}
+	return nil
+}
+
+// applyComponents applies the components in dependencies order,
+// components which don't depend on each other are applied in parallel.
+// Component is applied only when all its dependencies are applied and ready,
+// components which are waiting for failed or not ready dependencies are returned as pending
+func applyComponents(ctx context.Context, components []component, owner v1.Object, client client.Client, schema *runtime.Scheme, inventory *desired.Inventory) (pending []string, err error) {
+	log := logf.FromContext(ctx)
+	if err := validateComponents(components); err != nil {
+		return nil, err
+	}
+
+	type result struct {
+		ready bool
+		err   error
+	}
+
+	ready := map[string]bool{}
+	blocked := map[string]bool{}
+	var errs []error
+	remaining := components
+	for len(remaining) > 0 {
+		var phase, next []component
+		for _, c := range remaining {
+			switch {
+			case dependsOnAny(c, blocked):
+				blocked[c.name] = true
+				pending = append(pending, c.name)
+			case dependsOnAll(c, ready):
+				phase = append(phase, c)
+			default:
+				next = append(next, c)
+			}
+		}
+		if len(phase) == 0 {
+			// all the remaining components are waiting for blocked components
+			for _, c := range next {
+				pending = append(pending, c.name)
+			}
+			break
+		}
+
+		results := make([]result, len(phase))
+		var wg sync.WaitGroup
+		for i, c := range phase {
+			wg.Add(1)
+			go func(i int, c component) {
+				defer wg.Done()
+				log.Info("applying " + c.name)
+				start := time.Now()
+				err := applyComponent(ctx, c, owner, client, schema, inventory)
+				observeComponentApply(owner, c.name, start)
+				if err != nil {
+					results[i] = result{err: err}
+					return
+				}
+				if c.ready == nil {
+					results[i] = result{ready: true}
+					return
+				}
+				isReady, err := c.ready()
+				results[i] = result{ready: isReady, err: err}
+			}(i, c)
+		}
+		wg.Wait()
+
+		for i, c := range phase {
+			switch {
+			case results[i].err != nil:
+				log.Error(results[i].err, "failed to apply component", "component", c.name)
+				errs = append(errs, fmt.Errorf("%s: %v", c.name, results[i].err))
+				blocked[c.name] = true
+			case !results[i].ready:
+				log.Info("component is not ready yet, dependent components are postponed", "component", c.name)
+				blocked[c.name] = true
+			default:
+				ready[c.name] = true
+			}
+		}
+		remaining = next
+	}
+
+	return pending, utilerrors.NewAggregate(errs)
+}
+
+// validateComponents makes sure all the dependencies exist and there are no dependency cycles
+func validateComponents(components []component) error {
+	deps := map[string][]string{}
+	for _, c := range components {
+		deps[c.name] = c.dependsOn
+	}
+	for _, c := range components {
+		for _, d := range c.dependsOn {
+			if _, ok := deps[d]; !ok {
+				return fmt.Errorf("component %s depends on unknown component %s", c.name, d)
+			}
+		}
+	}
+	// 0 - not visited, 1 - in progress, 2 - done
+	visited := map[string]int{}
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch visited[name] {
+		case 1:
+			return fmt.Errorf("dependency cycle detected at component %s", name)
+		case 2:
+			return nil
+		}
+		visited[name] = 1
+		for _, d := range deps[name] {
+			if err := visit(d); err != nil {
+				return err
+			}
+		}
+		visited[name] = 2
+		return nil
+	}
+	for _, c := range components {
+		if err := visit(c.name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func dependsOnAny(c component, names map[string]bool) bool {
+	for _, d := range c.dependsOn {
+		if names[d] {
+			return true
+		}
+	}
+	return false
+}
+
+func dependsOnAll(c component, names map[string]bool) bool {
+	for _, d := range c.dependsOn {
+		if !names[d] {
+			return false
+		}
+	}
+	return true
+}
+
+// pruneComponents deletes objects applied by the previous reconcile which are not in the current inventory,
+// and stores the current inventory for the next reconcile
+func pruneComponents(ctx context.Context, inventoryName types.NamespacedName, inventory *desired.Inventory, owner v1.Object, client client.Client, schema *runtime.Scheme) error {
+	log := logf.FromContext(ctx)
+	if viper.GetBool("dry-run") {
+		return nil
+	}
+	previous, err := desired.LoadInventory(ctx, inventoryName, client)
+	if err != nil {
+		log.Error(err, "can't load inventory", "name", inventoryName)
+		return err
+	}
+	if viper.GetBool("prune") {
+		if err := desired.Prune(ctx, previous, inventory, owner, client, log); err != nil {
+			return err
+		}
+	}
+	if err := inventory.Save(ctx, inventoryName, owner, client, schema); err != nil {
+		log.Error(err, "can't save inventory", "name", inventoryName)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	mlopsv1 "github.com/AccessibleAI/cnvrg-operator/api/v1"
+	"github.com/AccessibleAI/cnvrg-operator/pkg/desired"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sync"
+)
+
+// +kubebuilder:docs-gen:collapse=Imports
+
+var _ = Describe("Components", func() {
+
+	var (
+		mu      sync.Mutex
+		applied []string
+	)
+
+	syncFn := func(name string, err error) func() error {
+		return func() error {
+			mu.Lock()
+			defer mu.Unlock()
+			applied = append(applied, name)
+			return err
+		}
+	}
+
+	apply := func(components []component) ([]string, error) {
+		applied = nil
+		app := getDefaultTestAppSpec("default")
+		ctx := logf.IntoContext(context.Background(), ctrl.Log.WithName("components"))
+		return applyComponents(ctx, components, app, k8sClient, scheme.Scheme, desired.NewInventory())
+	}
+
+	Context("Test Dependency Order", func() {
+		It("Dependent components are applied after their dependencies", func() {
+			pending, err := apply([]component{
+				{name: "controlplane", dependsOn: []string{"pg", "redis"}, sync: syncFn("controlplane", nil)},
+				{name: "pg", sync: syncFn("pg", nil)},
+				{name: "redis", sync: syncFn("redis", nil)},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pending).To(BeEmpty())
+			Expect(applied).To(HaveLen(3))
+			Expect(applied[2]).To(Equal("controlplane"))
+		})
+
+		It("Dependent components are pending until dependencies are ready", func() {
+			pending, err := apply([]component{
+				{name: "pg", sync: syncFn("pg", nil), ready: func() (bool, error) { return false, nil }},
+				{name: "es", sync: syncFn("es", nil)},
+				{name: "controlplane", dependsOn: []string{"pg", "es"}, sync: syncFn("controlplane", nil)},
+				{name: "ingress check", dependsOn: []string{"controlplane"}, sync: syncFn("ingress check", nil)},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pending).To(ConsistOf("controlplane", "ingress check"))
+			Expect(applied).To(ConsistOf("pg", "es"))
+		})
+
+		It("Failed components are reported, independent components are applied", func() {
+			pending, err := apply([]component{
+				{name: "pg", sync: syncFn("pg", fmt.Errorf("boom"))},
+				{name: "networking", sync: syncFn("networking", nil)},
+				{name: "backups", dependsOn: []string{"pg"}, sync: syncFn("backups", nil)},
+			})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("pg: boom"))
+			Expect(pending).To(ConsistOf("backups"))
+			Expect(applied).To(ConsistOf("pg", "networking"))
+		})
+
+		It("Dependency cycles are rejected", func() {
+			_, err := apply([]component{
+				{name: "a", dependsOn: []string{"b"}},
+				{name: "b", dependsOn: []string{"a"}},
+			})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("dependency cycle"))
+		})
+
+		It("CnvrgApp components have valid dependencies", func() {
+			r := &CnvrgAppReconciler{Client: k8sClient, Scheme: scheme.Scheme}
+			Expect(validateComponents(r.appComponents(context.Background(), &mlopsv1.CnvrgApp{}))).To(Succeed())
+			i := &CnvrgInfraReconciler{Client: k8sClient, Scheme: scheme.Scheme}
+			Expect(validateComponents(i.infraComponents(context.Background(), &mlopsv1.CnvrgInfra{}))).To(Succeed())
+		})
+	})
+})
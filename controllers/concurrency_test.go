@@ -0,0 +1,124 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sync"
+)
+
+// +kubebuilder:docs-gen:collapse=Imports
+
+// logEntry is a log line recorded by the recordingLogger
+type logEntry struct {
+	msg    string
+	values map[string]interface{}
+}
+
+// recordingLogger records the log lines with their key/values, it's safe for concurrent use
+type recordingLogger struct {
+	mu      *sync.Mutex
+	entries *[]logEntry
+	values  []interface{}
+}
+
+func newRecordingLogger() recordingLogger {
+	return recordingLogger{mu: &sync.Mutex{}, entries: &[]logEntry{}}
+}
+
+func (l recordingLogger) record(msg string, keysAndValues []interface{}) {
+	values := map[string]interface{}{}
+	kv := append(append([]interface{}{}, l.values...), keysAndValues...)
+	for i := 0; i+1 < len(kv); i += 2 {
+		values[fmt.Sprint(kv[i])] = kv[i+1]
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	*l.entries = append(*l.entries, logEntry{msg: msg, values: values})
+}
+
+func (l recordingLogger) Enabled() bool { return true }
+
+func (l recordingLogger) Info(msg string, keysAndValues ...interface{}) {
+	l.record(msg, keysAndValues)
+}
+
+func (l recordingLogger) Error(err error, msg string, keysAndValues ...interface{}) {
+	l.record(msg, keysAndValues)
+}
+
+func (l recordingLogger) V(level int) logr.Logger { return l }
+
+func (l recordingLogger) WithName(name string) logr.Logger { return l }
+
+func (l recordingLogger) WithValues(keysAndValues ...interface{}) logr.Logger {
+	l.values = append(append([]interface{}{}, l.values...), keysAndValues...)
+	return l
+}
+
+func (l recordingLogger) logged() []logEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]logEntry{}, *l.entries...)
+}
+
+var _ = Describe("Concurrent Reconciles", func() {
+
+	Context("Test Concurrent Reconciles", func() {
+		It("Log lines are attributed to the reconciled CnvrgApp", func() {
+			const apps = 5
+			ctx := context.Background()
+			log := newRecordingLogger()
+			r := &CnvrgAppReconciler{
+				Client:   k8sClient,
+				Scheme:   scheme.Scheme,
+				Log:      log,
+				recorder: record.NewFakeRecorder(1000),
+			}
+
+			var names []types.NamespacedName
+			for i := 0; i < apps; i++ {
+				ns := createNs()
+				testApp := getDefaultTestAppSpec(ns)
+				testApp.Spec.Dbs.Pg.Enabled = true
+				Expect(k8sClient.Create(ctx, testApp)).Should(Succeed())
+				names = append(names, types.NamespacedName{Name: testApp.Name, Namespace: ns})
+			}
+
+			// reconcile all the apps concurrently, conflicts with the manager reconciles are expected
+			var wg sync.WaitGroup
+			for _, name := range names {
+				wg.Add(1)
+				go func(name types.NamespacedName) {
+					defer GinkgoRecover()
+					defer wg.Done()
+					for i := 0; i < 3; i++ {
+						_, _ = r.Reconcile(ctx, ctrl.Request{NamespacedName: name})
+					}
+				}(name)
+			}
+			wg.Wait()
+
+			// every app components were applied with the logger of the app request
+			applied := map[string]bool{}
+			for _, e := range log.logged() {
+				if e.msg == "applying pg" {
+					applied[fmt.Sprint(e.values["name"])] = true
+				}
+			}
+			for _, name := range names {
+				Expect(applied).To(HaveKey(name.String()))
+			}
+			for _, name := range names {
+				app := getDefaultTestAppSpec(name.Namespace)
+				Expect(k8sClient.Delete(ctx, app)).Should(Succeed())
+			}
+		})
+	})
+})
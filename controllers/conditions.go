@@ -0,0 +1,183 @@
+package controllers
+
+import (
+	"fmt"
+	mlopsv1 "github.com/AccessibleAI/cnvrg-operator/api/v1"
+	v1batch "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"strings"
+)
+
+// condition message is limited by the crd schema
+const maxConditionMessage = 32768
+
+// appReadinessConditions maps the stack readiness keys to the condition types
+var appReadinessConditions = map[string]string{
+	"ingressCheck": mlopsv1.ConditionIngressReachable,
+	"webApp":       mlopsv1.ConditionWebAppAvailable,
+	"sidekiq":      mlopsv1.ConditionSidekiqAvailable,
+	"searchkiq":    mlopsv1.ConditionSearchkiqAvailable,
+	"systemkiq":    mlopsv1.ConditionSystemkiqAvailable,
+	"pg":           mlopsv1.ConditionPgReady,
+	"minio":        mlopsv1.ConditionMinioReady,
+	"redis":        mlopsv1.ConditionRedisReady,
+	"es":           mlopsv1.ConditionEsReady,
+	"kibana":       mlopsv1.ConditionKibanaReady,
+	"prometheus":   mlopsv1.ConditionPrometheusReady,
+	"grafana":      mlopsv1.ConditionGrafanaReady,
+}
+
+// infraReadinessConditions maps the infra stack readiness keys to the condition types
+var infraReadinessConditions = map[string]string{
+	"istiod":     mlopsv1.ConditionIstioReady,
+	"redis":      mlopsv1.ConditionRedisReady,
+	"fluentbit":  mlopsv1.ConditionFluentbitReady,
+	"prometheus": mlopsv1.ConditionPrometheusReady,
+	"grafana":    mlopsv1.ConditionGrafanaReady,
+}
+
+// reconciledCondition reports the result of applying the manifests
+func reconciledCondition(pending []string, err error) metav1.Condition {
+	switch {
+	case err != nil:
+		return metav1.Condition{
+			Type:    mlopsv1.ConditionReconciled,
+			Status:  metav1.ConditionFalse,
+			Reason:  mlopsv1.ReasonReconcileFailed,
+			Message: err.Error(),
+		}
+	case len(pending) > 0:
+		return metav1.Condition{
+			Type:    mlopsv1.ConditionReconciled,
+			Status:  metav1.ConditionFalse,
+			Reason:  mlopsv1.ReasonDependenciesPending,
+			Message: fmt.Sprintf("waiting for dependencies of: %s", strings.Join(pending, ", ")),
+		}
+	default:
+		return metav1.Condition{
+			Type:    mlopsv1.ConditionReconciled,
+			Status:  metav1.ConditionTrue,
+			Reason:  mlopsv1.ReasonReconcileSucceeded,
+			Message: "all the components has been applied",
+		}
+	}
+}
+
+// readinessConditions converts the stack readiness to conditions,
+// conditions of the components which are not in the stack readiness (disabled) are removed
+func readinessConditions(conditions *[]metav1.Condition, readiness map[string]bool, conditionTypes map[string]string, generation int64) {
+	for key, conditionType := range conditionTypes {
+		ready, ok := readiness[key]
+		if !ok {
+			meta.RemoveStatusCondition(conditions, conditionType)
+			continue
+		}
+		c := metav1.Condition{
+			Type:               conditionType,
+			Status:             metav1.ConditionTrue,
+			Reason:             mlopsv1.ReasonReady,
+			Message:            fmt.Sprintf("%s is ready", key),
+			ObservedGeneration: generation,
+		}
+		if !ready {
+			c.Status = metav1.ConditionFalse
+			c.Reason = mlopsv1.ReasonNotReady
+			c.Message = fmt.Sprintf("%s is not ready", key)
+		}
+		meta.SetStatusCondition(conditions, c)
+	}
+}
+
+// setConditions sets the conditions with the observed generation
+func setConditions(conditions *[]metav1.Condition, updates []metav1.Condition, generation int64) {
+	for _, c := range updates {
+		c.ObservedGeneration = generation
+		if len(c.Message) > maxConditionMessage {
+			c.Message = c.Message[:maxConditionMessage]
+		}
+		meta.SetStatusCondition(conditions, c)
+	}
+}
+
+// pgUpgradeCondition reports the phase of the pg major version upgrade
+func pgUpgradeCondition(upgrade *mlopsv1.PgUpgradeStatus) metav1.Condition {
+	c := metav1.Condition{Type: mlopsv1.ConditionPgUpgraded, Status: metav1.ConditionFalse, Message: upgrade.Message}
+	switch upgrade.Phase {
+	case mlopsv1.PgUpgradeRunning:
+		c.Reason = mlopsv1.ReasonPgUpgradeRunning
+	case mlopsv1.PgUpgradeFailed:
+		c.Reason = mlopsv1.ReasonPgUpgradeFailed
+	case mlopsv1.PgUpgradeSucceeded:
+		c.Status = metav1.ConditionTrue
+		c.Reason = mlopsv1.ReasonPgUpgradeSucceeded
+	case mlopsv1.PgUpgradeCompleted:
+		c.Status = metav1.ConditionTrue
+		c.Reason = mlopsv1.ReasonPgUpgradeCompleted
+	case mlopsv1.PgUpgradeRolledBack:
+		c.Reason = mlopsv1.ReasonPgUpgradeRolledBack
+	}
+	return c
+}
+
+// preflightCondition reports the result of the external databases preflight job
+func preflightCondition(name string, job *v1batch.Job, databases []string, failures []string) metav1.Condition {
+	c := metav1.Condition{Type: mlopsv1.ConditionExternalDbsReachable, Status: metav1.ConditionFalse}
+	switch {
+	case job != nil && job.Status.Succeeded > 0:
+		c.Status = metav1.ConditionTrue
+		c.Reason = mlopsv1.ReasonPreflightSucceeded
+		c.Message = fmt.Sprintf("external %s reachable", strings.Join(databases, ", "))
+	case job != nil && jobFailed(job) && len(failures) > 0:
+		c.Reason = mlopsv1.ReasonPreflightFailed
+		c.Message = fmt.Sprintf("preflight job %s failed: %s, delete the job to check again", name, strings.Join(failures, "; "))
+	case job != nil && jobFailed(job):
+		c.Reason = mlopsv1.ReasonPreflightFailed
+		c.Message = fmt.Sprintf("preflight job %s failed, check the job logs, delete the job to check again", name)
+	default:
+		c.Reason = mlopsv1.ReasonPreflightRunning
+		c.Message = fmt.Sprintf("preflight job %s is checking external %s", name, strings.Join(databases, ", "))
+	}
+	return c
+}
+
+// podSpecOverridesCondition reports the validation of the pod spec overrides, done by the reconciler when the webhooks are disabled
+func podSpecOverridesCondition(errs field.ErrorList) metav1.Condition {
+	if len(errs) > 0 {
+		return metav1.Condition{
+			Type:    mlopsv1.ConditionPodSpecOverridesValid,
+			Status:  metav1.ConditionFalse,
+			Reason:  mlopsv1.ReasonPodSpecOverridesInvalid,
+			Message: errs.ToAggregate().Error(),
+		}
+	}
+	return metav1.Condition{
+		Type:    mlopsv1.ConditionPodSpecOverridesValid,
+		Status:  metav1.ConditionTrue,
+		Reason:  mlopsv1.ReasonPodSpecOverridesValid,
+		Message: "the pod spec overrides are valid",
+	}
+}
+
+// overridesMatchedCondition reports the overrides which don't match any of the rendered objects
+func overridesMatchedCondition(unmatched []mlopsv1.Override) metav1.Condition {
+	if len(unmatched) > 0 {
+		var names []string
+		for _, o := range unmatched {
+			names = append(names, fmt.Sprintf("%s/%s", o.Kind, o.Name))
+		}
+		return metav1.Condition{
+			Type:    mlopsv1.ConditionOverridesMatched,
+			Status:  metav1.ConditionFalse,
+			Reason:  mlopsv1.ReasonOverridesUnmatched,
+			Message: fmt.Sprintf("overrides don't match any rendered object: %s", strings.Join(names, ", ")),
+		}
+	}
+	return metav1.Condition{
+		Type:    mlopsv1.ConditionOverridesMatched,
+		Status:  metav1.ConditionTrue,
+		Reason:  mlopsv1.ReasonOverridesMatched,
+		Message: "all the overrides match a rendered object",
+	}
+}
@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	mlopsv1 "github.com/AccessibleAI/cnvrg-operator/api/v1"
+	"github.com/spf13/viper"
+	"io/ioutil"
+	"k8s.io/client-go/util/cert"
+	"k8s.io/client-go/util/keyutil"
+	"path/filepath"
+)
+
+// cnvrgCrds are the CRDs served in v1 and v1beta2, v1beta2 is stored and v1 is converted by the operator webhook
+var cnvrgCrds = []string{"cnvrgapps.mlops.cnvrg.io", "cnvrginfras.mlops.cnvrg.io"}
+
+// EnsureWebhookCert generates a self signed webhook server certificate, unless the certificate is mounted
+// (e.g. by cert-manager with config/default). The conversion webhook is always served,
+// the api server can't read the cnvrg CRs in v1 without it
+func EnsureWebhookCert() error {
+	dir := viper.GetString("webhook-cert-dir")
+	certFile, keyFile, caFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key"), filepath.Join(dir, "ca.crt")
+	if ok, _ := cert.CanReadCertAndKey(certFile, keyFile); ok {
+		return nil
+	}
+	service := fmt.Sprintf("%s.%s.svc", viper.GetString("webhook-service"), viper.GetString("webhook-service-namespace"))
+	// the generated certificate is followed by its self signed ca, which is used as the ca bundle
+	crt, key, err := cert.GenerateSelfSignedCertKey(service, nil, nil)
+	if err != nil {
+		return fmt.Errorf("can't generate the webhook certificate: %v", err)
+	}
+	if err := cert.WriteCert(certFile, crt); err != nil {
+		return err
+	}
+	if err := cert.WriteCert(caFile, crt); err != nil {
+		return err
+	}
+	return keyutil.WriteKey(keyFile, key)
+}
+
+// crdConversionOverrides points the conversion webhook of the cnvrg CRDs to the operator webhook service
+// and sets the ca bundle of the webhook server certificate
+func crdConversionOverrides() ([]mlopsv1.Override, error) {
+	ca, err := ioutil.ReadFile(filepath.Join(viper.GetString("webhook-cert-dir"), "ca.crt"))
+	if err != nil {
+		return nil, fmt.Errorf("can't read the conversion webhook ca: %v", err)
+	}
+	patch, err := json.Marshal([]map[string]interface{}{{
+		"op":   "replace",
+		"path": "/spec/conversion/webhook/clientConfig",
+		"value": map[string]interface{}{
+			"caBundle": base64.StdEncoding.EncodeToString(ca),
+			"service": map[string]interface{}{
+				"namespace": viper.GetString("webhook-service-namespace"),
+				"name":      viper.GetString("webhook-service"),
+				"path":      "/convert",
+			},
+		},
+	}})
+	if err != nil {
+		return nil, err
+	}
+	var overrides []mlopsv1.Override
+	for _, crd := range cnvrgCrds {
+		overrides = append(overrides, mlopsv1.Override{
+			Kind:  "CustomResourceDefinition",
+			Name:  crd,
+			Type:  mlopsv1.JSONPatchType,
+			Patch: string(patch),
+		})
+	}
+	return overrides, nil
+}
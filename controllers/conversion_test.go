@@ -0,0 +1,201 @@
+package controllers
+
+import (
+	"context"
+	mlopsv1 "github.com/AccessibleAI/cnvrg-operator/api/v1"
+	mlopsv1beta2 "github.com/AccessibleAI/cnvrg-operator/api/v1beta2"
+	"github.com/AccessibleAI/cnvrg-operator/pkg/controlplane"
+	"github.com/AccessibleAI/cnvrg-operator/pkg/desired"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/viper"
+	"io/ioutil"
+	v1core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"os"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// +kubebuilder:docs-gen:collapse=Imports
+
+var _ = Describe("CRD Conversion", func() {
+
+	roundTripApp := func(app *mlopsv1.CnvrgApp) *mlopsv1beta2.CnvrgApp {
+		hub := &mlopsv1beta2.CnvrgApp{}
+		Expect(app.ConvertTo(hub)).To(Succeed())
+		back := &mlopsv1.CnvrgApp{}
+		Expect(back.ConvertFrom(hub)).To(Succeed())
+		// the type meta is set by the conversion webhook
+		Expect(back.ObjectMeta).To(Equal(app.ObjectMeta))
+		Expect(back.Spec).To(Equal(app.Spec))
+		return hub
+	}
+
+	roundTripHub := func(hub *mlopsv1beta2.CnvrgApp) *mlopsv1.CnvrgApp {
+		app := &mlopsv1.CnvrgApp{}
+		Expect(app.ConvertFrom(hub)).To(Succeed())
+		back := &mlopsv1beta2.CnvrgApp{}
+		Expect(app.ConvertTo(back)).To(Succeed())
+		Expect(back.ObjectMeta).To(Equal(hub.ObjectMeta))
+		Expect(back.Spec).To(Equal(hub.Spec))
+		return app
+	}
+
+	secretRef := func(name, key string) *v1core.SecretKeySelector {
+		return &v1core.SecretKeySelector{LocalObjectReference: v1core.LocalObjectReference{Name: name}, Key: key}
+	}
+
+	Context("Test CnvrgApp Round Trip", func() {
+		It("Default spec round trips without conversion data", func() {
+			hub := roundTripApp(getDefaultTestAppSpec("conversion-ns"))
+			Expect(hub.Annotations).ShouldNot(HaveKey(mlopsv1.ConversionDataAnnotation))
+		})
+
+		It("Credentials are converted to secret values", func() {
+			testApp := getDefaultTestAppSpec("conversion-ns")
+			testApp.Spec.ControlPlane.SMTP.Password = "smtp-pass"
+			testApp.Spec.ControlPlane.ObjectStorage.AccessKey = "access"
+			testApp.Spec.Registry.Password = "registry-pass"
+			testApp.Spec.SSO.ClientSecret = "client-secret"
+
+			hub := roundTripApp(testApp)
+			Expect(hub.Spec.ControlPlane.SMTP.Password.Value).Should(Equal("smtp-pass"))
+			Expect(hub.Spec.ControlPlane.ObjectStorage.AccessKey.Value).Should(Equal("access"))
+			Expect(hub.Spec.ControlPlane.ObjectStorage.SecretKey).Should(BeNil())
+			Expect(hub.Spec.Registry.Password.Value).Should(Equal("registry-pass"))
+			Expect(hub.Spec.SSO.ClientSecret.Value).Should(Equal("client-secret"))
+		})
+
+		It("Legacy fields are normalized and restored", func() {
+			testApp := getDefaultTestAppSpec("conversion-ns")
+			testApp.Spec.ControlPlane.Hyper.Limits = mlopsv1.Limits{Memory: "8Gi"}
+			testApp.Spec.ControlPlane.Hyper.CPULimit = "2"
+			testApp.Spec.ControlPlane.Hyper.MemoryLimit = "4Gi"
+			testApp.Spec.ControlPlane.Ldap.Port = "389"
+			testApp.Spec.ControlPlane.Ldap.Ssl = "false"
+			testApp.Spec.ControlPlane.BaseConfig.RunJobsOnSelfCluster = "yes"
+			testApp.Spec.ControlPlane.Mpi.Registry = mlopsv1.Registry{}
+
+			hub := roundTripApp(testApp)
+			// limits take precedence over the legacy cpuLimit/memoryLimit
+			Expect(hub.Spec.ControlPlane.Hyper.Limits).Should(Equal(mlopsv1beta2.Limits{Cpu: "2", Memory: "8Gi"}))
+			Expect(hub.Spec.ControlPlane.Ldap.Port).Should(Equal(389))
+			Expect(hub.Spec.ControlPlane.Ldap.Ssl).Should(BeFalse())
+			Expect(hub.Spec.ControlPlane.BaseConfig.RunJobsOnSelfCluster).Should(BeFalse())
+			Expect(hub.Spec.ControlPlane.Mpi.Registry).Should(BeNil())
+			Expect(hub.Annotations).Should(HaveKey(mlopsv1.ConversionDataAnnotation))
+		})
+
+		It("Secret references round trip through v1", func() {
+			hub := &mlopsv1beta2.CnvrgApp{}
+			Expect(getDefaultTestAppSpec("conversion-ns").ConvertTo(hub)).To(Succeed())
+			hub.Spec.SSO.CookieSecret = &mlopsv1beta2.SecretValue{SecretKeyRef: secretRef("sso", "cookie")}
+			hub.Spec.ControlPlane.Ldap.AdminPassword = &mlopsv1beta2.SecretValue{Value: "pass", SecretKeyRef: secretRef("ldap", "password")}
+			hub.Spec.ControlPlane.Ldap.Ssl = true
+			hub.Spec.ControlPlane.Ldap.Port = 636
+
+			app := roundTripHub(hub)
+			Expect(app.Spec.SSO.CookieSecret).Should(BeEmpty())
+			Expect(app.Spec.SSO.CookieSecretRef).Should(Equal(secretRef("sso", "cookie")))
+			Expect(app.Spec.ControlPlane.Ldap.AdminPassword).Should(Equal("pass"))
+			Expect(app.Spec.ControlPlane.Ldap.AdminPasswordSecretRef).Should(Equal(secretRef("ldap", "password")))
+			Expect(app.Annotations).ShouldNot(HaveKey(mlopsv1.ConversionDataAnnotation))
+			Expect(app.Spec.ControlPlane.Ldap.Ssl).Should(Equal("true"))
+			Expect(app.Spec.ControlPlane.Ldap.Port).Should(Equal("636"))
+		})
+
+		It("Values changed in v1beta2 replace the stashed values", func() {
+			testApp := getDefaultTestAppSpec("conversion-ns")
+			testApp.Spec.ControlPlane.Ldap.Ssl = "false"
+			hub := &mlopsv1beta2.CnvrgApp{}
+			Expect(testApp.ConvertTo(hub)).To(Succeed())
+			Expect(hub.Annotations).Should(HaveKey(mlopsv1.ConversionDataAnnotation))
+
+			hub.Spec.ControlPlane.Ldap.Ssl = true
+			app := &mlopsv1.CnvrgApp{}
+			Expect(app.ConvertFrom(hub)).To(Succeed())
+			Expect(app.Spec.ControlPlane.Ldap.Ssl).Should(Equal("true"))
+			Expect(app.Annotations).ShouldNot(HaveKey(mlopsv1.ConversionDataAnnotation))
+		})
+	})
+
+	Context("Test CnvrgInfra Round Trip", func() {
+		It("Infra spec round trips", func() {
+			testInfra := getDefaultTestInfraSpec("conversion-ns")
+			testInfra.Spec.Registry.Password = "registry-pass"
+			hub := &mlopsv1beta2.CnvrgInfra{}
+			Expect(testInfra.ConvertTo(hub)).To(Succeed())
+			Expect(hub.Spec.Registry.Password.Value).Should(Equal("registry-pass"))
+
+			hub.Spec.SSO.ClientSecret = &mlopsv1beta2.SecretValue{SecretKeyRef: secretRef("sso", "client")}
+			infra := &mlopsv1.CnvrgInfra{}
+			Expect(infra.ConvertFrom(hub)).To(Succeed())
+			back := &mlopsv1beta2.CnvrgInfra{}
+			Expect(infra.ConvertTo(back)).To(Succeed())
+			Expect(back.ObjectMeta).To(Equal(hub.ObjectMeta))
+			Expect(back.Spec).To(Equal(hub.Spec))
+		})
+	})
+
+	Context("Test CRD Versions", func() {
+		renderCrd := func(name string) *unstructured.Unstructured {
+			crds := controlplane.Crds()
+			Expect(desired.Render(context.Background(), crds, &mlopsv1.CnvrgInfra{Spec: mlopsv1.DefaultCnvrgInfraSpec()}, ctrl.Log.WithName("conversion"))).To(Succeed())
+			for _, crd := range crds {
+				if crd.Obj.GetName() == name {
+					return crd.Obj
+				}
+			}
+			return nil
+		}
+
+		version := func(crd *unstructured.Unstructured, name string) map[string]interface{} {
+			versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+			for _, v := range versions {
+				if v.(map[string]interface{})["name"] == name {
+					return v.(map[string]interface{})
+				}
+			}
+			return nil
+		}
+
+		It("Shipped CRDs store v1beta2 and serve v1 with the conversion webhook", func() {
+			for _, name := range []string{"cnvrgapps.mlops.cnvrg.io", "cnvrginfras.mlops.cnvrg.io"} {
+				crd := renderCrd(name)
+				Expect(crd).ShouldNot(BeNil())
+				Expect(version(crd, "v1")).Should(HaveKeyWithValue("storage", false), name)
+				Expect(version(crd, "v1")).Should(HaveKeyWithValue("served", true), name)
+				Expect(version(crd, "v1beta2")).Should(HaveKeyWithValue("storage", true), name)
+				Expect(version(crd, "v1beta2")).Should(HaveKeyWithValue("served", true), name)
+				strategy, _, _ := unstructured.NestedString(crd.Object, "spec", "conversion", "strategy")
+				Expect(strategy).Should(Equal("Webhook"), name)
+			}
+		})
+
+		It("Conversion webhook is pointed to the webhook service", func() {
+			dir, err := ioutil.TempDir("", "webhook-cert")
+			Expect(err).ToNot(HaveOccurred())
+			defer os.RemoveAll(dir)
+			viper.Set("webhook-cert-dir", dir)
+			viper.Set("webhook-service", "webhook-service")
+			viper.Set("webhook-service-namespace", "cnvrg-infra")
+			defer viper.Set("webhook-cert-dir", "")
+			Expect(EnsureWebhookCert()).To(Succeed())
+
+			overrides, err := crdConversionOverrides()
+			Expect(err).ToNot(HaveOccurred())
+			crds := controlplane.Crds()
+			spec := &mlopsv1.CnvrgInfra{Spec: mlopsv1.DefaultCnvrgInfraSpec()}
+			spec.Spec.Overrides = overrides
+			Expect(desired.Render(context.Background(), crds, spec, ctrl.Log.WithName("conversion"))).To(Succeed())
+			for _, crd := range crds {
+				if crd.Obj.GetName() != "cnvrgapps.mlops.cnvrg.io" && crd.Obj.GetName() != "cnvrginfras.mlops.cnvrg.io" {
+					continue
+				}
+				clientConfig, _, _ := unstructured.NestedMap(crd.Obj.Object, "spec", "conversion", "webhook", "clientConfig")
+				Expect(clientConfig).Should(HaveKey("caBundle"))
+				Expect(clientConfig["service"]).Should(HaveKeyWithValue("namespace", "cnvrg-infra"))
+			}
+		})
+	})
+})
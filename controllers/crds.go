@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"context"
+	mlopsv1 "github.com/AccessibleAI/cnvrg-operator/api/v1"
+	"github.com/AccessibleAI/cnvrg-operator/pkg/controlplane"
+	"github.com/AccessibleAI/cnvrg-operator/pkg/desired"
+	"github.com/AccessibleAI/cnvrg-operator/pkg/monitoring"
+	"github.com/AccessibleAI/cnvrg-operator/pkg/networking"
+	"github.com/spf13/viper"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"time"
+)
+
+// crdEstablishTimeout is the time the deployed CRDs have to be served, before the cluster APIs are discovered
+const crdEstablishTimeout = 2 * time.Minute
+
+// DeployDependedCrds applies the istio, prometheus and control plane CRDs shipped by the operator,
+// and waits for them to be established. Must be called at startup, before the cluster APIs are discovered
+// and before the watches are set up, the client must not be the cached manager client
+func DeployDependedCrds(ctx context.Context, c client.Client, scheme *runtime.Scheme) error {
+	log := logf.FromContext(ctx)
+	var crds []*desired.State
+
+	if viper.GetBool("own-istio-resources") {
+		istioCrds := networking.IstioCrds()
+		if err := desired.Apply(ctx, istioCrds, &mlopsv1.CnvrgInfra{Spec: mlopsv1.DefaultCnvrgInfraSpec()}, c, scheme, log); err != nil {
+			log.Error(err, "can't apply istio CRDs")
+			return err
+		}
+		crds = append(crds, istioCrds...)
+	}
+
+	if viper.GetBool("own-prometheus-resources") {
+		prometheusCrds := monitoring.Crds()
+		if err := desired.Apply(ctx, prometheusCrds, &mlopsv1.CnvrgInfra{Spec: mlopsv1.DefaultCnvrgInfraSpec()}, c, scheme, log); err != nil {
+			log.Error(err, "can't apply prometheus CRDs")
+			return err
+		}
+		crds = append(crds, prometheusCrds...)
+	}
+
+	overrides, err := crdConversionOverrides()
+	if err != nil {
+		log.Error(err, "can't configure control plane crds conversion")
+		return err
+	}
+	crdSpec := &mlopsv1.CnvrgInfra{Spec: mlopsv1.DefaultCnvrgInfraSpec()}
+	crdSpec.Spec.Overrides = overrides
+	controlPlaneCrds := controlplane.Crds()
+	if err := desired.Apply(ctx, controlPlaneCrds, crdSpec, c, scheme, log); err != nil {
+		log.Error(err, "can't apply control plane crds")
+		return err
+	}
+	crds = append(crds, controlPlaneCrds...)
+
+	if viper.GetBool("dry-run") {
+		return nil
+	}
+	return waitCrdsEstablished(ctx, c, crds)
+}
+
+// waitCrdsEstablished waits for the Established condition of the applied CRDs
+func waitCrdsEstablished(ctx context.Context, c client.Client, crds []*desired.State) error {
+	log := logf.FromContext(ctx)
+	return wait.PollImmediate(time.Second, crdEstablishTimeout, func() (bool, error) {
+		for _, crd := range crds {
+			live := &unstructured.Unstructured{}
+			live.SetGroupVersionKind(crd.GVK)
+			if err := c.Get(ctx, types.NamespacedName{Name: crd.Obj.GetName()}, live); err != nil {
+				return false, client.IgnoreNotFound(err)
+			}
+			if !crdEstablished(live) {
+				log.V(1).Info("waiting for crd to be established", "name", crd.Obj.GetName())
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+}
+
+func crdEstablished(crd *unstructured.Unstructured) bool {
+	conditions, _, _ := unstructured.NestedSlice(crd.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if ok && condition["type"] == "Established" && condition["status"] == "True" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"context"
+	mlopsv1 "github.com/AccessibleAI/cnvrg-operator/api/v1"
+	"github.com/AccessibleAI/cnvrg-operator/pkg/desired"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Differ compares the desired manifests of CnvrgApp/CnvrgInfra with the live cluster objects,
+// nothing is changed in the cluster, the updates are calculated with server side apply dry run.
+// Non template actions (grafana dashboards, backup annotations) are not compared.
+type Differ struct {
+	Client client.Client
+	Scheme *runtime.Scheme
+	Log    logr.Logger
+}
+
+// DiffCnvrgApp compares the cnvrgApp manifests with the live objects,
+// when infra is nil, the cnvrgInfra from the cluster is used
+func (d *Differ) DiffCnvrgApp(ctx context.Context, app *mlopsv1.CnvrgApp, infra *mlopsv1.CnvrgInfra) ([]desired.ObjectDiff, error) {
+	log := d.Log.WithValues("name", app.Name, "ns", app.Namespace)
+	reconciler := &CnvrgAppReconciler{Client: d.Client, Scheme: d.Scheme, Log: d.Log}
+	var err error
+	if infra == nil {
+		if infra, err = reconciler.getCnvrgInfra(logf.IntoContext(ctx, log)); err != nil {
+			log.Error(err, "can't get cnvrg infra")
+		}
+	} else if infra, err = d.desiredCnvrgInfra(ctx, infra); err != nil {
+		return nil, err
+	}
+
+	ctx = logf.IntoContext(ctx, log)
+	app = app.DeepCopy()
+	if err := d.setLiveMeta(ctx, app, types.NamespacedName{Name: app.Name, Namespace: app.Namespace}); err != nil {
+		return nil, err
+	}
+	desiredSpec, err := desiredCnvrgAppSpec(ctx, app, infra, d.Client)
+	if err != nil {
+		return nil, err
+	}
+	app.Spec = desiredSpec
+	// the pg upgrade status of the live spec decides the served pg version
+	live := &mlopsv1.CnvrgApp{}
+	if err := d.Client.Get(ctx, types.NamespacedName{Name: app.Name, Namespace: app.Namespace}, live); err == nil {
+		app.Status.PgUpgrade = live.Status.PgUpgrade
+	} else if !errors.IsNotFound(err) {
+		return nil, err
+	}
+	pgUpgradeSpec(app)
+	missing, err := resolveSecretRefs(ctx, d.Client, app.Namespace, appSecretRefs(app))
+	if err != nil {
+		return nil, err
+	}
+	if len(missing) > 0 {
+		log.Info("unresolved secret references, the credentials are compared as empty", "missing", missing)
+	}
+	return d.diff(ctx, reconciler.appComponents(ctx, app), app)
+}
+
+// DiffCnvrgInfra compares the cnvrgInfra manifests with the live objects
+func (d *Differ) DiffCnvrgInfra(ctx context.Context, infra *mlopsv1.CnvrgInfra) ([]desired.ObjectDiff, error) {
+	ctx = logf.IntoContext(ctx, d.Log.WithValues("name", infra.Name))
+	infra, err := d.desiredCnvrgInfra(ctx, infra)
+	if err != nil {
+		return nil, err
+	}
+	reconciler := &CnvrgInfraReconciler{Client: d.Client, Scheme: d.Scheme, Log: d.Log}
+	return d.diff(ctx, reconciler.infraComponents(ctx, infra), infra)
+}
+
+func (d *Differ) desiredCnvrgInfra(ctx context.Context, infra *mlopsv1.CnvrgInfra) (*mlopsv1.CnvrgInfra, error) {
+	infra = infra.DeepCopy()
+	if err := d.setLiveMeta(ctx, infra, types.NamespacedName{Name: infra.Name}); err != nil {
+		return nil, err
+	}
+	desiredSpec, err := desiredCnvrgInfraSpec(ctx, infra, d.Client)
+	if err != nil {
+		return nil, err
+	}
+	infra.Spec = desiredSpec
+	missing, err := resolveSecretRefs(ctx, d.Client, infra.Spec.InfraNamespace, infraSecretRefs(infra))
+	if err != nil {
+		return nil, err
+	}
+	if len(missing) > 0 {
+		logf.FromContext(ctx).Info("unresolved secret references, the credentials are compared as empty", "missing", missing)
+	}
+	return infra, nil
+}
+
+// setLiveMeta copies the uid of the live spec, so the owner references of the desired objects
+// are equal to the owner references of the live objects
+func (d *Differ) setLiveMeta(ctx context.Context, obj client.Object, name types.NamespacedName) error {
+	live := obj.DeepCopyObject().(client.Object)
+	if err := d.Client.Get(ctx, name, live); err != nil && errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	obj.SetUID(live.GetUID())
+	return nil
+}
+
+func (d *Differ) diff(ctx context.Context, components []component, owner v1.Object) ([]desired.ObjectDiff, error) {
+	var diffs []desired.ObjectDiff
+	for _, c := range components {
+		if c.states == nil {
+			continue
+		}
+		states, err := c.states()
+		if err != nil {
+			return nil, err
+		}
+		componentDiffs, err := desired.Diff(ctx, states, owner, d.Client, d.Scheme, logf.FromContext(ctx))
+		if err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, componentDiffs...)
+	}
+	return diffs, nil
+}
@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"context"
+	mlopsv1 "github.com/AccessibleAI/cnvrg-operator/api/v1"
+	"github.com/AccessibleAI/cnvrg-operator/pkg/desired"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"time"
+)
+
+// +kubebuilder:docs-gen:collapse=Imports
+
+var _ = Describe("Differ", func() {
+
+	const (
+		timeout  = time.Second * 60
+		interval = time.Millisecond * 250
+	)
+
+	findDiff := func(diffs []desired.ObjectDiff, kind, name string) *desired.ObjectDiff {
+		for i := range diffs {
+			if diffs[i].GVK.Kind == kind && diffs[i].Name == name {
+				return &diffs[i]
+			}
+		}
+		return nil
+	}
+
+	Context("Test Diff", func() {
+		It("Spec changes are reported as updates", func() {
+			ns := createNs()
+			ctx := context.Background()
+			testApp := getDefaultTestAppSpec(ns)
+			testApp.Spec.Dbs.Pg.Enabled = true
+			Expect(k8sClient.Create(ctx, testApp)).Should(Succeed())
+
+			deployment := v1.Deployment{}
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: testApp.Spec.Dbs.Pg.SvcName, Namespace: ns}, &deployment)
+				if err != nil {
+					return false
+				}
+				return true
+			}, timeout, interval).Should(BeTrue())
+
+			appRes := mlopsv1.CnvrgApp{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "cnvrgapp", Namespace: ns}, &appRes)).Should(Succeed())
+			appRes.Spec.Dbs.Pg.NodeSelector = map[string]string{"foo": "bar"}
+
+			differ := &Differ{Client: k8sClient, Scheme: scheme.Scheme, Log: ctrl.Log.WithName("diff")}
+			diffs, err := differ.DiffCnvrgApp(ctx, &appRes, nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			pgDiff := findDiff(diffs, "Deployment", testApp.Spec.Dbs.Pg.SvcName)
+			Expect(pgDiff).ShouldNot(BeNil())
+			Expect(pgDiff.Action).Should(Equal(desired.DiffUpdate))
+			Expect(pgDiff.Diff).Should(ContainSubstring("+        foo: bar"))
+
+			credsDiff := findDiff(diffs, "Secret", testApp.Spec.Dbs.Pg.CredsRef)
+			Expect(credsDiff).ShouldNot(BeNil())
+			Expect(credsDiff.Action).Should(Equal(desired.DiffSkip))
+
+			// diff doesn't change the live objects
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: testApp.Spec.Dbs.Pg.SvcName, Namespace: ns}, &deployment)).Should(Succeed())
+			Expect(deployment.Spec.Template.Spec.NodeSelector).ShouldNot(HaveKey("foo"))
+			Expect(k8sClient.Delete(ctx, testApp)).Should(Succeed())
+		})
+	})
+})
@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"context"
+	"github.com/AccessibleAI/cnvrg-operator/pkg/desired"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/viper"
+	"io/ioutil"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/kubernetes/scheme"
+	"os"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"time"
+)
+
+// +kubebuilder:docs-gen:collapse=Imports
+
+var _ = Describe("API Discovery", func() {
+
+	const (
+		timeout  = time.Second * 60
+		interval = time.Millisecond * 250
+	)
+
+	Context("Test Discovery", func() {
+		It("Objects are rendered with the discovered version", func() {
+			ns := createNs()
+			ctx := context.Background()
+			testApp := getDefaultTestAppSpec(ns)
+			testApp.Spec.Dbs.Pg.Enabled = true
+			Expect(k8sClient.Create(ctx, testApp)).Should(Succeed())
+
+			pdb := &unstructured.Unstructured{}
+			pdb.SetGroupVersionKind(desired.Kinds[desired.PodDisruptionBudgetGVK])
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: "pg", Namespace: ns}, pdb)
+				if err != nil {
+					return false
+				}
+				return true
+			}, timeout, interval).Should(BeTrue())
+			Expect(desired.APIVersion("PodDisruptionBudget")).Should(Equal(pdb.GetAPIVersion()))
+			Expect(k8sClient.Delete(ctx, testApp)).Should(Succeed())
+		})
+
+		It("Missing required API fails the discovery", func() {
+			dc, err := discovery.NewDiscoveryClientForConfig(cfg)
+			Expect(err).ToNot(HaveOccurred())
+			// openshift routes are not served by the test environment
+			err = desired.Discover(dc, []desired.GVKName{desired.OcpRouteGVK}, ctrl.Log.WithName("discovery"))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("required API Route is not served"))
+		})
+
+		It("Depended CRDs are established before the discovery", func() {
+			c, err := client.New(cfg, client.Options{Scheme: scheme.Scheme})
+			Expect(err).ToNot(HaveOccurred())
+			viper.Set("own-prometheus-resources", true)
+			defer viper.Set("own-prometheus-resources", false)
+			dir, err := ioutil.TempDir("", "webhook-cert")
+			Expect(err).ToNot(HaveOccurred())
+			defer os.RemoveAll(dir)
+			viper.Set("webhook-cert-dir", dir)
+			defer viper.Set("webhook-cert-dir", "")
+			Expect(EnsureWebhookCert()).To(Succeed())
+			ctx := logf.IntoContext(context.Background(), ctrl.Log.WithName("crds"))
+			Expect(DeployDependedCrds(ctx, c, scheme.Scheme)).Should(Succeed())
+			// the deployed CRDs point the conversion to the webhook service
+			Expect(convertTestCrds(c)).To(Succeed())
+
+			dc, err := discovery.NewDiscoveryClientForConfig(cfg)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(desired.Discover(dc, []desired.GVKName{desired.ServiceMonitorGVK}, ctrl.Log.WithName("discovery"))).Should(Succeed())
+		})
+	})
+})
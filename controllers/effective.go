@@ -0,0 +1,99 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	mlopsv1 "github.com/AccessibleAI/cnvrg-operator/api/v1"
+	"github.com/spf13/viper"
+	v1core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
+)
+
+// effectiveSpecKey is the key of the effective spec in the debug configmap
+const effectiveSpecKey = "spec.yaml"
+
+// effectiveCnvrgApp returns a copy of the cnvrgApp with the defaults applied.
+// The defaults are computed on every reconcile and never written back,
+// so the stored spec holds only the user settings
+func effectiveCnvrgApp(ctx context.Context, app *mlopsv1.CnvrgApp, infra *mlopsv1.CnvrgInfra, c client.Client) (*mlopsv1.CnvrgApp, error) {
+	effective := app.DeepCopy()
+	spec, err := desiredCnvrgAppSpec(ctx, effective, infra, c)
+	if err != nil {
+		return nil, err
+	}
+	effective.Spec = spec
+	return effective, nil
+}
+
+// effectiveCnvrgInfra returns a copy of the cnvrgInfra with the defaults applied
+func effectiveCnvrgInfra(ctx context.Context, infra *mlopsv1.CnvrgInfra, c client.Client) (*mlopsv1.CnvrgInfra, error) {
+	effective := infra.DeepCopy()
+	spec, err := desiredCnvrgInfraSpec(ctx, effective, c)
+	if err != nil {
+		return nil, err
+	}
+	effective.Spec = spec
+	return effective, nil
+}
+
+// effectiveSpecCmName is the name of the debug configmap of the effective spec
+func effectiveSpecCmName(name string) string {
+	return fmt.Sprintf("%s-effective-spec", name)
+}
+
+// redactedValue replaces the credentials in the effective spec dump
+const redactedValue = "<redacted>"
+
+// redactedCnvrgAppSpec returns a copy of the spec with the plaintext credentials blanked,
+// the dump configmap is readable by a wider audience than the cnvrgApp
+func redactedCnvrgAppSpec(app *mlopsv1.CnvrgApp) mlopsv1.CnvrgAppSpec {
+	redacted := app.DeepCopy()
+	redactSecretRefs(appSecretRefs(redacted))
+	for _, ext := range []*mlopsv1.ExternalDb{&redacted.Spec.Dbs.Pg.External, &redacted.Spec.Dbs.Redis.External, &redacted.Spec.Dbs.Es.External} {
+		redactSecretRefs([]secretRef{{value: &ext.Username}, {value: &ext.Password}})
+	}
+	return redacted.Spec
+}
+
+// redactedCnvrgInfraSpec returns a copy of the spec with the plaintext credentials blanked
+func redactedCnvrgInfraSpec(infra *mlopsv1.CnvrgInfra) mlopsv1.CnvrgInfraSpec {
+	redacted := infra.DeepCopy()
+	redactSecretRefs(infraSecretRefs(redacted))
+	return redacted.Spec
+}
+
+func redactSecretRefs(refs []secretRef) {
+	for _, r := range refs {
+		if *r.value != "" {
+			*r.value = redactedValue
+		}
+	}
+}
+
+// saveEffectiveSpec writes the effective spec to the debug configmap, when dump-effective-spec is enabled.
+// The spec must be redacted, errors are logged only, the configmap is a debugging aid and must not fail the reconcile
+func saveEffectiveSpec(ctx context.Context, c client.Client, scheme *runtime.Scheme, owner client.Object, namespace string, spec interface{}) {
+	log := logf.FromContext(ctx)
+	if !viper.GetBool("dump-effective-spec") {
+		return
+	}
+	b, err := yaml.Marshal(spec)
+	if err != nil {
+		log.Error(err, "can't marshal effective spec")
+		return
+	}
+	cm := &v1core.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: effectiveSpecCmName(owner.GetName()), Namespace: namespace}}
+	_, err = controllerutil.CreateOrUpdate(ctx, c, cm, func() error {
+		cm.Data = map[string]string{effectiveSpecKey: string(b)}
+		return ctrl.SetControllerReference(owner, cm, scheme)
+	})
+	if err != nil {
+		log.Error(err, "can't save effective spec", "cm", cm.Name)
+	}
+}
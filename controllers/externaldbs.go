@@ -0,0 +1,319 @@
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	mlopsv1 "github.com/AccessibleAI/cnvrg-operator/api/v1"
+	"github.com/AccessibleAI/cnvrg-operator/pkg/dbs"
+	"github.com/AccessibleAI/cnvrg-operator/pkg/desired"
+	v1batch "k8s.io/api/batch/v1"
+	v1core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"net"
+	"net/url"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// preflightLabel marks the preflight jobs of the external databases, the value is the hash of the checked configuration
+const preflightLabel = "mlops.cnvrg.io/preflight"
+
+// defaultExternalPgDatabase is the database of the external pg when the secret doesn't set it, same as the in-cluster pg
+const defaultExternalPgDatabase = "cnvrg_production"
+
+// keys of the external databases secrets
+const (
+	externalUsernameKey = "username"
+	externalPasswordKey = "password"
+	externalDatabaseKey = "database"
+)
+
+// externalDb is an enabled external database of the cnvrgApp
+type externalDb struct {
+	name     string
+	external *mlopsv1.ExternalDb
+	credsRef string
+}
+
+func externalDbs(app *mlopsv1.CnvrgApp) []externalDb {
+	var enabled []externalDb
+	for _, db := range []externalDb{
+		{"pg", &app.Spec.Dbs.Pg.External, app.Spec.Dbs.Pg.CredsRef},
+		{"redis", &app.Spec.Dbs.Redis.External, app.Spec.Dbs.Redis.CredsRef},
+		{"es", &app.Spec.Dbs.Es.External, app.Spec.Dbs.Es.CredsRef},
+	} {
+		if db.external.Enabled {
+			enabled = append(enabled, db)
+		}
+	}
+	return enabled
+}
+
+// externalDbSecretRefs are the credentials of the enabled external databases, the database key of pg
+// and the username of redis are optional
+func externalDbSecretRefs(app *mlopsv1.CnvrgApp) []secretRef {
+	key := func(name, key string, optional bool) *v1core.SecretKeySelector {
+		return &v1core.SecretKeySelector{LocalObjectReference: v1core.LocalObjectReference{Name: name}, Key: key, Optional: &optional}
+	}
+	var refs []secretRef
+	for _, db := range externalDbs(app) {
+		path := fmt.Sprintf("spec.dbs.%s.external.secretRef", db.name)
+		ext := db.external
+		refs = append(refs,
+			secretRef{path, key(ext.SecretRef, externalUsernameKey, db.name == "redis"), &ext.Username},
+			secretRef{path, key(ext.SecretRef, externalPasswordKey, false), &ext.Password},
+		)
+		if db.name == "pg" {
+			refs = append(refs, secretRef{path, key(ext.SecretRef, externalDatabaseKey, true), &ext.Database})
+		}
+	}
+	return refs
+}
+
+func externalPgCreds(app *mlopsv1.CnvrgApp) []*desired.State {
+	ext := app.Spec.Dbs.Pg.External
+	database := ext.Database
+	if database == "" {
+		database = defaultExternalPgDatabase
+	}
+	return dbs.ExternalPgCreds(dbs.ExternalPgCredsData{
+		Namespace:   app.Namespace,
+		Annotations: app.Spec.Annotations,
+		Labels:      app.Spec.Labels,
+		CredsRef:    app.Spec.Dbs.Pg.CredsRef,
+		Host:        ext.Host,
+		Port:        ext.Port,
+		TLS:         ext.TLS,
+		User:        ext.Username,
+		Password:    ext.Password,
+		Database:    database,
+	})
+}
+
+func externalRedisCreds(app *mlopsv1.CnvrgApp) []*desired.State {
+	ext := app.Spec.Dbs.Redis.External
+	return dbs.ExternalRedisCreds(dbs.ExternalRedisCredsData{
+		Namespace:   app.Namespace,
+		Annotations: app.Spec.Annotations,
+		Labels:      app.Spec.Labels,
+		CredsRef:    app.Spec.Dbs.Redis.CredsRef,
+		Password:    ext.Password,
+		Url:         externalDbUrl("redis", ext),
+	})
+}
+
+func externalEsCreds(app *mlopsv1.CnvrgApp) []*desired.State {
+	ext := app.Spec.Dbs.Es.External
+	return dbs.ExternalEsCreds(dbs.ExternalEsCredsData{
+		Namespace:   app.Namespace,
+		Annotations: app.Spec.Annotations,
+		Labels:      app.Spec.Labels,
+		CredsRef:    app.Spec.Dbs.Es.CredsRef,
+		User:        ext.Username,
+		Password:    ext.Password,
+		Url:         externalDbUrl("http", ext),
+	})
+}
+
+// esHostUrl is the es url of kibana, the external es when it's enabled, without the credentials
+func esHostUrl(app *mlopsv1.CnvrgApp) string {
+	es := app.Spec.Dbs.Es
+	if !es.External.Enabled {
+		return fmt.Sprintf("http://%s.%s.svc:%d", es.SvcName, app.Namespace, es.Port)
+	}
+	scheme := "http"
+	if es.External.TLS {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(es.External.Host, strconv.Itoa(es.External.Port)))
+}
+
+// externalDbUrl returns the url of the external database with the credentials, the tls scheme is suffixed by s
+func externalDbUrl(scheme string, ext mlopsv1.ExternalDb) string {
+	if ext.TLS {
+		scheme += "s"
+	}
+	u := url.URL{
+		Scheme: scheme,
+		User:   url.UserPassword(ext.Username, ext.Password),
+		Host:   net.JoinHostPort(ext.Host, strconv.Itoa(ext.Port)),
+	}
+	return u.String()
+}
+
+// preflightHash is the hash of the external databases configuration, the credentials included
+func preflightHash(app *mlopsv1.CnvrgApp) string {
+	h := sha256.New()
+	for _, db := range externalDbs(app) {
+		fmt.Fprintf(h, "%s/%s/%+v\n", db.name, db.credsRef, *db.external)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:10]
+}
+
+func preflightJobName(app *mlopsv1.CnvrgApp) string {
+	return "external-dbs-preflight-" + preflightHash(app)
+}
+
+// appPreflightState returns the preflight job of the external databases configuration
+func appPreflightState(app *mlopsv1.CnvrgApp) []*desired.State {
+	enabled := externalDbs(app)
+	if len(enabled) == 0 {
+		return nil
+	}
+	nodeSelector := map[string]string{}
+	if app.Spec.Tenancy.Enabled {
+		nodeSelector[app.Spec.Tenancy.Key] = app.Spec.Tenancy.Value
+	}
+	data := dbs.PreflightData{
+		Namespace:    app.Namespace,
+		Annotations:  app.Spec.Annotations,
+		Labels:       app.Spec.Labels,
+		Name:         preflightJobName(app),
+		Hash:         preflightHash(app),
+		NodeSelector: nodeSelector,
+		Tenancy:      app.Spec.Tenancy.Enabled,
+	}
+	for _, db := range enabled {
+		check := &dbs.PreflightCheck{SecretRef: db.external.SecretRef, Host: db.external.Host, Port: db.external.Port, TLS: db.external.TLS}
+		switch db.name {
+		case "pg":
+			check.Image = fullImage(app.Spec.ImageHub, app.Spec.Dbs.Pg.Image)
+			check.Database = defaultExternalPgDatabase
+			data.Pg = check
+		case "redis":
+			check.Image = fullImage(app.Spec.ImageHub, app.Spec.Dbs.Redis.Image)
+			data.Redis = check
+		case "es":
+			check.Image = fullImage(app.Spec.ImageHub, app.Spec.Dbs.Es.Image)
+			data.Es = check
+		}
+	}
+	return dbs.Preflight(data)
+}
+
+// externalDbsCreds returns the creds secrets of the external databases, the secrets are applied only once the
+// preflight of the external databases succeeded, so the control plane isn't reloaded with unreachable databases
+func externalDbsCreds(app *mlopsv1.CnvrgApp) []*desired.State {
+	var state []*desired.State
+	if app.Spec.Dbs.Pg.External.Enabled {
+		state = append(state, externalPgCreds(app)...)
+	}
+	if app.Spec.Dbs.Redis.External.Enabled {
+		state = append(state, externalRedisCreds(app)...)
+	}
+	if app.Spec.Dbs.Es.External.Enabled {
+		state = append(state, externalEsCreds(app)...)
+	}
+	return state
+}
+
+// preflightReady is true once the preflight job of the current configuration succeeded, or when there are no external databases
+func preflightReady(ctx context.Context, c client.Reader, app *mlopsv1.CnvrgApp) (bool, error) {
+	if len(externalDbs(app)) == 0 {
+		return true, nil
+	}
+	return checkJobReadiness(ctx, c, types.NamespacedName{Name: preflightJobName(app), Namespace: app.Namespace})
+}
+
+// syncPreflight removes the preflight jobs of the previous configurations, and reports the result of the current one
+func (r *CnvrgAppReconciler) syncPreflight(ctx context.Context, app *mlopsv1.CnvrgApp) error {
+	log := logf.FromContext(ctx)
+	enabled := externalDbs(app)
+	name := ""
+	if len(enabled) > 0 {
+		name = preflightJobName(app)
+	}
+
+	jobs := &v1batch.JobList{}
+	if err := r.List(ctx, jobs, client.InNamespace(app.Namespace), client.HasLabels{preflightLabel}); err != nil {
+		return err
+	}
+	var job *v1batch.Job
+	for i := range jobs.Items {
+		if jobs.Items[i].Name == name {
+			job = &jobs.Items[i]
+			continue
+		}
+		if err := r.Delete(ctx, &jobs.Items[i], client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+			log.Error(err, "error deleting preflight job", "name", jobs.Items[i].Name)
+			return err
+		}
+	}
+
+	if len(enabled) == 0 {
+		return r.updatePreflightCondition(ctx, app, nil)
+	}
+	var databases, failures []string
+	for _, db := range enabled {
+		databases = append(databases, db.name)
+	}
+	if job != nil && jobFailed(job) {
+		var err error
+		if failures, err = r.preflightFailures(ctx, job); err != nil {
+			return err
+		}
+	}
+	condition := preflightCondition(name, job, databases, failures)
+	return r.updatePreflightCondition(ctx, app, &condition)
+}
+
+// preflightFailures returns the failed checks of the last preflight pod, reported by the containers termination messages
+func (r *CnvrgAppReconciler) preflightFailures(ctx context.Context, job *v1batch.Job) ([]string, error) {
+	pods := &v1core.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(job.Namespace), client.MatchingLabels{"job-name": job.Name}); err != nil {
+		return nil, err
+	}
+	sort.Slice(pods.Items, func(i, j int) bool {
+		return pods.Items[j].CreationTimestamp.Before(&pods.Items[i].CreationTimestamp)
+	})
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != v1core.PodFailed {
+			continue
+		}
+		var failures []string
+		for _, c := range pod.Status.ContainerStatuses {
+			if c.State.Terminated != nil && c.State.Terminated.ExitCode != 0 {
+				failures = append(failures, strings.TrimSpace(c.State.Terminated.Message))
+			}
+		}
+		sort.Strings(failures)
+		return failures, nil
+	}
+	return nil, nil
+}
+
+// updatePreflightCondition sets the preflight condition, the condition is removed when there are no external databases
+func (r *CnvrgAppReconciler) updatePreflightCondition(ctx context.Context, app *mlopsv1.CnvrgApp, condition *metav1.Condition) error {
+	current := meta.FindStatusCondition(app.Status.Conditions, mlopsv1.ConditionExternalDbsReachable)
+	if condition == nil && current == nil {
+		return nil
+	}
+	if condition != nil && current != nil && current.Status == condition.Status && current.Reason == condition.Reason &&
+		current.Message == condition.Message && current.ObservedGeneration == app.Generation {
+		return nil
+	}
+	if condition != nil && condition.Reason == mlopsv1.ReasonPreflightFailed {
+		r.recorder.Event(app, "Warning", condition.Reason, condition.Message)
+	}
+	generation := app.Generation
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest, err := r.getCnvrgAppSpec(ctx, types.NamespacedName{Namespace: app.Namespace, Name: app.Name})
+		if err != nil || latest == nil {
+			return err
+		}
+		if condition == nil {
+			meta.RemoveStatusCondition(&latest.Status.Conditions, mlopsv1.ConditionExternalDbsReachable)
+		} else {
+			setConditions(&latest.Status.Conditions, []metav1.Condition{*condition}, generation)
+		}
+		return r.Status().Update(ctx, latest)
+	})
+}
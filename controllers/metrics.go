@@ -0,0 +1,104 @@
+package controllers
+
+import (
+	mlopsv1 "github.com/AccessibleAI/cnvrg-operator/api/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sync"
+	"time"
+)
+
+var componentApplyDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "cnvrg_operator_component_apply_duration_seconds",
+		Help:    "Duration of applying a component, by owner kind and component",
+		Buckets: []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	},
+	[]string{"kind", "component"},
+)
+
+var readinessPercentage = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "cnvrg_operator_readiness_percentage",
+		Help: "Percentage of the ready components of the CnvrgApp/CnvrgInfra",
+	},
+	[]string{"kind", "namespace", "name"},
+)
+
+var componentReady = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "cnvrg_operator_component_ready",
+		Help: "Readiness of the CnvrgApp/CnvrgInfra component (1 ready, 0 not ready), by stack readiness key",
+	},
+	[]string{"kind", "namespace", "name", "component"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(componentApplyDuration, readinessPercentage, componentReady)
+}
+
+// readinessComponents holds the components with published readiness by CR,
+// so the series of disabled components and deleted CRs can be removed
+var readinessComponents = struct {
+	sync.Mutex
+	components map[string]map[types.NamespacedName][]string
+}{components: map[string]map[types.NamespacedName][]string{}}
+
+// ownerKind returns the kind label of the owner, the owner's TypeMeta isn't always populated
+func ownerKind(owner v1.Object) string {
+	switch owner.(type) {
+	case *mlopsv1.CnvrgApp:
+		return "CnvrgApp"
+	case *mlopsv1.CnvrgInfra:
+		return "CnvrgInfra"
+	}
+	return ""
+}
+
+func observeComponentApply(owner v1.Object, component string, start time.Time) {
+	componentApplyDuration.WithLabelValues(ownerKind(owner), component).Observe(time.Since(start).Seconds())
+}
+
+// observeReadiness publishes the readiness percentage and the readiness of each stack readiness key
+func observeReadiness(owner v1.Object, progress int, stackReadiness map[string]bool) {
+	kind := ownerKind(owner)
+	name := types.NamespacedName{Namespace: owner.GetNamespace(), Name: owner.GetName()}
+	readinessPercentage.WithLabelValues(kind, name.Namespace, name.Name).Set(float64(progress))
+
+	readinessComponents.Lock()
+	defer readinessComponents.Unlock()
+	if readinessComponents.components[kind] == nil {
+		readinessComponents.components[kind] = map[types.NamespacedName][]string{}
+	}
+	for _, c := range readinessComponents.components[kind][name] {
+		if _, ok := stackReadiness[c]; !ok {
+			componentReady.DeleteLabelValues(kind, name.Namespace, name.Name, c)
+		}
+	}
+	var components []string
+	for c, ready := range stackReadiness {
+		value := 0.0
+		if ready {
+			value = 1
+		}
+		componentReady.WithLabelValues(kind, name.Namespace, name.Name, c).Set(value)
+		components = append(components, c)
+	}
+	readinessComponents.components[kind][name] = components
+}
+
+// deleteReadinessMetrics removes the readiness series of the deleted CR
+func deleteReadinessMetrics(owner v1.Object) {
+	kind := ownerKind(owner)
+	name := types.NamespacedName{Namespace: owner.GetNamespace(), Name: owner.GetName()}
+	readinessPercentage.DeleteLabelValues(kind, name.Namespace, name.Name)
+
+	readinessComponents.Lock()
+	defer readinessComponents.Unlock()
+	for _, c := range readinessComponents.components[kind][name] {
+		componentReady.DeleteLabelValues(kind, name.Namespace, name.Name, c)
+	}
+	delete(readinessComponents.components[kind], name)
+}
@@ -0,0 +1,351 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	mlopsv1 "github.com/AccessibleAI/cnvrg-operator/api/v1"
+	"github.com/AccessibleAI/cnvrg-operator/pkg/dbs"
+	"github.com/AccessibleAI/cnvrg-operator/pkg/desired"
+	v1apps "k8s.io/api/apps/v1"
+	v1batch "k8s.io/api/batch/v1"
+	v1core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"reflect"
+	"regexp"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"strconv"
+	"strings"
+)
+
+// pg container and data volume of the pg deployment, must match the pg deployment template
+const (
+	pgContainerName = "postgresql"
+	pgDataVolume    = "postgres-data"
+)
+
+// pgUpgradeQueued is appended to the upgrade message while the pg image is set to a third version
+const pgUpgradeQueued = ", the upgrade to version "
+
+// pgImageVersion matches the major version in the image name, e.g. postgresql-12-centos7
+var pgImageVersion = regexp.MustCompile(`postgres(?:ql)?-(\d+)`)
+
+// pgMajorVersion returns the pg major version of the image, from the image name (postgresql-12-centos7:latest)
+// or from the tag of the postgres images (postgres:13.4)
+func pgMajorVersion(image string) (int, bool) {
+	name := image[strings.LastIndex(image, "/")+1:]
+	if i := strings.Index(name, "@"); i >= 0 {
+		name = name[:i]
+	}
+	if m := pgImageVersion.FindStringSubmatch(name); m != nil {
+		version, err := strconv.Atoi(m[1])
+		return version, err == nil
+	}
+	i := strings.Index(name, ":")
+	if i < 0 || !strings.HasPrefix(name, "postgres") {
+		return 0, false
+	}
+	tag := name[i+1:]
+	end := strings.IndexFunc(tag, func(r rune) bool { return r < '0' || r > '9' })
+	if end >= 0 {
+		tag = tag[:end]
+	}
+	version, err := strconv.Atoi(tag)
+	return version, err == nil
+}
+
+// fullImage prefixes the image with the image hub, same as the image template function
+func fullImage(imageHub, image string) string {
+	if strings.Contains(image, "/") {
+		return image
+	}
+	return fmt.Sprintf("%s/%s", imageHub, image)
+}
+
+func pgUpgradeJobName(app *mlopsv1.CnvrgApp) string {
+	return app.Spec.Dbs.Pg.SvcName + "-upgrade"
+}
+
+// pgWorkloads are the control plane deployments writing to pg, they are quiesced while the data is copied
+func pgWorkloads(app *mlopsv1.CnvrgApp) []string {
+	return []string{app.Spec.ControlPlane.WebApp.SvcName, "sidekiq", "searchkiq", "systemkiq", "scheduler"}
+}
+
+func pgUpgradeRunning(app *mlopsv1.CnvrgApp) bool {
+	return app.Status.PgUpgrade != nil && app.Status.PgUpgrade.Phase == mlopsv1.PgUpgradeRunning
+}
+
+// reconcilePgUpgrade drives the pg major version upgrade. The upgrade starts when the major version of the pg image
+// differs from the version of the running pg deployment. The upgrade job copies the data into a new pvc while the
+// previous version keeps serving, and pg is switched to the new version only once the job verified the copy.
+// Reverting the image rolls the upgrade back, the pvc of the previous version is kept until the upgrade is confirmed
+func (r *CnvrgAppReconciler) reconcilePgUpgrade(ctx context.Context, app *mlopsv1.CnvrgApp) error {
+	log := logf.FromContext(ctx)
+	pg := app.Spec.Dbs.Pg
+	if !pg.Enabled || pg.HA.Enabled {
+		return nil
+	}
+	upgrade := app.Status.PgUpgrade.DeepCopy()
+	version, versioned := pgMajorVersion(pg.Image)
+	now := metav1.Now()
+
+	phase := mlopsv1.PgUpgradePhase("")
+	if upgrade != nil {
+		phase = upgrade.Phase
+	}
+	switch phase {
+	case mlopsv1.PgUpgradeRunning, mlopsv1.PgUpgradeFailed:
+		if versioned && version == upgrade.FromVersion {
+			if err := r.rollbackPgUpgrade(ctx, app, upgrade, &now); err != nil {
+				return err
+			}
+			break
+		}
+		job := &v1batch.Job{}
+		err := r.Get(ctx, types.NamespacedName{Name: pgUpgradeJobName(app), Namespace: app.Namespace}, job)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		switch {
+		case errors.IsNotFound(err) || (job.Status.Succeeded == 0 && !jobFailed(job)):
+			// the job is created by the pg component, a deleted failed job is recreated, hence retried
+			if upgrade.Phase != mlopsv1.PgUpgradeRunning {
+				upgrade.Phase = mlopsv1.PgUpgradeRunning
+				upgrade.Message = fmt.Sprintf("retrying the upgrade from version %d to %d", upgrade.FromVersion, upgrade.ToVersion)
+				upgrade.CompletionTime = nil
+			}
+			if err := quiesce(ctx, r.Client, app.Namespace, pgWorkloads(app)); err != nil {
+				return err
+			}
+		case job.Status.Succeeded > 0:
+			upgrade.Phase = mlopsv1.PgUpgradeSucceeded
+			upgrade.Message = fmt.Sprintf("pg is serving version %d, set spec.dbs.pg.upgrade.confirmedVersion to %d to remove the %s pvc",
+				upgrade.ToVersion, upgrade.ToVersion, upgrade.PreviousPvcName)
+			upgrade.CompletionTime = &now
+			if err := unquiesce(ctx, r.Client, app.Namespace, pgWorkloads(app)); err != nil {
+				return err
+			}
+		case upgrade.Phase != mlopsv1.PgUpgradeFailed:
+			upgrade.Phase = mlopsv1.PgUpgradeFailed
+			upgrade.Message = fmt.Sprintf("upgrade job %s failed, pg is serving version %d, check the job logs, "+
+				"delete the job to retry or revert the pg image to roll back", job.Name, upgrade.FromVersion)
+			upgrade.CompletionTime = &now
+			if err := unquiesce(ctx, r.Client, app.Namespace, pgWorkloads(app)); err != nil {
+				return err
+			}
+		}
+	case mlopsv1.PgUpgradeSucceeded:
+		if versioned && version == upgrade.FromVersion {
+			if err := r.rollbackPgUpgrade(ctx, app, upgrade, &now); err != nil {
+				return err
+			}
+		} else if pg.Upgrade.ConfirmedVersion == upgrade.ToVersion {
+			if err := r.deletePgUpgrade(ctx, app, upgrade.PreviousPvcName); err != nil {
+				return err
+			}
+			upgrade.Phase = mlopsv1.PgUpgradeCompleted
+			upgrade.Message = fmt.Sprintf("upgrade to version %d is confirmed, %s pvc is removed", upgrade.ToVersion, upgrade.PreviousPvcName)
+		}
+	default:
+		dep := &v1apps.Deployment{}
+		if err := r.Get(ctx, types.NamespacedName{Name: pg.SvcName, Namespace: app.Namespace}, dep); err != nil {
+			// pg is not deployed yet, the first deployment initializes the data with the image version
+			return client.IgnoreNotFound(err)
+		}
+		container, pvcName := pgDeploymentData(dep)
+		running, ok := pgMajorVersion(container)
+		if !versioned || !ok || version == running {
+			break
+		}
+		if version < running {
+			return fmt.Errorf("pg downgrade from version %d to %d is not supported", running, version)
+		}
+		log.Info("pg major version changed, starting upgrade", "from", running, "to", version)
+		upgrade = &mlopsv1.PgUpgradeStatus{
+			Phase:           mlopsv1.PgUpgradeRunning,
+			FromVersion:     running,
+			ToVersion:       version,
+			FromImage:       container,
+			ToImage:         fullImage(app.Spec.ImageHub, pg.Image),
+			PvcName:         fmt.Sprintf("%s-%d", pg.PvcName, version),
+			PreviousPvcName: pvcName,
+			Message:         fmt.Sprintf("upgrading from version %d to %d", running, version),
+			StartTime:       &now,
+		}
+		if err := quiesce(ctx, r.Client, app.Namespace, pgWorkloads(app)); err != nil {
+			return err
+		}
+	}
+
+	queuePgUpgrade(upgrade, version, versioned)
+
+	if reflect.DeepEqual(app.Status.PgUpgrade, upgrade) {
+		return nil
+	}
+	app.Status.PgUpgrade = upgrade
+	return r.updatePgUpgradeStatus(ctx, app)
+}
+
+// queuePgUpgrade reports a pg image of a third version, the new upgrade starts only once the current upgrade
+// is confirmed or rolled back, until then pg keeps the image of the current upgrade
+func queuePgUpgrade(upgrade *mlopsv1.PgUpgradeStatus, version int, versioned bool) {
+	if upgrade == nil {
+		return
+	}
+	if i := strings.Index(upgrade.Message, pgUpgradeQueued); i >= 0 {
+		upgrade.Message = upgrade.Message[:i]
+	}
+	switch upgrade.Phase {
+	case mlopsv1.PgUpgradeRunning, mlopsv1.PgUpgradeFailed, mlopsv1.PgUpgradeSucceeded:
+		if versioned && version != upgrade.FromVersion && version != upgrade.ToVersion {
+			upgrade.Message += fmt.Sprintf(pgUpgradeQueued+"%d is queued until the upgrade to version %d is confirmed or rolled back",
+				version, upgrade.ToVersion)
+		}
+	}
+}
+
+// rollbackPgUpgrade switches pg back to the previous version pvc, the new version pvc is removed,
+// data written after the switch to the new version is lost
+func (r *CnvrgAppReconciler) rollbackPgUpgrade(ctx context.Context, app *mlopsv1.CnvrgApp, upgrade *mlopsv1.PgUpgradeStatus, now *metav1.Time) error {
+	logf.FromContext(ctx).Info("rolling back pg upgrade", "from", upgrade.ToVersion, "to", upgrade.FromVersion)
+	if err := r.deletePgUpgrade(ctx, app, upgrade.PvcName); err != nil {
+		return err
+	}
+	if err := unquiesce(ctx, r.Client, app.Namespace, pgWorkloads(app)); err != nil {
+		return err
+	}
+	upgrade.Phase = mlopsv1.PgUpgradeRolledBack
+	upgrade.Message = fmt.Sprintf("upgrade to version %d is rolled back, pg is serving version %d", upgrade.ToVersion, upgrade.FromVersion)
+	upgrade.CompletionTime = now
+	return nil
+}
+
+// deletePgUpgrade removes the upgrade job and the pvc which is not served anymore
+func (r *CnvrgAppReconciler) deletePgUpgrade(ctx context.Context, app *mlopsv1.CnvrgApp, pvcName string) error {
+	log := logf.FromContext(ctx)
+	job := &v1batch.Job{ObjectMeta: metav1.ObjectMeta{Name: pgUpgradeJobName(app), Namespace: app.Namespace}}
+	if err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+		log.Error(err, "error deleting pg upgrade job", "name", job.Name)
+		return err
+	}
+	pvc := &v1core.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: pvcName, Namespace: app.Namespace}}
+	if err := r.Delete(ctx, pvc); client.IgnoreNotFound(err) != nil {
+		log.Error(err, "error deleting pg pvc", "name", pvcName)
+		return err
+	}
+	return nil
+}
+
+func (r *CnvrgAppReconciler) updatePgUpgradeStatus(ctx context.Context, app *mlopsv1.CnvrgApp) error {
+	upgrade := app.Status.PgUpgrade
+	eventType := "Normal"
+	if upgrade.Phase == mlopsv1.PgUpgradeFailed {
+		eventType = "Warning"
+	}
+	r.recorder.Event(app, eventType, "PgUpgrade"+string(upgrade.Phase), upgrade.Message)
+	generation := app.Generation
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest, err := r.getCnvrgAppSpec(ctx, types.NamespacedName{Namespace: app.Namespace, Name: app.Name})
+		if err != nil || latest == nil {
+			return err
+		}
+		latest.Status.PgUpgrade = upgrade
+		setConditions(&latest.Status.Conditions, []metav1.Condition{pgUpgradeCondition(upgrade)}, generation)
+		return r.Status().Update(ctx, latest)
+	})
+}
+
+// pgUpgradeSpec points pg at the image and the pvc of the version which is served during the upgrade
+func pgUpgradeSpec(app *mlopsv1.CnvrgApp) {
+	upgrade := app.Status.PgUpgrade
+	if upgrade == nil || !app.Spec.Dbs.Pg.Enabled || app.Spec.Dbs.Pg.HA.Enabled {
+		return
+	}
+	switch upgrade.Phase {
+	case mlopsv1.PgUpgradeRunning, mlopsv1.PgUpgradeFailed:
+		app.Spec.Dbs.Pg.Image = upgrade.FromImage
+		app.Spec.Dbs.Pg.PvcName = upgrade.PreviousPvcName
+	case mlopsv1.PgUpgradeSucceeded, mlopsv1.PgUpgradeCompleted:
+		app.Spec.Dbs.Pg.PvcName = upgrade.PvcName
+		// the data is of the upgraded version, an image of a third version is served only once its upgrade starts
+		if version, ok := pgMajorVersion(app.Spec.Dbs.Pg.Image); ok && version != upgrade.ToVersion {
+			app.Spec.Dbs.Pg.Image = upgrade.ToImage
+		}
+	case mlopsv1.PgUpgradeRolledBack:
+		app.Spec.Dbs.Pg.PvcName = upgrade.PreviousPvcName
+	}
+}
+
+// appPgUpgradeState returns the upgrade job and the new version pvc while the upgrade is in progress,
+// and the previous version pvc until the upgrade is confirmed
+func appPgUpgradeState(app *mlopsv1.CnvrgApp) []*desired.State {
+	upgrade := app.Status.PgUpgrade
+	pg := app.Spec.Dbs.Pg
+	if upgrade == nil || !pg.Enabled || pg.HA.Enabled {
+		return nil
+	}
+	pvcData := dbs.PgPvcData{
+		Namespace:    app.Namespace,
+		Annotations:  app.Spec.Annotations,
+		Labels:       app.Spec.Labels,
+		StorageSize:  pg.StorageSize,
+		StorageClass: pg.StorageClass,
+	}
+	switch upgrade.Phase {
+	case mlopsv1.PgUpgradeRunning, mlopsv1.PgUpgradeFailed:
+		pvcData.PvcName = upgrade.PvcName
+		nodeSelector := map[string]string{}
+		for k, v := range pg.NodeSelector {
+			nodeSelector[k] = v
+		}
+		if app.Spec.Tenancy.Enabled {
+			nodeSelector[app.Spec.Tenancy.Key] = app.Spec.Tenancy.Value
+		}
+		jobData := dbs.PgUpgradeData{
+			Namespace:      app.Namespace,
+			Annotations:    app.Spec.Annotations,
+			Labels:         app.Spec.Labels,
+			Name:           pgUpgradeJobName(app),
+			Image:          upgrade.ToImage,
+			ServiceAccount: pg.ServiceAccount,
+			NodeSelector:   nodeSelector,
+			Tenancy:        app.Spec.Tenancy.Enabled,
+			CredsRef:       pg.CredsRef,
+			PreviousHost:   pg.SvcName,
+			Port:           pg.Port,
+			PvcName:        upgrade.PvcName,
+		}
+		return append(dbs.PgPvc(pvcData), dbs.PgUpgrade(jobData)...)
+	case mlopsv1.PgUpgradeSucceeded:
+		pvcData.PvcName = upgrade.PreviousPvcName
+		return dbs.PgPvc(pvcData)
+	}
+	return nil
+}
+
+// pgDeploymentData returns the image and the data pvc of the pg deployment
+func pgDeploymentData(dep *v1apps.Deployment) (image string, pvcName string) {
+	for _, c := range dep.Spec.Template.Spec.Containers {
+		if c.Name == pgContainerName {
+			image = c.Image
+		}
+	}
+	for _, v := range dep.Spec.Template.Spec.Volumes {
+		if v.Name == pgDataVolume && v.PersistentVolumeClaim != nil {
+			pvcName = v.PersistentVolumeClaim.ClaimName
+		}
+	}
+	return image, pvcName
+}
+
+func jobFailed(job *v1batch.Job) bool {
+	for _, c := range job.Status.Conditions {
+		if c.Type == v1batch.JobFailed && c.Status == v1core.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
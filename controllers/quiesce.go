@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	"context"
+	v1apps "k8s.io/api/apps/v1"
+	v1core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"strconv"
+)
+
+// quiescedReplicasAnnotation holds the replicas of a deployment scaled down by the operator.
+// The cnvrgApp reconciler would apply the rendered replicas back, hence the components of the quiesced deployments
+// are held while they are quiesced (pg upgrade, restore), and the replicas are restored from the annotation
+const quiescedReplicasAnnotation = "mlops.cnvrg.io/quiesced-replicas"
+
+// quiesce scales the deployments down to zero, e.g. the control plane while its database is copied.
+// Missing deployments are skipped, quiescing an already quiesced deployment is a no-op
+func quiesce(ctx context.Context, c client.Client, namespace string, names []string) error {
+	log := logf.FromContext(ctx)
+	for _, name := range names {
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			dep := &v1apps.Deployment{}
+			if err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, dep); err != nil {
+				return client.IgnoreNotFound(err)
+			}
+			if _, ok := dep.Annotations[quiescedReplicasAnnotation]; ok {
+				return nil
+			}
+			replicas := int32(1)
+			if dep.Spec.Replicas != nil {
+				replicas = *dep.Spec.Replicas
+			}
+			if dep.Annotations == nil {
+				dep.Annotations = map[string]string{}
+			}
+			dep.Annotations[quiescedReplicasAnnotation] = strconv.Itoa(int(replicas))
+			zero := int32(0)
+			dep.Spec.Replicas = &zero
+			log.Info("quiescing deployment", "name", name, "replicas", replicas)
+			return c.Update(ctx, dep)
+		})
+		if err != nil {
+			log.Error(err, "can't quiesce deployment", "name", name)
+			return err
+		}
+	}
+	return nil
+}
+
+// unquiesce scales the quiesced deployments back to their replicas
+func unquiesce(ctx context.Context, c client.Client, namespace string, names []string) error {
+	log := logf.FromContext(ctx)
+	for _, name := range names {
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			dep := &v1apps.Deployment{}
+			if err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, dep); err != nil {
+				return client.IgnoreNotFound(err)
+			}
+			value, ok := dep.Annotations[quiescedReplicasAnnotation]
+			if !ok {
+				return nil
+			}
+			replicas, err := strconv.Atoi(value)
+			if err != nil {
+				log.Error(err, "bad quiesced replicas annotation, restoring a single replica", "name", name, "value", value)
+				replicas = 1
+			}
+			r := int32(replicas)
+			dep.Spec.Replicas = &r
+			delete(dep.Annotations, quiescedReplicasAnnotation)
+			log.Info("unquiescing deployment", "name", name, "replicas", replicas)
+			return c.Update(ctx, dep)
+		})
+		if err != nil {
+			log.Error(err, "can't unquiesce deployment", "name", name)
+			return err
+		}
+	}
+	return nil
+}
+
+// scaledDown checks the pods of the quiesced deployments are gone, including the terminating pods,
+// e.g. before the pvc of a quiesced deployment is written
+func scaledDown(ctx context.Context, c client.Client, namespace string, names []string) (bool, error) {
+	for _, name := range names {
+		dep := &v1apps.Deployment{}
+		if err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, dep); err != nil {
+			if client.IgnoreNotFound(err) != nil {
+				return false, err
+			}
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(dep.Spec.Selector)
+		if err != nil {
+			return false, err
+		}
+		pods := &v1core.PodList{}
+		if err := c.List(ctx, pods, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return false, err
+		}
+		if len(pods.Items) > 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,141 @@
+package controllers
+
+import (
+	"context"
+	v1apps "k8s.io/api/apps/v1"
+	v1batch "k8s.io/api/batch/v1"
+	v1core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// patroni labels of the pg ha members, must match the pg stateful set template
+const (
+	pgScopeLabel  = "cluster-name"
+	pgRoleLabel   = "spilo-role"
+	pgPrimaryRole = "master"
+)
+
+func checkJobReadiness(ctx context.Context, c client.Reader, name types.NamespacedName) (bool, error) {
+	job := &v1batch.Job{}
+
+	if err := c.Get(ctx, name, job); err != nil && errors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	if job.Status.Succeeded >= 1 {
+		return true, nil
+	}
+
+	return false, nil
+}
+
+func checkDeploymentReadiness(ctx context.Context, c client.Reader, name types.NamespacedName) (bool, error) {
+	deployment := &v1apps.Deployment{}
+
+	if err := c.Get(ctx, name, deployment); err != nil && errors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	if deployment.Status.Replicas == deployment.Status.ReadyReplicas {
+		return true, nil
+	}
+
+	return false, nil
+}
+
+func checkStatefulSetReadiness(ctx context.Context, c client.Reader, name types.NamespacedName) (bool, error) {
+	sts := &v1apps.StatefulSet{}
+
+	if err := c.Get(ctx, name, sts); err != nil && errors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	if sts.Status.Replicas == sts.Status.ReadyReplicas {
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// checkPgPrimaryReadiness checks the pg ha cluster has a single ready primary, patroni labels the primary pod
+// with the master role, during a failover there is no ready primary until a replica is promoted
+func checkPgPrimaryReadiness(ctx context.Context, c client.Reader, name types.NamespacedName) (bool, error) {
+	pods := &v1core.PodList{}
+
+	selector := client.MatchingLabels{"app": name.Name, pgRoleLabel: pgPrimaryRole}
+	if err := c.List(ctx, pods, client.InNamespace(name.Namespace), selector); err != nil {
+		return false, err
+	}
+
+	primaries := 0
+	for _, pod := range pods.Items {
+		if podReady(&pod) {
+			primaries++
+		}
+	}
+
+	return primaries == 1, nil
+}
+
+func podReady(pod *v1core.Pod) bool {
+	if pod.DeletionTimestamp != nil {
+		return false
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1core.PodReady {
+			return condition.Status == v1core.ConditionTrue
+		}
+	}
+	return false
+}
+
+// checkDaemonSetReadiness checks all the scheduled pods of the daemon set are ready and updated,
+// daemon set without matching nodes (e.g. device plugins on cluster without gpu nodes) is considered ready
+func checkDaemonSetReadiness(ctx context.Context, c client.Reader, name types.NamespacedName) (bool, error) {
+	ds := &v1apps.DaemonSet{}
+
+	if err := c.Get(ctx, name, ds); err != nil && errors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	if ds.Status.ObservedGeneration < ds.Generation {
+		return false, nil
+	}
+
+	if ds.Status.NumberReady == ds.Status.DesiredNumberScheduled &&
+		ds.Status.UpdatedNumberScheduled == ds.Status.DesiredNumberScheduled {
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// stackProgress returns whether all the components are ready, and the percentage of the ready components
+func stackProgress(readyState map[string]bool) (bool, int) {
+
+	percentageReady := 0
+
+	readyCount := 0
+
+	for _, ready := range readyState {
+		if ready {
+			readyCount++
+		}
+	}
+
+	if len(readyState) > 0 {
+		percentageReady = readyCount * 100 / len(readyState)
+	}
+
+	return readyCount == len(readyState), percentageReady
+}
@@ -0,0 +1,121 @@
+package controllers
+
+import (
+	"context"
+	mlopsv1 "github.com/AccessibleAI/cnvrg-operator/api/v1"
+	"github.com/AccessibleAI/cnvrg-operator/pkg/desired"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Renderer renders CnvrgApp/CnvrgInfra manifests without cluster access.
+// Objects the components are reading during the reconcile (creds secrets, configmaps)
+// are served from in memory client, which is populated by the rendered manifests.
+// Non template actions (grafana dashboards, backup annotations) are not rendered.
+// Secret key references are not resolved, the credentials they are referencing are rendered empty.
+// Generated credentials (random passwords and keys) are rendered as fixed placeholders,
+// so rendering the same spec twice gives the same manifests.
+type Renderer struct {
+	Scheme *runtime.Scheme
+	Log    logr.Logger
+	// Cri is used when the spec doesn't set it, instead of discovering it from the cluster nodes
+	Cri mlopsv1.CriType
+}
+
+// RenderCnvrgApp returns the manifests of the cnvrgApp,
+// when infra is nil, the default cnvrgInfra is used
+func (r *Renderer) RenderCnvrgApp(ctx context.Context, app *mlopsv1.CnvrgApp, infra *mlopsv1.CnvrgInfra) ([]*unstructured.Unstructured, error) {
+	if infra == nil {
+		infra = &mlopsv1.CnvrgInfra{ObjectMeta: v1.ObjectMeta{Name: "cnvrg-infra"}}
+	}
+	ctx = desired.WithPlaceholderCredentials(ctx)
+	c := fake.NewClientBuilder().WithScheme(r.Scheme).Build()
+
+	// infra manifests are rendered first, app components are reading infra objects (e.g. prometheus creds)
+	infraCtx := logf.IntoContext(ctx, r.Log.WithValues("name", infra.Name))
+	infra, err := r.defaultCnvrgInfra(infraCtx, infra, c)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.Create(ctx, infra); err != nil {
+		return nil, err
+	}
+	infraReconciler := &CnvrgInfraReconciler{Client: c, Scheme: r.Scheme, Log: r.Log}
+	if _, err := r.render(infraCtx, infraReconciler.infraComponents(infraCtx, infra), infra, c); err != nil {
+		return nil, err
+	}
+
+	ctx = logf.IntoContext(ctx, r.Log.WithValues("name", app.Name, "ns", app.Namespace))
+	app = app.DeepCopy()
+	if app.Spec.Cri == "" {
+		app.Spec.Cri = r.Cri
+	}
+	desiredSpec, err := desiredCnvrgAppSpec(ctx, app, infra, c)
+	if err != nil {
+		return nil, err
+	}
+	app.Spec = desiredSpec
+	pgUpgradeSpec(app)
+	appReconciler := &CnvrgAppReconciler{Client: c, Scheme: r.Scheme, Log: r.Log}
+	return r.render(ctx, appReconciler.appComponents(ctx, app), app, c)
+}
+
+// RenderCnvrgInfra returns the manifests of the cnvrgInfra
+func (r *Renderer) RenderCnvrgInfra(ctx context.Context, infra *mlopsv1.CnvrgInfra) ([]*unstructured.Unstructured, error) {
+	ctx = logf.IntoContext(desired.WithPlaceholderCredentials(ctx), r.Log.WithValues("name", infra.Name))
+	c := fake.NewClientBuilder().WithScheme(r.Scheme).Build()
+	infra, err := r.defaultCnvrgInfra(ctx, infra, c)
+	if err != nil {
+		return nil, err
+	}
+	infraReconciler := &CnvrgInfraReconciler{Client: c, Scheme: r.Scheme, Log: r.Log}
+	return r.render(ctx, infraReconciler.infraComponents(ctx, infra), infra, c)
+}
+
+func (r *Renderer) defaultCnvrgInfra(ctx context.Context, infra *mlopsv1.CnvrgInfra, c client.Client) (*mlopsv1.CnvrgInfra, error) {
+	infra = infra.DeepCopy()
+	if infra.Spec.Cri == "" {
+		infra.Spec.Cri = r.Cri
+	}
+	desiredSpec, err := desiredCnvrgInfraSpec(ctx, infra, c)
+	if err != nil {
+		return nil, err
+	}
+	infra.Spec = desiredSpec
+	return infra, nil
+}
+
+func (r *Renderer) render(ctx context.Context, components []component, owner v1.Object, c client.Client) ([]*unstructured.Unstructured, error) {
+	var objs []*unstructured.Unstructured
+	for _, comp := range components {
+		if comp.states == nil {
+			continue
+		}
+		states, err := comp.states()
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range states {
+			s.PlaceholderCredentials = true
+		}
+		if err := desired.Render(ctx, states, owner, logf.FromContext(ctx)); err != nil {
+			return nil, err
+		}
+		for _, s := range states {
+			objs = append(objs, s.Obj)
+			if s.GVK != desired.Kinds[desired.SecretGVK] && s.GVK != desired.Kinds[desired.ConfigMapGVK] {
+				continue
+			}
+			if err := c.Create(ctx, s.Obj.DeepCopy()); err != nil && !errors.IsAlreadyExists(err) {
+				return nil, err
+			}
+		}
+	}
+	return objs, nil
+}
@@ -0,0 +1,681 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	mlopsv1 "github.com/AccessibleAI/cnvrg-operator/api/v1"
+	"github.com/AccessibleAI/cnvrg-operator/pkg/desired"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1batch "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/yaml"
+	"strconv"
+)
+
+// +kubebuilder:docs-gen:collapse=Imports
+
+var _ = Describe("Renderer", func() {
+
+	findObj := func(objs []*unstructured.Unstructured, kind, name string) *unstructured.Unstructured {
+		for _, obj := range objs {
+			if obj.GetKind() == kind && obj.GetName() == name {
+				return obj
+			}
+		}
+		return nil
+	}
+
+	podSpecOverrides := func() mlopsv1.PodSpecOverrides {
+		fsGroup := int64(3000)
+		return mlopsv1.PodSpecOverrides{
+			Tolerations: []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "cnvrg", Effect: corev1.TaintEffectNoSchedule}},
+			Affinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+					MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"a"}}},
+				}}},
+			}},
+			TopologySpreadConstraints: []corev1.TopologySpreadConstraint{{
+				MaxSkew: 1, TopologyKey: "zone", WhenUnsatisfiable: corev1.ScheduleAnyway,
+				LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"foo": "bar"}},
+			}},
+			Env:             []corev1.EnvVar{{Name: "EXTRA_ENV", Value: "extra"}},
+			EnvFrom:         []corev1.EnvFromSource{{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "extra-cm"}}}},
+			Volumes:         []corev1.Volume{{Name: "extra-vol", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}},
+			VolumeMounts:    []corev1.VolumeMount{{Name: "extra-vol", MountPath: "/extra"}},
+			PodAnnotations:  map[string]string{"extra/annotation": "extra"},
+			SecurityContext: &corev1.PodSecurityContext{FSGroup: &fsGroup},
+			ImagePullPolicy: corev1.PullIfNotPresent,
+		}
+	}
+
+	// expectPodSpecOverrides checks the overrides are rendered in the pod template and its main container
+	expectPodSpecOverrides := func(obj *unstructured.Unstructured, container string) {
+		Expect(obj).ShouldNot(BeNil())
+		template, found, err := unstructured.NestedMap(obj.Object, "spec", "template")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(BeTrue(), obj.GetName())
+		pod := corev1.PodTemplateSpec{}
+		Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(template, &pod)).To(Succeed())
+		expected := podSpecOverrides()
+		Expect(pod.Annotations).Should(HaveKeyWithValue("extra/annotation", "extra"), obj.GetName())
+		Expect(pod.Spec.Tolerations).Should(Equal(expected.Tolerations), obj.GetName())
+		Expect(pod.Spec.Affinity).Should(Equal(expected.Affinity), obj.GetName())
+		Expect(pod.Spec.TopologySpreadConstraints).Should(Equal(expected.TopologySpreadConstraints), obj.GetName())
+		Expect(pod.Spec.SecurityContext).Should(Equal(expected.SecurityContext), obj.GetName())
+		Expect(pod.Spec.Volumes).Should(ContainElement(expected.Volumes[0]), obj.GetName())
+		var main *corev1.Container
+		for i := range pod.Spec.Containers {
+			if pod.Spec.Containers[i].Name == container {
+				main = &pod.Spec.Containers[i]
+			}
+		}
+		Expect(main).ShouldNot(BeNil(), obj.GetName())
+		Expect(main.Env).Should(ContainElement(expected.Env[0]), obj.GetName())
+		Expect(main.EnvFrom).Should(ContainElement(expected.EnvFrom[0]), obj.GetName())
+		Expect(main.VolumeMounts).Should(ContainElement(expected.VolumeMounts[0]), obj.GetName())
+		Expect(main.ImagePullPolicy).Should(Equal(expected.ImagePullPolicy), obj.GetName())
+	}
+
+	// expectPrometheusOverrides checks the overrides are rendered in the prometheus-operator Prometheus spec
+	expectPrometheusOverrides := func(obj *unstructured.Unstructured) {
+		Expect(obj).ShouldNot(BeNil())
+		expected := podSpecOverrides()
+		spec := struct {
+			PodMetadata               metav1.ObjectMeta                 `json:"podMetadata"`
+			Tolerations               []corev1.Toleration               `json:"tolerations"`
+			Affinity                  *corev1.Affinity                  `json:"affinity"`
+			TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints"`
+			SecurityContext           *corev1.PodSecurityContext        `json:"securityContext"`
+			Volumes                   []corev1.Volume                   `json:"volumes"`
+			VolumeMounts              []corev1.VolumeMount              `json:"volumeMounts"`
+			Containers                []corev1.Container                `json:"containers"`
+		}{}
+		Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object["spec"].(map[string]interface{}), &spec)).To(Succeed())
+		Expect(spec.PodMetadata.Annotations).Should(HaveKeyWithValue("extra/annotation", "extra"))
+		Expect(spec.Tolerations).Should(Equal(expected.Tolerations))
+		Expect(spec.Affinity).Should(Equal(expected.Affinity))
+		Expect(spec.TopologySpreadConstraints).Should(Equal(expected.TopologySpreadConstraints))
+		Expect(spec.SecurityContext).Should(Equal(expected.SecurityContext))
+		Expect(spec.Volumes).Should(ContainElement(expected.Volumes[0]))
+		Expect(spec.VolumeMounts).Should(ContainElement(expected.VolumeMounts[0]))
+		// env and pull policy are merged by the prometheus-operator into the prometheus container
+		Expect(spec.Containers).Should(ContainElement(corev1.Container{
+			Name:            "prometheus",
+			Env:             expected.Env,
+			EnvFrom:         expected.EnvFrom,
+			ImagePullPolicy: expected.ImagePullPolicy,
+		}))
+	}
+
+	Context("Test Render", func() {
+		It("CnvrgApp manifests are rendered without cluster", func() {
+			renderer := &Renderer{Scheme: scheme.Scheme, Log: ctrl.Log.WithName("render"), Cri: mlopsv1.CriTypeContainerd}
+			testApp := getDefaultTestAppSpec("render-ns")
+			testApp.Spec.Dbs.Pg.Enabled = true
+			testApp.Spec.Dbs.Es.Enabled = true
+			testApp.Spec.Logging.Kibana.Enabled = true
+
+			objs, err := renderer.RenderCnvrgApp(context.Background(), testApp, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(findObj(objs, "Deployment", testApp.Spec.Dbs.Pg.SvcName)).ShouldNot(BeNil())
+			Expect(findObj(objs, "Secret", testApp.Spec.Dbs.Pg.CredsRef)).ShouldNot(BeNil())
+			// kibana config is rendered with the es creds rendered in the dbs component
+			Expect(findObj(objs, "Secret", "kibana-config")).ShouldNot(BeNil())
+		})
+
+		It("Rendering the same spec twice gives the same manifests", func() {
+			render := func() string {
+				renderer := &Renderer{Scheme: scheme.Scheme, Log: ctrl.Log.WithName("render"), Cri: mlopsv1.CriTypeContainerd}
+				testApp := getDefaultTestAppSpec("render-ns")
+				testApp.Spec.ControlPlane.WebApp.Enabled = true
+				testApp.Spec.ControlPlane.Sidekiq.Enabled = true
+				testApp.Spec.Dbs.Pg.Enabled = true
+				testApp.Spec.Dbs.Redis.Enabled = true
+				testApp.Spec.Dbs.Es.Enabled = true
+				testApp.Spec.Dbs.Minio.Enabled = true
+				testApp.Spec.Logging.Kibana.Enabled = true
+				testApp.Spec.Logging.Elastalert.Enabled = true
+				testApp.Spec.Monitoring.Prometheus.Enabled = true
+				testApp.Spec.Monitoring.Grafana.Enabled = true
+				infra := &mlopsv1.CnvrgInfra{ObjectMeta: metav1.ObjectMeta{Name: "cnvrg-infra"}, Spec: mlopsv1.DefaultCnvrgInfraSpec()}
+				infra.Spec.Monitoring.Prometheus.Enabled = true
+
+				objs, err := renderer.RenderCnvrgApp(context.Background(), testApp, infra)
+				Expect(err).ToNot(HaveOccurred())
+				var out bytes.Buffer
+				for _, obj := range objs {
+					b, err := yaml.Marshal(obj.Object)
+					Expect(err).ToNot(HaveOccurred())
+					out.WriteString("---\n")
+					out.Write(b)
+				}
+				return out.String()
+			}
+			Expect(render()).Should(Equal(render()))
+		})
+
+		It("PG HA is rendered as a stateful set", func() {
+			renderer := &Renderer{Scheme: scheme.Scheme, Log: ctrl.Log.WithName("render"), Cri: mlopsv1.CriTypeContainerd}
+			testApp := getDefaultTestAppSpec("render-ns")
+			testApp.Spec.Dbs.Pg.Enabled = true
+			testApp.Spec.Dbs.Pg.HA.Enabled = true
+			testApp.Spec.Dbs.Pg.HA.Replicas = 2
+
+			objs, err := renderer.RenderCnvrgApp(context.Background(), testApp, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(findObj(objs, "Deployment", testApp.Spec.Dbs.Pg.SvcName)).Should(BeNil())
+			Expect(findObj(objs, "PersistentVolumeClaim", testApp.Spec.Dbs.Pg.PvcName)).Should(BeNil())
+			Expect(findObj(objs, "ConfigMap", testApp.Spec.Dbs.Pg.SvcName+"-post-init")).ShouldNot(BeNil())
+			Expect(findObj(objs, "Service", testApp.Spec.Dbs.Pg.SvcName+"-headless")).ShouldNot(BeNil())
+			sts := findObj(objs, "StatefulSet", testApp.Spec.Dbs.Pg.SvcName)
+			Expect(sts).ShouldNot(BeNil())
+			replicas, _, err := unstructured.NestedInt64(sts.Object, "spec", "replicas")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(replicas).Should(BeEquivalentTo(2))
+			selector, _, err := unstructured.NestedStringMap(findObj(objs, "Service", testApp.Spec.Dbs.Pg.SvcName).Object, "spec", "selector")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(selector).Should(HaveKeyWithValue(pgRoleLabel, pgPrimaryRole))
+		})
+
+		It("PG upgrade is rendered as a job and a new pvc, pg keeps serving the previous version", func() {
+			renderer := &Renderer{Scheme: scheme.Scheme, Log: ctrl.Log.WithName("render"), Cri: mlopsv1.CriTypeContainerd}
+			testApp := getDefaultTestAppSpec("render-ns")
+			testApp.Spec.Dbs.Pg.Enabled = true
+			testApp.Spec.Dbs.Pg.Image = "postgresql-13-centos7:latest"
+			testApp.Status.PgUpgrade = &mlopsv1.PgUpgradeStatus{
+				Phase:           mlopsv1.PgUpgradeRunning,
+				FromVersion:     12,
+				ToVersion:       13,
+				FromImage:       "docker.io/cnvrg/postgresql-12-centos7:latest",
+				ToImage:         "docker.io/cnvrg/postgresql-13-centos7:latest",
+				PvcName:         "pg-storage-13",
+				PreviousPvcName: "pg-storage",
+			}
+
+			objs, err := renderer.RenderCnvrgApp(context.Background(), testApp, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(findObj(objs, "PersistentVolumeClaim", "pg-storage")).ShouldNot(BeNil())
+			Expect(findObj(objs, "PersistentVolumeClaim", "pg-storage-13")).ShouldNot(BeNil())
+			job := findObj(objs, "Job", testApp.Spec.Dbs.Pg.SvcName+"-upgrade")
+			Expect(job).ShouldNot(BeNil())
+			containers, _, err := unstructured.NestedSlice(job.Object, "spec", "template", "spec", "containers")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(containers[0].(map[string]interface{})["image"]).Should(Equal("docker.io/cnvrg/postgresql-13-centos7:latest"))
+			dep := findObj(objs, "Deployment", testApp.Spec.Dbs.Pg.SvcName)
+			containers, _, err = unstructured.NestedSlice(dep.Object, "spec", "template", "spec", "containers")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(containers[0].(map[string]interface{})["image"]).Should(Equal("docker.io/cnvrg/postgresql-12-centos7:latest"))
+		})
+
+		It("PG image of a third version is queued until the upgrade is confirmed", func() {
+			renderer := &Renderer{Scheme: scheme.Scheme, Log: ctrl.Log.WithName("render"), Cri: mlopsv1.CriTypeContainerd}
+			testApp := getDefaultTestAppSpec("render-ns")
+			testApp.Spec.Dbs.Pg.Enabled = true
+			testApp.Spec.Dbs.Pg.Image = "postgresql-14-centos7:latest"
+			testApp.Status.PgUpgrade = &mlopsv1.PgUpgradeStatus{
+				Phase:           mlopsv1.PgUpgradeSucceeded,
+				FromVersion:     12,
+				ToVersion:       13,
+				FromImage:       "docker.io/cnvrg/postgresql-12-centos7:latest",
+				ToImage:         "docker.io/cnvrg/postgresql-13-centos7:latest",
+				PvcName:         "pg-storage-13",
+				PreviousPvcName: "pg-storage",
+				Message:         "pg is serving version 13",
+			}
+
+			objs, err := renderer.RenderCnvrgApp(context.Background(), testApp, nil)
+			Expect(err).ToNot(HaveOccurred())
+			dep := findObj(objs, "Deployment", testApp.Spec.Dbs.Pg.SvcName)
+			containers, _, err := unstructured.NestedSlice(dep.Object, "spec", "template", "spec", "containers")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(containers[0].(map[string]interface{})["image"]).Should(Equal("docker.io/cnvrg/postgresql-13-centos7:latest"))
+			volumes, _, err := unstructured.NestedSlice(dep.Object, "spec", "template", "spec", "volumes")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(volumes).Should(ContainElement(HaveKeyWithValue("persistentVolumeClaim", HaveKeyWithValue("claimName", "pg-storage-13"))))
+
+			upgrade := testApp.Status.PgUpgrade.DeepCopy()
+			queuePgUpgrade(upgrade, 14, true)
+			Expect(upgrade.Message).Should(Equal("pg is serving version 13, the upgrade to version 14 is queued until the upgrade to version 13 is confirmed or rolled back"))
+			queuePgUpgrade(upgrade, 13, true)
+			Expect(upgrade.Message).Should(Equal("pg is serving version 13"))
+		})
+
+		It("PG major version is parsed from the image", func() {
+			for image, version := range map[string]int{
+				"postgresql-12-centos7:latest":               12,
+				"docker.io/cnvrg/postgresql-13-centos7:v1.2": 13,
+				"centos/postgresql-96-centos7":               96,
+				"postgres:14.1":                              14,
+				"registry.local/postgres:11":                 11,
+			} {
+				parsed, ok := pgMajorVersion(image)
+				Expect(ok).Should(BeTrue(), image)
+				Expect(parsed).Should(Equal(version), image)
+			}
+			_, ok := pgMajorVersion("postgres:latest")
+			Expect(ok).Should(BeFalse())
+		})
+
+		It("ES snapshots are rendered as a cron job", func() {
+			renderer := &Renderer{Scheme: scheme.Scheme, Log: ctrl.Log.WithName("render"), Cri: mlopsv1.CriTypeContainerd}
+			testApp := getDefaultTestAppSpec("render-ns")
+			testApp.Spec.Dbs.Es.Enabled = true
+			testApp.Spec.Dbs.Es.Backup.Enabled = true
+			testApp.Spec.Dbs.Es.Backup.Period = "6h"
+
+			objs, err := renderer.RenderCnvrgApp(context.Background(), testApp, nil)
+			Expect(err).ToNot(HaveOccurred())
+			cronJob := findObj(objs, "CronJob", testApp.Spec.Dbs.Es.SvcName+"-backup")
+			Expect(cronJob).ShouldNot(BeNil())
+			schedule, _, err := unstructured.NestedString(cronJob.Object, "spec", "schedule")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(schedule).Should(Equal("0 */6 * * *"))
+			initContainers, _, err := unstructured.NestedSlice(findObj(objs, "StatefulSet", testApp.Spec.Dbs.Es.SvcName).Object,
+				"spec", "template", "spec", "initContainers")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(initContainers).Should(HaveLen(1))
+			Expect(initContainers[0].(map[string]interface{})["name"]).Should(Equal("s3-client"))
+			// the snapshots fail with the missing plugin, es starts without it
+			containers, _, err := unstructured.NestedSlice(cronJob.Object, "spec", "jobTemplate", "spec", "template", "spec", "containers")
+			Expect(err).ToNot(HaveOccurred())
+			command := fmt.Sprint(containers[0].(map[string]interface{})["command"])
+			Expect(command).Should(ContainSubstring("_cat/plugins"))
+			Expect(command).Should(ContainSubstring(`snapshot_repository "$REPOSITORY" "$CNVRG_STORAGE_BUCKET" "$BASE_PATH"`))
+
+			for period, schedule := range map[string]string{"30s": "* * * * *", "45m": "*/30 * * * *", "1h": "0 */1 * * *", "48h": "0 0 */2 * *"} {
+				Expect(desired.BackupSchedule(period)).Should(Equal(schedule), period)
+			}
+		})
+
+		It("Redis backups are rendered as a cron job", func() {
+			renderer := &Renderer{Scheme: scheme.Scheme, Log: ctrl.Log.WithName("render"), Cri: mlopsv1.CriTypeContainerd}
+			testApp := getDefaultTestAppSpec("render-ns")
+			testApp.Spec.Dbs.Redis.Enabled = true
+			testApp.Spec.Dbs.Redis.Backup.Enabled = true
+			testApp.Spec.Dbs.Redis.Backup.Period = "12h"
+
+			objs, err := renderer.RenderCnvrgApp(context.Background(), testApp, nil)
+			Expect(err).ToNot(HaveOccurred())
+			cronJob := findObj(objs, "CronJob", testApp.Spec.Dbs.Redis.SvcName+"-backup")
+			Expect(cronJob).ShouldNot(BeNil())
+			schedule, _, err := unstructured.NestedString(cronJob.Object, "spec", "schedule")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(schedule).Should(Equal("0 */12 * * *"))
+			podSpec, _, err := unstructured.NestedMap(cronJob.Object, "spec", "jobTemplate", "spec", "template", "spec")
+			Expect(err).ToNot(HaveOccurred())
+			pod := corev1.PodSpec{}
+			Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(podSpec, &pod)).To(Succeed())
+			Expect(pod.InitContainers).Should(HaveLen(1))
+			Expect(pod.InitContainers[0].Name).Should(Equal("dump-redis"))
+			Expect(pod.Containers[0].Env).Should(ContainElement(corev1.EnvVar{Name: "PREFIX", Value: "render-ns/redis"}))
+		})
+
+		It("CnvrgInfra manifests are rendered without cluster", func() {
+			renderer := &Renderer{Scheme: scheme.Scheme, Log: ctrl.Log.WithName("render"), Cri: mlopsv1.CriTypeContainerd}
+			infra := getDefaultTestInfraSpec("render-infra-ns")
+			infra.Spec.InfraNamespace = "render-infra-ns"
+			infra.Spec.Monitoring.Prometheus.Enabled = true
+
+			objs, err := renderer.RenderCnvrgInfra(context.Background(), infra)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(findObj(objs, "Secret", infra.Spec.Monitoring.Prometheus.CredsRef)).ShouldNot(BeNil())
+			Expect(findObj(objs, "PriorityClass", infra.Spec.CnvrgAppPriorityClass.Name)).ShouldNot(BeNil())
+		})
+
+		It("Pod spec overrides are rendered for every component", func() {
+			renderer := &Renderer{Scheme: scheme.Scheme, Log: ctrl.Log.WithName("render"), Cri: mlopsv1.CriTypeContainerd}
+			testApp := getDefaultTestAppSpec("render-ns")
+			testApp.Spec.Tenancy.Enabled = true
+			spec := &testApp.Spec
+			spec.ControlPlane.WebApp.Enabled = true
+			spec.ControlPlane.Sidekiq.Enabled = true
+			spec.ControlPlane.Hyper.Enabled = true
+			spec.Dbs.Pg.Enabled = true
+			spec.Dbs.Redis.Enabled = true
+			spec.Dbs.Es.Enabled = true
+			spec.Dbs.Minio.Enabled = true
+			spec.Dbs.Cvat.Enabled = true
+			spec.Logging.Kibana.Enabled = true
+			spec.Monitoring.Grafana.Enabled = true
+			spec.Monitoring.Prometheus.Enabled = true
+			for _, o := range []*mlopsv1.PodSpecOverrides{
+				&spec.ControlPlane.WebApp.PodSpecOverrides, &spec.ControlPlane.Sidekiq.PodSpecOverrides,
+				&spec.ControlPlane.Hyper.PodSpecOverrides, &spec.Dbs.Pg.PodSpecOverrides, &spec.Dbs.Redis.PodSpecOverrides,
+				&spec.Dbs.Es.PodSpecOverrides, &spec.Dbs.Minio.PodSpecOverrides, &spec.Dbs.Cvat.Pg.PodSpecOverrides,
+				&spec.Dbs.Cvat.Redis.PodSpecOverrides, &spec.Logging.Kibana.PodSpecOverrides,
+				&spec.Monitoring.Grafana.PodSpecOverrides, &spec.Monitoring.Prometheus.PodSpecOverrides,
+			} {
+				*o = podSpecOverrides()
+			}
+
+			// the ccp prometheus federates the infra prometheus
+			infra := getDefaultTestInfraSpec("render-infra-ns")
+			infra.Spec.Monitoring.Prometheus.Enabled = true
+
+			objs, err := renderer.RenderCnvrgApp(context.Background(), testApp, infra)
+			Expect(err).ToNot(HaveOccurred())
+			expectPodSpecOverrides(findObj(objs, "Deployment", spec.ControlPlane.WebApp.SvcName), "cnvrg-app")
+			expectPodSpecOverrides(findObj(objs, "Deployment", "sidekiq"), "sidekiq")
+			expectPodSpecOverrides(findObj(objs, "Deployment", spec.ControlPlane.Hyper.SvcName), spec.ControlPlane.Hyper.SvcName)
+			expectPodSpecOverrides(findObj(objs, "Deployment", spec.Dbs.Pg.SvcName), "postgresql")
+			expectPodSpecOverrides(findObj(objs, "Deployment", spec.Dbs.Redis.SvcName), "redis")
+			expectPodSpecOverrides(findObj(objs, "StatefulSet", spec.Dbs.Es.SvcName), "elastic")
+			expectPodSpecOverrides(findObj(objs, "Deployment", spec.Dbs.Minio.SvcName), "minio")
+			expectPodSpecOverrides(findObj(objs, "Deployment", spec.Dbs.Cvat.Pg.SvcName), "postgresql")
+			expectPodSpecOverrides(findObj(objs, "Deployment", spec.Dbs.Cvat.Redis.SvcName), "redis")
+			expectPodSpecOverrides(findObj(objs, "Deployment", spec.Logging.Kibana.SvcName), spec.Logging.Kibana.SvcName)
+			expectPodSpecOverrides(findObj(objs, "Deployment", spec.Monitoring.Grafana.SvcName), "grafana")
+			expectPrometheusOverrides(findObj(objs, "Prometheus", "cnvrg-ccp-prometheus"))
+
+			// the shared storage minio is rendered by its own template
+			spec.Dbs.Minio.SharedStorage.Enabled = true
+			objs, err = renderer.RenderCnvrgApp(context.Background(), testApp, infra)
+			Expect(err).ToNot(HaveOccurred())
+			expectPodSpecOverrides(findObj(objs, "Deployment", spec.Dbs.Minio.SvcName), "minio")
+		})
+
+		It("Pod spec overrides are rendered for every infra component", func() {
+			renderer := &Renderer{Scheme: scheme.Scheme, Log: ctrl.Log.WithName("render"), Cri: mlopsv1.CriTypeContainerd}
+			infra := getDefaultTestInfraSpec("render-infra-ns")
+			infra.Spec.InfraNamespace = "render-infra-ns"
+			infra.Spec.Tenancy.Enabled = true
+			spec := &infra.Spec
+			spec.Dbs.Redis.Enabled = true
+			spec.Logging.Fluentbit.Enabled = true
+			spec.Monitoring.Grafana.Enabled = true
+			spec.Monitoring.Prometheus.Enabled = true
+			for _, o := range []*mlopsv1.PodSpecOverrides{
+				&spec.Dbs.Redis.PodSpecOverrides, &spec.Logging.Fluentbit.PodSpecOverrides,
+				&spec.Monitoring.Grafana.PodSpecOverrides, &spec.Monitoring.Prometheus.PodSpecOverrides,
+			} {
+				*o = podSpecOverrides()
+			}
+
+			objs, err := renderer.RenderCnvrgInfra(context.Background(), infra)
+			Expect(err).ToNot(HaveOccurred())
+			expectPodSpecOverrides(findObj(objs, "Deployment", spec.Dbs.Redis.SvcName), "redis")
+			expectPodSpecOverrides(findObj(objs, "DaemonSet", "cnvrg-fluentbit"), "fluentbit")
+			expectPodSpecOverrides(findObj(objs, "Deployment", spec.Monitoring.Grafana.SvcName), "grafana")
+			expectPrometheusOverrides(findObj(objs, "Prometheus", "cnvrg-infra-prometheus"))
+		})
+	})
+
+	Context("Test Render Profiles", func() {
+
+		// profileApp returns a cnvrgApp which sets only the profile and the enabled components
+		profileApp := func(profile mlopsv1.Profile) *mlopsv1.CnvrgApp {
+			app := &mlopsv1.CnvrgApp{
+				ObjectMeta: metav1.ObjectMeta{Name: "cnvrgapp", Namespace: "profile-ns"},
+				Spec: mlopsv1.CnvrgAppSpec{
+					Profile:       profile,
+					Cri:           mlopsv1.CriTypeContainerd,
+					ClusterDomain: "test.local",
+				},
+			}
+			app.Spec.ControlPlane.WebApp.Enabled = true
+			app.Spec.ControlPlane.WebApp.Hpa.Enabled = true
+			app.Spec.ControlPlane.Sidekiq.Enabled = true
+			app.Spec.Dbs.Pg.Enabled = true
+			app.Spec.Dbs.Redis.Enabled = true
+			app.Spec.Dbs.Minio.Enabled = true
+			app.Spec.Dbs.Es.Enabled = true
+			return app
+		}
+
+		podSpec := func(obj *unstructured.Unstructured) corev1.PodSpec {
+			Expect(obj).ShouldNot(BeNil())
+			template, _, err := unstructured.NestedMap(obj.Object, "spec", "template")
+			Expect(err).ToNot(HaveOccurred())
+			pod := corev1.PodTemplateSpec{}
+			Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(template, &pod)).To(Succeed())
+			return pod.Spec
+		}
+
+		expectResources := func(container corev1.Container, requests mlopsv1.Requests, limits mlopsv1.Limits) {
+			Expect(container.Resources.Requests.Cpu().Cmp(resource.MustParse(requests.Cpu))).To(BeZero(), container.Name)
+			Expect(container.Resources.Requests.Memory().Cmp(resource.MustParse(requests.Memory))).To(BeZero(), container.Name)
+			Expect(container.Resources.Limits.Cpu().Cmp(resource.MustParse(limits.Cpu))).To(BeZero(), container.Name)
+			Expect(container.Resources.Limits.Memory().Cmp(resource.MustParse(limits.Memory))).To(BeZero(), container.Name)
+		}
+
+		storage := func(obj *unstructured.Unstructured, fields ...string) string {
+			Expect(obj).ShouldNot(BeNil())
+			size, found, err := unstructured.NestedString(obj.Object, fields...)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeTrue(), obj.GetName())
+			return size
+		}
+
+		It("Every profile is rendered with its sizing", func() {
+			renderer := &Renderer{Scheme: scheme.Scheme, Log: ctrl.Log.WithName("render"), Cri: mlopsv1.CriTypeContainerd}
+			for _, profile := range mlopsv1.Profiles {
+				expected, err := mlopsv1.DefaultCnvrgAppSpecForProfile(profile)
+				Expect(err).ToNot(HaveOccurred())
+				app := profileApp(profile)
+
+				objs, err := renderer.RenderCnvrgApp(context.Background(), app, nil)
+				Expect(err).ToNot(HaveOccurred(), string(profile))
+
+				webapp := findObj(objs, "Deployment", expected.ControlPlane.WebApp.SvcName)
+				expectResources(podSpec(webapp).Containers[0], expected.ControlPlane.WebApp.Requests, expected.ControlPlane.WebApp.Limits)
+				// the webapp replicas are managed by the hpa
+				hpa := findObj(objs, "HorizontalPodAutoscaler", expected.ControlPlane.WebApp.SvcName)
+				Expect(hpa).ShouldNot(BeNil())
+				replicas, _, _ := unstructured.NestedInt64(hpa.Object, "spec", "minReplicas")
+				Expect(replicas).To(BeEquivalentTo(expected.ControlPlane.WebApp.Replicas), string(profile))
+				maxReplicas, _, _ := unstructured.NestedInt64(hpa.Object, "spec", "maxReplicas")
+				Expect(maxReplicas).To(BeEquivalentTo(expected.ControlPlane.WebApp.Hpa.MaxReplicas), string(profile))
+				Expect(maxReplicas).To(BeNumerically(">=", replicas), string(profile))
+
+				pg := podSpec(findObj(objs, "Deployment", expected.Dbs.Pg.SvcName))
+				expectResources(pg.Containers[0], expected.Dbs.Pg.Requests, expected.Dbs.Pg.Limits)
+				Expect(storage(findObj(objs, "PersistentVolumeClaim", expected.Dbs.Pg.PvcName), "spec", "resources", "requests", "storage")).To(Equal(expected.Dbs.Pg.StorageSize))
+				pgCreds := findObj(objs, "Secret", expected.Dbs.Pg.CredsRef)
+				Expect(pgCreds).ShouldNot(BeNil())
+				secret := corev1.Secret{}
+				Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(pgCreds.Object, &secret)).To(Succeed())
+				Expect(string(secret.Data["POSTGRESQL_MAX_CONNECTIONS"])).To(Equal(strconv.Itoa(expected.Dbs.Pg.MaxConnections)))
+				Expect(string(secret.Data["POSTGRESQL_SHARED_BUFFERS"])).To(Equal(expected.Dbs.Pg.SharedBuffers))
+
+				es := findObj(objs, "StatefulSet", expected.Dbs.Es.SvcName)
+				esPod := podSpec(es)
+				expectResources(esPod.Containers[0], expected.Dbs.Es.Requests, expected.Dbs.Es.Limits)
+				Expect(esPod.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "ES_JAVA_OPTS", Value: expected.Dbs.Es.JavaOpts}))
+				claims, _, _ := unstructured.NestedSlice(es.Object, "spec", "volumeClaimTemplates")
+				Expect(storage(&unstructured.Unstructured{Object: claims[0].(map[string]interface{})}, "spec", "resources", "requests", "storage")).To(Equal(expected.Dbs.Es.StorageSize))
+
+				// every rendered container requests fit its limits
+				for _, obj := range objs {
+					if obj.GetKind() != "Deployment" && obj.GetKind() != "StatefulSet" {
+						continue
+					}
+					for _, c := range podSpec(obj).Containers {
+						for name, request := range c.Resources.Requests {
+							if limit, ok := c.Resources.Limits[name]; ok {
+								Expect(request.Cmp(limit)).To(BeNumerically("<=", 0), fmt.Sprintf("%s %s %s", profile, obj.GetName(), name))
+							}
+						}
+					}
+				}
+			}
+		})
+
+		It("Profiles grow from dev to large", func() {
+			var previous *mlopsv1.CnvrgAppSpec
+			for _, profile := range mlopsv1.Profiles {
+				spec, err := mlopsv1.DefaultCnvrgAppSpecForProfile(profile)
+				Expect(err).ToNot(HaveOccurred())
+				if previous != nil {
+					for _, q := range []struct{ current, previous string }{
+						{spec.ControlPlane.WebApp.Requests.Memory, previous.ControlPlane.WebApp.Requests.Memory},
+						{spec.ControlPlane.Sidekiq.Requests.Memory, previous.ControlPlane.Sidekiq.Requests.Memory},
+						{spec.Dbs.Pg.Requests.Memory, previous.Dbs.Pg.Requests.Memory},
+						{spec.Dbs.Pg.StorageSize, previous.Dbs.Pg.StorageSize},
+						{spec.Dbs.Es.Requests.Memory, previous.Dbs.Es.Requests.Memory},
+						{spec.Dbs.Es.StorageSize, previous.Dbs.Es.StorageSize},
+						{spec.Dbs.Minio.StorageSize, previous.Dbs.Minio.StorageSize},
+					} {
+						current := resource.MustParse(q.current)
+						Expect(current.Cmp(resource.MustParse(q.previous))).To(BeNumerically(">=", 0), string(profile))
+					}
+					Expect(spec.Dbs.Pg.MaxConnections).To(BeNumerically(">=", previous.Dbs.Pg.MaxConnections))
+				}
+				previous = &spec
+			}
+		})
+
+		It("Spec fields take precedence over the profile", func() {
+			renderer := &Renderer{Scheme: scheme.Scheme, Log: ctrl.Log.WithName("render"), Cri: mlopsv1.CriTypeContainerd}
+			app := profileApp(mlopsv1.LargeProfile)
+			app.Spec.ControlPlane.WebApp.Replicas = 7
+			app.Spec.Dbs.Pg.MaxConnections = 42
+			app.Spec.Dbs.Es.Requests.Memory = "6Gi"
+
+			objs, err := renderer.RenderCnvrgApp(context.Background(), app, nil)
+			Expect(err).ToNot(HaveOccurred())
+			large, _ := mlopsv1.DefaultCnvrgAppSpecForProfile(mlopsv1.LargeProfile)
+			replicas, _, _ := unstructured.NestedInt64(findObj(objs, "HorizontalPodAutoscaler", large.ControlPlane.WebApp.SvcName).Object, "spec", "minReplicas")
+			Expect(replicas).To(BeEquivalentTo(7))
+			expectResources(podSpec(findObj(objs, "Deployment", large.ControlPlane.WebApp.SvcName)).Containers[0], large.ControlPlane.WebApp.Requests, large.ControlPlane.WebApp.Limits)
+			secret := corev1.Secret{}
+			Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(findObj(objs, "Secret", large.Dbs.Pg.CredsRef).Object, &secret)).To(Succeed())
+			Expect(string(secret.Data["POSTGRESQL_MAX_CONNECTIONS"])).To(Equal("42"))
+			// es heap follows the es requests set in the spec
+			es := podSpec(findObj(objs, "StatefulSet", large.Dbs.Es.SvcName))
+			Expect(es.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "ES_JAVA_OPTS", Value: "-Xms3g -Xmx3g"}))
+		})
+
+		It("ES heap follows the default requests without a profile", func() {
+			renderer := &Renderer{Scheme: scheme.Scheme, Log: ctrl.Log.WithName("render"), Cri: mlopsv1.CriTypeContainerd}
+			app := profileApp("")
+
+			objs, err := renderer.RenderCnvrgApp(context.Background(), app, nil)
+			Expect(err).ToNot(HaveOccurred())
+			defaults := mlopsv1.DefaultCnvrgAppSpec()
+			es := podSpec(findObj(objs, "StatefulSet", defaults.Dbs.Es.SvcName))
+			// the default 4Gi request
+			Expect(es.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "ES_JAVA_OPTS", Value: "-Xms2g -Xmx2g"}))
+		})
+
+		It("Unknown profile is not rendered", func() {
+			renderer := &Renderer{Scheme: scheme.Scheme, Log: ctrl.Log.WithName("render"), Cri: mlopsv1.CriTypeContainerd}
+			_, err := renderer.RenderCnvrgApp(context.Background(), profileApp("huge"), nil)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("Test Render External Databases", func() {
+		externalApp := func() *mlopsv1.CnvrgApp {
+			testApp := getDefaultTestAppSpec("render-ns")
+			testApp.Spec.Dbs.Pg.Enabled = false
+			testApp.Spec.Dbs.Pg.External = mlopsv1.ExternalDb{Enabled: true, Host: "pg.rds.local", Port: 5432, TLS: true, SecretRef: "rds-creds",
+				Username: "cnvrg", Password: "p@ss/word"}
+			testApp.Spec.Dbs.Redis.Enabled = false
+			testApp.Spec.Dbs.Redis.External = mlopsv1.ExternalDb{Enabled: true, Host: "redis.local", Port: 6380, TLS: true, SecretRef: "redis-ext",
+				Password: "redis-pass"}
+			return testApp
+		}
+
+		It("External databases creds secrets and preflight job are rendered", func() {
+			renderer := &Renderer{Scheme: scheme.Scheme, Log: ctrl.Log.WithName("render"), Cri: mlopsv1.CriTypeContainerd}
+			testApp := externalApp()
+
+			objs, err := renderer.RenderCnvrgApp(context.Background(), testApp, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(findObj(objs, "Deployment", testApp.Spec.Dbs.Pg.SvcName)).Should(BeNil())
+
+			pgCreds := corev1.Secret{}
+			Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(findObj(objs, "Secret", testApp.Spec.Dbs.Pg.CredsRef).Object, &pgCreds)).To(Succeed())
+			Expect(string(pgCreds.Data["POSTGRES_HOST"])).Should(Equal("pg.rds.local"))
+			Expect(string(pgCreds.Data["POSTGRES_PORT"])).Should(Equal("5432"))
+			Expect(string(pgCreds.Data["POSTGRES_USER"])).Should(Equal("cnvrg"))
+			Expect(string(pgCreds.Data["POSTGRES_DB"])).Should(Equal(defaultExternalPgDatabase))
+			Expect(string(pgCreds.Data["PGSSLMODE"])).Should(Equal("require"))
+
+			redisCreds := corev1.Secret{}
+			Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(findObj(objs, "Secret", testApp.Spec.Dbs.Redis.CredsRef).Object, &redisCreds)).To(Succeed())
+			Expect(string(redisCreds.Data["REDIS_URL"])).Should(Equal("rediss://:redis-pass@redis.local:6380"))
+
+			job := v1batch.Job{}
+			Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(findObj(objs, "Job", preflightJobName(testApp)).Object, &job)).To(Succeed())
+			Expect(job.Labels).Should(HaveKeyWithValue(preflightLabel, preflightHash(testApp)))
+			var containers []string
+			for _, c := range job.Spec.Template.Spec.Containers {
+				containers = append(containers, c.Name)
+			}
+			Expect(containers).Should(Equal([]string{"pg", "redis"}))
+			// the preflight reads the credentials from the external secrets
+			for _, env := range job.Spec.Template.Spec.Containers[0].Env {
+				if env.ValueFrom != nil {
+					Expect(env.ValueFrom.SecretKeyRef.Name).Should(Equal("rds-creds"))
+				}
+			}
+		})
+
+		It("Kibana and Elastalert use the external ES", func() {
+			renderer := &Renderer{Scheme: scheme.Scheme, Log: ctrl.Log.WithName("render"), Cri: mlopsv1.CriTypeContainerd}
+			testApp := externalApp()
+			testApp.Spec.Dbs.Es.Enabled = false
+			testApp.Spec.Dbs.Es.External = mlopsv1.ExternalDb{Enabled: true, Host: "es.local", Port: 9243, TLS: true, SecretRef: "es-ext",
+				Username: "elastic", Password: "es-pass"}
+			testApp.Spec.Logging.Elastalert.Enabled = true
+			Expect(esHostUrl(testApp)).Should(Equal("https://es.local:9243"))
+
+			objs, err := renderer.RenderCnvrgApp(context.Background(), testApp, nil)
+			Expect(err).ToNot(HaveOccurred())
+			config, _, err := unstructured.NestedString(findObj(objs, "ConfigMap", "elastalert-config").Object, "data", "config.yaml")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(config).Should(ContainSubstring("es_host: es.local"))
+			Expect(config).Should(ContainSubstring("es_port: 9243"))
+			Expect(config).Should(ContainSubstring("use_ssl: True"))
+		})
+
+		It("Preflight job is named after the external databases settings", func() {
+			testApp := externalApp()
+			name := preflightJobName(testApp)
+			Expect(preflightJobName(externalApp())).Should(Equal(name))
+			testApp.Spec.Dbs.Pg.External.Password = "rotated"
+			Expect(preflightJobName(testApp)).ShouldNot(Equal(name))
+			Expect(appPreflightState(getDefaultTestAppSpec("render-ns"))).Should(BeEmpty())
+		})
+
+		It("External databases credentials are secret references", func() {
+			testApp := externalApp()
+			var keys []string
+			for _, ref := range externalDbSecretRefs(testApp) {
+				keys = append(keys, fmt.Sprintf("%s/%s/%t", ref.ref.Name, ref.ref.Key, *ref.ref.Optional))
+			}
+			Expect(keys).Should(Equal([]string{
+				"rds-creds/username/false", "rds-creds/password/false", "rds-creds/database/true",
+				"redis-ext/username/true", "redis-ext/password/false",
+			}))
+			Expect(referencesSecret(appSecretRefs(testApp), "redis-ext")).Should(BeTrue())
+		})
+
+		It("Preflight condition reports the failed checks", func() {
+			job := &v1batch.Job{}
+			running := preflightCondition("preflight", job, []string{"pg"}, nil)
+			Expect(running.Reason).Should(Equal(mlopsv1.ReasonPreflightRunning))
+			Expect(running.Status).Should(Equal(metav1.ConditionFalse))
+
+			job.Status.Conditions = []v1batch.JobCondition{{Type: v1batch.JobFailed, Status: corev1.ConditionTrue}}
+			failed := preflightCondition("preflight", job, []string{"pg"}, []string{"pg: can't connect to pg.rds.local:5432"})
+			Expect(failed.Reason).Should(Equal(mlopsv1.ReasonPreflightFailed))
+			Expect(failed.Message).Should(ContainSubstring("pg: can't connect to pg.rds.local:5432"))
+
+			job.Status.Conditions = nil
+			job.Status.Succeeded = 1
+			succeeded := preflightCondition("preflight", job, []string{"pg", "redis"}, nil)
+			Expect(succeeded.Status).Should(Equal(metav1.ConditionTrue))
+			Expect(succeeded.Message).Should(Equal("external pg, redis reachable"))
+		})
+	})
+})
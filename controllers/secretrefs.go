@@ -0,0 +1,172 @@
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	mlopsv1 "github.com/AccessibleAI/cnvrg-operator/api/v1"
+	v1core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"strings"
+)
+
+// secretRef is a credential of the spec which can be given by a secret key reference
+type secretRef struct {
+	path  string
+	ref   *v1core.SecretKeySelector
+	value *string
+}
+
+func appSecretRefs(app *mlopsv1.CnvrgApp) []secretRef {
+	cp := &app.Spec.ControlPlane
+	refs := []secretRef{
+		{"spec.controlPlane.hyper.tokenSecretRef", cp.Hyper.TokenSecretRef, &cp.Hyper.Token},
+		{"spec.controlPlane.ldap.adminPasswordSecretRef", cp.Ldap.AdminPasswordSecretRef, &cp.Ldap.AdminPassword},
+		{"spec.controlPlane.smtp.passwordSecretRef", cp.SMTP.PasswordSecretRef, &cp.SMTP.Password},
+		{"spec.controlPlane.objectStorage.accessKeySecretRef", cp.ObjectStorage.AccessKeySecretRef, &cp.ObjectStorage.AccessKey},
+		{"spec.controlPlane.objectStorage.secretKeySecretRef", cp.ObjectStorage.SecretKeySecretRef, &cp.ObjectStorage.SecretKey},
+		{"spec.controlPlane.mpi.registry.passwordSecretRef", cp.Mpi.Registry.PasswordSecretRef, &cp.Mpi.Registry.Password},
+		{"spec.registry.passwordSecretRef", app.Spec.Registry.PasswordSecretRef, &app.Spec.Registry.Password},
+		{"spec.sso.clientSecretRef", app.Spec.SSO.ClientSecretRef, &app.Spec.SSO.ClientSecret},
+		{"spec.sso.cookieSecretRef", app.Spec.SSO.CookieSecretRef, &app.Spec.SSO.CookieSecret},
+	}
+	return append(refs, externalDbSecretRefs(app)...)
+}
+
+func infraSecretRefs(infra *mlopsv1.CnvrgInfra) []secretRef {
+	return []secretRef{
+		{"spec.registry.passwordSecretRef", infra.Spec.Registry.PasswordSecretRef, &infra.Spec.Registry.Password},
+		{"spec.sso.clientSecretRef", infra.Spec.SSO.ClientSecretRef, &infra.Spec.SSO.ClientSecret},
+		{"spec.sso.cookieSecretRef", infra.Spec.SSO.CookieSecretRef, &infra.Spec.SSO.CookieSecret},
+	}
+}
+
+// infraSecretsNamespace is the namespace of the secrets referenced by the cluster scoped cnvrgInfra
+func infraSecretsNamespace(infra *mlopsv1.CnvrgInfra) string {
+	if infra.Spec.InfraNamespace != "" {
+		return infra.Spec.InfraNamespace
+	}
+	return mlopsv1.DefaultCnvrgInfraSpec().InfraNamespace
+}
+
+// resolveSecretRefs sets the credentials given by secret references to the referenced secret values.
+// The references are resolved on the effective spec only, the values are never written to the CR.
+// Returns the references which can't be resolved (missing secret or key), optional references are skipped
+func resolveSecretRefs(ctx context.Context, c client.Client, namespace string, refs []secretRef) (missing []string, err error) {
+	for _, r := range refs {
+		if r.ref == nil {
+			continue
+		}
+		optional := r.ref.Optional != nil && *r.ref.Optional
+		secret := &v1core.Secret{}
+		err := c.Get(ctx, types.NamespacedName{Name: r.ref.Name, Namespace: namespace}, secret)
+		if errors.IsNotFound(err) {
+			if !optional {
+				missing = append(missing, fmt.Sprintf("%s: secret %s/%s not found", r.path, namespace, r.ref.Name))
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		value, ok := secret.Data[r.ref.Key]
+		if !ok {
+			if !optional {
+				missing = append(missing, fmt.Sprintf("%s: key %s not found in secret %s/%s", r.path, r.ref.Key, namespace, r.ref.Name))
+			}
+			continue
+		}
+		*r.value = string(value)
+	}
+	return missing, nil
+}
+
+// secretRefsChecksum is the checksum of the values resolved from the references, empty when no reference is set.
+// The values are not in the rendered pod templates, the checksum annotates them so changes of the secrets are rolled out
+func secretRefsChecksum(refs []secretRef) string {
+	h := sha256.New()
+	resolved := false
+	for _, r := range refs {
+		if r.ref == nil {
+			continue
+		}
+		resolved = true
+		fmt.Fprintf(h, "%s=%s\n", r.path, *r.value)
+	}
+	if !resolved {
+		return ""
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// referencesSecret returns true if any of the references points to the secret
+func referencesSecret(refs []secretRef, name string) bool {
+	for _, r := range refs {
+		if r.ref != nil && r.ref.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// secretRefsCondition reports the resolution of the secret references
+func secretRefsCondition(missing []string) metav1.Condition {
+	if len(missing) > 0 {
+		return metav1.Condition{
+			Type:    mlopsv1.ConditionSecretRefsResolved,
+			Status:  metav1.ConditionFalse,
+			Reason:  mlopsv1.ReasonSecretRefMissing,
+			Message: strings.Join(missing, "; "),
+		}
+	}
+	return metav1.Condition{
+		Type:    mlopsv1.ConditionSecretRefsResolved,
+		Status:  metav1.ConditionTrue,
+		Reason:  mlopsv1.ReasonSecretRefsResolved,
+		Message: "all the secret references are resolved",
+	}
+}
+
+// secretRefRequests maps a secret to the cnvrgApps referencing it, so changes of the secret are rolled out,
+// the map func lists with the manager context, it is not cancelled with a single reconcile
+func (r *CnvrgAppReconciler) secretRefRequests(ctx context.Context) handler.MapFunc {
+	return func(secret client.Object) []reconcile.Request {
+		apps := mlopsv1.CnvrgAppList{}
+		if err := r.List(ctx, &apps, client.InNamespace(secret.GetNamespace())); err != nil {
+			r.Log.Error(err, "can't list cnvrgapps for secret", "secret", secret.GetName())
+			return nil
+		}
+		var requests []reconcile.Request
+		for i := range apps.Items {
+			if referencesSecret(appSecretRefs(&apps.Items[i]), secret.GetName()) {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: apps.Items[i].Name, Namespace: apps.Items[i].Namespace}})
+			}
+		}
+		return requests
+	}
+}
+
+// secretRefRequests maps a secret to the cnvrgInfras referencing it
+func (r *CnvrgInfraReconciler) secretRefRequests(ctx context.Context) handler.MapFunc {
+	return func(secret client.Object) []reconcile.Request {
+		infras := mlopsv1.CnvrgInfraList{}
+		if err := r.List(ctx, &infras); err != nil {
+			r.Log.Error(err, "can't list cnvrginfras for secret", "secret", secret.GetName())
+			return nil
+		}
+		var requests []reconcile.Request
+		for i := range infras.Items {
+			infra := &infras.Items[i]
+			if infraSecretsNamespace(infra) == secret.GetNamespace() && referencesSecret(infraSecretRefs(infra), secret.GetName()) {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: infra.Name}})
+			}
+		}
+		return requests
+	}
+}
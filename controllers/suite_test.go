@@ -17,19 +17,31 @@ limitations under the License.
 package controllers
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
 	mlopsv1 "github.com/AccessibleAI/cnvrg-operator/api/v1"
+	mlopsv1beta2 "github.com/AccessibleAI/cnvrg-operator/api/v1beta2"
+	"github.com/AccessibleAI/cnvrg-operator/pkg/desired"
 	. "github.com/onsi/ginkgo"
 	"github.com/onsi/ginkgo/reporters"
 	. "github.com/onsi/gomega"
 	"github.com/spf13/viper"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
+	"net"
 	"path/filepath"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"strconv"
 	"strings"
 	"testing"
 	// +kubebuilder:scaffold:imports
@@ -58,12 +70,16 @@ var _ = BeforeSuite(func(done Done) {
 			filepath.Join("..", "config", "crd", "bases"),
 			filepath.Join("..", "pkg", "networking", "tmpl", "istio", "crds"),
 			filepath.Join("..", "pkg", "monitoring", "tmpl", "crds")},
+		WebhookInstallOptions: envtest.WebhookInstallOptions{
+			Paths: []string{filepath.Join("..", "config", "webhook")},
+		},
 		UseExistingCluster: &useExistingCluster,
 	}
 
 	viper.AutomaticEnv()
 	viper.SetEnvPrefix("CNVRG_OPERATOR")
 	viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
+	viper.SetDefault("prune", true)
 
 	var err error
 	cfg, err = testEnv.Start()
@@ -73,7 +89,7 @@ var _ = BeforeSuite(func(done Done) {
 	err = mlopsv1.AddToScheme(scheme.Scheme)
 	Expect(err).NotTo(HaveOccurred())
 
-	err = mlopsv1.AddToScheme(scheme.Scheme)
+	err = mlopsv1beta2.AddToScheme(scheme.Scheme)
 	Expect(err).NotTo(HaveOccurred())
 
 	// +kubebuilder:scaffold:scheme
@@ -82,27 +98,58 @@ var _ = BeforeSuite(func(done Done) {
 	Expect(err).ToNot(HaveOccurred())
 	Expect(k8sClient).ToNot(BeNil())
 
+	Expect(convertTestCrds(k8sClient)).To(Succeed())
+
+	dc, err := discovery.NewDiscoveryClientForConfig(cfg)
+	Expect(err).ToNot(HaveOccurred())
+	err = desired.Discover(dc, []desired.GVKName{desired.PodDisruptionBudgetGVK, desired.HpaGVK}, ctrl.Log.WithName("discovery"))
+	Expect(err).ToNot(HaveOccurred())
+
 	k8sManager, err := ctrl.NewManager(cfg, ctrl.Options{
-		Scheme: scheme.Scheme,
+		Scheme:  scheme.Scheme,
+		Host:    testEnv.WebhookInstallOptions.LocalServingHost,
+		Port:    testEnv.WebhookInstallOptions.LocalServingPort,
+		CertDir: testEnv.WebhookInstallOptions.LocalServingCertDir,
 	})
 	Expect(err).ToNot(HaveOccurred())
+	ctx := ctrl.SetupSignalHandler()
+
+	err = (&mlopsv1.CnvrgApp{}).SetupWebhookWithManager(k8sManager)
+	Expect(err).ToNot(HaveOccurred())
+
+	err = (&mlopsv1.CnvrgInfra{}).SetupWebhookWithManager(k8sManager)
+	Expect(err).ToNot(HaveOccurred())
 
 	err = (&CnvrgAppReconciler{
 		Client: k8sManager.GetClient(),
 		Scheme: k8sManager.GetScheme(),
 		Log:    ctrl.Log.WithName("controllers").WithName("CnvrgApp"),
-	}).SetupWithManager(k8sManager)
+	}).SetupWithManager(ctx, k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
 	err = (&CnvrgInfraReconciler{
 		Client: k8sManager.GetClient(),
 		Scheme: k8sManager.GetScheme(),
 		Log:    ctrl.Log.WithName("controllers").WithName("CnvrgInfra"),
+	}).SetupWithManager(ctx, k8sManager)
+	Expect(err).ToNot(HaveOccurred())
+
+	err = (&CnvrgBackupReconciler{
+		Client: k8sManager.GetClient(),
+		Scheme: k8sManager.GetScheme(),
+		Log:    ctrl.Log.WithName("controllers").WithName("CnvrgBackup"),
+	}).SetupWithManager(k8sManager)
+	Expect(err).ToNot(HaveOccurred())
+
+	err = (&CnvrgRestoreReconciler{
+		Client: k8sManager.GetClient(),
+		Scheme: k8sManager.GetScheme(),
+		Log:    ctrl.Log.WithName("controllers").WithName("CnvrgRestore"),
 	}).SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
 	go func() {
-		err = k8sManager.Start(ctrl.SetupSignalHandler())
+		err = k8sManager.Start(ctx)
 		Expect(err).ToNot(HaveOccurred())
 	}()
 
@@ -113,6 +160,37 @@ var _ = BeforeSuite(func(done Done) {
 	close(done)
 }, 60)
 
+// convertTestCrds points the conversion webhook of the cnvrg CRDs to the webhook server of the test manager,
+// envtest configures only the admission webhooks
+func convertTestCrds(c client.Client) error {
+	opts := testEnv.WebhookInstallOptions
+	url := fmt.Sprintf("https://%s/convert", net.JoinHostPort(opts.LocalServingHost, strconv.Itoa(opts.LocalServingPort)))
+	for _, name := range cnvrgCrds {
+		crd := &unstructured.Unstructured{}
+		crd.SetGroupVersionKind(schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"})
+		crd.SetName(name)
+		patch := map[string]interface{}{"spec": map[string]interface{}{"conversion": map[string]interface{}{
+			"strategy": "Webhook",
+			"webhook": map[string]interface{}{
+				"conversionReviewVersions": []string{"v1beta1"},
+				"clientConfig": map[string]interface{}{
+					"url":      url,
+					"service":  nil,
+					"caBundle": base64.StdEncoding.EncodeToString(opts.LocalServingCAData),
+				},
+			},
+		}}}
+		b, err := json.Marshal(patch)
+		if err != nil {
+			return err
+		}
+		if err := c.Patch(context.Background(), crd, client.RawPatch(types.MergePatchType, b)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 var _ = AfterSuite(func() {
 	By("tearing down the test environment")
 	err := testEnv.Stop()
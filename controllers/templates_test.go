@@ -0,0 +1,29 @@
+package controllers
+
+import (
+	"context"
+	"github.com/AccessibleAI/cnvrg-operator/pkg/dbs"
+	"github.com/AccessibleAI/cnvrg-operator/pkg/desired"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// +kubebuilder:docs-gen:collapse=Imports
+
+var _ = Describe("Templates", func() {
+
+	Context("Test Templates Registry", func() {
+		It("All the templates are parsed", func() {
+			Expect(desired.LoadTemplates("/pkg")).To(Succeed())
+		})
+
+		It("Missing template data keys fail the rendering", func() {
+			state := dbs.PgCreds(dbs.PgCredsData{})[0]
+			state.TemplateData = desired.TemplateData{Data: map[string]interface{}{"Namespace": "cnvrg"}}
+			err := desired.Render(context.Background(), []*desired.State{state}, getDefaultTestAppSpec("cnvrg"), ctrl.Log.WithName("templates"))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("map has no entry for key"))
+		})
+	})
+})
@@ -60,9 +60,9 @@ func generateSecureToken(length int) string {
 	return hex.EncodeToString(b)
 }
 
-func DiscoverCri(clientset client.Client) (mlopsv1.CriType, error) {
+func DiscoverCri(ctx context.Context, clientset client.Client) (mlopsv1.CriType, error) {
 	nodeList := &v1.NodeList{}
-	if err := clientset.List(context.Background(), nodeList, client.Limit(1)); err != nil {
+	if err := clientset.List(ctx, nodeList, client.Limit(1)); err != nil {
 		return "", err
 	}
 	if len(nodeList.Items) == 0 {
@@ -81,9 +81,24 @@ func DiscoverCri(clientset client.Client) (mlopsv1.CriType, error) {
 	}
 }
 
-func calculateAndApplyAppDefaults(app *mlopsv1.CnvrgApp, desiredAppSpec *mlopsv1.CnvrgAppSpec, infra *mlopsv1.CnvrgInfra, clientset client.Client) error {
+// applyEsHeap sets the ES heap to half of the effective memory request, when the java opts are not set by the user.
+// Must be called on the merged spec, the request is set by the defaults or the profile unless set by the user
+func applyEsHeap(app *mlopsv1.CnvrgApp, desiredAppSpec *mlopsv1.CnvrgAppSpec) {
+	if app.Spec.Dbs.Es.JavaOpts != "" || !strings.HasSuffix(desiredAppSpec.Dbs.Es.Requests.Memory, "Gi") {
+		return
+	}
+	mem, err := strconv.Atoi(strings.TrimSuffix(desiredAppSpec.Dbs.Es.Requests.Memory, "Gi"))
+	if err != nil {
+		return
+	}
+	if heapMem := mem / 2; heapMem > 0 {
+		desiredAppSpec.Dbs.Es.JavaOpts = fmt.Sprintf("-Xms%dg -Xmx%dg", heapMem, heapMem)
+	}
+}
+
+func calculateAndApplyAppDefaults(ctx context.Context, app *mlopsv1.CnvrgApp, desiredAppSpec *mlopsv1.CnvrgAppSpec, infra *mlopsv1.CnvrgInfra, clientset client.Client) error {
 	if app.Spec.Cri == "" {
-		cri, err := DiscoverCri(clientset)
+		cri, err := DiscoverCri(ctx, clientset)
 		if err != nil {
 			return err
 		}
@@ -95,18 +110,6 @@ func calculateAndApplyAppDefaults(app *mlopsv1.CnvrgApp, desiredAppSpec *mlopsv1
 		desiredAppSpec.Dbs.Cvat.Redis.Enabled = true
 	}
 
-	// set default heap size for ES if not set by user
-	if strings.Contains(app.Spec.Dbs.Es.Requests.Memory, "Gi") && app.Spec.Dbs.Es.JavaOpts == "" {
-		requestMem := strings.TrimSuffix(app.Spec.Dbs.Es.Requests.Memory, "Gi")
-		mem, err := strconv.Atoi(requestMem)
-		if err == nil {
-			heapMem := mem / 2
-			if heapMem > 0 {
-				desiredAppSpec.Dbs.Es.JavaOpts = fmt.Sprintf("-Xms%dg -Xmx%dg", heapMem, heapMem)
-			}
-		}
-	}
-
 	if app.Spec.Networking.Ingress.IstioGwName == "" {
 		desiredAppSpec.Networking.Ingress.IstioGwName = fmt.Sprintf(mlopsv1.IstioGwName, app.Namespace)
 	}
@@ -139,9 +142,9 @@ func calculateAndApplyAppDefaults(app *mlopsv1.CnvrgApp, desiredAppSpec *mlopsv1
 	return nil
 }
 
-func calculateAndApplyInfraDefaults(infra *mlopsv1.CnvrgInfra, desiredInfraSpec *mlopsv1.CnvrgInfraSpec, clientset client.Client) error {
+func calculateAndApplyInfraDefaults(ctx context.Context, infra *mlopsv1.CnvrgInfra, desiredInfraSpec *mlopsv1.CnvrgInfraSpec, clientset client.Client) error {
 	if infra.Spec.Cri == "" {
-		cri, err := DiscoverCri(clientset)
+		cri, err := DiscoverCri(ctx, clientset)
 		if err != nil {
 			return err
 		}
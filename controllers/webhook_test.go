@@ -0,0 +1,223 @@
+package controllers
+
+import (
+	"context"
+	mlopsv1 "github.com/AccessibleAI/cnvrg-operator/api/v1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"time"
+)
+
+// +kubebuilder:docs-gen:collapse=Imports
+
+var _ = Describe("Validating Webhook", func() {
+
+	const (
+		timeout  = time.Second * 60
+		interval = time.Millisecond * 250
+	)
+
+	expectInvalid := func(err error, fieldPath string) {
+		Expect(err).To(HaveOccurred())
+		Expect(errors.IsInvalid(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring(fieldPath))
+	}
+
+	Context("Test CnvrgApp Validation", func() {
+		It("Invalid storage size is rejected", func() {
+			ns := createNs()
+			testApp := getDefaultTestAppSpec(ns)
+			testApp.Spec.Dbs.Pg.StorageSize = "80GB"
+			expectInvalid(k8sClient.Create(context.Background(), testApp), "spec.dbs.pg.storageSize")
+		})
+
+		It("Requests larger than limits are rejected", func() {
+			ns := createNs()
+			testApp := getDefaultTestAppSpec(ns)
+			testApp.Spec.ControlPlane.WebApp.Requests.Memory = "8Gi"
+			testApp.Spec.ControlPlane.WebApp.Limits.Memory = "4Gi"
+			expectInvalid(k8sClient.Create(context.Background(), testApp), "spec.controlPlane.webapp.requests.memory")
+		})
+
+		It("Limits lower than the default requests are rejected", func() {
+			ns := createNs()
+			testApp := getDefaultTestAppSpec(ns)
+			testApp.Spec.ControlPlane.WebApp.Requests = mlopsv1.Requests{}
+			testApp.Spec.ControlPlane.WebApp.Limits.Memory = "1Gi"
+			expectInvalid(k8sClient.Create(context.Background(), testApp), "spec.controlPlane.webapp.limits.memory")
+		})
+
+		It("Limits are validated against the requests of the profile", func() {
+			ns := createNs()
+			ctx := context.Background()
+			testApp := getDefaultTestAppSpec(ns)
+			testApp.Spec.Profile = mlopsv1.DevProfile
+			testApp.Spec.ControlPlane.WebApp.Requests = mlopsv1.Requests{}
+			testApp.Spec.ControlPlane.WebApp.Limits.Memory = "2Gi"
+			Expect(k8sClient.Create(ctx, testApp)).Should(Succeed())
+			Expect(k8sClient.Delete(ctx, testApp)).Should(Succeed())
+		})
+
+		It("HTTPS without certificate is rejected", func() {
+			ns := createNs()
+			testApp := getDefaultTestAppSpec(ns)
+			testApp.Spec.Networking.HTTPS.Enabled = true
+			testApp.Spec.Networking.HTTPS.Cert = "cert"
+			expectInvalid(k8sClient.Create(context.Background(), testApp), "spec.networking.https.key")
+		})
+
+		It("HTTPS with certificate secret is valid", func() {
+			ns := createNs()
+			ctx := context.Background()
+			testApp := getDefaultTestAppSpec(ns)
+			testApp.Spec.Networking.HTTPS.Enabled = true
+			testApp.Spec.Networking.HTTPS.CertSecret = "tls-secret"
+			Expect(k8sClient.Create(ctx, testApp)).Should(Succeed())
+			Expect(k8sClient.Delete(ctx, testApp)).Should(Succeed())
+		})
+
+		It("SSO without client id is rejected", func() {
+			ns := createNs()
+			testApp := getDefaultTestAppSpec(ns)
+			testApp.Spec.SSO.Enabled = true
+			testApp.Spec.SSO.Provider = "oidc"
+			expectInvalid(k8sClient.Create(context.Background(), testApp), "spec.sso.clientId")
+		})
+
+		It("Colliding node ports are rejected", func() {
+			ns := createNs()
+			testApp := getDefaultTestAppSpec(ns)
+			testApp.Spec.Networking.Ingress.Type = mlopsv1.NodePortIngress
+			testApp.Spec.ControlPlane.WebApp.Enabled = true
+			testApp.Spec.Logging.Kibana.Enabled = true
+			testApp.Spec.Logging.Kibana.NodePort = testApp.Spec.ControlPlane.WebApp.NodePort
+			err := k8sClient.Create(context.Background(), testApp)
+			expectInvalid(err, "spec.logging.kibana.nodePort")
+			Expect(err.Error()).To(ContainSubstring("collides with spec.controlPlane.webapp.nodePort"))
+		})
+
+		It("Invalid backup period is rejected", func() {
+			ns := createNs()
+			testApp := getDefaultTestAppSpec(ns)
+			testApp.Spec.Dbs.Pg.Backup.Period = "1d"
+			expectInvalid(k8sClient.Create(context.Background(), testApp), "spec.dbs.pg.backup.period")
+		})
+
+		It("GCP object storage without secret is rejected", func() {
+			ns := createNs()
+			testApp := getDefaultTestAppSpec(ns)
+			testApp.Spec.ControlPlane.ObjectStorage.Type = mlopsv1.GcpObjectStorageType
+			testApp.Spec.ControlPlane.ObjectStorage.GcpSecretRef = ""
+			expectInvalid(k8sClient.Create(context.Background(), testApp), "spec.controlPlane.objectStorage.gcpSecretRef")
+		})
+
+		It("Secret reference with plaintext value is rejected", func() {
+			ns := createNs()
+			testApp := getDefaultTestAppSpec(ns)
+			testApp.Spec.ControlPlane.SMTP.Password = "smtp-pass"
+			testApp.Spec.ControlPlane.SMTP.PasswordSecretRef = &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "smtp"},
+				Key:                  "password",
+			}
+			expectInvalid(k8sClient.Create(context.Background(), testApp), "spec.controlPlane.smtp.passwordSecretRef")
+		})
+
+		It("Pod spec overrides volume mount without mount path is rejected", func() {
+			ns := createNs()
+			testApp := getDefaultTestAppSpec(ns)
+			testApp.Spec.Dbs.Pg.PodSpecOverrides.VolumeMounts = []corev1.VolumeMount{{Name: "extra"}}
+			expectInvalid(k8sClient.Create(context.Background(), testApp), "spec.dbs.pg.podSpecOverrides.volumeMounts[0].mountPath")
+		})
+
+		It("PG HA with a single replica is rejected", func() {
+			ns := createNs()
+			testApp := getDefaultTestAppSpec(ns)
+			testApp.Spec.Dbs.Pg.HA.Enabled = true
+			testApp.Spec.Dbs.Pg.HA.Replicas = 1
+			expectInvalid(k8sClient.Create(context.Background(), testApp), "spec.dbs.pg.ha.replicas")
+		})
+
+		It("CVAT PG upgrade confirmation is rejected", func() {
+			ns := createNs()
+			testApp := getDefaultTestAppSpec(ns)
+			testApp.Spec.Dbs.Cvat.Pg.Upgrade.ConfirmedVersion = 13
+			expectInvalid(k8sClient.Create(context.Background(), testApp), "spec.dbs.cvat.pg.upgrade.confirmedVersion")
+		})
+
+		It("External PG together with the in-cluster PG is rejected", func() {
+			ns := createNs()
+			testApp := getDefaultTestAppSpec(ns)
+			testApp.Spec.Dbs.Pg.Enabled = true
+			testApp.Spec.Dbs.Pg.External = mlopsv1.ExternalDb{Enabled: true, Host: "pg.rds.local", Port: 5432, SecretRef: "rds-creds"}
+			expectInvalid(k8sClient.Create(context.Background(), testApp), "spec.dbs.pg.external.enabled")
+		})
+
+		It("Invalid override patch is rejected", func() {
+			ns := createNs()
+			testApp := getDefaultTestAppSpec(ns)
+			testApp.Spec.Overrides = []mlopsv1.Override{
+				{Kind: "Deployment", Name: "sidekiq", Patch: "spec:\n  replicas: 2"},
+				{Kind: "Service", Name: "app", Type: mlopsv1.JSONPatchType, Patch: `{"op": "add"}`},
+			}
+			expectInvalid(k8sClient.Create(context.Background(), testApp), "spec.overrides[1].patch")
+		})
+
+		It("Invalid update is rejected", func() {
+			ns := createNs()
+			ctx := context.Background()
+			testApp := getDefaultTestAppSpec(ns)
+			Expect(k8sClient.Create(ctx, testApp)).Should(Succeed())
+
+			app := mlopsv1.CnvrgApp{}
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: testApp.Name, Namespace: ns}, &app); err != nil {
+					return err
+				}
+				app.Spec.Dbs.Redis.StorageSize = "not-a-size"
+				return k8sClient.Update(ctx, &app)
+			}, timeout, interval).Should(MatchError(ContainSubstring("spec.dbs.redis.storageSize")))
+			Expect(k8sClient.Delete(ctx, testApp)).Should(Succeed())
+		})
+
+		It("Toggling PG HA is rejected", func() {
+			ns := createNs()
+			ctx := context.Background()
+			testApp := getDefaultTestAppSpec(ns)
+			testApp.Spec.Dbs.Pg.Enabled = true
+			Expect(k8sClient.Create(ctx, testApp)).Should(Succeed())
+
+			app := mlopsv1.CnvrgApp{}
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: testApp.Name, Namespace: ns}, &app); err != nil {
+					return err
+				}
+				app.Spec.Dbs.Pg.HA.Enabled = true
+				app.Spec.Dbs.Pg.HA.Replicas = 3
+				return k8sClient.Update(ctx, &app)
+			}, timeout, interval).Should(MatchError(ContainSubstring("spec.dbs.pg.ha.enabled")))
+			Expect(k8sClient.Delete(ctx, testApp)).Should(Succeed())
+		})
+	})
+
+	Context("Test CnvrgInfra Validation", func() {
+		It("Invalid infra spec is rejected", func() {
+			ns := createNs()
+			testInfra := getDefaultTestInfraSpec(ns)
+			testInfra.Spec.Capsule.StorageSize = "100 Gi"
+			testInfra.Spec.Dbs.Redis.Backup.Period = "daily"
+			err := k8sClient.Create(context.Background(), testInfra)
+			expectInvalid(err, "spec.capsule.storageSize")
+			Expect(err.Error()).To(ContainSubstring("spec.dbs.redis.backup.period"))
+		})
+
+		It("Invalid infra override patch is rejected", func() {
+			ns := createNs()
+			testInfra := getDefaultTestInfraSpec(ns)
+			testInfra.Spec.Overrides = []mlopsv1.Override{{Kind: "DaemonSet", Name: "fluent-bit", Patch: "- not: an object"}}
+			expectInvalid(k8sClient.Create(context.Background(), testInfra), "spec.overrides[0].patch")
+		})
+	})
+})
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/AccessibleAI/cnvrg-operator/controllers"
+	"github.com/AccessibleAI/cnvrg-operator/pkg/desired"
+	"github.com/go-logr/zapr"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"io"
+	"os"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var diffParams = []param{
+	{name: "show-unchanged", shorthand: "", value: false, usage: "list objects without changes"},
+}
+
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Show the changes between live objects and CnvrgApp/CnvrgInfra manifests",
+	PreRun: func(cmd *cobra.Command, args []string) {
+		bindParams(specParams, cmd)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := diff(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	},
+}
+
+func diff() error {
+	if viper.GetString("file") == "" {
+		return fmt.Errorf("--file is required")
+	}
+	apps, infra, err := readSpecs(viper.GetString("file"))
+	if err != nil {
+		return err
+	}
+
+	cfg := ctrl.GetConfigOrDie()
+	if err := discoverKinds(cfg); err != nil {
+		return err
+	}
+	c, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		return err
+	}
+	differ := &controllers.Differ{
+		Client: c,
+		Scheme: scheme,
+		Log:    zapr.NewLogger(initZapLog()),
+	}
+
+	ctx := context.Background()
+	var diffs []desired.ObjectDiff
+	if infra != nil {
+		infraDiffs, err := differ.DiffCnvrgInfra(ctx, infra)
+		if err != nil {
+			return err
+		}
+		diffs = append(diffs, infraDiffs...)
+	}
+	for _, app := range apps {
+		appDiffs, err := differ.DiffCnvrgApp(ctx, app, infra)
+		if err != nil {
+			return err
+		}
+		diffs = append(diffs, appDiffs...)
+	}
+
+	return printDiffs(diffs, os.Stdout, viper.GetBool("show-unchanged"))
+}
+
+func printDiffs(diffs []desired.ObjectDiff, w io.Writer, showUnchanged bool) error {
+	marks := map[desired.DiffAction]string{
+		desired.DiffCreate: "+ create",
+		desired.DiffUpdate: "~ update",
+		desired.DiffSkip:   "= skip (not updatable)",
+		desired.DiffNone:   "  unchanged",
+	}
+	count := map[desired.DiffAction]int{}
+	for _, d := range diffs {
+		count[d.Action]++
+		if d.Action == desired.DiffNone && !showUnchanged {
+			continue
+		}
+		name := d.Name
+		if d.Namespace != "" {
+			name = d.Namespace + "/" + d.Name
+		}
+		if _, err := fmt.Fprintf(w, "%s %s %s %s\n", marks[d.Action], d.GVK.GroupVersion().String(), d.GVK.Kind, name); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprint(w, d.Diff); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "create: %d, update: %d, skip: %d, unchanged: %d\n",
+		count[desired.DiffCreate], count[desired.DiffUpdate], count[desired.DiffSkip], count[desired.DiffNone])
+	return err
+}
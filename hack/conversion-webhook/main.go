@@ -0,0 +1,65 @@
+// conversion-webhook configures the conversion webhook of the operator on CRD manifests.
+// controller-gen doesn't generate the conversion of multi version CRDs, the service is the default
+// webhook service of the operator, the operator sets the service and the caBundle when it deploys the CRDs.
+//
+// usage: go run ./hack/conversion-webhook -service webhook-service -namespace cnvrg-infra config/crd/bases/mlops.cnvrg.io_cnvrgapps.yaml
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sigs.k8s.io/yaml"
+)
+
+func main() {
+	service := flag.String("service", "webhook-service", "name of the webhook service")
+	namespace := flag.String("namespace", "cnvrg-infra", "namespace of the webhook service")
+	flag.Parse()
+	if flag.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: conversion-webhook [-service <name>] [-namespace <namespace>] <crd.yaml>...")
+		os.Exit(1)
+	}
+	for _, file := range flag.Args() {
+		if err := conversionWebhook(file, *service, *namespace); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", file, err)
+			os.Exit(1)
+		}
+	}
+}
+
+func conversionWebhook(file, service, namespace string) error {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	crd := map[string]interface{}{}
+	if err := yaml.Unmarshal(b, &crd); err != nil {
+		return err
+	}
+	spec, ok := crd["spec"].(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("missing crd spec")
+	}
+	spec["conversion"] = map[string]interface{}{
+		"strategy": "Webhook",
+		"webhook": map[string]interface{}{
+			"conversionReviewVersions": []string{"v1beta1"},
+			"clientConfig": map[string]interface{}{
+				"service": map[string]interface{}{
+					"name":      service,
+					"namespace": namespace,
+					"path":      "/convert",
+				},
+			},
+		},
+	}
+	out, err := yaml.Marshal(crd)
+	if err != nil {
+		return err
+	}
+	// keep the document separator written by controller-gen
+	return ioutil.WriteFile(file, append([]byte("\n---\n"), bytes.TrimPrefix(out, []byte("\n---\n"))...), 0644)
+}
@@ -0,0 +1,130 @@
+// preserve-unknown-fields replaces the generated schema of the properties of the given field in CRD manifests
+// with schemas which preserve unknown fields, the properties listed in -keep retain the generated schema.
+// Used for fields embedding large core types (e.g. volumes, affinity), which are repeated
+// by each component and would exceed the CRD size limits, the fields are validated by the operator.
+//
+// usage: go run ./hack/preserve-unknown-fields -field podSpecOverrides -keep env,tolerations config/crd/bases/*.yaml
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sigs.k8s.io/yaml"
+	"strings"
+)
+
+func main() {
+	field := flag.String("field", "", "name of the field to replace")
+	keep := flag.String("keep", "", "comma separated properties of the field which keep the generated schema")
+	flag.Parse()
+	if *field == "" || flag.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: preserve-unknown-fields -field <name> [-keep <property>,...] <crd.yaml>...")
+		os.Exit(1)
+	}
+	kept := map[string]bool{}
+	for _, p := range strings.Split(*keep, ",") {
+		if p != "" {
+			kept[p] = true
+		}
+	}
+	for _, file := range flag.Args() {
+		if err := preserveUnknownFields(file, *field, kept); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", file, err)
+			os.Exit(1)
+		}
+	}
+}
+
+func preserveUnknownFields(file, field string, kept map[string]bool) error {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	crd := map[string]interface{}{}
+	if err := yaml.Unmarshal(b, &crd); err != nil {
+		return err
+	}
+	replaceField(crd, field, kept)
+	out, err := yaml.Marshal(crd)
+	if err != nil {
+		return err
+	}
+	// keep the document separator written by controller-gen
+	return ioutil.WriteFile(file, append([]byte("\n---\n"), bytes.TrimPrefix(out, []byte("\n---\n"))...), 0644)
+}
+
+// replaceField replaces the properties of the field in the properties of every nested schema
+func replaceField(node interface{}, field string, kept map[string]bool) {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if properties, ok := n["properties"].(map[string]interface{}); ok {
+			if schema, ok := properties[field].(map[string]interface{}); ok {
+				replaceProperties(schema, kept)
+			}
+		}
+		for _, v := range n {
+			replaceField(v, field, kept)
+		}
+	case []interface{}:
+		for _, v := range n {
+			replaceField(v, field, kept)
+		}
+	}
+}
+
+// replaceProperties replaces the schema of the properties which are not kept,
+// objects and the items of arrays preserve unknown fields.
+// The nested descriptions of the kept properties are dropped, the core types docs are repeated by each component
+func replaceProperties(schema map[string]interface{}, kept map[string]bool) {
+	properties, ok := schema["properties"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	for name, p := range properties {
+		property, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if kept[name] {
+			for k, v := range property {
+				if k != "description" {
+					dropDescriptions(v)
+				}
+			}
+			continue
+		}
+		if property["type"] != "object" && property["type"] != "array" {
+			continue
+		}
+		replaced := map[string]interface{}{"type": property["type"]}
+		preserved := map[string]interface{}{"type": "object", "x-kubernetes-preserve-unknown-fields": true}
+		if property["type"] == "array" {
+			replaced["items"] = preserved
+		} else {
+			replaced = preserved
+		}
+		if description, ok := property["description"]; ok {
+			replaced["description"] = description
+		}
+		properties[name] = replaced
+	}
+}
+
+func dropDescriptions(node interface{}) {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if _, ok := n["description"].(string); ok {
+			delete(n, "description")
+		}
+		for _, v := range n {
+			dropDescriptions(v)
+		}
+	case []interface{}:
+		for _, v := range n {
+			dropDescriptions(v)
+		}
+	}
+}
@@ -9,14 +9,21 @@ import (
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/discovery"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
 	"os"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
 	"strings"
 
 	mlopsv1 "github.com/AccessibleAI/cnvrg-operator/api/v1"
+	mlopsv1beta2 "github.com/AccessibleAI/cnvrg-operator/api/v1beta2"
 	"github.com/AccessibleAI/cnvrg-operator/controllers"
+	"github.com/AccessibleAI/cnvrg-operator/pkg/desired"
 )
 
 type param struct {
@@ -48,12 +55,20 @@ var (
 		{name: "own-prometheus-resources", shorthand: "", value: true, usage: "Watch for Prometheus resources"},
 		{name: "max-concurrent-reconciles", shorthand: "", value: 1, usage: "Max concurrent reconciles"},
 		{name: "cleanup-pvc", shorthand: "", value: false, usage: "set to true to delete PVCs on CR delete"},
+		{name: "prune", shorthand: "", value: true, usage: "set to false to keep resources of disabled components"},
+		{name: "prune-pvc", shorthand: "", value: false, usage: "set to true to delete PVCs of disabled components"},
+		{name: "dump-effective-spec", shorthand: "", value: false, usage: "Write the effective (defaulted) spec of each CnvrgApp/CnvrgInfra to <name>-effective-spec configmap, for debugging"},
+		{name: "enable-webhooks", shorthand: "", value: false, usage: "Enable the admission webhooks, the conversion webhook is always served"},
+		{name: "webhook-cert-dir", shorthand: "", value: "/tmp/k8s-webhook-server/serving-certs", usage: "Directory of the webhook server certificate (tls.crt, tls.key and ca.crt), a self signed certificate is generated when missing"},
+		{name: "webhook-service", shorthand: "", value: "webhook-service", usage: "Name of the webhook service, referenced by the CRDs conversion webhook"},
+		{name: "webhook-service-namespace", shorthand: "", value: "cnvrg-infra", usage: "Namespace of the webhook service"},
 	}
 )
 
 func init() {
 	_ = clientgoscheme.AddToScheme(scheme)
 	_ = mlopsv1.AddToScheme(scheme)
+	_ = mlopsv1beta2.AddToScheme(scheme)
 	// +kubebuilder:scaffold:scheme
 }
 
@@ -110,6 +125,16 @@ func setParams(params []param, command *cobra.Command) {
 	}
 }
 
+// bindParams binds params which are defined on more than one command,
+// viper key can be bound only to a single flag, hence it is bound to the flag of the executed command
+func bindParams(params []param, command *cobra.Command) {
+	for _, param := range params {
+		if err := viper.BindPFlag(param.name, command.PersistentFlags().Lookup(param.name)); err != nil {
+			panic(err)
+		}
+	}
+}
+
 func runOperator() {
 	ctrl.SetLogger(zapr.NewLogger(initZapLog()))
 
@@ -118,6 +143,7 @@ func runOperator() {
 		MetricsBindAddress:     viper.GetString("metrics-addr"),
 		HealthProbeBindAddress: viper.GetString("health-probe-addr"),
 		Port:                   9443,
+		CertDir:                viper.GetString("webhook-cert-dir"),
 		LeaderElection:         viper.GetBool("enable-leader-election"),
 		LeaderElectionID:       "99748453.cnvrg.io",
 	})
@@ -126,11 +152,40 @@ func runOperator() {
 		os.Exit(1)
 	}
 
+	ctx := ctrl.SetupSignalHandler()
+
+	if err := controllers.EnsureWebhookCert(); err != nil {
+		setupLog.Error(err, "unable to set up the webhook server certificate")
+		os.Exit(1)
+	}
+	// v1beta2 is the storage version, the cnvrg CRs are read in v1 through the conversion webhook
+	mgr.GetWebhookServer().Register("/convert", &conversion.Webhook{})
+
+	if viper.GetBool("deploy-depended-crds") {
+		// the depended CRDs are deployed before discovery, so the kinds they serve are discovered and watched
+		c, err := client.New(mgr.GetConfig(), client.Options{Scheme: mgr.GetScheme()})
+		if err != nil {
+			setupLog.Error(err, "unable to create client")
+			os.Exit(1)
+		}
+		if err := controllers.DeployDependedCrds(logf.IntoContext(ctx, ctrl.Log.WithName("crds")), c, mgr.GetScheme()); err != nil {
+			setupLog.Error(err, "unable to deploy depended crds")
+			os.Exit(1)
+		}
+	} else {
+		zap.S().Info("deploy-depended-crds is false, I hope CRDs was deployed ahead and match expected versions, if not I will fail...")
+	}
+
+	if err := discoverKinds(mgr.GetConfig()); err != nil {
+		setupLog.Error(err, "unable to discover cluster APIs")
+		os.Exit(1)
+	}
+
 	if err = (&controllers.CnvrgAppReconciler{
 		Client: mgr.GetClient(),
 		Log:    ctrl.Log.WithName("controllers").WithName("CnvrgApp"),
 		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr); err != nil {
+	}).SetupWithManager(ctx, mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "CnvrgApp")
 		os.Exit(1)
 	}
@@ -139,10 +194,39 @@ func runOperator() {
 		Client: mgr.GetClient(),
 		Log:    ctrl.Log.WithName("controllers").WithName("CnvrgInfra"),
 		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr); err != nil {
+	}).SetupWithManager(ctx, mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "CnvrgInfra")
 		os.Exit(1)
 	}
+
+	if err = (&controllers.CnvrgBackupReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("CnvrgBackup"),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "CnvrgBackup")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.CnvrgRestoreReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("CnvrgRestore"),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "CnvrgRestore")
+		os.Exit(1)
+	}
+
+	if viper.GetBool("enable-webhooks") {
+		if err = (&mlopsv1.CnvrgApp{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "CnvrgApp")
+			os.Exit(1)
+		}
+		if err = (&mlopsv1.CnvrgInfra{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "CnvrgInfra")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -156,7 +240,7 @@ func runOperator() {
 
 	zap.S().Infof("cnvrg operator version: %s", BuildVersion)
 	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	if err := mgr.Start(ctx); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
@@ -169,10 +253,32 @@ func setupCommands() {
 	// Setup commands
 	setParams(runOperatorParams, runOperatorCmd)
 	setParams(rootParams, rootCmd)
+	setParams(append(specParams, renderParams...), renderCmd)
+	setParams(append(specParams, diffParams...), diffCmd)
 	rootCmd.AddCommand(operatorVersion)
 	rootCmd.AddCommand(runOperatorCmd)
+	rootCmd.AddCommand(renderCmd)
+	rootCmd.AddCommand(diffCmd)
+}
+
+// templateDirs are the embedded templates, parsed once at startup
+var templateDirs = []string{
+	"/pkg/controlplane/tmpl",
+	"/pkg/dbs/tmpl",
+	"/pkg/logging/tmpl",
+	"/pkg/monitoring/tmpl",
+	"/pkg/networking/tmpl",
+	"/pkg/registry/tmpl",
+	"/pkg/storage/tmpl",
+	"/pkg/gpu/tmpl",
+	"/pkg/reloader/tmpl",
+	"/pkg/capsule/tmpl",
+	"/pkg/priorityclass/tmpl",
+	"/pkg/ingresscheck/tmpl",
+	"/pkg/backup/tmpl",
 }
 
+// informPkger includes the templateDirs in pkged.go, pkger requires the dirs as literals
 func informPkger() {
 	pkger.Include("/pkg/controlplane/tmpl")
 	pkger.Include("/pkg/dbs/tmpl")
@@ -186,13 +292,44 @@ func informPkger() {
 	pkger.Include("/pkg/capsule/tmpl")
 	pkger.Include("/pkg/priorityclass/tmpl")
 	pkger.Include("/pkg/ingresscheck/tmpl")
+	pkger.Include("/pkg/backup/tmpl")
 }
 
 func main() {
 	informPkger()
+	if err := desired.LoadTemplates(templateDirs...); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
 	setupCommands()
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
 }
+
+// discoverKinds sets the versions of the kinds the operator renders and watches to the versions served by the cluster
+func discoverKinds(cfg *rest.Config) error {
+	dc, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return err
+	}
+	return desired.Discover(dc, requiredKinds(), ctrl.Log.WithName("discovery"))
+}
+
+// requiredKinds are the kinds which are watched by the controllers,
+// kinds of the CRDs deployed by the operator itself are served once they are deployed and are not required
+func requiredKinds() []desired.GVKName {
+	required := []desired.GVKName{desired.PodDisruptionBudgetGVK, desired.HpaGVK}
+	deployed := viper.GetBool("deploy-depended-crds")
+	if viper.GetBool("own-istio-resources") && !deployed {
+		required = append(required, desired.IstioVsGVK, desired.IstioDestinationRuleGVK, desired.IstioGwGVK, desired.IstioGVK)
+	}
+	if viper.GetBool("own-prometheus-resources") && !deployed {
+		required = append(required, desired.PrometheusGVK, desired.ServiceMonitorGVK, desired.PrometheusRuleGVK)
+	}
+	if viper.GetBool("own-openshift-resources") {
+		required = append(required, desired.OcpRouteGVK)
+	}
+	return required
+}
@@ -0,0 +1,43 @@
+package backup
+
+import (
+	"github.com/AccessibleAI/cnvrg-operator/pkg/desired"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const path = "/pkg/backup/tmpl"
+
+// BackupState returns the backup job, or the cron job of a scheduled backup
+func BackupState(data Data) []*desired.State {
+	gvk, updatable := desired.Kinds[desired.JobGVK], false
+	if data.Schedule != "" {
+		gvk, updatable = desired.Kinds[desired.CronJobGVK], true
+	}
+	return []*desired.State{
+		{
+			TemplatePath:   path + "/job.tpl",
+			Template:       nil,
+			ParsedTemplate: "",
+			Obj:            &unstructured.Unstructured{},
+			GVK:            gvk,
+			TemplateData:   desired.TemplateData{Namespace: data.Namespace, Data: data},
+			Own:            true,
+			Updatable:      updatable,
+		},
+	}
+}
+
+func RestoreState(data RestoreData) []*desired.State {
+	return []*desired.State{
+		{
+			TemplatePath:   path + "/restore-job.tpl",
+			Template:       nil,
+			ParsedTemplate: "",
+			Obj:            &unstructured.Unstructured{},
+			GVK:            desired.Kinds[desired.JobGVK],
+			TemplateData:   desired.TemplateData{Namespace: data.Namespace, Data: data},
+			Own:            true,
+			Updatable:      false,
+		},
+	}
+}
@@ -0,0 +1,76 @@
+package backup
+
+import (
+	mlopsv1 "github.com/AccessibleAI/cnvrg-operator/api/v1"
+	"github.com/AccessibleAI/cnvrg-operator/pkg/desired"
+)
+
+// PgDump is a pg database which is dumped with pg_dump, or restored with pg_restore from Location
+type PgDump struct {
+	Database mlopsv1.BackupDatabase
+	Image    string
+	CredsRef string
+	Host     string
+	Port     int
+	Location string
+}
+
+// RedisDump is the rdb snapshot of redis, the restore writes it into the data pvc of the quiesced redis
+type RedisDump struct {
+	Image    string
+	CredsRef string
+	Host     string
+	Port     int
+	PvcName  string
+	Location string
+}
+
+// EsSnapshot is an es snapshot in the s3 repository of the backup
+type EsSnapshot struct {
+	Image      string
+	CredsRef   string
+	Url        string
+	Repository string
+	BasePath   string
+	Location   string
+}
+
+// Data is the input of the backup job template, a cron job when Schedule is set
+type Data struct {
+	Namespace    string
+	Annotations  map[string]string
+	Labels       map[string]string
+	Name         string
+	JobName      string
+	Schedule     string
+	Rotation     int
+	BucketRef    string
+	Image        string
+	NodeSelector map[string]string
+	Tenancy      bool
+	Prefix       string
+	Pg           []PgDump
+	Redis        *RedisDump
+	Es           *EsSnapshot
+}
+
+// RestoreData is the input of the restore job template
+type RestoreData struct {
+	Namespace    string
+	Annotations  map[string]string
+	Labels       map[string]string
+	Name         string
+	JobName      string
+	BucketRef    string
+	Image        string
+	NodeSelector map[string]string
+	Tenancy      bool
+	Pg           []PgDump
+	Redis        *RedisDump
+	Es           *EsSnapshot
+}
+
+func init() {
+	desired.RegisterTemplateData(path+"/job.tpl", Data{})
+	desired.RegisterTemplateData(path+"/restore-job.tpl", RestoreData{})
+}
@@ -0,0 +1,216 @@
+{{- if .Data.Schedule }}
+apiVersion: {{ apiVersion "CronJob" }}
+kind: CronJob
+{{- else }}
+apiVersion: batch/v1
+kind: Job
+{{- end }}
+metadata:
+  name: {{ .Data.JobName }}
+  namespace: {{ .Data.Namespace }}
+  annotations:
+    {{- range $k, $v := .Data.Annotations }}
+    {{$k}}: "{{$v}}"
+    {{- end }}
+  labels:
+    app: {{ .Data.JobName }}
+    cnvrg-component: backup
+    mlops.cnvrg.io/backup: {{ .Data.Name }}
+    {{- range $k, $v := .Data.Labels }}
+    {{$k}}: "{{$v}}"
+    {{- end }}
+spec:
+  {{- if .Data.Schedule }}
+  schedule: {{ .Data.Schedule | quote }}
+  concurrencyPolicy: Forbid
+  successfulJobsHistoryLimit: 1
+  failedJobsHistoryLimit: 3
+  jobTemplate:
+    metadata:
+      labels:
+        app: {{ .Data.JobName }}
+        cnvrg-component: backup
+        mlops.cnvrg.io/backup: {{ .Data.Name }}
+    spec:
+  {{- end }}
+      # the job spec is indented as the job template of the cron job, which is valid for the job as well
+      backoffLimit: 2
+      template:
+        metadata:
+          annotations:
+            {{- range $k, $v := .Data.Annotations }}
+            {{$k}}: "{{$v}}"
+            {{- end }}
+          labels:
+            app: {{ .Data.JobName }}
+            cnvrg-component: backup
+            mlops.cnvrg.io/backup: {{ .Data.Name }}
+            {{- range $k, $v := .Data.Labels }}
+            {{$k}}: "{{$v}}"
+            {{- end }}
+        spec:
+          restartPolicy: Never
+          {{- if gt (len .Data.NodeSelector) 0 }}
+          nodeSelector:
+            {{- range $key, $val := .Data.NodeSelector }}
+            {{ $key }}: {{ $val }}
+            {{- end }}
+          {{- end }}
+          {{- if .Data.Tenancy }}
+          tolerations:
+            - operator: "Exists"
+          {{- end }}
+          securityContext:
+            runAsUser: 1000
+            fsGroup: 1000
+          initContainers:
+            {{- range .Data.Pg }}
+            - name: dump-{{ .Database | toString | lower }}
+              image: {{ .Image }}
+              imagePullPolicy: IfNotPresent
+              envFrom:
+                - secretRef:
+                    name: {{ .CredsRef }}
+              env:
+                - name: PGHOST
+                  value: {{ .Host | quote }}
+                - name: PGPORT
+                  value: {{ .Port | quote }}
+                - name: DUMP
+                  value: /backup/{{ .Database }}.dump
+              command:
+                - /bin/bash
+                - -c
+                - |
+                  set -euo pipefail
+                  echo "dumping $POSTGRESQL_DATABASE from $PGHOST"
+                  PGPASSWORD="$POSTGRESQL_PASSWORD" pg_dump -Fc -U "$POSTGRESQL_USER" -d "$POSTGRESQL_DATABASE" -f "$DUMP"
+              volumeMounts:
+                - name: backup
+                  mountPath: /backup
+            {{- end }}
+            {{- with .Data.Redis }}
+            - name: dump-redis
+              image: {{ .Image }}
+              imagePullPolicy: IfNotPresent
+              envFrom:
+                - secretRef:
+                    name: {{ .CredsRef }}
+              env:
+                - name: REDIS_HOST
+                  value: {{ .Host | quote }}
+                - name: REDIS_PORT
+                  value: {{ .Port | quote }}
+              command:
+                - /bin/bash
+                - -c
+                - |
+                  set -euo pipefail
+                  echo "dumping redis from $REDIS_HOST"
+                  redis-cli -h "$REDIS_HOST" -p "$REDIS_PORT" -a "$CNVRG_REDIS_PASSWORD" --rdb /backup/redis.rdb
+              volumeMounts:
+                - name: backup
+                  mountPath: /backup
+            {{- end }}
+            {{- with .Data.Es }}
+            - name: snapshot-es
+              image: {{ .Image }}
+              imagePullPolicy: IfNotPresent
+              envFrom:
+                - secretRef:
+                    name: {{ .CredsRef }}
+                - secretRef:
+                    name: {{ $.Data.BucketRef }}
+              env:
+                - name: ES_URL
+                  value: {{ .Url | quote }}
+                - name: REPOSITORY
+                  value: {{ .Repository | quote }}
+                - name: BASE_PATH
+                  value: {{ .BasePath | quote }}
+                - name: NAME
+                  value: {{ $.Data.Name | quote }}
+                - name: ROTATION
+                  value: {{ $.Data.Rotation | quote }}
+              command:
+                - /bin/bash
+                - -c
+                - |
+                  set -euo pipefail
+                  {{- esSnapshotRepository | nindent 18 }}
+                  snapshot_repository "$REPOSITORY" "$CNVRG_STORAGE_BUCKET" "$BASE_PATH"
+                  snapshot="$NAME-$(date -u +%Y%m%d%H%M%S)"
+                  echo "creating snapshot $snapshot"
+                  result=$(es -X PUT "$ES_URL/_snapshot/$REPOSITORY/$snapshot?wait_for_completion=true")
+                  if ! echo "$result" | grep -q '"state":"SUCCESS"'; then
+                    echo "snapshot $snapshot failed: $result"
+                    exit 1
+                  fi
+                  size=$(es "$ES_URL/_snapshot/$REPOSITORY/$snapshot/_status" | grep -o '"total":{"file_count":[0-9]*,"size_in_bytes":[0-9]*' | head -n 1 | sed 's/.*://')
+                  echo -n "s3://$CNVRG_STORAGE_BUCKET/$BASE_PATH#$snapshot" > /backup/es.snapshot
+                  echo -n "${size:-0}" > /backup/es.size
+                  # keep the last $ROTATION snapshots
+                  if [ "$ROTATION" -gt 0 ]; then
+                    es "$ES_URL/_cat/snapshots/$REPOSITORY?h=id&s=end_epoch" | head -n -"$ROTATION" | while read -r old; do
+                      echo "removing snapshot $old"
+                      es -X DELETE "$ES_URL/_snapshot/$REPOSITORY/$old"
+                    done
+                  fi
+              volumeMounts:
+                - name: backup
+                  mountPath: /backup
+            {{- end }}
+          containers:
+            - name: upload
+              image: {{ .Data.Image }}
+              imagePullPolicy: IfNotPresent
+              envFrom:
+                - secretRef:
+                    name: {{ .Data.BucketRef }}
+              env:
+                - name: PREFIX
+                  value: {{ .Data.Prefix | quote }}
+                - name: ROTATION
+                  value: {{ .Data.Rotation | quote }}
+              command:
+                - /bin/bash
+                - -c
+                - |
+                  set -euo pipefail
+                  mc() { command mc --config-dir /tmp/.mc --quiet "$@"; }
+                  case "${CNVRG_STORAGE_TYPE:-}" in
+                    minio) endpoint="$CNVRG_STORAGE_ENDPOINT" ;;
+                    aws) endpoint="https://s3.amazonaws.com" ;;
+                    *)
+                      echo "backups require s3 compatible object storage, object storage type: ${CNVRG_STORAGE_TYPE:-not set}"
+                      exit 1
+                      ;;
+                  esac
+                  mc alias set storage "$endpoint" "$CNVRG_STORAGE_ACCESS_KEY" "$CNVRG_STORAGE_SECRET_KEY" > /dev/null
+                  run="$PREFIX/$(date -u +%Y%m%d%H%M%S)"
+                  artifacts=""
+                  for f in /backup/*.dump /backup/*.rdb; do
+                    [ -f "$f" ] || continue
+                    file=$(basename "$f")
+                    echo "uploading $file to $CNVRG_STORAGE_BUCKET/$run"
+                    mc cp "$f" "storage/$CNVRG_STORAGE_BUCKET/$run/$file"
+                    artifacts="$artifacts{\"database\":\"${file%.*}\",\"location\":\"s3://$CNVRG_STORAGE_BUCKET/$run/$file\",\"size\":$(stat -c %s "$f")},"
+                  done
+                  if [ -f /backup/es.snapshot ]; then
+                    artifacts="$artifacts{\"database\":\"es\",\"location\":\"$(cat /backup/es.snapshot)\",\"size\":$(cat /backup/es.size)},"
+                  fi
+                  # keep the last $ROTATION runs, the es snapshots are rotated by es
+                  if [ "$ROTATION" -gt 0 ]; then
+                    mc ls "storage/$CNVRG_STORAGE_BUCKET/$PREFIX/" | sed -n 's/.* \([0-9]\{14\}\)\/$/\1/p' | sort | head -n -"$ROTATION" | while read -r old; do
+                      echo "removing backup $old"
+                      mc rm --recursive --force "storage/$CNVRG_STORAGE_BUCKET/$PREFIX/$old"
+                    done
+                  fi
+                  # the artifacts are reported to the operator with the termination message
+                  echo -n "[${artifacts%,}]" > /dev/termination-log
+              volumeMounts:
+                - name: backup
+                  mountPath: /backup
+          volumes:
+            - name: backup
+              emptyDir: {}
@@ -0,0 +1,193 @@
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: {{ .Data.JobName }}
+  namespace: {{ .Data.Namespace }}
+  annotations:
+    {{- range $k, $v := .Data.Annotations }}
+    {{$k}}: "{{$v}}"
+    {{- end }}
+  labels:
+    app: {{ .Data.JobName }}
+    cnvrg-component: restore
+    mlops.cnvrg.io/restore: {{ .Data.Name }}
+    {{- range $k, $v := .Data.Labels }}
+    {{$k}}: "{{$v}}"
+    {{- end }}
+spec:
+  # a partial restore is not retried, the restore is recreated once the failure is fixed
+  backoffLimit: 0
+  template:
+    metadata:
+      annotations:
+        {{- range $k, $v := .Data.Annotations }}
+        {{$k}}: "{{$v}}"
+        {{- end }}
+      labels:
+        app: {{ .Data.JobName }}
+        cnvrg-component: restore
+        mlops.cnvrg.io/restore: {{ .Data.Name }}
+        {{- range $k, $v := .Data.Labels }}
+        {{$k}}: "{{$v}}"
+        {{- end }}
+    spec:
+      restartPolicy: Never
+      {{- if gt (len .Data.NodeSelector) 0 }}
+      nodeSelector:
+        {{- range $key, $val := .Data.NodeSelector }}
+        {{ $key }}: {{ $val }}
+        {{- end }}
+      {{- end }}
+      {{- if .Data.Tenancy }}
+      tolerations:
+        - operator: "Exists"
+      {{- end }}
+      securityContext:
+        runAsUser: 1000
+        fsGroup: 1000
+      {{- $downloads := "" }}
+      {{- range .Data.Pg }}
+      {{- $downloads = printf "%s%s.dump %s\n" $downloads .Database .Location }}
+      {{- end }}
+      {{- with .Data.Redis }}
+      {{- $downloads = printf "%sredis.rdb %s\n" $downloads .Location }}
+      {{- end }}
+      initContainers:
+        - name: download
+          image: {{ .Data.Image }}
+          imagePullPolicy: IfNotPresent
+          envFrom:
+            - secretRef:
+                name: {{ .Data.BucketRef }}
+          env:
+            - name: DOWNLOADS
+              value: {{ $downloads | quote }}
+          command:
+            - /bin/bash
+            - -c
+            - |
+              set -euo pipefail
+              mc() { command mc --config-dir /tmp/.mc --quiet "$@"; }
+              [ -n "$DOWNLOADS" ] || exit 0
+              case "${CNVRG_STORAGE_TYPE:-}" in
+                minio) endpoint="$CNVRG_STORAGE_ENDPOINT" ;;
+                aws) endpoint="https://s3.amazonaws.com" ;;
+                *)
+                  echo "backups require s3 compatible object storage, object storage type: ${CNVRG_STORAGE_TYPE:-not set}"
+                  exit 1
+                  ;;
+              esac
+              mc alias set storage "$endpoint" "$CNVRG_STORAGE_ACCESS_KEY" "$CNVRG_STORAGE_SECRET_KEY" > /dev/null
+              echo "$DOWNLOADS" | while read -r file location; do
+                [ -n "$file" ] || continue
+                echo "downloading $location"
+                mc cp "storage/${location#s3://}" "/backup/$file"
+              done
+          volumeMounts:
+            - name: backup
+              mountPath: /backup
+        {{- range .Data.Pg }}
+        - name: restore-{{ .Database | toString | lower }}
+          image: {{ .Image }}
+          imagePullPolicy: IfNotPresent
+          envFrom:
+            - secretRef:
+                name: {{ .CredsRef }}
+          env:
+            - name: PGHOST
+              value: {{ .Host | quote }}
+            - name: PGPORT
+              value: {{ .Port | quote }}
+            - name: DUMP
+              value: /backup/{{ .Database }}.dump
+          command:
+            - /bin/bash
+            - -c
+            - |
+              set -euo pipefail
+              echo "restoring $POSTGRESQL_DATABASE on $PGHOST"
+              PGPASSWORD="$POSTGRESQL_PASSWORD" pg_restore --clean --if-exists --no-owner -U "$POSTGRESQL_USER" -d "$POSTGRESQL_DATABASE" "$DUMP"
+          volumeMounts:
+            - name: backup
+              mountPath: /backup
+        {{- end }}
+        {{- with .Data.Redis }}
+        - name: restore-redis
+          image: {{ .Image }}
+          imagePullPolicy: IfNotPresent
+          command:
+            - /bin/bash
+            - -c
+            - |
+              set -euo pipefail
+              # redis is quiesced, the snapshot is loaded by a local redis which rewrites the append only file of the pvc
+              cp /backup/redis.rdb /data/dump.rdb
+              rm -f /data/appendonly.aof
+              redis-server --bind 127.0.0.1 --port 6379 --dir /data --dbfilename dump.rdb --appendonly no --daemonize yes
+              cli() { redis-cli -p 6379 "$@"; }
+              until cli info persistence 2> /dev/null | grep -q "loading:0"; do sleep 1; done
+              cli config set appendonly yes
+              sleep 1
+              until cli info persistence | grep -q "aof_rewrite_in_progress:0" && cli info persistence | grep -q "aof_rewrite_scheduled:0"; do sleep 1; done
+              cli shutdown || true
+              echo "redis is restored"
+          volumeMounts:
+            - name: backup
+              mountPath: /backup
+            - name: redis-data
+              mountPath: /data
+        {{- end }}
+        {{- with .Data.Es }}
+        - name: restore-es
+          image: {{ .Image }}
+          imagePullPolicy: IfNotPresent
+          envFrom:
+            - secretRef:
+                name: {{ .CredsRef }}
+          env:
+            - name: ES_URL
+              value: {{ .Url | quote }}
+            - name: REPOSITORY
+              value: {{ .Repository | quote }}
+            - name: LOCATION
+              value: {{ .Location | quote }}
+          command:
+            - /bin/bash
+            - -c
+            - |
+              set -euo pipefail
+              {{- esSnapshotRepository | nindent 14 }}
+              # location is s3://<bucket>/<base path>#<snapshot>
+              path="${LOCATION#s3://}"
+              bucket="${path%%/*}"
+              base_path="${path#*/}"
+              base_path="${base_path%%#*}"
+              snapshot="${LOCATION##*#}"
+              snapshot_repository "$REPOSITORY" "$bucket" "$base_path" true
+              echo "restoring snapshot $snapshot"
+              # open indices can't be restored, the system indices are kept
+              es -X POST "$ES_URL/*,-.*/_close?expand_wildcards=open"
+              result=$(es -X POST "$ES_URL/_snapshot/$REPOSITORY/$snapshot/_restore?wait_for_completion=true" \
+                -d '{"indices":"*,-.*","include_global_state":false}')
+              es -X DELETE "$ES_URL/_snapshot/$REPOSITORY"
+              if ! echo "$result" | grep -q '"failed":0'; then
+                echo "restore of snapshot $snapshot failed: $result"
+                exit 1
+              fi
+        {{- end }}
+      containers:
+        - name: complete
+          image: {{ .Data.Image }}
+          imagePullPolicy: IfNotPresent
+          command:
+            - /bin/bash
+            - -c
+            - echo "restore completed"
+      volumes:
+        - name: backup
+          emptyDir: {}
+        {{- with .Data.Redis }}
+        - name: redis-data
+          persistentVolumeClaim:
+            claimName: {{ .PvcName }}
+        {{- end }}
@@ -27,7 +27,7 @@ spec:
     spec:
       priorityClassName: {{ .Spec.CnvrgAppPriorityClass.Name }}
       serviceAccountName: cnvrg-capsule
-      {{- if isTrue .Spec.Tenancy.Enabled }}
+      {{- if .Spec.Tenancy.Enabled }}
       nodeSelector:
         "{{ .Spec.Tenancy.Key }}": "{{ .Spec.Tenancy.Value }}"
       tolerations:
@@ -821,6 +821,8 @@ func Crds() (crds []*desired.State) {
 			GVK:            desired.Kinds[desired.CrdGVK],
 			Own:            false,
 			Updatable:      updatable,
+			// crds installed by the chart are upgraded to the operator's version
+			ConflictPolicy: desired.ConflictForce,
 		}
 		crds = append(crds, crd)
 		return nil
@@ -16,7 +16,7 @@ spec:
         control-plane: controller-manager
     spec:
       priorityClassName: {{ .Spec.CnvrgAppPriorityClass.Name }}
-      {{- if isTrue .Spec.Tenancy.Enabled }}
+      {{- if .Spec.Tenancy.Enabled }}
       nodeSelector:
         {{ .Spec.Tenancy.Key }}: "{{ .Spec.Tenancy.Value }}"
       tolerations:
@@ -25,7 +25,7 @@ data:
   SHOW_INTERCOM: "{{ .Spec.ControlPlane.BaseConfig.Intercom }}"
   SPLIT_SIDEKIQ: "{{ .Spec.ControlPlane.Sidekiq.Split }}"
   CNVRG_PASSENGER_MAX_POOL_SIZE: "{{ .Spec.ControlPlane.WebApp.PassengerMaxPoolSize }}"
-  OAUTH_PROXY_ENABLED: "{{ isTrue .Spec.SSO.Enabled }}"
+  OAUTH_PROXY_ENABLED: "{{ .Spec.SSO.Enabled }}"
   OAUTH_ADMIN_USER: "{{ .Spec.SSO.AdminUser }}"
   CNVRG_PASSENGER_BIND_ADDRESS: "{{ cnvrgPassengerBindAddress . }}"
   CNVRG_PASSENGER_BIND_PORT: "{{ cnvrgPassengerBindPort . }}"
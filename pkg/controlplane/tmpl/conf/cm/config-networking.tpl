@@ -25,12 +25,12 @@ data:
   ROUTE_BY_NGINX_INGRESS: "{{ routeBy . "NGINX_INGRESS" }}"
   ROUTE_BY_NODE_PORT: "{{ routeBy . "NODE_PORT" }}"
   CNVRG_ISTIO_GATEWAY: "{{ .Spec.Networking.Ingress.IstioGwName }}"
-  {{- if isTrue .Spec.ControlPlane.CnvrgRouter.Enabled }}
+  {{- if .Spec.ControlPlane.CnvrgRouter.Enabled }}
   DEPLOY_URL: "{{ cnvrgRoutingService . }}"
   NOTEBOOK_URL: "{{ cnvrgRoutingService . }}"
   TENSORBOARD_URL: "{{ cnvrgRoutingService . }}"
   {{- end }}
-  {{- if isTrue .Spec.Networking.Proxy.Enabled }}
+  {{- if .Spec.Networking.Proxy.Enabled }}
   CNVRG_PROXY_CONFIG_REF: {{ .Spec.Networking.Proxy.ConfigRef }}
   {{- end }}
 
@@ -17,7 +17,7 @@ data:
   CNVRG_STORAGE_ENDPOINT: {{ objectStorageUrl . | b64enc }}
   ################## minio/aws storage ObjectStorage  ###########################
   CNVRG_STORAGE_BUCKET: {{ .Spec.ControlPlane.ObjectStorage.Bucket | b64enc }}
-  {{- if and ( isTrue .Spec.Dbs.Minio.Enabled ) (eq .Spec.ControlPlane.ObjectStorage.Type "minio") (eq .Spec.ControlPlane.ObjectStorage.AccessKey "") (eq .Spec.ControlPlane.ObjectStorage.SecretKey "") }}
+  {{- if and .Spec.Dbs.Minio.Enabled (eq .Spec.ControlPlane.ObjectStorage.Type "minio") (eq .Spec.ControlPlane.ObjectStorage.AccessKey "") (eq .Spec.ControlPlane.ObjectStorage.SecretKey "") }}
   CNVRG_STORAGE_ACCESS_KEY:  {{ randAlpha 20 | b64enc }}
   CNVRG_STORAGE_SECRET_KEY: {{ randAlpha 40 | b64enc }}
   {{- else }}
@@ -1,3 +1,4 @@
+{{- $pod := .Spec.ControlPlane.Hyper.PodSpecOverrides }}
 apiVersion: apps/v1
 kind: Deployment
 metadata:
@@ -30,6 +31,12 @@ spec:
         {{- range $k, $v := .Spec.Annotations }}
         {{$k}}: "{{$v}}"
         {{- end }}
+        {{- range $k, $v := $pod.PodAnnotations }}
+        {{$k}}: "{{$v}}"
+        {{- end }}
+        {{- with .Spec.SecretRefsChecksum }}
+        mlops.cnvrg.io/secret-refs-checksum: "{{ . }}"
+        {{- end }}
       labels:
         app: {{ .Spec.ControlPlane.Hyper.SvcName }}
         owner: cnvrg-control-plane
@@ -38,9 +45,19 @@ spec:
         {{- end }}
     spec:
       priorityClassName: {{ .Spec.CnvrgAppPriorityClass.Name }}
-      {{- if isTrue .Spec.Tenancy.Enabled }}
+      {{- with $pod.Affinity }}
+      affinity: {{ toJson . }}
+      {{- end }}
+      {{- with $pod.TopologySpreadConstraints }}
+      topologySpreadConstraints: {{ toJson . }}
+      {{- end }}
+      {{- if .Spec.Tenancy.Enabled }}
       nodeSelector:
         "{{ .Spec.Tenancy.Key }}": "{{ .Spec.Tenancy.Value }}"
+      {{- end }}
+      {{- if $pod.Tolerations }}
+      tolerations: {{ toJson $pod.Tolerations }}
+      {{- else if .Spec.Tenancy.Enabled }}
       tolerations:
         - key: "{{ .Spec.Tenancy.Key }}"
           operator: "Equal"
@@ -48,9 +65,24 @@ spec:
           effect: "NoSchedule"
       {{- end }}
       serviceAccountName: cnvrg-control-plane
+      {{- with $pod.SecurityContext }}
+      securityContext: {{ toJson . }}
+      {{- end }}
+      {{- with $pod.Volumes }}
+      volumes: {{ toJson . }}
+      {{- end }}
       containers:
         - image: {{ image .Spec.ImageHub .Spec.ControlPlane.Hyper.Image }}
           name: {{ .Spec.ControlPlane.Hyper.SvcName }}
+          {{- with $pod.ImagePullPolicy }}
+          imagePullPolicy: {{ . }}
+          {{- end }}
+          {{- with $pod.Env }}
+          env: {{ toJson . }}
+          {{- end }}
+          {{- with $pod.VolumeMounts }}
+          volumeMounts: {{ toJson . }}
+          {{- end }}
           envFrom:
             - configMapRef:
                 name: cp-base-config
@@ -62,6 +94,9 @@ spec:
                 name: cp-ldap
             - secretRef:
                 name: cp-object-storage
+            {{- range $pod.EnvFrom }}
+            - {{ toJson . }}
+            {{- end }}
           ports:
             - containerPort: {{ .Spec.ControlPlane.Hyper.Port }}
           readinessProbe:
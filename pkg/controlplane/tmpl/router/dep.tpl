@@ -23,7 +23,7 @@ spec:
         app: {{ .Spec.ControlPlane.CnvrgRouter.SvcName }}
     spec:
       priorityClassName: {{ .Spec.CnvrgAppPriorityClass.Name }}
-      {{- if isTrue .Spec.Tenancy.Enabled }}
+      {{- if .Spec.Tenancy.Enabled }}
       nodeSelector:
         {{ .Spec.Tenancy.Key }}: "{{ .Spec.Tenancy.Value }}"
       tolerations:
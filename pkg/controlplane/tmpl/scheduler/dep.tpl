@@ -26,6 +26,9 @@ spec:
         {{- range $k, $v := .Spec.Annotations }}
         {{$k}}: "{{$v}}"
         {{- end }}
+        {{- with .Spec.SecretRefsChecksum }}
+        mlops.cnvrg.io/secret-refs-checksum: "{{ . }}"
+        {{- end }}
       labels:
         app: scheduler
         owner: cnvrg-control-plane
@@ -35,7 +38,7 @@ spec:
         {{- end }}
     spec:
       priorityClassName: {{ .Spec.CnvrgAppPriorityClass.Name }}
-      {{- if isTrue .Spec.Tenancy.Enabled }}
+      {{- if .Spec.Tenancy.Enabled }}
       nodeSelector:
         "{{ .Spec.Tenancy.Key }}": "{{ .Spec.Tenancy.Value }}"
       tolerations:
@@ -90,7 +93,7 @@ spec:
               name: {{ .Spec.Dbs.Redis.CredsRef }}
           - secretRef:
               name: {{ .Spec.Monitoring.Prometheus.CredsRef }}
-          {{- if isTrue .Spec.Networking.Proxy.Enabled }}
+          {{- if .Spec.Networking.Proxy.Enabled }}
           - configMapRef:
               name: {{ .Spec.Networking.Proxy.ConfigRef }}
           {{- end }}
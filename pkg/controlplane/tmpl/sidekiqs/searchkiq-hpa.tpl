@@ -1,4 +1,4 @@
-apiVersion: autoscaling/v2beta2
+apiVersion: {{ apiVersion "HorizontalPodAutoscaler" }}
 kind: HorizontalPodAutoscaler
 metadata:
   name: searchkiq
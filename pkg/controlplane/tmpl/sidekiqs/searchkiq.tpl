@@ -16,7 +16,7 @@ metadata:
     {{$k}}: "{{$v}}"
     {{- end }}
 spec:
-  {{- if eq false (isTrue .Spec.ControlPlane.Searchkiq.Hpa.Enabled) }}
+  {{- if eq false (.Spec.ControlPlane.Searchkiq.Hpa.Enabled) }}
   replicas: {{ .Spec.ControlPlane.Searchkiq.Replicas }}
   {{- end }}
   selector:
@@ -28,6 +28,9 @@ spec:
         {{- range $k, $v := .Spec.Annotations }}
         {{$k}}: "{{$v}}"
         {{- end }}
+        {{- with .Spec.SecretRefsChecksum }}
+        mlops.cnvrg.io/secret-refs-checksum: "{{ . }}"
+        {{- end }}
       labels:
         app: searchkiq
         owner: cnvrg-control-plane
@@ -48,7 +51,7 @@ spec:
               topologyKey: kubernetes.io/hostname
             weight: 1
       priorityClassName: {{ .Spec.CnvrgAppPriorityClass.Name }}
-      {{- if isTrue .Spec.Tenancy.Enabled }}
+      {{- if .Spec.Tenancy.Enabled }}
       nodeSelector:
         "{{ .Spec.Tenancy.Key }}": "{{ .Spec.Tenancy.Value }}"
       tolerations:
@@ -100,7 +103,7 @@ spec:
               name: {{ .Spec.Dbs.Redis.CredsRef }}
           - secretRef:
               name: {{ .Spec.Monitoring.Prometheus.CredsRef }}
-          {{- if isTrue .Spec.Networking.Proxy.Enabled }}
+          {{- if .Spec.Networking.Proxy.Enabled }}
           - configMapRef:
               name: {{ .Spec.Networking.Proxy.ConfigRef }}
           {{- end }}
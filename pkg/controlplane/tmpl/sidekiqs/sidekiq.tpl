@@ -1,3 +1,4 @@
+{{- $pod := .Spec.ControlPlane.Sidekiq.PodSpecOverrides }}
 apiVersion: apps/v1
 kind: Deployment
 metadata:
@@ -16,7 +17,7 @@ metadata:
     {{$k}}: "{{$v}}"
     {{- end }}
 spec:
-  {{- if eq false (isTrue .Spec.ControlPlane.Sidekiq.Hpa.Enabled) }}
+  {{- if eq false (.Spec.ControlPlane.Sidekiq.Hpa.Enabled) }}
   replicas: {{ .Spec.ControlPlane.Sidekiq.Replicas }}
   {{- end }}
   selector:
@@ -28,6 +29,12 @@ spec:
         {{- range $k, $v := .Spec.Annotations }}
         {{$k}}: "{{$v}}"
         {{- end }}
+        {{- range $k, $v := $pod.PodAnnotations }}
+        {{$k}}: "{{$v}}"
+        {{- end }}
+        {{- with .Spec.SecretRefsChecksum }}
+        mlops.cnvrg.io/secret-refs-checksum: "{{ . }}"
+        {{- end }}
       labels:
         app: sidekiq
         owner: cnvrg-control-plane
@@ -36,6 +43,9 @@ spec:
         {{$k}}: "{{$v}}"
         {{- end }}
     spec:
+      {{- if $pod.Affinity }}
+      affinity: {{ toJson $pod.Affinity }}
+      {{- else }}
       affinity:
         podAntiAffinity:
           preferredDuringSchedulingIgnoredDuringExecution:
@@ -47,10 +57,18 @@ spec:
               - {{ ns . }}
               topologyKey: kubernetes.io/hostname
             weight: 1
+      {{- end }}
+      {{- with $pod.TopologySpreadConstraints }}
+      topologySpreadConstraints: {{ toJson . }}
+      {{- end }}
       priorityClassName: {{ .Spec.CnvrgAppPriorityClass.Name }}
-      {{- if isTrue .Spec.Tenancy.Enabled }}
+      {{- if .Spec.Tenancy.Enabled }}
       nodeSelector:
         "{{ .Spec.Tenancy.Key }}": "{{ .Spec.Tenancy.Value }}"
+      {{- end }}
+      {{- if $pod.Tolerations }}
+      tolerations: {{ toJson $pod.Tolerations }}
+      {{- else if .Spec.Tenancy.Enabled }}
       tolerations:
         - key: "{{ .Spec.Tenancy.Key }}"
           operator: "Equal"
@@ -58,9 +76,13 @@ spec:
           effect: "NoSchedule"
       {{- end }}
       serviceAccountName: cnvrg-control-plane
+      {{- if $pod.SecurityContext }}
+      securityContext: {{ toJson $pod.SecurityContext }}
+      {{- else }}
       securityContext:
         runAsUser: 1000
         runAsGroup: 1000
+      {{- end }}
       terminationGracePeriodSeconds: 60
       containers:
         - name: sidekiq
@@ -68,12 +90,20 @@ spec:
           env:
             - name: "CNVRG_RUN_MODE"
               value: "sidekiq"
-          imagePullPolicy: Always
-          {{- if eq .Spec.ControlPlane.ObjectStorage.Type "gcp" }}
+            {{- range $pod.Env }}
+            - {{ toJson . }}
+            {{- end }}
+          imagePullPolicy: {{ $pod.ImagePullPolicy | default "Always" }}
+          {{- if or (eq .Spec.ControlPlane.ObjectStorage.Type "gcp") $pod.VolumeMounts }}
           volumeMounts:
+            {{- if eq .Spec.ControlPlane.ObjectStorage.Type "gcp" }}
             - name: {{ .Spec.ControlPlane.ObjectStorage.GcpSecretRef }}
               mountPath: /opt/app-root/conf/gcp-keyfile
               readOnly: true
+            {{- end }}
+            {{- range $pod.VolumeMounts }}
+            - {{ toJson . }}
+            {{- end }}
           {{- end }}
           envFrom:
             - configMapRef:
@@ -90,7 +120,7 @@ spec:
                 name: cp-object-storage
             - secretRef:
                 name: cp-smtp
-            {{- if isTrue .Spec.Dbs.Cvat.Enabled }}
+            {{- if .Spec.Dbs.Cvat.Enabled }}
             - secretRef:
                 name: {{ .Spec.Dbs.Cvat.Pg.CredsRef }}
             - secretRef:
@@ -104,10 +134,13 @@ spec:
                 name: {{ .Spec.Dbs.Redis.CredsRef }}
             - secretRef:
                 name: {{ .Spec.Monitoring.Prometheus.CredsRef }}
-            {{- if isTrue .Spec.Networking.Proxy.Enabled }}
+            {{- if .Spec.Networking.Proxy.Enabled }}
             - configMapRef:
                 name: {{ .Spec.Networking.Proxy.ConfigRef }}
             {{- end }}
+            {{- range $pod.EnvFrom }}
+            - {{ toJson . }}
+            {{- end }}
           resources:
             requests:
               cpu: {{ .Spec.ControlPlane.Sidekiq.Requests.Cpu }}
@@ -119,11 +152,16 @@ spec:
             preStop:
               exec:
                 command: ["/bin/bash","-lc","sidekiqctl quiet sidekiq-0.pid && sidekiqctl stop sidekiq-0.pid 60"]
-      {{- if eq .Spec.ControlPlane.ObjectStorage.Type "gcp" }}
+      {{- if or (eq .Spec.ControlPlane.ObjectStorage.Type "gcp") $pod.Volumes }}
       volumes:
+        {{- if eq .Spec.ControlPlane.ObjectStorage.Type "gcp" }}
         - name: {{ .Spec.ControlPlane.ObjectStorage.GcpSecretRef }}
           secret:
             secretName: {{ .Spec.ControlPlane.ObjectStorage.GcpSecretRef }}
+        {{- end }}
+        {{- range $pod.Volumes }}
+        - {{ toJson . }}
+        {{- end }}
       {{- end }}
       initContainers:
         - name: seeder
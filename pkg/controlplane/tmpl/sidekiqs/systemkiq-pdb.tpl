@@ -1,4 +1,4 @@
-apiVersion: policy/v1beta1
+apiVersion: {{ apiVersion "PodDisruptionBudget" }}
 kind: PodDisruptionBudget
 metadata:
   name: systemkiq
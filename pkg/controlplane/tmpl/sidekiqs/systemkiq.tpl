@@ -16,7 +16,7 @@ metadata:
     {{$k}}: "{{$v}}"
     {{- end }}
 spec:
-  {{- if eq false (isTrue .Spec.ControlPlane.Systemkiq.Hpa.Enabled) }}
+  {{- if eq false (.Spec.ControlPlane.Systemkiq.Hpa.Enabled) }}
   replicas: {{ .Spec.ControlPlane.Systemkiq.Replicas }}
   {{- end }}
   selector:
@@ -28,6 +28,9 @@ spec:
         {{- range $k, $v := .Spec.Annotations }}
         {{$k}}: "{{$v}}"
         {{- end }}
+        {{- with .Spec.SecretRefsChecksum }}
+        mlops.cnvrg.io/secret-refs-checksum: "{{ . }}"
+        {{- end }}
       labels:
         app: systemkiq
         cnvrg-component: systemkiq
@@ -47,7 +50,7 @@ spec:
               topologyKey: kubernetes.io/hostname
             weight: 1
       priorityClassName: {{ .Spec.CnvrgAppPriorityClass.Name }}
-      {{- if isTrue .Spec.Tenancy.Enabled }}
+      {{- if .Spec.Tenancy.Enabled }}
       nodeSelector:
         "{{ .Spec.Tenancy.Key }}": "{{ .Spec.Tenancy.Value }}"
       tolerations:
@@ -91,7 +94,7 @@ spec:
                 name: cp-object-storage
             - secretRef:
                 name: cp-smtp
-            {{- if isTrue .Spec.Dbs.Cvat.Enabled }}
+            {{- if .Spec.Dbs.Cvat.Enabled }}
             - secretRef:
                 name: {{ .Spec.Dbs.Cvat.Pg.CredsRef }}
             - secretRef:
@@ -105,7 +108,7 @@ spec:
                 name: {{ .Spec.Dbs.Redis.CredsRef }}
             - secretRef:
                 name: {{ .Spec.Monitoring.Prometheus.CredsRef }}
-            {{- if isTrue .Spec.Networking.Proxy.Enabled }}
+            {{- if .Spec.Networking.Proxy.Enabled }}
             - configMapRef:
                 name: {{ .Spec.Networking.Proxy.ConfigRef }}
             {{- end }}
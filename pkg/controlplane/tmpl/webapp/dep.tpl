@@ -1,3 +1,4 @@
+{{- $pod := .Spec.ControlPlane.WebApp.PodSpecOverrides }}
 apiVersion: apps/v1
 kind: Deployment
 metadata:
@@ -16,7 +17,7 @@ metadata:
     {{$k}}: "{{$v}}"
     {{- end }}
 spec:
-  {{- if eq false (isTrue .Spec.ControlPlane.WebApp.Hpa.Enabled) }}
+  {{- if eq false (.Spec.ControlPlane.WebApp.Hpa.Enabled) }}
   replicas: {{ .Spec.ControlPlane.WebApp.Replicas }}
   {{- end }}
   strategy:
@@ -33,6 +34,12 @@ spec:
         {{- range $k, $v := .Spec.Annotations }}
         {{$k}}: "{{$v}}"
         {{- end }}
+        {{- range $k, $v := $pod.PodAnnotations }}
+        {{$k}}: "{{$v}}"
+        {{- end }}
+        {{- with .Spec.SecretRefsChecksum }}
+        mlops.cnvrg.io/secret-refs-checksum: "{{ . }}"
+        {{- end }}
       labels:
         app: {{.Spec.ControlPlane.WebApp.SvcName}}
         owner: cnvrg-control-plane
@@ -41,6 +48,9 @@ spec:
         {{$k}}: "{{$v}}"
         {{- end }}
     spec:
+      {{- if $pod.Affinity }}
+      affinity: {{ toJson $pod.Affinity }}
+      {{- else }}
       affinity:
         podAntiAffinity:
           preferredDuringSchedulingIgnoredDuringExecution:
@@ -52,9 +62,17 @@ spec:
               - {{ ns . }}
               topologyKey: kubernetes.io/hostname
             weight: 1
-      {{- if isTrue .Spec.Tenancy.Enabled }}
+      {{- end }}
+      {{- with $pod.TopologySpreadConstraints }}
+      topologySpreadConstraints: {{ toJson . }}
+      {{- end }}
+      {{- if .Spec.Tenancy.Enabled }}
       nodeSelector:
         {{ .Spec.Tenancy.Key }}: "{{ .Spec.Tenancy.Value }}"
+      {{- end }}
+      {{- if $pod.Tolerations }}
+      tolerations: {{ toJson $pod.Tolerations }}
+      {{- else if .Spec.Tenancy.Enabled }}
       tolerations:
         - key: "{{ .Spec.Tenancy.Key }}"
           operator: "Equal"
@@ -62,12 +80,16 @@ spec:
           effect: "NoSchedule"
       {{- end }}
       serviceAccountName: cnvrg-control-plane
+      {{- if $pod.SecurityContext }}
+      securityContext: {{ toJson $pod.SecurityContext }}
+      {{- else }}
       securityContext:
         runAsUser: 1000
         runAsGroup: 1000
+      {{- end }}
       priorityClassName: {{ .Spec.CnvrgAppPriorityClass.Name }}
       containers:
-      {{- if isTrue .Spec.SSO.Enabled }}
+      {{- if .Spec.SSO.Enabled }}
       - name: "cnvrg-oauth-proxy"
         image: {{ image .Spec.ImageHub .Spec.SSO.Image }}
         command: [ "oauth2-proxy","--config", "/opt/app-root/conf/proxy-config/conf" ]
@@ -98,9 +120,15 @@ spec:
             readOnly: true
       {{- end }}
       - image: {{ image .Spec.ImageHub .Spec.ControlPlane.Image }}
+        {{- with $pod.ImagePullPolicy }}
+        imagePullPolicy: {{ . }}
+        {{- end }}
         env:
         - name: "CNVRG_RUN_MODE"
           value: "webapp"
+        {{- range $pod.Env }}
+        - {{ toJson . }}
+        {{- end }}
         envFrom:
         - configMapRef:
             name: cp-base-config
@@ -116,7 +144,7 @@ spec:
             name: cp-object-storage
         - secretRef:
             name: cp-smtp
-        {{- if isTrue .Spec.Dbs.Cvat.Enabled }}
+        {{- if .Spec.Dbs.Cvat.Enabled }}
         - secretRef:
             name: {{ .Spec.Dbs.Cvat.Pg.CredsRef }}
         - secretRef:
@@ -130,10 +158,13 @@ spec:
             name: {{ .Spec.Dbs.Redis.CredsRef }}
         - secretRef:
             name: {{ .Spec.Monitoring.Prometheus.CredsRef }}
-        {{- if isTrue .Spec.Networking.Proxy.Enabled }}
+        {{- if .Spec.Networking.Proxy.Enabled }}
         - configMapRef:
             name: {{ .Spec.Networking.Proxy.ConfigRef }}
         {{- end }}
+        {{- range $pod.EnvFrom }}
+        - {{ toJson . }}
+        {{- end }}
         name: cnvrg-app
         ports:
           - containerPort: {{ .Spec.ControlPlane.WebApp.Port }}
@@ -170,14 +201,19 @@ spec:
           limits:
             cpu: "{{.Spec.ControlPlane.WebApp.Limits.Cpu}}"
             memory: "{{.Spec.ControlPlane.WebApp.Limits.Memory}}"
-        {{- if eq .Spec.ControlPlane.ObjectStorage.Type "gcp" }}
+        {{- if or (eq .Spec.ControlPlane.ObjectStorage.Type "gcp") $pod.VolumeMounts }}
         volumeMounts:
+        {{- if eq .Spec.ControlPlane.ObjectStorage.Type "gcp" }}
         - name: {{ .Spec.ControlPlane.ObjectStorage.GcpSecretRef }}
           mountPath: /opt/app-root/conf/gcp-keyfile
           readOnly: true
         {{- end }}
+        {{- range $pod.VolumeMounts }}
+        - {{ toJson . }}
+        {{- end }}
+        {{- end }}
       volumes:
-      {{- if isTrue .Spec.SSO.Enabled }}
+      {{- if .Spec.SSO.Enabled }}
       - name: "oauth-proxy-webapp"
         secret:
          secretName: "oauth-proxy-webapp"
@@ -187,6 +223,9 @@ spec:
         secret:
           secretName: {{ .Spec.ControlPlane.ObjectStorage.GcpSecretRef }}
       {{- end }}
+      {{- range $pod.Volumes }}
+      - {{ toJson . }}
+      {{- end }}
       initContainers:
       - name: ingresscheck
         image: {{ image .Spec.ImageHub .Spec.ControlPlane.Image }}
@@ -201,7 +240,7 @@ spec:
             name: {{ .Spec.Dbs.Redis.CredsRef }}
         - secretRef:
             name: {{ .Spec.Monitoring.Prometheus.CredsRef }}
-        {{- if isTrue .Spec.Networking.Proxy.Enabled }}
+        {{- if .Spec.Networking.Proxy.Enabled }}
         - configMapRef:
             name: {{ .Spec.Networking.Proxy.ConfigRef }}
         {{- end }}
@@ -221,7 +260,11 @@ spec:
           echo true > ${flagFile}
           while $(cat ${flagFile}); do
 
+            {{- if .Spec.Dbs.Redis.External.Enabled }}
+            timeout 2 bash -c "</dev/tcp/{{.Spec.Dbs.Redis.External.Host}}/{{.Spec.Dbs.Redis.External.Port}}";
+            {{- else }}
             timeout 2 bash -c "</dev/tcp/{{.Spec.Dbs.Redis.SvcName}}/{{.Spec.Dbs.Redis.Port}}";
+            {{- end }}
             if [[ $? != 0 ]]; then
               echo "[$(date)] redis not ready"
               sleep 1
@@ -229,9 +272,13 @@ spec:
             fi
             echo "[$(date)] redis is ready!"
 
-            timeout 2 bash -c "</dev/tcp/${POSTGRES_HOST}/{{.Spec.Dbs.Pg.Port}}";
+            {{- $pgPort := .Spec.Dbs.Pg.Port }}
+            {{- if .Spec.Dbs.Pg.External.Enabled }}
+            {{- $pgPort = .Spec.Dbs.Pg.External.Port }}
+            {{- end }}
+            timeout 2 bash -c "</dev/tcp/${POSTGRES_HOST}/{{ $pgPort }}";
             if [[ $? != 0 ]]; then
-              echo "[$(date)] postgres [${POSTGRES_HOST}:{{.Spec.Dbs.Pg.Port}}] not ready"
+              echo "[$(date)] postgres [${POSTGRES_HOST}:{{ $pgPort }}] not ready"
               sleep 1
               continue
             fi
@@ -244,7 +291,7 @@ spec:
             fi
             echo "[$(date)] elasticsearch is ready!"
 
-            {{- if and ( isTrue .Spec.Dbs.Minio.Enabled ) (eq .Spec.ControlPlane.ObjectStorage.Type "minio") }}
+            {{- if and .Spec.Dbs.Minio.Enabled (eq .Spec.ControlPlane.ObjectStorage.Type "minio") }}
             if [[ $(curl -sk $CNVRG_STORAGE_ENDPOINT/minio/health/ready -o /dev/null -w '%{http_code}') != 200 ]]; then
               echo "[$(date)] minio [$CNVRG_STORAGE_ENDPOINT/minio/health/ready] not ready"
               sleep 1
@@ -296,7 +343,7 @@ spec:
             name: {{ .Spec.Dbs.Redis.CredsRef }}
         - secretRef:
             name: {{ .Spec.Monitoring.Prometheus.CredsRef }}
-        {{- if isTrue .Spec.Networking.Proxy.Enabled }}
+        {{- if .Spec.Networking.Proxy.Enabled }}
         - configMapRef:
             name: {{ .Spec.Networking.Proxy.ConfigRef }}
         {{- end }}
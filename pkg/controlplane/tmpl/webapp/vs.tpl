@@ -1,4 +1,4 @@
-apiVersion: networking.istio.io/v1alpha3
+apiVersion: {{ apiVersion "VirtualService" }}
 kind: VirtualService
 metadata:
   name: {{ .Spec.ControlPlane.WebApp.SvcName }}
@@ -27,7 +27,7 @@ spec:
       headers:
         request:
           set:
-            {{- if isTrue .Spec.Networking.HTTPS.Enabled }}
+            {{- if .Spec.Networking.HTTPS.Enabled }}
             x-forwarded-proto: https
             {{- else }}
             x-forwarded-proto: http
@@ -0,0 +1,134 @@
+package dbs
+
+import "github.com/AccessibleAI/cnvrg-operator/pkg/desired"
+
+// PgCredsData is the input of the pg creds secret template
+type PgCredsData struct {
+	Namespace          string
+	Annotations        map[string]string
+	Labels             map[string]string
+	CredsRef           string
+	SvcName            string
+	MaxConnections     int
+	SharedBuffers      string
+	EffectiveCacheSize string
+}
+
+// RedisCredsData is the input of the redis creds secret template
+type RedisCredsData struct {
+	Namespace   string
+	Annotations map[string]string
+	Labels      map[string]string
+	CredsRef    string
+	SvcName     string
+}
+
+// EsCredsData is the input of the es creds secret template
+type EsCredsData struct {
+	Namespace   string
+	Annotations map[string]string
+	Labels      map[string]string
+	CredsRef    string
+	EsUrl       string
+}
+
+// PgUpgradeData is the input of the pg major version upgrade job template
+type PgUpgradeData struct {
+	Namespace      string
+	Annotations    map[string]string
+	Labels         map[string]string
+	Name           string
+	Image          string
+	ServiceAccount string
+	NodeSelector   map[string]string
+	Tenancy        bool
+	CredsRef       string
+	PreviousHost   string
+	Port           int
+	PvcName        string
+}
+
+// PgPvcData is the input of the pg upgrade pvcs template, the new version pvc and the kept previous version pvc
+type PgPvcData struct {
+	Namespace    string
+	Annotations  map[string]string
+	Labels       map[string]string
+	PvcName      string
+	StorageSize  string
+	StorageClass string
+}
+
+// ExternalPgCredsData is the input of the external pg creds secret template
+type ExternalPgCredsData struct {
+	Namespace   string
+	Annotations map[string]string
+	Labels      map[string]string
+	CredsRef    string
+	Host        string
+	Port        int
+	TLS         bool
+	User        string
+	Password    string
+	Database    string
+}
+
+// ExternalRedisCredsData is the input of the external redis creds secret template
+type ExternalRedisCredsData struct {
+	Namespace   string
+	Annotations map[string]string
+	Labels      map[string]string
+	CredsRef    string
+	Password    string
+	// Url is the redis url with the credentials, rediss:// when tls is enabled
+	Url string
+}
+
+// ExternalEsCredsData is the input of the external es creds secret template
+type ExternalEsCredsData struct {
+	Namespace   string
+	Annotations map[string]string
+	Labels      map[string]string
+	CredsRef    string
+	User        string
+	Password    string
+	// Url is the es url with the credentials, https:// when tls is enabled
+	Url string
+}
+
+// PreflightCheck is the connectivity check of an external database, the credentials are read from the external database secret,
+// the creds secret is generated only once the check succeeded
+type PreflightCheck struct {
+	Image     string
+	SecretRef string
+	Host      string
+	Port      int
+	TLS       bool
+	// Database is the pg database when the secret doesn't set it
+	Database string
+}
+
+// PreflightData is the input of the external databases preflight job template
+type PreflightData struct {
+	Namespace    string
+	Annotations  map[string]string
+	Labels       map[string]string
+	Name         string
+	Hash         string
+	NodeSelector map[string]string
+	Tenancy      bool
+	Pg           *PreflightCheck
+	Redis        *PreflightCheck
+	Es           *PreflightCheck
+}
+
+func init() {
+	desired.RegisterTemplateData(path+"/pg/secret.tpl", PgCredsData{})
+	desired.RegisterTemplateData(path+"/redis/secret.tpl", RedisCredsData{})
+	desired.RegisterTemplateData(path+"/es/secret.tpl", EsCredsData{})
+	desired.RegisterTemplateData(path+"/pg/upgrade-job.tpl", PgUpgradeData{})
+	desired.RegisterTemplateData(path+"/pg/upgrade-pvc.tpl", PgPvcData{})
+	desired.RegisterTemplateData(path+"/external/pg-secret.tpl", ExternalPgCredsData{})
+	desired.RegisterTemplateData(path+"/external/redis-secret.tpl", ExternalRedisCredsData{})
+	desired.RegisterTemplateData(path+"/external/es-secret.tpl", ExternalEsCredsData{})
+	desired.RegisterTemplateData(path+"/external/preflight-job.tpl", PreflightData{})
+}
@@ -8,7 +8,8 @@ import (
 
 const path = "/pkg/dbs/tmpl"
 
-func EsCreds(data interface{}) []*desired.State {
+func EsCreds(data EsCredsData) []*desired.State {
+	templateData := desired.TemplateData{Namespace: data.Namespace, Data: data}
 	return []*desired.State{
 		{
 			TemplatePath:   path + "/es/secret.tpl",
@@ -16,7 +17,7 @@ func EsCreds(data interface{}) []*desired.State {
 			ParsedTemplate: "",
 			Obj:            &unstructured.Unstructured{},
 			GVK:            desired.Kinds[desired.SecretGVK],
-			TemplateData:   data,
+			TemplateData:   templateData,
 			Own:            true,
 			Updatable:      false,
 		},
@@ -82,7 +83,8 @@ func esState() []*desired.State {
 	}
 }
 
-func PgCreds(data interface{}) []*desired.State {
+func PgCreds(data PgCredsData) []*desired.State {
+	templateData := desired.TemplateData{Namespace: data.Namespace, Data: data}
 	return []*desired.State{
 		{
 			TemplatePath:   path + "/pg/secret.tpl",
@@ -90,14 +92,95 @@ func PgCreds(data interface{}) []*desired.State {
 			ParsedTemplate: "",
 			Obj:            &unstructured.Unstructured{},
 			GVK:            desired.Kinds[desired.SecretGVK],
-			TemplateData:   data,
+			TemplateData:   templateData,
 			Own:            true,
 			Updatable:      false,
 		},
 	}
 }
 
-func pgState() []*desired.State {
+// PgUpgrade is the job copying the data of the previous pg major version into the new version pvc
+func PgUpgrade(data PgUpgradeData) []*desired.State {
+	templateData := desired.TemplateData{Namespace: data.Namespace, Data: data}
+	return []*desired.State{
+		{
+			TemplatePath:   path + "/pg/upgrade-job.tpl",
+			Template:       nil,
+			ParsedTemplate: "",
+			Obj:            &unstructured.Unstructured{},
+			GVK:            desired.Kinds[desired.JobGVK],
+			TemplateData:   templateData,
+			Own:            true,
+			Updatable:      false,
+		},
+	}
+}
+
+func PgPvc(data PgPvcData) []*desired.State {
+	templateData := desired.TemplateData{Namespace: data.Namespace, Data: data}
+	return []*desired.State{
+		{
+			TemplatePath:   path + "/pg/upgrade-pvc.tpl",
+			Template:       nil,
+			ParsedTemplate: "",
+			Obj:            &unstructured.Unstructured{},
+			GVK:            desired.Kinds[desired.PvcGVK],
+			TemplateData:   templateData,
+			Own:            true,
+			Updatable:      false,
+		},
+	}
+}
+
+// ExternalPgCreds is the pg creds secret of the external pg, updated when the external credentials change
+func ExternalPgCreds(data ExternalPgCredsData) []*desired.State {
+	return externalCreds(path+"/external/pg-secret.tpl", desired.TemplateData{Namespace: data.Namespace, Data: data})
+}
+
+// ExternalRedisCreds is the redis creds secret of the external redis
+func ExternalRedisCreds(data ExternalRedisCredsData) []*desired.State {
+	return externalCreds(path+"/external/redis-secret.tpl", desired.TemplateData{Namespace: data.Namespace, Data: data})
+}
+
+// ExternalEsCreds is the es creds secret of the external es
+func ExternalEsCreds(data ExternalEsCredsData) []*desired.State {
+	return externalCreds(path+"/external/es-secret.tpl", desired.TemplateData{Namespace: data.Namespace, Data: data})
+}
+
+func externalCreds(templatePath string, templateData desired.TemplateData) []*desired.State {
+	return []*desired.State{
+		{
+			TemplatePath:   templatePath,
+			Template:       nil,
+			ParsedTemplate: "",
+			Obj:            &unstructured.Unstructured{},
+			GVK:            desired.Kinds[desired.SecretGVK],
+			TemplateData:   templateData,
+			Own:            true,
+			Updatable:      true,
+		},
+	}
+}
+
+// Preflight is the job checking the external databases are reachable with the creds secrets credentials.
+// The job is named by the hash of the checked configuration, hence a changed configuration is checked again
+func Preflight(data PreflightData) []*desired.State {
+	templateData := desired.TemplateData{Namespace: data.Namespace, Data: data}
+	return []*desired.State{
+		{
+			TemplatePath:   path + "/external/preflight-job.tpl",
+			Template:       nil,
+			ParsedTemplate: "",
+			Obj:            &unstructured.Unstructured{},
+			GVK:            desired.Kinds[desired.JobGVK],
+			TemplateData:   templateData,
+			Own:            true,
+			Updatable:      false,
+		},
+	}
+}
+
+func singlePg() []*desired.State {
 	return []*desired.State{
 		{
 			TemplatePath:   path + "/pg/sa.tpl",
@@ -168,7 +251,87 @@ func pgState() []*desired.State {
 	}
 }
 
-func RedisCreds(data interface{}) []*desired.State {
+// haPg runs pg as a patroni cluster, the members pvcs are created from the stateful set claim template
+func haPg() []*desired.State {
+	return []*desired.State{
+		{
+			TemplatePath:   path + "/pg/sa.tpl",
+			Template:       nil,
+			ParsedTemplate: "",
+			Obj:            &unstructured.Unstructured{},
+			GVK:            desired.Kinds[desired.SaGVK],
+			Own:            true,
+			Updatable:      false,
+		},
+		{
+			TemplatePath:   path + "/pg/role.tpl",
+			Template:       nil,
+			ParsedTemplate: "",
+			Obj:            &unstructured.Unstructured{},
+			GVK:            desired.Kinds[desired.RoleGVK],
+			Own:            true,
+			Updatable:      true,
+		},
+		{
+			TemplatePath:   path + "/pg/rolebinding.tpl",
+			Template:       nil,
+			ParsedTemplate: "",
+			Obj:            &unstructured.Unstructured{},
+			GVK:            desired.Kinds[desired.RoleBindingGVK],
+			Own:            true,
+			Updatable:      true,
+		},
+		{
+			TemplatePath:   path + "/pg/ha-cm.tpl",
+			Template:       nil,
+			ParsedTemplate: "",
+			Obj:            &unstructured.Unstructured{},
+			GVK:            desired.Kinds[desired.ConfigMapGVK],
+			Own:            true,
+			Updatable:      true,
+		},
+		{
+			TemplatePath:   path + "/pg/ha-svc.tpl",
+			Template:       nil,
+			ParsedTemplate: "",
+			Obj:            &unstructured.Unstructured{},
+			GVK:            desired.Kinds[desired.SvcGVK],
+			Own:            true,
+			Updatable:      true,
+		},
+		{
+			TemplatePath:   path + "/pg/sts.tpl",
+			Template:       nil,
+			ParsedTemplate: "",
+			Obj:            &unstructured.Unstructured{},
+			GVK:            desired.Kinds[desired.StatefulSetGVK],
+			Own:            true,
+			Updatable:      true,
+		},
+		{
+
+			TemplatePath:   path + "/pg/svc.tpl",
+			Template:       nil,
+			ParsedTemplate: "",
+			Obj:            &unstructured.Unstructured{},
+			GVK:            desired.Kinds[desired.SvcGVK],
+			Own:            true,
+			Updatable:      true,
+		},
+		{
+			TemplatePath:   path + "/pg/pdb.tpl",
+			Template:       nil,
+			ParsedTemplate: "",
+			Obj:            &unstructured.Unstructured{},
+			GVK:            desired.Kinds[desired.PodDisruptionBudgetGVK],
+			Own:            true,
+			Updatable:      true,
+		},
+	}
+}
+
+func RedisCreds(data RedisCredsData) []*desired.State {
+	templateData := desired.TemplateData{Namespace: data.Namespace, Data: data}
 	return []*desired.State{
 		{
 			TemplatePath:   path + "/redis/secret.tpl",
@@ -176,7 +339,7 @@ func RedisCreds(data interface{}) []*desired.State {
 			ParsedTemplate: "",
 			Obj:            &unstructured.Unstructured{},
 			GVK:            desired.Kinds[desired.SecretGVK],
-			TemplateData:   data,
+			TemplateData:   templateData,
 			Own:            true,
 			Updatable:      false,
 		},
@@ -593,52 +756,92 @@ func cvatState() []*desired.State {
 
 func AppDbsState(cnvrgApp *mlopsv1.CnvrgApp) []*desired.State {
 	var state []*desired.State
+	state = append(state, AppCvatState(cnvrgApp)...)
+	state = append(state, AppPgState(cnvrgApp)...)
+	state = append(state, AppRedisState(cnvrgApp)...)
+	state = append(state, AppMinioState(cnvrgApp)...)
+	state = append(state, AppEsState(cnvrgApp)...)
+	return state
+}
 
-	// cvat pg and redis
-	if cnvrgApp.Spec.Dbs.Cvat.Enabled {
-		state = append(state, cvatState()...)
+// AppCvatState returns cvat pg and redis
+func AppCvatState(cnvrgApp *mlopsv1.CnvrgApp) []*desired.State {
+	if !cnvrgApp.Spec.Dbs.Cvat.Enabled {
+		return nil
 	}
+	return cvatState()
+}
 
-	// pg
-	if cnvrgApp.Spec.Dbs.Pg.Enabled {
-		state = append(state, pgState()...)
+func AppPgState(cnvrgApp *mlopsv1.CnvrgApp) []*desired.State {
+	if !cnvrgApp.Spec.Dbs.Pg.Enabled {
+		return nil
+	}
+	if cnvrgApp.Spec.Dbs.Pg.HA.Enabled {
+		return haPg()
 	}
+	return singlePg()
+}
 
-	// redis
-	if cnvrgApp.Spec.Dbs.Redis.Enabled {
-		state = append(state, redisState()...)
+func AppRedisState(cnvrgApp *mlopsv1.CnvrgApp) []*desired.State {
+	if !cnvrgApp.Spec.Dbs.Redis.Enabled {
+		return nil
+	}
+	return redisState()
+}
+
+func AppMinioState(cnvrgApp *mlopsv1.CnvrgApp) []*desired.State {
+	if !cnvrgApp.Spec.Dbs.Minio.Enabled {
+		return nil
 	}
 
-	// minio
-	if cnvrgApp.Spec.Dbs.Minio.Enabled && cnvrgApp.Spec.Dbs.Minio.SharedStorage.Enabled {
+	var state []*desired.State
+	if cnvrgApp.Spec.Dbs.Minio.SharedStorage.Enabled {
 		state = append(state, sharedBackendMinio()...)
-	} else if cnvrgApp.Spec.Dbs.Minio.Enabled {
+	} else {
 		state = append(state, singleBackendMinio()...)
 	}
-	if cnvrgApp.Spec.Dbs.Minio.Enabled {
-		switch cnvrgApp.Spec.Networking.Ingress.Type {
-		case mlopsv1.IstioIngress:
-			state = append(state, minioIstioVs()...)
-		case mlopsv1.NginxIngress:
-			state = append(state, minioIngress()...)
-		case mlopsv1.OpenShiftIngress:
-			state = append(state, minioOcpRoute()...)
-		}
-	}
-
-	// elasticsearch
-	if cnvrgApp.Spec.Dbs.Es.Enabled {
-		state = append(state, esState()...)
-		switch cnvrgApp.Spec.Networking.Ingress.Type {
-		case mlopsv1.IstioIngress:
-			state = append(state, esIstioVs()...)
-		case mlopsv1.NginxIngress:
-			state = append(state, esIngress()...)
-		case mlopsv1.OpenShiftIngress:
-			state = append(state, esOcpRoute()...)
-		}
+	switch cnvrgApp.Spec.Networking.Ingress.Type {
+	case mlopsv1.IstioIngress:
+		state = append(state, minioIstioVs()...)
+	case mlopsv1.NginxIngress:
+		state = append(state, minioIngress()...)
+	case mlopsv1.OpenShiftIngress:
+		state = append(state, minioOcpRoute()...)
 	}
+	return state
+}
 
+func esBackup() []*desired.State {
+	return []*desired.State{
+		{
+			TemplatePath:   path + "/es/backup-cronjob.tpl",
+			Template:       nil,
+			ParsedTemplate: "",
+			Obj:            &unstructured.Unstructured{},
+			GVK:            desired.Kinds[desired.CronJobGVK],
+			Own:            true,
+			Updatable:      true,
+		},
+	}
+}
+
+func AppEsState(cnvrgApp *mlopsv1.CnvrgApp) []*desired.State {
+	if !cnvrgApp.Spec.Dbs.Es.Enabled {
+		return nil
+	}
+
+	state := esState()
+	if cnvrgApp.Spec.Dbs.Es.Backup.Enabled {
+		state = append(state, esBackup()...)
+	}
+	switch cnvrgApp.Spec.Networking.Ingress.Type {
+	case mlopsv1.IstioIngress:
+		state = append(state, esIstioVs()...)
+	case mlopsv1.NginxIngress:
+		state = append(state, esIngress()...)
+	case mlopsv1.OpenShiftIngress:
+		state = append(state, esOcpRoute()...)
+	}
 	return state
 }
 
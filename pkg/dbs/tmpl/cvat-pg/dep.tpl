@@ -1,3 +1,4 @@
+{{- $pod := .Spec.Dbs.Cvat.Pg.PodSpecOverrides }}
 apiVersion: apps/v1
 kind: Deployment
 metadata:
@@ -26,6 +27,9 @@ spec:
         {{- range $k, $v := .Spec.Annotations }}
         {{$k}}: "{{$v}}"
         {{- end }}
+        {{- range $k, $v := $pod.PodAnnotations }}
+        {{$k}}: "{{$v}}"
+        {{- end }}
       labels:
         app: {{.Spec.Dbs.Cvat.Pg.SvcName }}
         cnvrg-component: pg
@@ -40,17 +44,36 @@ spec:
         {{ $key }}: {{ $val }}
         {{- end }}
       {{- end }}
+      {{- with $pod.Affinity }}
+      affinity: {{ toJson . }}
+      {{- end }}
+      {{- with $pod.TopologySpreadConstraints }}
+      topologySpreadConstraints: {{ toJson . }}
+      {{- end }}
+      {{- with $pod.Tolerations }}
+      tolerations: {{ toJson . }}
+      {{- end }}
       serviceAccountName: {{ .Spec.Dbs.Cvat.Pg.ServiceAccount }}
+      {{- if $pod.SecurityContext }}
+      securityContext: {{ toJson $pod.SecurityContext }}
+      {{- else }}
       securityContext:
         runAsUser: 26
         fsGroup: 26
+      {{- end }}
       containers:
         - name: postgresql
           envFrom:
             - secretRef:
                 name: {{ .Spec.Dbs.Cvat.Pg.CredsRef }}
+            {{- range $pod.EnvFrom }}
+            - {{ toJson . }}
+            {{- end }}
+          {{- with $pod.Env }}
+          env: {{ toJson . }}
+          {{- end }}
           image: {{ image .Spec.ImageHub .Spec.Dbs.Cvat.Pg.Image}}
-          imagePullPolicy: IfNotPresent
+          imagePullPolicy: {{ $pod.ImagePullPolicy | default "IfNotPresent" }}
           ports:
             - containerPort: {{.Spec.Dbs.Cvat.Pg.Port}}
               protocol: TCP
@@ -76,15 +99,18 @@ spec:
               name: postgres-data
             - mountPath: /dev/shm
               name: dshm
-            {{- if isTrue .Spec.Dbs.Cvat.Pg.HugePages.Enabled }}
+            {{- if .Spec.Dbs.Cvat.Pg.HugePages.Enabled }}
             - mountPath: "/hugepages"
               name: "hugepage"
             {{- end }}
+            {{- range $pod.VolumeMounts }}
+            - {{ toJson . }}
+            {{- end }}
           resources:
             limits:
               cpu: {{ .Spec.Dbs.Cvat.Pg.Limits.Cpu }}
               memory: {{ .Spec.Dbs.Cvat.Pg.Limits.Memory }}
-              {{- if isTrue .Spec.Dbs.Cvat.Pg.HugePages.Enabled }}
+              {{- if .Spec.Dbs.Cvat.Pg.HugePages.Enabled }}
               {{- if eq .Spec.Dbs.Cvat.Pg.HugePages.Memory ""}}
               hugepages-{{ .Spec.Dbs.Cvat.Pg.HugePages.Size }}: {{ .Spec.Dbs.Cvat.Pg.Requests.Memory }}
               {{- else }}
@@ -102,8 +128,11 @@ spec:
           emptyDir:
             medium: Memory
             sizeLimit: 2Gi
-        {{- if isTrue .Spec.Dbs.Cvat.Pg.HugePages.Enabled }}
+        {{- if .Spec.Dbs.Cvat.Pg.HugePages.Enabled }}
         - name: "hugepage"
           emptyDir:
             medium: HugePages
         {{- end}}
+        {{- range $pod.Volumes }}
+        - {{ toJson . }}
+        {{- end }}
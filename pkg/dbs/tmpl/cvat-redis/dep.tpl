@@ -1,3 +1,4 @@
+{{- $pod := .Spec.Dbs.Cvat.Redis.PodSpecOverrides }}
 apiVersion: apps/v1
 kind: Deployment
 metadata:
@@ -23,6 +24,9 @@ spec:
         {{- range $k, $v := .Spec.Annotations }}
         {{$k}}: "{{$v}}"
         {{- end }}
+        {{- range $k, $v := $pod.PodAnnotations }}
+        {{$k}}: "{{$v}}"
+        {{- end }}
       labels:
         app: {{.Spec.Dbs.Cvat.Redis.SvcName }}
         owner: cnvrg-control-plane
@@ -35,10 +39,37 @@ spec:
         {{ $key }}: {{ $val }}
         {{- end }}
       {{- end }}
+      {{- with $pod.Affinity }}
+      affinity: {{ toJson . }}
+      {{- end }}
+      {{- with $pod.TopologySpreadConstraints }}
+      topologySpreadConstraints: {{ toJson . }}
+      {{- end }}
+      {{- with $pod.Tolerations }}
+      tolerations: {{ toJson . }}
+      {{- end }}
       serviceAccountName: {{ .Spec.Dbs.Cvat.Redis.ServiceAccount }}
+      {{- with $pod.SecurityContext }}
+      securityContext: {{ toJson . }}
+      {{- end }}
+      {{- with $pod.Volumes }}
+      volumes: {{ toJson . }}
+      {{- end }}
       containers:
         - image: {{ .Spec.Dbs.Cvat.Redis.Image }}
           name: redis
+          {{- with $pod.ImagePullPolicy }}
+          imagePullPolicy: {{ . }}
+          {{- end }}
+          {{- with $pod.Env }}
+          env: {{ toJson . }}
+          {{- end }}
+          {{- with $pod.EnvFrom }}
+          envFrom: {{ toJson . }}
+          {{- end }}
+          {{- with $pod.VolumeMounts }}
+          volumeMounts: {{ toJson . }}
+          {{- end }}
           ports:
             - containerPort: {{ .Spec.Dbs.Cvat.Redis.Port }}
           resources:
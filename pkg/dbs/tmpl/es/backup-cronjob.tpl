@@ -0,0 +1,92 @@
+apiVersion: {{ apiVersion "CronJob" }}
+kind: CronJob
+metadata:
+  name: {{ .Spec.Dbs.Es.SvcName }}-backup
+  namespace: {{ ns . }}
+  annotations:
+    {{- range $k, $v := .Spec.Annotations }}
+    {{$k}}: "{{$v}}"
+    {{- end }}
+  labels:
+    app: {{ .Spec.Dbs.Es.SvcName }}-backup
+    {{- range $k, $v := .Spec.Labels }}
+    {{$k}}: "{{$v}}"
+    {{- end }}
+spec:
+  schedule: {{ backupSchedule .Spec.Dbs.Es.Backup.Period | quote }}
+  concurrencyPolicy: Forbid
+  successfulJobsHistoryLimit: 1
+  failedJobsHistoryLimit: 3
+  jobTemplate:
+    spec:
+      backoffLimit: 2
+      template:
+        metadata:
+          annotations:
+            {{- range $k, $v := .Spec.Annotations }}
+            {{$k}}: "{{$v}}"
+            {{- end }}
+          labels:
+            app: {{ .Spec.Dbs.Es.SvcName }}-backup
+            {{- range $k, $v := .Spec.Labels }}
+            {{$k}}: "{{$v}}"
+            {{- end }}
+        spec:
+          restartPolicy: Never
+          serviceAccountName: {{ .Spec.Dbs.Es.ServiceAccount }}
+          {{- if .Spec.Tenancy.Enabled }}
+          nodeSelector:
+            {{ .Spec.Tenancy.Key }}: {{ .Spec.Tenancy.Value }}
+          tolerations:
+            - operator: "Exists"
+          {{- end }}
+          securityContext:
+            runAsUser: 1000
+            fsGroup: 1000
+          containers:
+            - name: backup
+              image: {{ image .Spec.ImageHub .Spec.Dbs.Es.Image }}
+              imagePullPolicy: IfNotPresent
+              envFrom:
+                - secretRef:
+                    name: {{ .Spec.Dbs.Es.Backup.CredsRef }}
+                - secretRef:
+                    name: {{ .Spec.Dbs.Es.Backup.BucketRef }}
+              env:
+                - name: ES_URL
+                  value: "http://{{ .Spec.Dbs.Es.SvcName }}:{{ .Spec.Dbs.Es.Port }}"
+                - name: REPOSITORY
+                  value: "cnvrg-backups"
+                - name: BASE_PATH
+                  value: "{{ ns . }}/elasticsearch"
+                - name: ROTATION
+                  value: "{{ .Spec.Dbs.Es.Backup.Rotation }}"
+              resources:
+                limits:
+                  cpu: 200m
+                  memory: 200Mi
+                requests:
+                  cpu: 100m
+                  memory: 100Mi
+              command:
+                - /bin/bash
+                - -c
+                - |
+                  set -euo pipefail
+                  {{- esSnapshotRepository | nindent 18 }}
+                  # the repository points at the bucket of the object storage
+                  snapshot_repository "$REPOSITORY" "$CNVRG_STORAGE_BUCKET" "$BASE_PATH"
+                  snapshot="snapshot-$(date -u +%Y%m%d%H%M%S)"
+                  echo "creating snapshot $snapshot"
+                  result=$(es -X PUT "$ES_URL/_snapshot/$REPOSITORY/$snapshot?wait_for_completion=true")
+                  if ! echo "$result" | grep -q '"state":"SUCCESS"'; then
+                    echo "snapshot $snapshot failed: $result"
+                    exit 1
+                  fi
+                  # keep the last $ROTATION snapshots
+                  if [ "$ROTATION" -gt 0 ]; then
+                    es "$ES_URL/_cat/snapshots/$REPOSITORY?h=id&s=end_epoch" | head -n -"$ROTATION" | while read -r old; do
+                      echo "removing snapshot $old"
+                      es -X DELETE "$ES_URL/_snapshot/$REPOSITORY/$old"
+                    done
+                  fi
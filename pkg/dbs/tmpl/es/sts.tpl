@@ -1,3 +1,4 @@
+{{- $pod := .Spec.Dbs.Es.PodSpecOverrides }}
 apiVersion: apps/v1
 kind: StatefulSet
 metadata:
@@ -8,6 +9,9 @@ metadata:
     {{$k}}: "{{$v}}"
     {{- end }}
   labels:
+    {{- if .Spec.Dbs.Es.Backup.Enabled }}
+    cnvrg-config-reloader.mlops.cnvrg.io: "autoreload-ccp"
+    {{- end }}
     {{- range $k, $v := .Spec.Labels }}
     {{$k}}: "{{$v}}"
     {{- end }}
@@ -23,6 +27,9 @@ spec:
         {{- range $k, $v := .Spec.Annotations }}
         {{$k}}: "{{$v}}"
         {{- end }}
+        {{- range $k, $v := $pod.PodAnnotations }}
+        {{$k}}: "{{$v}}"
+        {{- end }}
       labels:
         app: {{ .Spec.Dbs.Es.SvcName }}
         {{- range $k, $v := .Spec.Labels }}
@@ -30,23 +37,35 @@ spec:
         {{- end }}
     spec:
       priorityClassName: {{ .Spec.CnvrgAppPriorityClass.Name }}
-      {{- if isTrue .Spec.Tenancy.Enabled }}
+      {{- if .Spec.Tenancy.Enabled }}
       nodeSelector:
         {{ .Spec.Tenancy.Key }}: {{ .Spec.Tenancy.Value }}
         {{- range $key, $val := .Spec.Dbs.Es.NodeSelector }}
         {{ $key }}: {{ $val }}
         {{- end }}
-      tolerations:
-        - operator: "Exists"
       {{- else if (gt (len .Spec.Dbs.Es.NodeSelector) 0) }}
       nodeSelector:
         {{- range $key, $val := .Spec.Dbs.Es.NodeSelector }}
         {{ $key }}: {{ $val }}
         {{- end }}
       {{- end }}
+      {{- with $pod.Affinity }}
+      affinity: {{ toJson . }}
+      {{- end }}
+      {{- with $pod.TopologySpreadConstraints }}
+      topologySpreadConstraints: {{ toJson . }}
+      {{- end }}
+      {{- if $pod.Tolerations }}
+      tolerations: {{ toJson $pod.Tolerations }}
+      {{- else if .Spec.Tenancy.Enabled }}
+      tolerations:
+        - operator: "Exists"
+      {{- end }}
       serviceAccountName: {{ .Spec.Dbs.Es.ServiceAccount }}
-      {{- if isTrue .Spec.Dbs.Es.PatchEsNodes }}
+      {{- if or .Spec.Dbs.Es.PatchEsNodes .Spec.Dbs.Es.Backup.Enabled }}
       initContainers:
+      {{- end }}
+      {{- if .Spec.Dbs.Es.PatchEsNodes }}
       - name: "maxmap"
         image: {{ image .Spec.ImageHub .Spec.Dbs.Es.Image }}
         imagePullPolicy: "Always"
@@ -62,12 +81,81 @@ spec:
             cpu: 100m
             memory: 100Mi
       {{- end }}
+      {{- if .Spec.Dbs.Es.Backup.Enabled }}
+      # configures the s3 client of the snapshots repository, the config and the plugins are copied to the shared volumes
+      - name: "s3-client"
+        image: {{ image .Spec.ImageHub .Spec.Dbs.Es.Image }}
+        command:
+          - /bin/bash
+          - -c
+          - |
+            set -euo pipefail
+            cd /usr/share/elasticsearch
+            if [ "${CNVRG_STORAGE_TYPE:-}" == "minio" ] || [ "${CNVRG_STORAGE_TYPE:-}" == "aws" ]; then
+              # the plugin is downloaded when the image doesn't bundle it, without internet access es starts without the plugin,
+              # and the snapshot jobs fail with the missing plugin until spec.dbs.es.image is set to an image which bundles it
+              if ! bin/elasticsearch-plugin list | grep -q repository-s3; then
+                bin/elasticsearch-plugin install --batch repository-s3 \
+                  || echo "can't install the repository-s3 plugin, es snapshots are not available, use an es image which bundles the plugin"
+              fi
+              [ -f config/elasticsearch.keystore ] || bin/elasticsearch-keystore create
+              echo -n "$CNVRG_STORAGE_ACCESS_KEY" | bin/elasticsearch-keystore add --stdin --force s3.client.default.access_key
+              echo -n "$CNVRG_STORAGE_SECRET_KEY" | bin/elasticsearch-keystore add --stdin --force s3.client.default.secret_key
+              if [ "${CNVRG_STORAGE_TYPE:-}" == "minio" ]; then
+                echo "s3.client.default.endpoint: ${CNVRG_STORAGE_ENDPOINT#*://}" >> config/elasticsearch.yml
+                echo "s3.client.default.protocol: ${CNVRG_STORAGE_ENDPOINT%%://*}" >> config/elasticsearch.yml
+                echo "s3.client.default.path_style_access: true" >> config/elasticsearch.yml
+              elif [ -n "${CNVRG_STORAGE_REGION:-}" ]; then
+                echo "s3.client.default.endpoint: s3.${CNVRG_STORAGE_REGION}.amazonaws.com" >> config/elasticsearch.yml
+              fi
+            else
+              echo "es snapshots require s3 compatible object storage, object storage type: ${CNVRG_STORAGE_TYPE:-not set}"
+            fi
+            cp -a config/. /es-config/
+            cp -a plugins/. /es-plugins/
+        envFrom:
+          # the object storage secret of the control plane is created after es, the reloader restarts es once it's created
+          - secretRef:
+              name: {{ .Spec.Dbs.Es.Backup.BucketRef }}
+              optional: true
+        volumeMounts:
+          - name: es-config
+            mountPath: /es-config
+          - name: es-plugins
+            mountPath: /es-plugins
+        resources:
+          limits:
+            cpu: 500m
+            memory: 1Gi
+          requests:
+            cpu: 100m
+            memory: 200Mi
+      {{- end }}
+      {{- if $pod.SecurityContext }}
+      securityContext: {{ toJson $pod.SecurityContext }}
+      {{- else }}
       securityContext:
         runAsUser: 1000
         fsGroup: 1000
+      {{- end }}
+      {{- if or $pod.Volumes .Spec.Dbs.Es.Backup.Enabled }}
+      volumes:
+        {{- if .Spec.Dbs.Es.Backup.Enabled }}
+        - name: es-config
+          emptyDir: {}
+        - name: es-plugins
+          emptyDir: {}
+        {{- end }}
+        {{- range $pod.Volumes }}
+        - {{ toJson . }}
+        {{- end }}
+      {{- end }}
       containers:
       - name: elastic
         image: {{ image .Spec.ImageHub .Spec.Dbs.Es.Image }}
+        {{- with $pod.ImagePullPolicy }}
+        imagePullPolicy: {{ . }}
+        {{- end }}
         env:
         - name: "ES_CLUSTER_NAME"
           value: "cnvrg-es"
@@ -89,11 +177,17 @@ spec:
           value: "{{ .Spec.Dbs.Es.JavaOpts }}"
         - name: "ES_SECURITY_ENABLED"
           value: "true"
+        {{- range $pod.Env }}
+        - {{ toJson . }}
+        {{- end }}
         envFrom:
           - secretRef:
               name: {{ .Spec.Dbs.Es.CredsRef }}
           - configMapRef:
               name: es-ilm-cm
+          {{- range $pod.EnvFrom }}
+          - {{ toJson . }}
+          {{- end }}
         ports:
         - containerPort: {{ .Spec.Dbs.Es.Port }}
         resources:
@@ -136,6 +230,15 @@ spec:
         volumeMounts:
         - name: es-storage
           mountPath: "/usr/share/elasticsearch/data"
+        {{- if .Spec.Dbs.Es.Backup.Enabled }}
+        - name: es-config
+          mountPath: "/usr/share/elasticsearch/config"
+        - name: es-plugins
+          mountPath: "/usr/share/elasticsearch/plugins"
+        {{- end }}
+        {{- range $pod.VolumeMounts }}
+        - {{ toJson . }}
+        {{- end }}
   volumeClaimTemplates:
   - metadata:
       name: {{ .Spec.Dbs.Es.PvcName  }}
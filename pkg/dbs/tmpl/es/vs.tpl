@@ -1,4 +1,4 @@
-apiVersion: networking.istio.io/v1alpha3
+apiVersion: {{ apiVersion "VirtualService" }}
 kind: VirtualService
 metadata:
   name: {{.Spec.Dbs.Es.SvcName }}
@@ -0,0 +1,24 @@
+apiVersion: v1
+kind: Secret
+metadata:
+  name: {{ .Data.CredsRef }}
+  namespace: {{ .Data.Namespace }}
+  annotations:
+    {{- range $k, $v := .Data.Annotations }}
+    {{$k}}: "{{$v}}"
+    {{- end }}
+  labels:
+    cnvrg-config-reloader.mlops.cnvrg.io: "autoreload-ccp"
+    {{- range $k, $v := .Data.Labels }}
+    {{$k}}: "{{$v}}"
+    {{- end }}
+data:
+  {{- $esUser := .Data.User | b64enc }}
+  {{- $esPass := .Data.Password | b64enc }}
+  CNVRG_ES_USER:          {{ $esUser }}                  # env for webapp/kiqs
+  CNVRG_ES_PASS:          {{ $esPass }}                  # env for webapp/kiqs
+  ELASTICSEARCH_URL:      {{ .Data.Url | b64enc }}       # env for webapp/kiqs
+  ES_USERNAME:            {{ $esUser }}                  # env for elastalerts
+  ES_PASSWORD:            {{ $esPass }}                  # env for elastalerts
+  ELASTICSEARCH_USERNAME: {{ $esUser }}                  # env for kibana
+  ELASTICSEARCH_PASSWORD: {{ $esPass }}                  # env for kibana
\ No newline at end of file
@@ -0,0 +1,33 @@
+apiVersion: v1
+kind: Secret
+metadata:
+  name: {{ .Data.CredsRef }}
+  namespace: {{ .Data.Namespace }}
+  annotations:
+    {{- range $k, $v := .Data.Annotations }}
+    {{$k}}: "{{$v}}"
+    {{- end }}
+  labels:
+    cnvrg-config-reloader.mlops.cnvrg.io: "autoreload-ccp"
+    {{- range $k, $v := .Data.Labels }}
+    {{$k}}: "{{$v}}"
+    {{- end }}
+data:
+  {{- $user := .Data.User | b64enc }}
+  {{- $pass := .Data.Password | b64enc }}
+  {{- $db := .Data.Database | b64enc }}
+  # the same keys as the in-cluster pg creds, except the settings of the pg server
+  POSTGRESQL_USER:      {{ $user }}
+  POSTGRESQL_PASSWORD:  {{ $pass }}
+  POSTGRESQL_DATABASE:  {{ $db }}
+  # required vars for the app
+  POSTGRES_DB:          {{ $db }}
+  POSTGRES_PASSWORD:    {{ $pass }}
+  POSTGRES_USER:        {{ $user }}
+  POSTGRES_HOST:        {{ .Data.Host | b64enc }}
+  POSTGRES_PORT:        {{ .Data.Port | toString | b64enc }}
+  # libpq defaults, for the clients which are not setting the port and the ssl mode
+  PGPORT:               {{ .Data.Port | toString | b64enc }}
+  {{- if .Data.TLS }}
+  PGSSLMODE:            {{ "require" | b64enc }}
+  {{- end }}
\ No newline at end of file
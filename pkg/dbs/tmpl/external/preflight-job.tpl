@@ -0,0 +1,157 @@
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: {{ .Data.Name }}
+  namespace: {{ .Data.Namespace }}
+  annotations:
+    {{- range $k, $v := .Data.Annotations }}
+    {{$k}}: "{{$v}}"
+    {{- end }}
+  labels:
+    app: {{ .Data.Name }}
+    cnvrg-component: external-dbs-preflight
+    mlops.cnvrg.io/preflight: {{ .Data.Hash }}
+    {{- range $k, $v := .Data.Labels }}
+    {{$k}}: "{{$v}}"
+    {{- end }}
+spec:
+  # the databases are checked a few times before the preflight fails, delete the job to check again
+  backoffLimit: 3
+  template:
+    metadata:
+      annotations:
+        {{- range $k, $v := .Data.Annotations }}
+        {{$k}}: "{{$v}}"
+        {{- end }}
+      labels:
+        app: {{ .Data.Name }}
+        cnvrg-component: external-dbs-preflight
+        mlops.cnvrg.io/preflight: {{ .Data.Hash }}
+        {{- range $k, $v := .Data.Labels }}
+        {{$k}}: "{{$v}}"
+        {{- end }}
+    spec:
+      restartPolicy: Never
+      {{- if gt (len .Data.NodeSelector) 0 }}
+      nodeSelector:
+        {{- range $key, $val := .Data.NodeSelector }}
+        {{ $key }}: {{ $val }}
+        {{- end }}
+      {{- end }}
+      {{- if .Data.Tenancy }}
+      tolerations:
+        - operator: "Exists"
+      {{- end }}
+      # each database is checked by a container of the database image, with the credentials of the external database secret,
+      # the failure is reported by the termination message
+      containers:
+        {{- with .Data.Pg }}
+        - name: pg
+          image: {{ .Image }}
+          imagePullPolicy: IfNotPresent
+          terminationMessagePolicy: FallbackToLogsOnError
+          env:
+            - name: HOST
+              value: {{ .Host | quote }}
+            - name: PORT
+              value: {{ .Port | quote }}
+            - name: PGSSLMODE
+              value: {{ ternary "require" "prefer" .TLS }}
+            - name: USERNAME
+              valueFrom:
+                secretKeyRef:
+                  name: {{ .SecretRef }}
+                  key: username
+            - name: PASSWORD
+              valueFrom:
+                secretKeyRef:
+                  name: {{ .SecretRef }}
+                  key: password
+            - name: DATABASE
+              valueFrom:
+                secretKeyRef:
+                  name: {{ .SecretRef }}
+                  key: database
+                  optional: true
+          command:
+            - /bin/bash
+            - -c
+            - |
+              set -uo pipefail
+              fail() { echo "$1"; echo -n "$1" > /dev/termination-log; exit 1; }
+              DATABASE="${DATABASE:-{{ .Database }}}"
+              timeout 5 bash -c "</dev/tcp/$HOST/$PORT" 2> /dev/null || fail "pg: can't connect to $HOST:$PORT"
+              out=$(PGPASSWORD="$PASSWORD" PGCONNECT_TIMEOUT=10 psql -h "$HOST" -p "$PORT" -U "$USERNAME" -d "$DATABASE" -Atc "select 1" 2>&1) \
+                || fail "pg: can't login to $DATABASE on $HOST:$PORT as $USERNAME: $out"
+              echo "pg is reachable"
+        {{- end }}
+        {{- with .Data.Redis }}
+        - name: redis
+          image: {{ .Image }}
+          imagePullPolicy: IfNotPresent
+          terminationMessagePolicy: FallbackToLogsOnError
+          env:
+            - name: HOST
+              value: {{ .Host | quote }}
+            - name: PORT
+              value: {{ .Port | quote }}
+            - name: USERNAME
+              valueFrom:
+                secretKeyRef:
+                  name: {{ .SecretRef }}
+                  key: username
+                  optional: true
+            # the password is passed to redis-cli by the env
+            - name: REDISCLI_AUTH
+              valueFrom:
+                secretKeyRef:
+                  name: {{ .SecretRef }}
+                  key: password
+          command:
+            - /bin/sh
+            - -c
+            - |
+              fail() { echo "$1"; echo -n "$1" > /dev/termination-log; exit 1; }
+              set -- -h "$HOST" -p "$PORT"{{ if .TLS }} --tls{{ end }}
+              [ -n "$USERNAME" ] && set -- "$@" --user "$USERNAME"
+              out=$(timeout 10 redis-cli --no-auth-warning "$@" ping 2>&1)
+              case "$out" in
+                PONG) echo "redis is reachable" ;;
+                *"Could not connect"*|*"Connection refused"*|"") fail "redis: can't connect to $HOST:$PORT: $out" ;;
+                *) fail "redis: can't login to $HOST:$PORT: $out" ;;
+              esac
+        {{- end }}
+        {{- with .Data.Es }}
+        - name: es
+          image: {{ .Image }}
+          imagePullPolicy: IfNotPresent
+          terminationMessagePolicy: FallbackToLogsOnError
+          env:
+            - name: HOST
+              value: {{ .Host | quote }}
+            - name: PORT
+              value: {{ .Port | quote }}
+            - name: ES_URL
+              value: {{ printf "%s://%s:%d" (ternary "https" "http" .TLS) .Host .Port | quote }}
+            - name: USERNAME
+              valueFrom:
+                secretKeyRef:
+                  name: {{ .SecretRef }}
+                  key: username
+            - name: PASSWORD
+              valueFrom:
+                secretKeyRef:
+                  name: {{ .SecretRef }}
+                  key: password
+          command:
+            - /bin/bash
+            - -c
+            - |
+              set -uo pipefail
+              fail() { echo "$1"; echo -n "$1" > /dev/termination-log; exit 1; }
+              timeout 5 bash -c "</dev/tcp/$HOST/$PORT" 2> /dev/null || fail "es: can't connect to $HOST:$PORT"
+              code=$(curl -sS -o /dev/null -w "%{http_code}" --max-time 10 -u "$USERNAME:$PASSWORD" "$ES_URL/_cluster/health" 2> /tmp/error) \
+                || fail "es: can't request $ES_URL: $(cat /tmp/error)"
+              [ "$code" = "200" ] || fail "es: can't login to $ES_URL as $USERNAME, http status $code"
+              echo "es is reachable"
+        {{- end }}
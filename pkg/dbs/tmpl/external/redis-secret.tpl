@@ -0,0 +1,19 @@
+apiVersion: v1
+kind: Secret
+metadata:
+  name: {{ .Data.CredsRef }}
+  namespace: {{ .Data.Namespace }}
+  annotations:
+    {{- range $k, $v := .Data.Annotations }}
+    {{$k}}: "{{$v}}"
+    {{- end }}
+  labels:
+    cnvrg-config-reloader.mlops.cnvrg.io: "autoreload-ccp"
+    {{- range $k, $v := .Data.Labels }}
+    {{$k}}: "{{$v}}"
+    {{- end }}
+data:
+  {{- $redisUrl := .Data.Url | b64enc }}
+  CNVRG_REDIS_PASSWORD:               {{ .Data.Password | b64enc }}
+  OAUTH2_PROXY_REDIS_CONNECTION_URL:  {{ $redisUrl }} # for oauth2 proxy
+  REDIS_URL:                          {{ $redisUrl }} # for cnvrg webapp/sidekiq
\ No newline at end of file
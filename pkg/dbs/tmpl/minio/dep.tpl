@@ -1,3 +1,4 @@
+{{- $pod := .Spec.Dbs.Minio.PodSpecOverrides }}
 apiVersion: apps/v1
 kind: Deployment
 metadata:
@@ -24,36 +25,62 @@ spec:
         {{- range $k, $v := .Spec.Annotations }}
         {{$k}}: "{{$v}}"
         {{- end }}
+        {{- range $k, $v := $pod.PodAnnotations }}
+        {{$k}}: "{{$v}}"
+        {{- end }}
+        {{- with .Spec.SecretRefsChecksum }}
+        mlops.cnvrg.io/secret-refs-checksum: "{{ . }}"
+        {{- end }}
       labels:
         app: {{ .Spec.Dbs.Minio.SvcName }}
         owner: cnvrg-control-plane
         cnvrg-component: minio
     spec:
       priorityClassName: {{ .Spec.CnvrgAppPriorityClass.Name }}
-      {{- if isTrue .Spec.Tenancy.Enabled }}
+      {{- if .Spec.Tenancy.Enabled }}
       nodeSelector:
         {{ .Spec.Tenancy.Key }}: {{ .Spec.Tenancy.Value }}
         {{- range $key, $val := .Spec.Dbs.Minio.NodeSelector }}
         {{ $key }}: {{ $val }}
         {{- end }}
-      tolerations:
-        - operator: "Exists"
       {{- else if (gt (len .Spec.Dbs.Minio.NodeSelector) 0) }}
       nodeSelector:
         {{- range $key, $val := .Spec.Dbs.Minio.NodeSelector }}
         {{ $key }}: {{ $val }}
         {{- end }}
       {{- end }}
+      {{- with $pod.Affinity }}
+      affinity: {{ toJson . }}
+      {{- end }}
+      {{- with $pod.TopologySpreadConstraints }}
+      topologySpreadConstraints: {{ toJson . }}
+      {{- end }}
+      {{- if $pod.Tolerations }}
+      tolerations: {{ toJson $pod.Tolerations }}
+      {{- else if .Spec.Tenancy.Enabled }}
+      tolerations:
+        - operator: "Exists"
+      {{- end }}
+      {{- if $pod.SecurityContext }}
+      securityContext: {{ toJson $pod.SecurityContext }}
+      {{- else }}
       securityContext:
         runAsUser: 1000
         fsGroup: 1000
+      {{- end }}
       serviceAccountName: {{ .Spec.Dbs.Minio.ServiceAccount }}
       containers:
         - name: minio
           image: {{ image .Spec.ImageHub .Spec.Dbs.Minio.Image }}
+          {{- with $pod.ImagePullPolicy }}
+          imagePullPolicy: {{ . }}
+          {{- end }}
           envFrom:
           - secretRef:
               name: cp-object-storage
+          {{- range $pod.EnvFrom }}
+          - {{ toJson . }}
+          {{- end }}
           env:
             - name: MINIO_ACCESS_KEY
               valueFrom:
@@ -65,11 +92,17 @@ spec:
                 secretKeyRef:
                   name: cp-object-storage
                   key: CNVRG_STORAGE_SECRET_KEY
+            {{- range $pod.Env }}
+            - {{ toJson . }}
+            {{- end }}
           ports:
             - containerPort: {{ .Spec.Dbs.Minio.Port }}
           volumeMounts:
             - name: minio-storage
               mountPath: /data # the Minio mount path have to be /data -> it's hardcoded into Minio server startup command
+            {{- range $pod.VolumeMounts }}
+            - {{ toJson . }}
+            {{- end }}
           readinessProbe:
             httpGet:
               path: /minio/health/ready
@@ -93,3 +126,6 @@ spec:
         - name: minio-storage
           persistentVolumeClaim:
             claimName: {{ .Spec.Dbs.Minio.PvcName }}
+        {{- range $pod.Volumes }}
+        - {{ toJson . }}
+        {{- end }}
@@ -1,4 +1,4 @@
-apiVersion: networking.istio.io/v1alpha3
+apiVersion: {{ apiVersion "DestinationRule" }}
 kind: DestinationRule
 metadata:
   name: {{ .Spec.Dbs.Minio.SvcName }}
@@ -16,4 +16,4 @@ spec:
   trafficPolicy:
     loadBalancer:
       consistentHash:
-        {{ .Spec.Dbs.Minio.SharedStorage.ConsistentHash.key }}: {{ .Spec.Dbs.Minio.SharedStorage.ConsistentHash.Value }}
\ No newline at end of file
+        {{ .Spec.Dbs.Minio.SharedStorage.ConsistentHash.Key }}: {{ .Spec.Dbs.Minio.SharedStorage.ConsistentHash.Value }}
\ No newline at end of file
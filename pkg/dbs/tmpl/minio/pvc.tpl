@@ -13,7 +13,7 @@ metadata:
     {{- end }}
 spec:
   accessModes:
-    {{- if isTrue .Spec.Dbs.Minio.SharedStorage.Enabled }}
+    {{- if .Spec.Dbs.Minio.SharedStorage.Enabled }}
     - ReadWriteMany
     {{- else }}
     - ReadWriteOnce
@@ -1,3 +1,4 @@
+{{- $pod := .Spec.Dbs.Minio.PodSpecOverrides }}
 apiVersion: apps/v1
 kind: Deployment
 metadata:
@@ -24,6 +25,12 @@ spec:
         {{- range $k, $v := .Spec.Annotations }}
         {{$k}}: "{{$v}}"
         {{- end }}
+        {{- range $k, $v := $pod.PodAnnotations }}
+        {{$k}}: "{{$v}}"
+        {{- end }}
+        {{- with .Spec.SecretRefsChecksum }}
+        mlops.cnvrg.io/secret-refs-checksum: "{{ . }}"
+        {{- end }}
       labels:
         app: {{ .Spec.Dbs.Minio.SvcName }}
         cnvrg-component: minio
@@ -32,26 +39,40 @@ spec:
         {{- end }}
     spec:
       priorityClassName: {{ .Spec.CnvrgAppPriorityClass.Name }}
-      {{- if isTrue .Spec.Tenancy.Enabled }}
+      {{- if .Spec.Tenancy.Enabled }}
       nodeSelector:
         {{ .Spec.Tenancy.Key }}: {{ .Spec.Tenancy.Value }}
         {{- range $key, $val := .Spec.Dbs.Minio.NodeSelector }}
         {{ $key }}: {{ $val }}
         {{- end }}
-      tolerations:
-        - key: "{{ .Spec.Tenancy.Key }}"
-          operator: "Equal"
-          value: "{{ .Spec.Tenancy.Value }}"
-          effect: "NoSchedule"
       {{- else if (gt (len .Spec.Dbs.Minio.NodeSelector) 0) }}
       nodeSelector:
         {{- range $key, $val := .Spec.Dbs.Minio.NodeSelector }}
         {{ $key }}: {{ $val }}
         {{- end }}
       {{- end }}
+      {{- with $pod.Affinity }}
+      affinity: {{ toJson . }}
+      {{- end }}
+      {{- with $pod.TopologySpreadConstraints }}
+      topologySpreadConstraints: {{ toJson . }}
+      {{- end }}
+      {{- if $pod.Tolerations }}
+      tolerations: {{ toJson $pod.Tolerations }}
+      {{- else if .Spec.Tenancy.Enabled }}
+      tolerations:
+        - key: "{{ .Spec.Tenancy.Key }}"
+          operator: "Equal"
+          value: "{{ .Spec.Tenancy.Value }}"
+          effect: "NoSchedule"
+      {{- end }}
+      {{- if $pod.SecurityContext }}
+      securityContext: {{ toJson $pod.SecurityContext }}
+      {{- else }}
       securityContext:
         runAsUser: 1000
         fsGroup: 1000
+      {{- end }}
       serviceAccountName: {{ .Spec.Dbs.Minio.ServiceAccount }}
       containers:
         - args:
@@ -71,7 +92,7 @@ spec:
             - name: PILOT_CERT_PROVIDER
               value: istiod
             - name: CA_ADDR
-              value: "istiod.{{ ansible_operator_meta.namespace }}.svc:15012"
+              value: "istiod.{{ ns . }}.svc:15012"
             - name: POD_NAME
               valueFrom:
                 fieldRef:
@@ -102,7 +123,7 @@ spec:
                   fieldPath: metadata.labels['service.networking.istio.io/canonical-revision']
             - name: PROXY_CONFIG
               value: |
-                {"discoveryAddress":"istiod.{{ ansible_operator_meta.namespace }}.svc:15012","tracing":{"zipkin":{"address":"zipkin.{{ ansible_operator_meta.namespace }}:9411"}},"proxyMetadata":{"DNS_AGENT":""}}
+                {"discoveryAddress":"istiod.{{ ns . }}.svc:15012","tracing":{"zipkin":{"address":"zipkin.{{ ns . }}:9411"}},"proxyMetadata":{"DNS_AGENT":""}}
             - name: ISTIO_META_POD_PORTS
               value: |-
                 [
@@ -117,7 +138,7 @@ spec:
             - name: ISTIO_META_WORKLOAD_NAME
               value: minio
             - name: ISTIO_META_OWNER
-              value: "kubernetes://apis/apps/v1/namespaces/{{ ansible_operator_meta.namespace }}/deployments/minio"
+              value: "kubernetes://apis/apps/v1/namespaces/{{ ns . }}/deployments/minio"
             - name: ISTIO_META_MESH_ID
               value: cluster.local
             - name: TRUST_DOMAIN
@@ -168,6 +189,12 @@ spec:
               name: istio-podinfo
         - name: minio
           image: {{ image .Spec.ImageHub .Spec.Dbs.Minio.Image}}
+          {{- with $pod.ImagePullPolicy }}
+          imagePullPolicy: {{ . }}
+          {{- end }}
+          {{- with $pod.EnvFrom }}
+          envFrom: {{ toJson . }}
+          {{- end }}
           args:
             - gateway
             - nas
@@ -183,11 +210,17 @@ spec:
                 secretKeyRef:
                   name: cp-object-storage
                   key: CNVRG_STORAGE_SECRET_KEY
+            {{- range $pod.Env }}
+            - {{ toJson . }}
+            {{- end }}
           ports:
             - containerPort: {{ .Spec.Dbs.Minio.Port }}
           volumeMounts:
             - name: minio-storage
               mountPath: /data
+            {{- range $pod.VolumeMounts }}
+            - {{ toJson . }}
+            {{- end }}
           readinessProbe:
             httpGet:
               path: /minio/health/ready
@@ -268,4 +301,7 @@ spec:
           name: istio-podinfo
         - configMap:
             name: istio-ca-root-cert
-          name: istiod-ca-cert
\ No newline at end of file
+          name: istiod-ca-cert
+        {{- range $pod.Volumes }}
+        - {{ toJson . }}
+        {{- end }}
\ No newline at end of file
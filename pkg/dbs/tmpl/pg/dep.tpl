@@ -1,3 +1,4 @@
+{{- $pod := .Spec.Dbs.Pg.PodSpecOverrides }}
 apiVersion: apps/v1
 kind: Deployment
 metadata:
@@ -26,6 +27,9 @@ spec:
         {{- range $k, $v := .Spec.Annotations }}
         {{$k}}: "{{$v}}"
         {{- end }}
+        {{- range $k, $v := $pod.PodAnnotations }}
+        {{$k}}: "{{$v}}"
+        {{- end }}
       labels:
         app: {{.Spec.Dbs.Pg.SvcName }}
         cnvrg-component: pg
@@ -34,31 +38,51 @@ spec:
         {{- end }}
     spec:
       priorityClassName: {{ .Spec.CnvrgAppPriorityClass.Name }}
-      {{- if isTrue .Spec.Tenancy.Enabled }}
+      {{- if .Spec.Tenancy.Enabled }}
       nodeSelector:
         {{ .Spec.Tenancy.Key }}: {{ .Spec.Tenancy.Value }}
         {{- range $key, $val := .Spec.Dbs.Pg.NodeSelector }}
         {{ $key }}: {{ $val }}
         {{- end }}
-      tolerations:
-        - operator: "Exists"
       {{- else if (gt (len .Spec.Dbs.Pg.NodeSelector) 0) }}
       nodeSelector:
         {{- range $key, $val := .Spec.Dbs.Pg.NodeSelector }}
         {{ $key }}: {{ $val }}
         {{- end }}
       {{- end }}
+      {{- with $pod.Affinity }}
+      affinity: {{ toJson . }}
+      {{- end }}
+      {{- with $pod.TopologySpreadConstraints }}
+      topologySpreadConstraints: {{ toJson . }}
+      {{- end }}
+      {{- if $pod.Tolerations }}
+      tolerations: {{ toJson $pod.Tolerations }}
+      {{- else if .Spec.Tenancy.Enabled }}
+      tolerations:
+        - operator: "Exists"
+      {{- end }}
       serviceAccountName: {{ .Spec.Dbs.Pg.ServiceAccount }}
+      {{- if $pod.SecurityContext }}
+      securityContext: {{ toJson $pod.SecurityContext }}
+      {{- else }}
       securityContext:
         runAsUser: 26
         fsGroup: 26
+      {{- end }}
       containers:
         - name: postgresql
           envFrom:
             - secretRef:
                 name: {{ .Spec.Dbs.Pg.CredsRef }}
+            {{- range $pod.EnvFrom }}
+            - {{ toJson . }}
+            {{- end }}
+          {{- with $pod.Env }}
+          env: {{ toJson . }}
+          {{- end }}
           image: {{ image .Spec.ImageHub .Spec.Dbs.Pg.Image}}
-          imagePullPolicy: IfNotPresent
+          imagePullPolicy: {{ $pod.ImagePullPolicy | default "IfNotPresent" }}
           ports:
             - containerPort: {{.Spec.Dbs.Pg.Port}}
               protocol: TCP
@@ -84,15 +108,18 @@ spec:
               name: postgres-data
             - mountPath: /dev/shm
               name: dshm
-            {{- if isTrue .Spec.Dbs.Pg.HugePages.Enabled }}
+            {{- if .Spec.Dbs.Pg.HugePages.Enabled }}
             - mountPath: "/hugepages"
               name: "hugepage"
             {{- end }}
+            {{- range $pod.VolumeMounts }}
+            - {{ toJson . }}
+            {{- end }}
           resources:
             limits:
               cpu: {{ .Spec.Dbs.Pg.Limits.Cpu }}
               memory: {{ .Spec.Dbs.Pg.Limits.Memory }}
-              {{- if isTrue .Spec.Dbs.Pg.HugePages.Enabled }}
+              {{- if .Spec.Dbs.Pg.HugePages.Enabled }}
               {{- if eq .Spec.Dbs.Pg.HugePages.Memory ""}}
               hugepages-{{ .Spec.Dbs.Pg.HugePages.Size }}: {{ .Spec.Dbs.Pg.Requests.Memory }}
               {{- else }}
@@ -110,8 +137,11 @@ spec:
           emptyDir:
             medium: Memory
             sizeLimit: 2Gi
-        {{- if isTrue .Spec.Dbs.Pg.HugePages.Enabled }}
+        {{- if .Spec.Dbs.Pg.HugePages.Enabled }}
         - name: "hugepage"
           emptyDir:
             medium: HugePages
         {{- end}}
+        {{- range $pod.Volumes }}
+        - {{ toJson . }}
+        {{- end }}
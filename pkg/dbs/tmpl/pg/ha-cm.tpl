@@ -0,0 +1,30 @@
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Spec.Dbs.Pg.SvcName }}-post-init
+  namespace: {{ ns . }}
+  annotations:
+    {{- range $k, $v := .Spec.Annotations }}
+    {{$k}}: "{{$v}}"
+    {{- end }}
+  labels:
+    {{- range $k, $v := .Spec.Labels }}
+    {{$k}}: "{{$v}}"
+    {{- end }}
+data:
+  # patroni runs the script once on the primary after the cluster bootstrap, with the superuser connection url as argument
+  post-init.sh: |
+    #!/bin/bash
+    set -e
+    psql -v ON_ERROR_STOP=1 -d "$1" <<SQL
+    DO \$\$
+    BEGIN
+      IF NOT EXISTS (SELECT FROM pg_roles WHERE rolname = '${POSTGRESQL_USER}') THEN
+        CREATE ROLE "${POSTGRESQL_USER}" LOGIN PASSWORD '${POSTGRESQL_PASSWORD}';
+      END IF;
+    END
+    \$\$;
+    SQL
+    if [ -z "$(psql -d "$1" -tAc "SELECT 1 FROM pg_database WHERE datname = '${POSTGRESQL_DATABASE}'")" ]; then
+      psql -v ON_ERROR_STOP=1 -d "$1" -c "CREATE DATABASE \"${POSTGRESQL_DATABASE}\" OWNER \"${POSTGRESQL_USER}\""
+    fi
\ No newline at end of file
@@ -0,0 +1,24 @@
+apiVersion: v1
+kind: Service
+metadata:
+  name: {{ .Spec.Dbs.Pg.SvcName }}-headless
+  namespace: {{ ns . }}
+  annotations:
+    {{- range $k, $v := .Spec.Annotations }}
+    {{$k}}: "{{$v}}"
+    {{- end }}
+  labels:
+    app: cnvrg-postgres
+    {{- range $k, $v := .Spec.Labels }}
+    {{$k}}: "{{$v}}"
+    {{- end }}
+spec:
+  clusterIP: None
+  publishNotReadyAddresses: true
+  ports:
+    - name: postgresql
+      port: {{ .Spec.Dbs.Pg.Port }}
+    - name: patroni
+      port: 8008
+  selector:
+    app: {{ .Spec.Dbs.Pg.SvcName }}
\ No newline at end of file
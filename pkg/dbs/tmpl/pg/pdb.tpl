@@ -1,4 +1,4 @@
-apiVersion: policy/v1beta1
+apiVersion: {{ apiVersion "PodDisruptionBudget" }}
 kind: PodDisruptionBudget
 metadata:
   name: pg
@@ -12,7 +12,11 @@ metadata:
     {{$k}}: "{{$v}}"
     {{- end }}
 spec:
+  {{- if .Spec.Dbs.Pg.HA.Enabled }}
+  maxUnavailable: 1
+  {{- else }}
   minAvailable: 1
+  {{- end }}
   selector:
     matchLabels:
       cnvrg-component: pg
\ No newline at end of file
@@ -10,4 +10,14 @@ metadata:
   labels:
     {{- range $k, $v := .Spec.Labels }}
     {{$k}}: "{{$v}}"
-    {{- end }}
\ No newline at end of file
+    {{- end }}
+{{- if .Spec.Dbs.Pg.HA.Enabled }}
+rules:
+  # patroni keeps the cluster state in config maps and labels the pods with their role
+  - apiGroups: [""]
+    resources: ["configmaps"]
+    verbs: ["create", "get", "list", "patch", "update", "watch", "delete", "deletecollection"]
+  - apiGroups: [""]
+    resources: ["pods"]
+    verbs: ["get", "list", "patch", "update", "watch"]
+{{- end }}
\ No newline at end of file
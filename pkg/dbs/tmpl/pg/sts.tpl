@@ -0,0 +1,206 @@
+{{- $pod := .Spec.Dbs.Pg.PodSpecOverrides }}
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: {{ .Spec.Dbs.Pg.SvcName }}
+  namespace: {{ ns . }}
+  annotations:
+    {{- range $k, $v := .Spec.Annotations }}
+    {{$k}}: "{{$v}}"
+    {{- end }}
+  labels:
+    app: {{ .Spec.Dbs.Pg.SvcName }}
+    cnvrg-component: pg
+    {{- range $k, $v := .Spec.Labels }}
+    {{$k}}: "{{$v}}"
+    {{- end }}
+spec:
+  serviceName: {{ .Spec.Dbs.Pg.SvcName }}-headless
+  replicas: {{ .Spec.Dbs.Pg.HA.Replicas }}
+  podManagementPolicy: Parallel
+  selector:
+    matchLabels:
+      app: {{ .Spec.Dbs.Pg.SvcName }}
+  template:
+    metadata:
+      annotations:
+        {{- range $k, $v := .Spec.Annotations }}
+        {{$k}}: "{{$v}}"
+        {{- end }}
+        {{- range $k, $v := $pod.PodAnnotations }}
+        {{$k}}: "{{$v}}"
+        {{- end }}
+      labels:
+        app: {{ .Spec.Dbs.Pg.SvcName }}
+        cnvrg-component: pg
+        cluster-name: {{ .Spec.Dbs.Pg.SvcName }}
+        {{- range $k, $v := .Spec.Labels }}
+        {{$k}}: "{{$v}}"
+        {{- end }}
+    spec:
+      priorityClassName: {{ .Spec.CnvrgAppPriorityClass.Name }}
+      {{- if .Spec.Tenancy.Enabled }}
+      nodeSelector:
+        {{ .Spec.Tenancy.Key }}: {{ .Spec.Tenancy.Value }}
+        {{- range $key, $val := .Spec.Dbs.Pg.NodeSelector }}
+        {{ $key }}: {{ $val }}
+        {{- end }}
+      {{- else if (gt (len .Spec.Dbs.Pg.NodeSelector) 0) }}
+      nodeSelector:
+        {{- range $key, $val := .Spec.Dbs.Pg.NodeSelector }}
+        {{ $key }}: {{ $val }}
+        {{- end }}
+      {{- end }}
+      {{- if $pod.Affinity }}
+      affinity: {{ toJson $pod.Affinity }}
+      {{- else }}
+      # spread the primary and the replicas, a node failure should take down a single member
+      affinity:
+        podAntiAffinity:
+          preferredDuringSchedulingIgnoredDuringExecution:
+            - weight: 100
+              podAffinityTerm:
+                topologyKey: kubernetes.io/hostname
+                labelSelector:
+                  matchLabels:
+                    app: {{ .Spec.Dbs.Pg.SvcName }}
+      {{- end }}
+      {{- with $pod.TopologySpreadConstraints }}
+      topologySpreadConstraints: {{ toJson . }}
+      {{- end }}
+      {{- if $pod.Tolerations }}
+      tolerations: {{ toJson $pod.Tolerations }}
+      {{- else if .Spec.Tenancy.Enabled }}
+      tolerations:
+        - operator: "Exists"
+      {{- end }}
+      serviceAccountName: {{ .Spec.Dbs.Pg.ServiceAccount }}
+      {{- if $pod.SecurityContext }}
+      securityContext: {{ toJson $pod.SecurityContext }}
+      {{- else }}
+      securityContext:
+        runAsUser: 101
+        fsGroup: 103
+      {{- end }}
+      containers:
+        - name: postgresql
+          envFrom:
+            - secretRef:
+                name: {{ .Spec.Dbs.Pg.CredsRef }}
+            {{- range $pod.EnvFrom }}
+            - {{ toJson . }}
+            {{- end }}
+          env:
+            - name: SCOPE
+              value: {{ .Spec.Dbs.Pg.SvcName }}
+            - name: PGPORT
+              value: "{{ .Spec.Dbs.Pg.Port }}"
+            - name: PGROOT
+              value: /home/postgres/pgdata/pgroot
+            - name: POD_IP
+              valueFrom:
+                fieldRef:
+                  fieldPath: status.podIP
+            - name: POD_NAMESPACE
+              valueFrom:
+                fieldRef:
+                  fieldPath: metadata.namespace
+            - name: DCS_ENABLE_KUBERNETES_API
+              value: "true"
+            - name: KUBERNETES_USE_CONFIGMAPS
+              value: "true"
+            - name: KUBERNETES_SCOPE_LABEL
+              value: cluster-name
+            - name: KUBERNETES_ROLE_LABEL
+              value: spilo-role
+            - name: KUBERNETES_LABELS
+              value: '{"app": "{{ .Spec.Dbs.Pg.SvcName }}"}'
+            - name: PGUSER_SUPERUSER
+              value: postgres
+            - name: PGPASSWORD_SUPERUSER
+              valueFrom:
+                secretKeyRef:
+                  name: {{ .Spec.Dbs.Pg.CredsRef }}
+                  key: POSTGRESQL_ADMIN_PASSWORD
+            - name: PGUSER_STANDBY
+              value: standby
+            - name: PGPASSWORD_STANDBY
+              valueFrom:
+                secretKeyRef:
+                  name: {{ .Spec.Dbs.Pg.CredsRef }}
+                  key: POSTGRESQL_ADMIN_PASSWORD
+            - name: ALLOW_NOSSL
+              value: "true"
+            - name: SPILO_CONFIGURATION
+              value: |
+                bootstrap:
+                  post_init: /scripts/post-init.sh
+                  dcs:
+                    postgresql:
+                      parameters:
+                        max_connections: {{ .Spec.Dbs.Pg.MaxConnections }}
+                        shared_buffers: {{ .Spec.Dbs.Pg.SharedBuffers }}
+                        effective_cache_size: {{ .Spec.Dbs.Pg.EffectiveCacheSize }}
+            {{- range $pod.Env }}
+            - {{ toJson . }}
+            {{- end }}
+          image: {{ image .Spec.ImageHub .Spec.Dbs.Pg.HA.Image }}
+          imagePullPolicy: {{ $pod.ImagePullPolicy | default "IfNotPresent" }}
+          ports:
+            - name: postgresql
+              containerPort: {{ .Spec.Dbs.Pg.Port }}
+              protocol: TCP
+            - name: patroni
+              containerPort: 8008
+              protocol: TCP
+          # patroni answers 200 on the primary and on the replicas which are streaming from it
+          readinessProbe:
+            httpGet:
+              path: /readiness
+              port: 8008
+            initialDelaySeconds: 5
+            periodSeconds: 10
+            timeoutSeconds: 5
+            failureThreshold: 3
+          terminationMessagePath: /dev/termination-log
+          volumeMounts:
+            - mountPath: /home/postgres/pgdata
+              name: pgdata
+            - mountPath: /scripts
+              name: post-init
+            - mountPath: /dev/shm
+              name: dshm
+            {{- range $pod.VolumeMounts }}
+            - {{ toJson . }}
+            {{- end }}
+          resources:
+            limits:
+              cpu: {{ .Spec.Dbs.Pg.Limits.Cpu }}
+              memory: {{ .Spec.Dbs.Pg.Limits.Memory }}
+            requests:
+              cpu: {{ .Spec.Dbs.Pg.Requests.Cpu }}
+              memory: {{ .Spec.Dbs.Pg.Requests.Memory }}
+      terminationGracePeriodSeconds: 30
+      volumes:
+        - name: post-init
+          configMap:
+            name: {{ .Spec.Dbs.Pg.SvcName }}-post-init
+            defaultMode: 0755
+        - name: dshm
+          emptyDir:
+            medium: Memory
+            sizeLimit: 2Gi
+        {{- range $pod.Volumes }}
+        - {{ toJson . }}
+        {{- end }}
+  volumeClaimTemplates:
+    - metadata:
+        name: pgdata
+      spec:
+        accessModes: [ ReadWriteOnce ]
+        resources:
+          requests:
+            storage: {{ .Spec.Dbs.Pg.StorageSize }}
+        {{- if ne .Spec.Dbs.Pg.StorageClass "" }}
+        storageClassName: {{ .Spec.Dbs.Pg.StorageClass }}
+        {{- end }}
\ No newline at end of file
@@ -16,4 +16,7 @@ spec:
   ports:
     - port: {{ .Spec.Dbs.Pg.Port }}
   selector:
-    app: {{ .Spec.Dbs.Pg.SvcName }}
\ No newline at end of file
+    app: {{ .Spec.Dbs.Pg.SvcName }}
+    {{- if .Spec.Dbs.Pg.HA.Enabled }}
+    spilo-role: master
+    {{- end }}
\ No newline at end of file
@@ -0,0 +1,102 @@
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: {{ .Data.Name }}
+  namespace: {{ .Data.Namespace }}
+  annotations:
+    {{- range $k, $v := .Data.Annotations }}
+    {{$k}}: "{{$v}}"
+    {{- end }}
+  labels:
+    app: {{ .Data.Name }}
+    cnvrg-component: pg-upgrade
+    {{- range $k, $v := .Data.Labels }}
+    {{$k}}: "{{$v}}"
+    {{- end }}
+spec:
+  backoffLimit: 0
+  template:
+    metadata:
+      annotations:
+        {{- range $k, $v := .Data.Annotations }}
+        {{$k}}: "{{$v}}"
+        {{- end }}
+      labels:
+        app: {{ .Data.Name }}
+        cnvrg-component: pg-upgrade
+        {{- range $k, $v := .Data.Labels }}
+        {{$k}}: "{{$v}}"
+        {{- end }}
+    spec:
+      restartPolicy: Never
+      serviceAccountName: {{ .Data.ServiceAccount }}
+      {{- if gt (len .Data.NodeSelector) 0 }}
+      nodeSelector:
+        {{- range $key, $val := .Data.NodeSelector }}
+        {{ $key }}: {{ $val }}
+        {{- end }}
+      {{- end }}
+      {{- if .Data.Tenancy }}
+      tolerations:
+        - operator: "Exists"
+      {{- end }}
+      securityContext:
+        runAsUser: 26
+        fsGroup: 26
+      containers:
+        - name: upgrade
+          image: {{ .Data.Image }}
+          imagePullPolicy: IfNotPresent
+          envFrom:
+            - secretRef:
+                name: {{ .Data.CredsRef }}
+          env:
+            - name: PREVIOUS_HOST
+              value: {{ .Data.PreviousHost | quote }}
+            - name: PREVIOUS_PORT
+              value: {{ .Data.Port | quote }}
+          command:
+            - /bin/bash
+            - -c
+          args:
+            - |
+              set -euo pipefail
+              # the data is restored into a fresh cluster on the new version pvc, a retry starts from scratch
+              export PGDATA=/var/lib/pgsql/data/userdata
+              rm -rf "$PGDATA" /var/lib/pgsql/data/upgrade
+              mkdir -p /var/lib/pgsql/data/upgrade
+              initdb --username=postgres --pgdata="$PGDATA"
+              # same layout as the image entrypoint creates, so the pg deployment starts on the restored data
+              echo "include '${POSTGRESQL_CONFIG_FILE:-/var/lib/pgsql/openshift-custom-postgresql.conf}'" >> "$PGDATA/postgresql.conf"
+              echo "host all all all md5" >> "$PGDATA/pg_hba.conf"
+              pg_ctl --pgdata="$PGDATA" -o "-c listen_addresses='' -c unix_socket_directories=/tmp" -w start
+
+              echo "dumping ${PREVIOUS_HOST}:${PREVIOUS_PORT}"
+              export PGPASSWORD="$POSTGRESQL_ADMIN_PASSWORD"
+              pg_dumpall -h "$PREVIOUS_HOST" -p "$PREVIOUS_PORT" -U postgres -f /var/lib/pgsql/data/upgrade/dump.sql
+              echo "restoring"
+              # the postgres role already exists in the new cluster, hence the errors are checked by the verification
+              psql -h /tmp -U postgres -d postgres -q -f /var/lib/pgsql/data/upgrade/dump.sql > /var/lib/pgsql/data/upgrade/restore.log 2>&1 || true
+
+              echo "verifying"
+              query="select count(*) from information_schema.tables where table_schema not in ('pg_catalog', 'information_schema')"
+              dbs=$(psql -h "$PREVIOUS_HOST" -p "$PREVIOUS_PORT" -U postgres -d postgres -At -c "select datname from pg_database where datallowconn and datname <> 'template1'")
+              for db in $dbs; do
+                previous=$(psql -h "$PREVIOUS_HOST" -p "$PREVIOUS_PORT" -U postgres -d "$db" -At -c "$query")
+                current=$(psql -h /tmp -U postgres -d "$db" -At -c "$query")
+                if [ "$previous" != "$current" ]; then
+                  echo "verification failed, database $db has $previous tables, restored $current"
+                  tail -n 50 /var/lib/pgsql/data/upgrade/restore.log
+                  exit 1
+                fi
+                echo "database $db verified, $current tables"
+              done
+              pg_ctl --pgdata="$PGDATA" -w stop
+              echo "upgrade done"
+          volumeMounts:
+            - mountPath: /var/lib/pgsql/data
+              name: postgres-data
+      volumes:
+        - name: postgres-data
+          persistentVolumeClaim:
+            claimName: {{ .Data.PvcName }}
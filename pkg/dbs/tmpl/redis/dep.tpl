@@ -1,3 +1,4 @@
+{{- $pod := .Spec.Dbs.Redis.PodSpecOverrides }}
 apiVersion: apps/v1
 kind: Deployment
 metadata:
@@ -23,33 +24,59 @@ spec:
         {{- range $k, $v := .Spec.Annotations }}
         {{$k}}: "{{$v}}"
         {{- end }}
+        {{- range $k, $v := $pod.PodAnnotations }}
+        {{$k}}: "{{$v}}"
+        {{- end }}
       labels:
         app: {{.Spec.Dbs.Redis.SvcName }}
         owner: cnvrg-control-plane
         cnvrg-component: redis
     spec:
       priorityClassName: {{ .Spec.CnvrgAppPriorityClass.Name }}
-      {{- if isTrue .Spec.Tenancy.Enabled }}
+      {{- if .Spec.Tenancy.Enabled }}
       nodeSelector:
         {{ .Spec.Tenancy.Key }}: {{ .Spec.Tenancy.Value }}
         {{- range $key, $val := .Spec.Dbs.Redis.NodeSelector }}
         {{ $key }}: {{ $val }}
         {{- end }}
-      tolerations:
-        - operator: "Exists"
       {{- else if (gt (len .Spec.Dbs.Redis.NodeSelector) 0) }}
       nodeSelector:
         {{- range $key, $val := .Spec.Dbs.Redis.NodeSelector }}
         {{ $key }}: {{ $val }}
         {{- end }}
       {{- end }}
+      {{- with $pod.Affinity }}
+      affinity: {{ toJson . }}
+      {{- end }}
+      {{- with $pod.TopologySpreadConstraints }}
+      topologySpreadConstraints: {{ toJson . }}
+      {{- end }}
+      {{- if $pod.Tolerations }}
+      tolerations: {{ toJson $pod.Tolerations }}
+      {{- else if .Spec.Tenancy.Enabled }}
+      tolerations:
+        - operator: "Exists"
+      {{- end }}
       serviceAccountName: {{ .Spec.Dbs.Redis.ServiceAccount }}
+      {{- if $pod.SecurityContext }}
+      securityContext: {{ toJson $pod.SecurityContext }}
+      {{- else }}
       securityContext:
         runAsUser: 1000
         fsGroup: 1000
+      {{- end }}
       containers:
         - image: {{ image .Spec.ImageHub .Spec.Dbs.Redis.Image }}
           name: redis
+          {{- with $pod.ImagePullPolicy }}
+          imagePullPolicy: {{ . }}
+          {{- end }}
+          {{- with $pod.Env }}
+          env: {{ toJson . }}
+          {{- end }}
+          {{- with $pod.EnvFrom }}
+          envFrom: {{ toJson . }}
+          {{- end }}
           command: [ "/bin/bash", "-lc", "redis-server /config/redis.conf" ]
           ports:
             - containerPort: {{ .Spec.Dbs.Redis.Port }}
@@ -65,6 +92,9 @@ spec:
               mountPath: /data
             - name: redis-config
               mountPath: /config
+            {{- range $pod.VolumeMounts }}
+            - {{ toJson . }}
+            {{- end }}
       volumes:
         - name: redis-data
           persistentVolumeClaim:
@@ -72,3 +102,6 @@ spec:
         - name: redis-config
           secret:
             secretName: {{ .Spec.Dbs.Redis.CredsRef }}
+        {{- range $pod.Volumes }}
+        - {{ toJson . }}
+        {{- end }}
@@ -0,0 +1,176 @@
+package desired
+
+import (
+	"context"
+	"github.com/go-logr/logr"
+	"github.com/pmezard/go-difflib/difflib"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+type DiffAction string
+
+const (
+	// DiffCreate object doesn't exist and will be created
+	DiffCreate DiffAction = "create"
+	// DiffUpdate object exists and will be updated
+	DiffUpdate DiffAction = "update"
+	// DiffSkip object exists, but it is not updatable, hence it won't be changed
+	DiffSkip DiffAction = "skip"
+	// DiffNone object exists and it is up to date
+	DiffNone DiffAction = "none"
+)
+
+// ObjectDiff describes what the apply will do with a single object
+type ObjectDiff struct {
+	Action    DiffAction
+	GVK       schema.GroupVersionKind
+	Namespace string
+	Name      string
+	// Diff is unified diff between the live and the desired object
+	Diff string
+}
+
+// Diff renders the desired manifests and compares them with the live objects.
+// The desired object is calculated by server side apply dry run,
+// hence the diff includes only the changes the apply would make.
+func Diff(ctx context.Context, desiredManifests []*State, desiredSpec v1.Object, c client.Client, schema *runtime.Scheme, log logr.Logger) ([]ObjectDiff, error) {
+	var diffs []ObjectDiff
+	for _, manifest := range desiredManifests {
+
+		if err := manifest.render(ctx, desiredSpec, log); err != nil {
+			return nil, err
+		}
+
+		// owner uid is empty when the spec still doesn't exist in the cluster
+		if manifest.Own && desiredSpec.GetUID() != "" {
+			if err := ctrl.SetControllerReference(desiredSpec, manifest.Obj, schema); err != nil {
+				log.Error(err, "error setting controller reference", "name", manifest.Obj.GetName())
+				return nil, err
+			}
+		}
+
+		if err := manifest.setHash(); err != nil {
+			log.Error(err, "error hashing manifest", "name", manifest.Obj.GetName())
+			return nil, err
+		}
+
+		d := ObjectDiff{GVK: manifest.GVK, Namespace: manifest.Obj.GetNamespace(), Name: manifest.Obj.GetName()}
+
+		actualObject, err := manifest.live(ctx, c, log)
+		if err != nil {
+			return nil, err
+		}
+
+		if actualObject == nil {
+			d.Action = DiffCreate
+			if d.Diff, err = unifiedDiff(nil, manifest.Obj, manifest.Obj.GetName()); err != nil {
+				return nil, err
+			}
+			diffs = append(diffs, d)
+			continue
+		}
+
+		if !manifest.Updatable {
+			d.Action = DiffSkip
+			diffs = append(diffs, d)
+			continue
+		}
+
+		appliedObject := manifest.Obj.DeepCopy()
+		opts := []client.PatchOption{client.FieldOwner(FieldManager), client.DryRunAll}
+		if manifest.ConflictPolicy.resolve() == ConflictForce {
+			opts = append(opts, client.ForceOwnership)
+		}
+		err = c.Patch(ctx, appliedObject, client.Apply, opts...)
+		if err != nil && errors.IsConflict(err) && manifest.ConflictPolicy.resolve() == ConflictSkip {
+			d.Action = DiffSkip
+			diffs = append(diffs, d)
+			continue
+		}
+		if err != nil {
+			log.Error(err, "error applying object (dry run)", "name", manifest.Obj.GetName(), "kind", manifest.GVK.Kind)
+			return nil, err
+		}
+		if d.Diff, err = unifiedDiff(actualObject, appliedObject, manifest.Obj.GetName()); err != nil {
+			return nil, err
+		}
+		d.Action = DiffUpdate
+		if d.Diff == "" {
+			d.Action = DiffNone
+		}
+		diffs = append(diffs, d)
+	}
+	return diffs, nil
+}
+
+func unifiedDiff(live, desired *unstructured.Unstructured, name string) (string, error) {
+	live, desired = cleanForDiff(live), cleanForDiff(desired)
+	if desired.GetKind() == Kinds[SecretGVK].Kind {
+		maskSecretData(live, desired)
+	}
+	var a, b []byte
+	var err error
+	if live != nil {
+		if a, err = yaml.Marshal(live.Object); err != nil {
+			return "", err
+		}
+	}
+	if b, err = yaml.Marshal(desired.Object); err != nil {
+		return "", err
+	}
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(a)),
+		B:        difflib.SplitLines(string(b)),
+		FromFile: "live/" + name,
+		ToFile:   "desired/" + name,
+		Context:  3,
+	})
+}
+
+// cleanForDiff removes fields which are managed by the api server
+func cleanForDiff(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	if obj == nil {
+		return nil
+	}
+	obj = obj.DeepCopy()
+	for _, f := range []string{"managedFields", "resourceVersion", "uid", "generation", "creationTimestamp", "selfLink"} {
+		unstructured.RemoveNestedField(obj.Object, "metadata", f)
+	}
+	unstructured.RemoveNestedField(obj.Object, "status")
+	return obj
+}
+
+// maskSecretData hides secret values, changed values are marked
+func maskSecretData(live, desired *unstructured.Unstructured) {
+	desiredData, _, _ := unstructured.NestedStringMap(desired.Object, "data")
+	liveData := map[string]string{}
+	if live != nil {
+		liveData, _, _ = unstructured.NestedStringMap(live.Object, "data")
+	}
+	for k, v := range liveData {
+		if dv, ok := desiredData[k]; ok && dv == v {
+			liveData[k] = "***"
+			desiredData[k] = "***"
+		} else {
+			liveData[k] = "*** (before)"
+		}
+	}
+	for k, v := range desiredData {
+		if v != "***" {
+			desiredData[k] = "*** (after)"
+		}
+	}
+	if len(liveData) > 0 {
+		_ = unstructured.SetNestedStringMap(live.Object, liveData, "data")
+	}
+	if len(desiredData) > 0 {
+		_ = unstructured.SetNestedStringMap(desired.Object, desiredData, "data")
+	}
+}
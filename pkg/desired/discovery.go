@@ -0,0 +1,92 @@
+package desired
+
+import (
+	"fmt"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"strings"
+)
+
+// Versions lists the supported versions of the kinds which are served in different versions
+// by different clusters, ordered by preference
+var Versions = map[GVKName][]string{
+	PodDisruptionBudgetGVK:  {"v1", "v1beta1"},
+	HpaGVK:                  {"v2", "v2beta2"},
+	IstioVsGVK:              {"v1beta1", "v1alpha3"},
+	IstioDestinationRuleGVK: {"v1beta1", "v1alpha3"},
+	IstioGwGVK:              {"v1beta1", "v1alpha3"},
+	CronJobGVK:              {"v1", "v1beta1"},
+}
+
+// Discover sets Kinds to the most preferred version served by the cluster,
+// kinds which are not served keep the default version, unless they are required.
+// Must be called at startup, before the Kinds are used for watches and templates
+func Discover(dc discovery.DiscoveryInterface, required []GVKName, log logr.Logger) error {
+	isRequired := map[GVKName]bool{}
+	for _, name := range required {
+		isRequired[name] = true
+	}
+
+	for name, gvk := range Kinds {
+		versions, ok := Versions[name]
+		if !ok {
+			// optional kinds with a single version don't have to be discovered
+			if !isRequired[name] {
+				continue
+			}
+			versions = []string{gvk.Version}
+		}
+		served := ""
+		for _, v := range versions {
+			candidate := schema.GroupVersionKind{Group: gvk.Group, Version: v, Kind: gvk.Kind}
+			isServed, err := serves(dc, candidate)
+			if err != nil {
+				return err
+			}
+			if isServed {
+				served = v
+				break
+			}
+		}
+		if served == "" {
+			if isRequired[name] {
+				return fmt.Errorf("required API %s is not served by the cluster, supported versions: %s/{%s}",
+					gvk.Kind, gvk.Group, strings.Join(versions, ","))
+			}
+			log.Info("API is not served by the cluster, using the default version", "kind", gvk.Kind, "version", gvk.GroupVersion().String())
+			continue
+		}
+		gvk.Version = served
+		Kinds[name] = gvk
+		log.V(1).Info("API discovered", "kind", gvk.Kind, "version", gvk.GroupVersion().String())
+	}
+	return nil
+}
+
+func serves(dc discovery.DiscoveryInterface, gvk schema.GroupVersionKind) (bool, error) {
+	resources, err := dc.ServerResourcesForGroupVersion(gvk.GroupVersion().String())
+	if err != nil && errors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	for _, r := range resources.APIResources {
+		if r.Kind == gvk.Kind {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// APIVersion returns the apiVersion of the kind, used by the templates
+// for rendering the kinds which are served in different versions
+func APIVersion(kind string) string {
+	for _, gvk := range Kinds {
+		if gvk.Kind == kind {
+			return gvk.GroupVersion().String()
+		}
+	}
+	return ""
+}
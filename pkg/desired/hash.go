@@ -0,0 +1,127 @@
+package desired
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"reflect"
+)
+
+// AppliedHashAnnotation holds the hash of the rendered object the operator applied last time
+const AppliedHashAnnotation = "mlops.cnvrg.io/applied-hash"
+
+// setHash annotates the rendered object with the hash of its content,
+// the hash covers the template output, user overrides and the owner reference
+func (s *State) setHash() error {
+	annotations := s.Obj.GetAnnotations()
+	delete(annotations, AppliedHashAnnotation)
+	s.Obj.SetAnnotations(annotations)
+
+	b, err := json.Marshal(s.Obj.Object)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(b)
+
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[AppliedHashAnnotation] = hex.EncodeToString(sum[:])
+	s.Obj.SetAnnotations(annotations)
+	return nil
+}
+
+// unchanged reports if the live object was applied from the same rendered object,
+// and none of the rendered fields has been changed since then
+func (s *State) unchanged(live *unstructured.Unstructured) bool {
+	hash := s.Obj.GetAnnotations()[AppliedHashAnnotation]
+	if hash == "" || live.GetAnnotations()[AppliedHashAnnotation] != hash {
+		return false
+	}
+	return isSubset(s.Obj.Object, live.Object)
+}
+
+// isSubset checks all the desired fields are present in the live object with the same values,
+// fields added by the api server (defaults, status, etc.) are ignored
+func isSubset(desired, live interface{}) bool {
+	switch d := desired.(type) {
+	case map[string]interface{}:
+		l, ok := live.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for k, v := range d {
+			lv, ok := l[k]
+			if !ok {
+				// empty desired values are dropped by the api server
+				if isEmpty(v) {
+					continue
+				}
+				return false
+			}
+			if !isSubset(v, lv) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		l, ok := live.([]interface{})
+		if !ok || len(d) != len(l) {
+			return false
+		}
+		for i := range d {
+			if !isSubset(d[i], l[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		if reflect.DeepEqual(desired, live) {
+			return true
+		}
+		// quantities are normalized by the api server (2000m -> 2, 1024Mi -> 1Gi)
+		return equalQuantities(desired, live)
+	}
+}
+
+func equalQuantities(desired, live interface{}) bool {
+	d, ok := quantity(desired)
+	if !ok {
+		return false
+	}
+	l, ok := quantity(live)
+	return ok && d.Cmp(l) == 0
+}
+
+// quantity parses quantity-like scalars, numbers are rendered unquoted by the templates (cpu: 2)
+func quantity(v interface{}) (resource.Quantity, bool) {
+	var s string
+	switch t := v.(type) {
+	case string:
+		s = t
+	case int64, float64:
+		s = fmt.Sprint(t)
+	default:
+		return resource.Quantity{}, false
+	}
+	q, err := resource.ParseQuantity(s)
+	return q, err == nil
+}
+
+func isEmpty(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	switch t := v.(type) {
+	case map[string]interface{}:
+		return len(t) == 0
+	case []interface{}:
+		return len(t) == 0
+	case string:
+		return t == ""
+	}
+	return false
+}
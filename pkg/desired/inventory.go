@@ -0,0 +1,176 @@
+package desired
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/go-logr/logr"
+	"github.com/spf13/viper"
+	v1core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sort"
+	"sync"
+)
+
+const inventoryKey = "inventory"
+
+// InventoryEntry identifies a single object applied on behalf of CnvrgApp/CnvrgInfra
+type InventoryEntry struct {
+	Group     string `json:"group,omitempty"`
+	Version   string `json:"version"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+func (e InventoryEntry) key() string {
+	return fmt.Sprintf("%s/%s/%s/%s", e.Group, e.Kind, e.Namespace, e.Name)
+}
+
+func (e InventoryEntry) gvk() schema.GroupVersionKind {
+	return schema.GroupVersionKind{Group: e.Group, Version: e.Version, Kind: e.Kind}
+}
+
+// Inventory is a set of owned objects applied during a single reconcile
+type Inventory struct {
+	mu      sync.Mutex
+	entries map[string]InventoryEntry
+}
+
+func NewInventory() *Inventory {
+	return &Inventory{entries: map[string]InventoryEntry{}}
+}
+
+// Add records owned states, states must be already rendered
+func (i *Inventory) Add(states []*State) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	for _, s := range states {
+		if !s.Own || s.Obj == nil || s.Obj.GetName() == "" {
+			continue
+		}
+		e := InventoryEntry{
+			Group:     s.GVK.Group,
+			Version:   s.GVK.Version,
+			Kind:      s.GVK.Kind,
+			Namespace: s.Obj.GetNamespace(),
+			Name:      s.Obj.GetName(),
+		}
+		i.entries[e.key()] = e
+	}
+}
+
+func (i *Inventory) addEntry(e InventoryEntry) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.entries[e.key()] = e
+}
+
+func (i *Inventory) Contains(e InventoryEntry) bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	_, ok := i.entries[e.key()]
+	return ok
+}
+
+func (i *Inventory) Entries() []InventoryEntry {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	var keys []string
+	for k := range i.entries {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	entries := make([]InventoryEntry, 0, len(keys))
+	for _, k := range keys {
+		entries = append(entries, i.entries[k])
+	}
+	return entries
+}
+
+// LoadInventory reads the inventory of the previous successful reconcile,
+// missing inventory configmap results in an empty inventory
+func LoadInventory(ctx context.Context, name types.NamespacedName, c client.Client) (*Inventory, error) {
+	inventory := NewInventory()
+	cm := &v1core.ConfigMap{}
+	if err := c.Get(ctx, name, cm); err != nil && errors.IsNotFound(err) {
+		return inventory, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var entries []InventoryEntry
+	if data, ok := cm.Data[inventoryKey]; ok {
+		if err := json.Unmarshal([]byte(data), &entries); err != nil {
+			return nil, err
+		}
+	}
+	for _, e := range entries {
+		inventory.entries[e.key()] = e
+	}
+	return inventory, nil
+}
+
+// Save stores the inventory in configmap owned by the owner object
+func (i *Inventory) Save(ctx context.Context, name types.NamespacedName, owner v1.Object, c client.Client, schema *runtime.Scheme) error {
+	data, err := json.Marshal(i.Entries())
+	if err != nil {
+		return err
+	}
+	cm := &v1core.ConfigMap{
+		TypeMeta:   v1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: v1.ObjectMeta{Name: name.Name, Namespace: name.Namespace},
+		Data:       map[string]string{inventoryKey: string(data)},
+	}
+	if err := ctrl.SetControllerReference(owner, cm, schema); err != nil {
+		return err
+	}
+	return c.Patch(ctx, cm, client.Apply, client.FieldOwner(FieldManager), client.ForceOwnership)
+}
+
+// Prune deletes objects that were applied during the previous reconcile,
+// but are not desired anymore (e.g. component has been disabled).
+// Only objects controlled by the owner are deleted, PVCs are deleted only when prune-pvc is set,
+// skipped PVCs are kept in the current inventory, so they are pruned once prune-pvc is set
+func Prune(ctx context.Context, previous, current *Inventory, owner v1.Object, c client.Client, log logr.Logger) error {
+	for _, e := range previous.Entries() {
+		if current.Contains(e) {
+			continue
+		}
+		if e.Kind == Kinds[PvcGVK].Kind && !viper.GetBool("prune-pvc") {
+			log.Info("prune-pvc is false, skipping pvc pruning", "name", e.Name, "namespace", e.Namespace)
+			current.addEntry(e)
+			continue
+		}
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(e.gvk())
+		err := c.Get(ctx, types.NamespacedName{Name: e.Name, Namespace: e.Namespace}, obj)
+		if err != nil && errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			log.Error(err, "error getting object for pruning", "name", e.Name, "kind", e.Kind)
+			return err
+		}
+		if !isControlledBy(obj, owner) {
+			log.Info("skipping pruning, object is not controlled by the spec", "name", e.Name, "kind", e.Kind)
+			continue
+		}
+		log.Info("pruning", "name", e.Name, "namespace", e.Namespace, "kind", e.Kind)
+		if err := c.Delete(ctx, obj, client.PropagationPolicy(v1.DeletePropagationBackground)); err != nil && !errors.IsNotFound(err) {
+			log.Error(err, "error pruning object", "name", e.Name, "kind", e.Kind)
+			return err
+		}
+	}
+	return nil
+}
+
+func isControlledBy(obj v1.Object, owner v1.Object) bool {
+	ref := v1.GetControllerOf(obj)
+	return ref != nil && ref.UID == owner.GetUID()
+}
@@ -32,6 +32,7 @@ const (
 	HpaGVK                  GVKName = "HpaGVK"
 	PriorityClassGVK        GVKName = "PriorityClassGVK"
 	JobGVK                  GVKName = "JobGvk"
+	CronJobGVK              GVKName = "CronJobGVK"
 )
 
 var Kinds = map[GVKName]schema.GroupVersionKind{
@@ -185,4 +186,9 @@ var Kinds = map[GVKName]schema.GroupVersionKind{
 		Group:   "batch",
 		Version: "v1",
 	},
+	CronJobGVK: schema.GroupVersionKind{
+		Kind:    "CronJob",
+		Group:   "batch",
+		Version: "v1beta1",
+	},
 }
@@ -0,0 +1,43 @@
+package desired
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+type applyAction string
+
+const (
+	actionCreated applyAction = "created"
+	actionUpdated applyAction = "updated"
+	actionSkipped applyAction = "skipped"
+)
+
+var appliedObjects = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "cnvrg_operator_applied_objects_total",
+		Help: "Number of rendered objects processed by apply, by GVK and action (created, updated, skipped)",
+	},
+	[]string{"group", "version", "kind", "action"},
+)
+
+var templateRenderFailures = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "cnvrg_operator_template_render_failures_total",
+		Help: "Number of templates which failed to be parsed, executed or decoded, by template path",
+	},
+	[]string{"template"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(appliedObjects, templateRenderFailures)
+}
+
+// applyStats counts the apply actions of a single Apply call
+type applyStats map[applyAction]int
+
+func (a applyStats) add(gvk schema.GroupVersionKind, action applyAction) {
+	a[action]++
+	appliedObjects.WithLabelValues(gvk.Group, gvk.Version, gvk.Kind, string(action)).Inc()
+}
@@ -0,0 +1,127 @@
+package desired
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	mlopsv1 "github.com/AccessibleAI/cnvrg-operator/api/v1"
+	jsonpatch "github.com/evanphx/json-patch"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sync"
+)
+
+// overridable is implemented by specs which carry user patches (CnvrgApp, CnvrgInfra)
+type overridable interface {
+	GetOverrides() []mlopsv1.Override
+}
+
+// OverrideMatches records the overrides which matched a rendered object,
+// the overrides which don't match any object are reported by the reconciler
+type OverrideMatches struct {
+	mu      sync.Mutex
+	matched map[string]bool
+}
+
+func NewOverrideMatches() *OverrideMatches {
+	return &OverrideMatches{matched: map[string]bool{}}
+}
+
+func overrideKey(o mlopsv1.Override) string {
+	return o.Kind + "/" + o.Name
+}
+
+func (m *OverrideMatches) add(o mlopsv1.Override) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.matched[overrideKey(o)] = true
+}
+
+// Unmatched returns the overrides which didn't match any of the rendered objects
+func (m *OverrideMatches) Unmatched(overrides []mlopsv1.Override) []mlopsv1.Override {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var unmatched []mlopsv1.Override
+	for _, o := range overrides {
+		if !m.matched[overrideKey(o)] {
+			unmatched = append(unmatched, o)
+		}
+	}
+	return unmatched
+}
+
+type overrideMatchesKey struct{}
+
+// WithOverrideMatches records the overrides matched by the manifests applied with the context
+func WithOverrideMatches(ctx context.Context, m *OverrideMatches) context.Context {
+	return context.WithValue(ctx, overrideMatchesKey{}, m)
+}
+
+func overrideMatches(ctx context.Context) *OverrideMatches {
+	m, _ := ctx.Value(overrideMatchesKey{}).(*OverrideMatches)
+	return m
+}
+
+// override applies user patches matching the rendered object kind and name
+func (s *State) override(ctx context.Context, desiredSpec interface{}) error {
+	spec, ok := desiredSpec.(overridable)
+	if !ok {
+		return nil
+	}
+	matches := overrideMatches(ctx)
+	for _, o := range spec.GetOverrides() {
+		if o.Kind != s.Obj.GetKind() || o.Name != s.Obj.GetName() {
+			continue
+		}
+		if matches != nil {
+			matches.add(o)
+		}
+		if err := s.applyOverride(o); err != nil {
+			return fmt.Errorf("failed to apply override %s/%s: %v", o.Kind, o.Name, err)
+		}
+	}
+	return nil
+}
+
+func (s *State) applyOverride(o mlopsv1.Override) error {
+	if err := o.Validate(); err != nil {
+		return err
+	}
+	patch, err := o.PatchJSON()
+	if err != nil {
+		return err
+	}
+	original, err := s.Obj.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	var patched []byte
+	switch o.GetType() {
+	case mlopsv1.JSONPatchType:
+		p, err := jsonpatch.DecodePatch(patch)
+		if err != nil {
+			return err
+		}
+		if patched, err = p.Apply(original); err != nil {
+			return err
+		}
+	default:
+		// strategic merge requires the go struct of the object,
+		// objects without it (CRDs, e.g. istio, prometheus) are patched with json merge patch
+		if typed, err := scheme.Scheme.New(s.GVK); err == nil {
+			if patched, err = strategicpatch.StrategicMergePatch(original, patch, typed); err != nil {
+				return err
+			}
+		} else if patched, err = jsonpatch.MergePatch(original, patch); err != nil {
+			return err
+		}
+	}
+
+	obj := map[string]interface{}{}
+	if err := json.Unmarshal(patched, &obj); err != nil {
+		return err
+	}
+	s.Obj.Object = obj
+	return nil
+}
@@ -3,19 +3,13 @@ package desired
 import (
 	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	mlopsv1 "github.com/AccessibleAI/cnvrg-operator/api/v1"
 	"github.com/Dimss/crypt/apr1_crypt"
-	"github.com/Masterminds/sprig"
 	"github.com/go-logr/logr"
 	"github.com/golang-jwt/jwt"
-	"github.com/imdario/mergo"
-	"github.com/markbates/pkger"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
-	"io/ioutil"
-	appv1 "k8s.io/api/apps/v1"
 	v1core "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -30,7 +24,6 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"strconv"
 	"strings"
-	"text/template"
 	"time"
 )
 
@@ -125,6 +118,9 @@ func cnvrgTemplateFuncs() map[string]interface{} {
 		"ns": func(obj interface{}) string {
 			return getNs(obj)
 		},
+		"apiVersion": func(kind string) string {
+			return APIVersion(kind)
+		},
 		"httpScheme": func(cnvrgApp mlopsv1.CnvrgApp) string {
 			if cnvrgApp.Spec.Networking.HTTPS.Enabled {
 				return "https://"
@@ -331,9 +327,6 @@ elasticsearch:
     Authorization: "Basic %s"
 `, host, port, esHost, esUser, esPass, esBasicAuth)
 		},
-		"isTrue": func(boolPointer bool) bool { // this is legacy function and should be removed in the future
-			return boolPointer
-		},
 		"promRetentionSize": func(retentionSize string) string {
 			size, err := strconv.Atoi(strings.TrimSuffix(retentionSize, "Gi"))
 			if err != nil {
@@ -341,6 +334,10 @@ elasticsearch:
 			}
 			return fmt.Sprintf("%dGB", size-2)
 		},
+		"backupSchedule": BackupSchedule,
+		"esSnapshotRepository": func() string {
+			return esSnapshotRepositoryScript
+		},
 		"image": func(imageHub string, imageName string) string {
 			if strings.Contains(imageName, "/") {
 				return imageName
@@ -379,29 +376,23 @@ timeout 15
 
 func (s *State) GenerateDeployable() error {
 	var tpl bytes.Buffer
-	f, err := pkger.Open(s.TemplatePath)
-	if err != nil {
-		zap.S().Error(err, "error reading path", "path", s.TemplatePath)
-		return err
-	}
-	b, err := ioutil.ReadAll(f)
-
-	if err != nil {
-		zap.S().Errorf("%v, error reading file: %v", err, s.TemplatePath)
-		return err
-	}
-
-	s.Template, err = template.New(strings.ReplaceAll(s.TemplatePath, "/", "-")).
-		Funcs(sprig.TxtFuncMap()).
-		Funcs(cnvrgTemplateFuncs()).
-		Parse(string(b))
+	var err error
+	s.Template, err = getTemplate(s.TemplatePath)
 	if err != nil {
-		zap.S().Errorf("%v, template: %v", err, s.TemplatePath)
+		zap.S().Error(err)
+		templateRenderFailures.WithLabelValues(templateKey(s.TemplatePath)).Inc()
 		return err
 	}
 	s.Obj.SetGroupVersionKind(s.GVK)
-	if err := s.Template.Execute(&tpl, s.TemplateData); err != nil {
+	execTemplate := s.Template
+	if s.PlaceholderCredentials {
+		if execTemplate, err = placeholderTemplate(s.Template); err != nil {
+			return err
+		}
+	}
+	if err := execTemplate.Execute(&tpl, s.TemplateData); err != nil {
 		zap.S().Error(err, "rendering template error", "file", s.TemplatePath)
+		templateRenderFailures.WithLabelValues(templateKey(s.TemplatePath)).Inc()
 		return err
 	}
 	s.ParsedTemplate = tpl.String()
@@ -410,6 +401,7 @@ func (s *State) GenerateDeployable() error {
 	dec := yaml.NewDecodingSerializer(unstructured.UnstructuredJSONScheme)
 	if _, _, err := dec.Decode([]byte(s.ParsedTemplate), nil, s.Obj); err != nil {
 		zap.S().Errorf("%v, template: %v", err, s.ParsedTemplate)
+		templateRenderFailures.WithLabelValues(templateKey(s.TemplatePath)).Inc()
 		return err
 	}
 	if err := s.dumpTemplateToFile(); err != nil {
@@ -419,17 +411,46 @@ func (s *State) GenerateDeployable() error {
 	return nil
 }
 
-func Apply(desiredManifests []*State, desiredSpec v1.Object, client client.Client, schema *runtime.Scheme, log logr.Logger) error {
-
-	ctx := context.Background()
+// Render generates the deployables without applying them,
+// states without template data are rendered with the desired spec,
+// user overrides of the desired spec are applied on the generated objects
+func Render(ctx context.Context, desiredManifests []*State, desiredSpec v1.Object, log logr.Logger) error {
 	for _, manifest := range desiredManifests {
+		if err := manifest.render(ctx, desiredSpec, log); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *State) render(ctx context.Context, desiredSpec v1.Object, log logr.Logger) error {
+	if s.TemplateData == nil {
+		s.TemplateData = desiredSpec
+	}
+	if err := s.GenerateDeployable(); err != nil {
+		log.Error(err, "error generating deployable", "name", s.Obj.GetName())
+		return err
+	}
+	if err := s.override(ctx, desiredSpec); err != nil {
+		log.Error(err, "error applying override", "name", s.Obj.GetName(), "kind", s.GVK.Kind)
+		return err
+	}
+	return nil
+}
 
-		if manifest.TemplateData == nil {
+// Apply renders and applies the desired manifests,
+// objects which are unchanged since the last apply are skipped
+func Apply(ctx context.Context, desiredManifests []*State, desiredSpec v1.Object, client client.Client, schema *runtime.Scheme, log logr.Logger) error {
 
-			manifest.TemplateData = desiredSpec
+	stats := applyStats{}
+	defer func() {
+		if len(stats) > 0 {
+			log.Info("manifests applied", "created", stats[actionCreated], "updated", stats[actionUpdated], "skipped", stats[actionSkipped])
 		}
-		if err := manifest.GenerateDeployable(); err != nil {
-			log.Error(err, "error generating deployable", "name", manifest.Obj.GetName())
+	}()
+	for _, manifest := range desiredManifests {
+
+		if err := manifest.render(ctx, desiredSpec, log); err != nil {
 			return err
 		}
 
@@ -440,176 +461,84 @@ func Apply(desiredManifests []*State, desiredSpec v1.Object, client client.Clien
 			}
 		}
 
+		if err := manifest.setHash(); err != nil {
+			log.Error(err, "error hashing manifest", "name", manifest.Obj.GetName())
+			return err
+		}
+
 		if viper.GetBool("dry-run") {
 			log.Info("dry run enabled, skipping applying...")
 			continue
 		}
-		actualObject := &unstructured.Unstructured{}
-		actualObject.SetGroupVersionKind(manifest.GVK)
-		err := client.Get(ctx, types.NamespacedName{Name: manifest.Obj.GetName(), Namespace: manifest.Obj.GetNamespace()}, actualObject)
-		if err != nil && errors.IsNotFound(err) {
-			log.V(1).Info("creating", "name", manifest.Obj.GetName(), "kind", manifest.GVK.Kind)
-			if err := client.Create(ctx, manifest.Obj); err != nil {
-				log.Error(err, "error creating object", "name", manifest.Obj.GetName())
-				return err
-			}
-		} else {
 
+		actualObject, err := manifest.live(ctx, client, log)
+		if err != nil {
+			return err
+		}
+
+		if actualObject != nil {
+			// not updatable manifests (generated secrets, pvcs, jobs, etc.) are applied only once
 			if !manifest.Updatable {
-				log.Info("skipping update, manifest is not updatable", "manifest", manifest.Obj.GetName(), "kind", manifest.GVK.Kind)
+				log.V(1).Info("skipping update, manifest is not updatable", "manifest", manifest.Obj.GetName(), "kind", manifest.GVK.Kind)
+				stats.add(manifest.GVK, actionSkipped)
 				continue
 			}
-
-			// for deployment|statefulset|daemonset do not override labels and annotations, but merge them
-			manifest.mergeMetadata(actualObject, log)
-
-			// if override true, do not merge object with existing state
-			// currently, Override=true set only for fluentbit CM
-			// this required when removing cnvrgapp, and fluentbit CM has to be overridden and not merged
-			// in case of merge, CM will have not existing cnvrgapp ES configuration/instance
-			if manifest.Override {
-				actualObject = manifest.Obj
-			} else {
-				if err := mergo.Merge(actualObject, manifest.Obj, mergo.WithOverride); err != nil {
-					log.Error(err, "can't merge")
-					return err
-				}
-			}
-
-			err := client.Update(ctx, actualObject)
-			if err != nil {
-				log.Info("error updating object", "manifest", manifest.TemplatePath)
-				return err
+			if manifest.unchanged(actualObject) {
+				log.V(1).Info("skipping update, manifest is unchanged", "manifest", manifest.Obj.GetName(), "kind", manifest.GVK.Kind)
+				stats.add(manifest.GVK, actionSkipped)
+				continue
 			}
 		}
-	}
-	return nil
-}
 
-func (s *State) mergeMetadata(actualObject *unstructured.Unstructured, log logr.Logger) {
-	var e error
-	var jsonStr []byte
-	if s.GVK.Kind == "Deployment" {
-
-		manifest, err := objectToDeployment(s.Obj)
+		applied, err := manifest.apply(ctx, client, log)
 		if err != nil {
-			log.Error(err, "error merging metadata")
-			return
-		}
-		actual, err := objectToDeployment(actualObject)
-		if err != nil {
-			log.Error(err, "error merging metadata")
-			return
-		}
-
-		if err := mergo.Merge(&manifest.ObjectMeta.Labels, actual.ObjectMeta.Labels); err != nil {
-			log.Error(err, "error merging metadata")
-			return
-		}
-
-		if err := mergo.Merge(&manifest.Spec.Template.ObjectMeta.Labels, actual.Spec.Template.ObjectMeta.Labels); err != nil {
-			log.Error(err, "error merging metadata")
-			return
-		}
-
-		if err := mergo.Merge(&manifest.ObjectMeta.Annotations, actual.ObjectMeta.Annotations); err != nil {
-			log.Error(err, "error merging metadata")
-			return
+			return err
 		}
-
-		if err := mergo.Merge(&manifest.Spec.Template.ObjectMeta.Annotations, actual.Spec.Template.ObjectMeta.Annotations); err != nil {
-			log.Error(err, "error merging metadata")
-			return
+		switch {
+		case !applied:
+			stats.add(manifest.GVK, actionSkipped)
+		case actualObject == nil:
+			stats.add(manifest.GVK, actionCreated)
+		default:
+			stats.add(manifest.GVK, actionUpdated)
 		}
-
-		jsonStr, e = json.Marshal(manifest)
 	}
+	return nil
+}
 
-	if s.GVK.Kind == "StatefulSet" {
-
-		manifest, err := objectToStatefulSet(s.Obj)
-		if err != nil {
-			log.Error(err, "error merging metadata")
-			return
-		}
-		actual, err := objectToStatefulSet(actualObject)
-		if err != nil {
-			log.Error(err, "error merging metadata")
-			return
-		}
-
-		if err := mergo.Merge(&manifest.ObjectMeta.Labels, actual.ObjectMeta.Labels); err != nil {
-			log.Error(err, "error merging metadata")
-			return
-		}
-
-		if err := mergo.Merge(&manifest.Spec.Template.ObjectMeta.Labels, actual.Spec.Template.ObjectMeta.Labels); err != nil {
-			log.Error(err, "error merging metadata")
-			return
-		}
-
-		if err := mergo.Merge(&manifest.ObjectMeta.Annotations, actual.ObjectMeta.Annotations); err != nil {
-			log.Error(err, "error merging metadata")
-			return
-
-		}
-
-		if err := mergo.Merge(&manifest.Spec.Template.ObjectMeta.Annotations, actual.Spec.Template.ObjectMeta.Annotations); err != nil {
-			log.Error(err, "error merging metadata")
-			return
-		}
-
-		jsonStr, e = json.Marshal(manifest)
-
+// live returns the actual object, or nil if the object doesn't exist
+func (s *State) live(ctx context.Context, c client.Client, log logr.Logger) (*unstructured.Unstructured, error) {
+	actualObject := &unstructured.Unstructured{}
+	actualObject.SetGroupVersionKind(s.GVK)
+	err := c.Get(ctx, types.NamespacedName{Name: s.Obj.GetName(), Namespace: s.Obj.GetNamespace()}, actualObject)
+	if err != nil && errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		log.Error(err, "error getting object", "name", s.Obj.GetName(), "kind", s.GVK.Kind)
+		return nil, err
 	}
+	return actualObject, nil
+}
 
-	if s.GVK.Kind == "DaemonSet" {
-
-		manifest, err := objectToDaemonSet(s.Obj)
-		if err != nil {
-			log.Error(err, "error merging metadata")
-			return
-		}
-		actual, err := objectToDaemonSet(actualObject)
-		if err != nil {
-			log.Error(err, "error merging metadata")
-			return
-		}
-
-		if err := mergo.Merge(&manifest.ObjectMeta.Labels, actual.ObjectMeta.Labels); err != nil {
-			log.Error(err, "error merging metadata")
-			return
-		}
-
-		if err := mergo.Merge(&manifest.Spec.Template.ObjectMeta.Labels, actual.Spec.Template.ObjectMeta.Labels); err != nil {
-			log.Error(err, "error merging metadata")
-			return
-		}
-
-		if err := mergo.Merge(&manifest.ObjectMeta.Annotations, actual.ObjectMeta.Annotations); err != nil {
-			log.Error(err, "error merging metadata")
-			return
-		}
-
-		if err := mergo.Merge(&manifest.Spec.Template.ObjectMeta.Annotations, actual.Spec.Template.ObjectMeta.Annotations); err != nil {
-			log.Error(err, "error merging metadata")
-			return
-		}
-
-		jsonStr, e = json.Marshal(manifest)
-
+// apply sends the rendered object with server side apply,
+// fields not present in the template are released by the operator field manager,
+// fields set by other actors (kubectl, hpa, webhooks) are preserved
+func (s *State) apply(ctx context.Context, c client.Client, log logr.Logger) (applied bool, err error) {
+	opts := []client.PatchOption{client.FieldOwner(FieldManager)}
+	if s.ConflictPolicy.resolve() == ConflictForce {
+		opts = append(opts, client.ForceOwnership)
 	}
-
-	if e == nil && len(jsonStr) > 0 {
-		dec := yaml.NewDecodingSerializer(unstructured.UnstructuredJSONScheme)
-		if _, _, err := dec.Decode(jsonStr, nil, s.Obj); err != nil {
-			log.Error(err, "error merging metadata")
-			return
-		}
-	} else if e != nil {
-		log.Error(e, "error merging metadata")
+	log.V(1).Info("applying", "name", s.Obj.GetName(), "kind", s.GVK.Kind)
+	err = c.Patch(ctx, s.Obj, client.Apply, opts...)
+	if err != nil && errors.IsConflict(err) && s.ConflictPolicy.resolve() == ConflictSkip {
+		log.Info("skipping apply, fields are managed by another field manager", "name", s.Obj.GetName(), "kind", s.GVK.Kind, "conflict", err.Error())
+		return false, nil
 	}
-
+	if err != nil {
+		log.Error(err, "error applying object", "manifest", s.TemplatePath, "name", s.Obj.GetName())
+		return false, err
+	}
+	return true, nil
 }
 
 func (s *State) dumpTemplateToFile() error {
@@ -641,49 +570,11 @@ func (s *State) dumpTemplateToFile() error {
 	return nil
 }
 
-func objectToDeployment(obj interface{}) (*appv1.Deployment, error) {
-
-	d := &appv1.Deployment{}
-	if err := objectToUnstructured(obj, d); err != nil {
-		return nil, err
-	}
-	return d, nil
-}
-
-func objectToStatefulSet(obj interface{}) (*appv1.StatefulSet, error) {
-
-	s := &appv1.StatefulSet{}
-	if err := objectToUnstructured(obj, s); err != nil {
-		return nil, err
-	}
-	return s, nil
-}
-
-func objectToDaemonSet(obj interface{}) (*appv1.DaemonSet, error) {
-
-	d := &appv1.DaemonSet{}
-	if err := objectToUnstructured(obj, d); err != nil {
-		return nil, err
-	}
-	return d, nil
-}
-
-func objectToUnstructured(obj interface{}, dst interface{}) error {
-	un, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
-	if err != nil {
-		return err
-	}
-	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(un, dst); err != nil {
-		return err
-	}
-	return nil
-}
-
-func GetPromCredsSecret(secretName string, secretNs string, client client.Client, log logr.Logger) (url, user, pass string, err error) {
+func GetPromCredsSecret(ctx context.Context, secretName string, secretNs string, client client.Client, log logr.Logger) (url, user, pass string, err error) {
 	user = "cnvrg"
 	namespacedName := types.NamespacedName{Name: secretName, Namespace: secretNs}
 	creds := v1core.Secret{ObjectMeta: v1.ObjectMeta{Name: namespacedName.Name, Namespace: namespacedName.Namespace}}
-	if err := client.Get(context.Background(), namespacedName, &creds); err != nil && errors.IsNotFound(err) {
+	if err := client.Get(ctx, namespacedName, &creds); err != nil && errors.IsNotFound(err) {
 		log.Error(err, "Prometheus creds secret not found (either not created yet or you are using external prometheus: https://install.cnvrg.io/deployments/openshift.html)", "name", secretName)
 		return "", "", "", err
 	} else if err != nil {
@@ -712,18 +603,18 @@ func GetPromCredsSecret(secretName string, secretNs string, client client.Client
 	return string(creds.Data["CNVRG_PROMETHEUS_URL"]), string(creds.Data["CNVRG_PROMETHEUS_USER"]), string(creds.Data["CNVRG_PROMETHEUS_PASS"]), nil
 }
 
-func CreatePromCredsSecret(obj v1.Object, secretName, secretNs, promUrl string, client client.Client, schema *runtime.Scheme, log logr.Logger) error {
+func CreatePromCredsSecret(ctx context.Context, obj v1.Object, secretName, secretNs, promUrl string, client client.Client, schema *runtime.Scheme, log logr.Logger) error {
 	user := "cnvrg"
 	namespacedName := types.NamespacedName{Name: secretName, Namespace: secretNs}
 	creds := v1core.Secret{ObjectMeta: v1.ObjectMeta{Name: namespacedName.Name, Namespace: namespacedName.Namespace}}
-	if err := client.Get(context.Background(), namespacedName, &creds); err != nil && errors.IsNotFound(err) {
+	if err := client.Get(ctx, namespacedName, &creds); err != nil && errors.IsNotFound(err) {
 		if err := ctrl.SetControllerReference(obj, &creds, schema); err != nil {
 			log.Error(err, "error set controller reference", "name", namespacedName.Name)
 			return err
 		}
 
-		pass := RandomString()
-		passHash, err := apr1_crypt.New().Generate([]byte(pass), nil)
+		pass := RandomString(ctx)
+		passHash, err := PasswordHash(ctx, pass)
 		if err != nil {
 			log.Error(err, "error generating prometheus hash")
 			return err
@@ -734,7 +625,7 @@ func CreatePromCredsSecret(obj v1.Object, secretName, secretNs, promUrl string,
 			"CNVRG_PROMETHEUS_URL":  []byte(promUrl),
 			"htpasswd":              []byte(fmt.Sprintf("%s:%s", user, passHash)),
 		}
-		if err := client.Create(context.Background(), &creds); err != nil {
+		if err := client.Create(ctx, &creds); err != nil {
 			log.Error(err, "error creating prometheus creds", "name", namespacedName.Name)
 			return err
 		}
@@ -766,7 +657,28 @@ func PrometheusUpstreamConfig(user, pass, ns, upstream string) string {
 `, user, pass, ns, upstream)
 }
 
-func RandomString() string {
+type placeholderCredentialsKey struct{}
+
+// WithPlaceholderCredentials marks the context of the operator render, the generated credentials are fixed placeholders,
+// so rendering the same spec twice gives the same manifests and the output holds no generated credentials
+func WithPlaceholderCredentials(ctx context.Context) context.Context {
+	return context.WithValue(ctx, placeholderCredentialsKey{}, true)
+}
+
+func placeholderCredentials(ctx context.Context) bool {
+	placeholders, _ := ctx.Value(placeholderCredentialsKey{}).(bool)
+	return placeholders
+}
+
+// placeholder is the generated credential of the given length with placeholder credentials
+func placeholder(length int) string {
+	return strings.Repeat("x", length)
+}
+
+func RandomString(ctx context.Context) string {
+	if placeholderCredentials(ctx) {
+		return placeholder(20)
+	}
 	var output strings.Builder
 	mathrand.Seed(time.Now().Unix())
 	charSet := "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
@@ -777,3 +689,60 @@ func RandomString() string {
 	}
 	return output.String()
 }
+
+// PasswordHash returns the apr1 (htpasswd) hash of the password, the salt is random unless the credentials are placeholders
+func PasswordHash(ctx context.Context, pass string) (string, error) {
+	var salt []byte
+	if placeholderCredentials(ctx) {
+		salt = []byte("$apr1$xxxxxxxx")
+	}
+	return apr1_crypt.New().Generate([]byte(pass), salt)
+}
+
+// BackupSchedule converts the backup period (one of Xs, Xm, Xh) to a cron schedule,
+// periods which don't divide the hour (minutes) or the day (hours) are rounded down to a divisor,
+// the shortest schedule is every minute
+func BackupSchedule(period string) string {
+	if len(period) < 2 {
+		return "0 0 * * *" // daily
+	}
+	value, err := strconv.Atoi(period[:len(period)-1])
+	if err != nil || value <= 0 {
+		return "0 0 * * *" // daily
+	}
+	minutes := value
+	switch period[len(period)-1] {
+	case 's':
+		minutes = value / 60
+	case 'h':
+		minutes = value * 60
+	}
+	divisor := func(n, of int) int {
+		for of%n != 0 {
+			n--
+		}
+		return n
+	}
+	switch {
+	case minutes <= 1:
+		return "* * * * *"
+	case minutes < 60:
+		return fmt.Sprintf("*/%d * * * *", divisor(minutes, 60))
+	case minutes < 24*60:
+		return fmt.Sprintf("0 */%d * * *", divisor(minutes/60, 24))
+	}
+	return fmt.Sprintf("0 0 */%d * *", minutes/(24*60))
+}
+
+// esSnapshotRepositoryScript is the shell setup of the es snapshot jobs (es backup cron job, backup and restore jobs),
+// it defines es (curl with the es credentials) and snapshot_repository <repository> <bucket> <base path> [readonly],
+// which checks the repository-s3 plugin and registers the s3 snapshot repository
+const esSnapshotRepositoryScript = `es() { curl -sSf -u "$CNVRG_ES_USER:$CNVRG_ES_PASS" -H "Content-Type: application/json" "$@"; }
+snapshot_repository() {
+  # the es pod installs the repository-s3 plugin only when the image bundles it or es has internet access
+  es "$ES_URL/_cat/plugins?h=component" | grep -q repository-s3 \
+    || { echo "the repository-s3 plugin isn't installed on es, set spec.dbs.es.image to an image which bundles it"; exit 1; }
+  # the s3 client of es is configured by the es stateful set when spec.dbs.es.backup is enabled
+  es -X PUT "$ES_URL/_snapshot/$1" \
+    -d "{\"type\":\"s3\",\"settings\":{\"bucket\":\"$2\",\"base_path\":\"$3\",\"readonly\":${4:-false}}}"
+}`
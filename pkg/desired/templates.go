@@ -0,0 +1,212 @@
+package desired
+
+import (
+	"fmt"
+	"github.com/Masterminds/sprig"
+	"github.com/markbates/pkger"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"text/template"
+	"text/template/parse"
+)
+
+// templateExtensions are the files parsed as templates,
+// other files (e.g. grafana dashboards) are read as is
+var templateExtensions = map[string]bool{".tpl": true, ".yaml": true}
+
+// registry holds the parsed templates by template path
+var registry = struct {
+	sync.RWMutex
+	templates map[string]*template.Template
+}{templates: map[string]*template.Template{}}
+
+// templateData holds the Data type of the templates rendered with TemplateData, by template path
+var templateData = map[string]reflect.Type{}
+
+// RegisterTemplateData registers the Data type of the template,
+// the template fields are checked against the type by LoadTemplates
+func RegisterTemplateData(path string, data interface{}) {
+	templateData[path] = reflect.TypeOf(data)
+}
+
+// LoadTemplates parses all the templates in the embedded dirs,
+// and checks the fields of the templates with registered data are resolvable.
+// Should be called once at startup, so broken templates are detected before the first reconcile
+func LoadTemplates(dirs ...string) error {
+	for _, dir := range dirs {
+		err := pkger.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() || !templateExtensions[filepath.Ext(path)] {
+				return nil
+			}
+			_, err = parseTemplate(path)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	for path, dataType := range templateData {
+		tpl, err := getTemplate(path)
+		if err != nil {
+			return err
+		}
+		if err := checkFields(tpl.Tree.Root, dataType, false); err != nil {
+			return fmt.Errorf("template %s: %v", path, err)
+		}
+	}
+	return nil
+}
+
+// getTemplate returns the parsed template, templates which weren't loaded at startup are parsed on first use
+func getTemplate(path string) (*template.Template, error) {
+	registry.RLock()
+	tpl, ok := registry.templates[templateKey(path)]
+	registry.RUnlock()
+	if ok {
+		return tpl, nil
+	}
+	return parseTemplate(path)
+}
+
+func parseTemplate(path string) (*template.Template, error) {
+	f, err := pkger.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading template %s: %v", path, err)
+	}
+	defer f.Close()
+	b, err := ioutil.ReadAll(f)
+	if err != nil {
+		return nil, fmt.Errorf("error reading template %s: %v", path, err)
+	}
+	tpl, err := template.New(strings.ReplaceAll(templateKey(path), "/", "-")).
+		Option("missingkey=error").
+		Funcs(sprig.TxtFuncMap()).
+		Funcs(cnvrgTemplateFuncs()).
+		Parse(string(b))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing template %s: %v", path, err)
+	}
+	registry.Lock()
+	registry.templates[templateKey(path)] = tpl
+	registry.Unlock()
+	return tpl, nil
+}
+
+// placeholderFuncs replace the random template funcs of the generated credentials
+var placeholderFuncs = template.FuncMap{
+	"randAlphaNum": placeholder,
+	"randAlpha":    placeholder,
+	"randNumeric":  placeholder,
+	"randAscii":    placeholder,
+}
+
+// placeholderTemplate returns a copy of the registered template which renders the generated credentials as placeholders
+func placeholderTemplate(tpl *template.Template) (*template.Template, error) {
+	clone, err := tpl.Clone()
+	if err != nil {
+		return nil, err
+	}
+	return clone.Funcs(placeholderFuncs), nil
+}
+
+// templateKey strips the module from pkger walk paths (module:/pkg/...)
+func templateKey(path string) string {
+	if i := strings.Index(path, ":"); i >= 0 {
+		return path[i+1:]
+	}
+	return path
+}
+
+// checkFields resolves the fields of the template against TemplateData with the given Data type.
+// Fields inside range and with blocks are relative to the block's dot, hence only $ rooted fields are checked there
+func checkFields(node parse.Node, dataType reflect.Type, nestedDot bool) error {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, c := range n.Nodes {
+			if err := checkFields(c, dataType, nestedDot); err != nil {
+				return err
+			}
+		}
+	case *parse.ActionNode:
+		return checkFields(n.Pipe, dataType, nestedDot)
+	case *parse.PipeNode:
+		if n == nil {
+			return nil
+		}
+		for _, c := range n.Cmds {
+			if err := checkFields(c, dataType, nestedDot); err != nil {
+				return err
+			}
+		}
+	case *parse.CommandNode:
+		for _, a := range n.Args {
+			if err := checkFields(a, dataType, nestedDot); err != nil {
+				return err
+			}
+		}
+	case *parse.IfNode:
+		return checkBranch(&n.BranchNode, dataType, nestedDot, nestedDot)
+	case *parse.RangeNode:
+		return checkBranch(&n.BranchNode, dataType, nestedDot, true)
+	case *parse.WithNode:
+		return checkBranch(&n.BranchNode, dataType, nestedDot, true)
+	case *parse.TemplateNode:
+		return checkFields(n.Pipe, dataType, nestedDot)
+	case *parse.FieldNode:
+		if !nestedDot {
+			return resolveField(n.Ident, dataType)
+		}
+	case *parse.VariableNode:
+		if n.Ident[0] == "$" {
+			return resolveField(n.Ident[1:], dataType)
+		}
+	}
+	return nil
+}
+
+func checkBranch(n *parse.BranchNode, dataType reflect.Type, nestedDot, bodyNestedDot bool) error {
+	if err := checkFields(n.Pipe, dataType, nestedDot); err != nil {
+		return err
+	}
+	if err := checkFields(n.List, dataType, bodyNestedDot); err != nil {
+		return err
+	}
+	// else branch of range and with is evaluated with the outer dot
+	return checkFields(n.ElseList, dataType, nestedDot)
+}
+
+// resolveField checks the field chain exists on TemplateData, maps and interfaces are not checked
+func resolveField(ident []string, dataType reflect.Type) error {
+	t := reflect.TypeOf(TemplateData{})
+	for i, name := range ident {
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if _, ok := t.MethodByName(name); ok {
+			return nil
+		}
+		if t.Kind() != reflect.Struct {
+			return nil
+		}
+		f, ok := t.FieldByName(name)
+		if !ok {
+			return fmt.Errorf("can't resolve .%s, %s has no field %s", strings.Join(ident[:i+1], "."), t, name)
+		}
+		t = f.Type
+		// TemplateData.Data holds the registered type
+		if i == 0 && name == "Data" {
+			t = dataType
+		}
+	}
+	return nil
+}
@@ -6,9 +6,39 @@ import (
 	"text/template"
 )
 
+// FieldManager is the server side apply field manager of all the operator's objects
+const FieldManager = "cnvrg-operator"
+
+// ConflictPolicy defines what apply does when a field in the rendered template
+// is already managed by another field manager (kubectl, hpa, webhooks, etc.)
+type ConflictPolicy int
+
+const (
+	// ConflictDefault is the policy of the states which don't set one, resolved to DefaultConflictPolicy
+	ConflictDefault ConflictPolicy = iota
+	// ConflictForce takes the ownership over conflicting fields
+	ConflictForce
+	// ConflictSkip leaves the object as is, and continues with the next manifest
+	ConflictSkip
+)
+
+// DefaultConflictPolicy is applied to the states without a conflict policy,
+// the operator owns the fields it renders, fields left to other actors (e.g. replicas with hpa) are omitted by the templates
+const DefaultConflictPolicy = ConflictForce
+
+// resolve returns the effective conflict policy
+func (p ConflictPolicy) resolve() ConflictPolicy {
+	if p == ConflictDefault {
+		return DefaultConflictPolicy
+	}
+	return p
+}
+
+// TemplateData is the input of the templates which are not rendered from the CnvrgApp/CnvrgInfra spec,
+// Data is a typed struct registered for the template with RegisterTemplateData
 type TemplateData struct {
 	Namespace string
-	Data      map[string]interface{}
+	Data      interface{}
 }
 
 type State struct {
@@ -18,7 +48,9 @@ type State struct {
 	Obj            *unstructured.Unstructured
 	GVK            schema.GroupVersionKind
 	Own            bool
-	Override       bool
 	Updatable      bool
+	ConflictPolicy ConflictPolicy
 	TemplateData   interface{}
+	// PlaceholderCredentials renders the random template funcs as fixed placeholders, see WithPlaceholderCredentials
+	PlaceholderCredentials bool
 }
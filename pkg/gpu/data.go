@@ -0,0 +1,56 @@
+package gpu
+
+import (
+	mlopsv1 "github.com/AccessibleAI/cnvrg-operator/api/v1"
+	"github.com/AccessibleAI/cnvrg-operator/pkg/desired"
+)
+
+// NvidiaDpData is the input of the nvidia device plugin templates
+type NvidiaDpData struct {
+	Namespace   string
+	Annotations map[string]string
+	Labels      map[string]string
+	NvidiaDp    mlopsv1.NvidiaDp
+	Registry    mlopsv1.Registry
+	ImageHub    string
+}
+
+// HabanaDpData is the input of the habana device plugin templates
+type HabanaDpData struct {
+	Namespace   string
+	Annotations map[string]string
+	Labels      map[string]string
+	HabanaDp    mlopsv1.HabanaDp
+	Registry    mlopsv1.Registry
+	ImageHub    string
+}
+
+// MetaGpuDpData is the input of the metagpu device plugin templates
+type MetaGpuDpData struct {
+	Namespace   string
+	Annotations map[string]string
+	Labels      map[string]string
+	MetaGpuDp   mlopsv1.MetaGpuDp
+	ImageHub    string
+}
+
+// MetaGpuPresenceData is the input of the metagpu presence configmap template
+type MetaGpuPresenceData struct {
+	Namespace   string
+	Annotations map[string]string
+	Labels      map[string]string
+	Enabled     bool
+}
+
+func init() {
+	for _, s := range nvidiaDp() {
+		desired.RegisterTemplateData(s.TemplatePath, NvidiaDpData{})
+	}
+	for _, s := range habanaDp() {
+		desired.RegisterTemplateData(s.TemplatePath, HabanaDpData{})
+	}
+	for _, s := range MetagpudpState(MetaGpuDpData{}) {
+		desired.RegisterTemplateData(s.TemplatePath, MetaGpuDpData{})
+	}
+	desired.RegisterTemplateData(path+"/metagpudp/presence.tpl", MetaGpuPresenceData{})
+}
@@ -31,14 +31,16 @@ func nvidiaDp() []*desired.State {
 	}
 }
 
-func NvidiaDpState(data interface{}) []*desired.State {
+func NvidiaDpState(data NvidiaDpData) []*desired.State {
+	templateData := desired.TemplateData{Namespace: data.Namespace, Data: data}
 	nvidiaDp := nvidiaDp()
-	nvidiaDp[0].TemplateData = data
-	nvidiaDp[1].TemplateData = data
+	nvidiaDp[0].TemplateData = templateData
+	nvidiaDp[1].TemplateData = templateData
 	return nvidiaDp
 }
 
-func MetagpudpPresenceState(data interface{}) []*desired.State {
+func MetagpudpPresenceState(data MetaGpuPresenceData) []*desired.State {
+	templateData := desired.TemplateData{Namespace: data.Namespace, Data: data}
 	return []*desired.State{
 		{
 			TemplatePath: path + "/metagpudp/presence.tpl",
@@ -46,12 +48,13 @@ func MetagpudpPresenceState(data interface{}) []*desired.State {
 			GVK:          desired.Kinds[desired.ConfigMapGVK],
 			Own:          true,
 			Updatable:    true,
-			TemplateData: data,
+			TemplateData: templateData,
 		},
 	}
 }
 
-func MetagpudpState(data interface{}) []*desired.State {
+func MetagpudpState(data MetaGpuDpData) []*desired.State {
+	templateData := desired.TemplateData{Namespace: data.Namespace, Data: data}
 	return []*desired.State{
 		{
 			TemplatePath: path + "/metagpudp/sa.tpl",
@@ -59,7 +62,7 @@ func MetagpudpState(data interface{}) []*desired.State {
 			GVK:          desired.Kinds[desired.SaGVK],
 			Own:          true,
 			Updatable:    false,
-			TemplateData: data,
+			TemplateData: templateData,
 		},
 		{
 			TemplatePath: path + "/metagpudp/binding.tpl",
@@ -67,7 +70,7 @@ func MetagpudpState(data interface{}) []*desired.State {
 			GVK:          desired.Kinds[desired.ClusterRoleBindingGVK],
 			Own:          true,
 			Updatable:    true,
-			TemplateData: data,
+			TemplateData: templateData,
 		},
 		{
 			TemplatePath: path + "/metagpudp/cm.tpl",
@@ -75,7 +78,7 @@ func MetagpudpState(data interface{}) []*desired.State {
 			GVK:          desired.Kinds[desired.ConfigMapGVK],
 			Own:          false,
 			Updatable:    false,
-			TemplateData: data,
+			TemplateData: templateData,
 		},
 		{
 			TemplatePath: path + "/metagpudp/ds.tpl",
@@ -83,7 +86,7 @@ func MetagpudpState(data interface{}) []*desired.State {
 			GVK:          desired.Kinds[desired.DaemonSetGVK],
 			Own:          true,
 			Updatable:    true,
-			TemplateData: data,
+			TemplateData: templateData,
 		},
 		{
 			TemplatePath:   path + "/metagpudp/role.tpl",
@@ -92,7 +95,7 @@ func MetagpudpState(data interface{}) []*desired.State {
 			GVK:            desired.Kinds[desired.ClusterRoleGVK],
 			Own:            true,
 			Updatable:      true,
-			TemplateData:   data,
+			TemplateData:   templateData,
 		},
 		{
 			TemplatePath: path + "/metagpudp/svc.tpl",
@@ -100,7 +103,7 @@ func MetagpudpState(data interface{}) []*desired.State {
 			GVK:          desired.Kinds[desired.SvcGVK],
 			Own:          true,
 			Updatable:    true,
-			TemplateData: data,
+			TemplateData: templateData,
 		},
 	}
 }
@@ -128,9 +131,10 @@ func habanaDp() []*desired.State {
 	}
 }
 
-func HabanaDpState(data interface{}) []*desired.State {
+func HabanaDpState(data HabanaDpData) []*desired.State {
+	templateData := desired.TemplateData{Namespace: data.Namespace, Data: data}
 	habanaDp := habanaDp()
-	habanaDp[0].TemplateData = data
-	habanaDp[1].TemplateData = data
+	habanaDp[0].TemplateData = templateData
+	habanaDp[1].TemplateData = templateData
 	return habanaDp
 }
@@ -51,19 +51,19 @@ spec:
                 name: cp-object-storage
             - secretRef:
                 name: cp-smtp
-            {{- if isTrue .Spec.Dbs.Es.Enabled }}
+            {{- if .Spec.Dbs.Es.Enabled }}
             - secretRef:
                 name: {{ .Spec.Dbs.Es.CredsRef }}
             {{- end }}
-            {{- if isTrue .Spec.Dbs.Redis.Enabled }}
+            {{- if .Spec.Dbs.Redis.Enabled }}
             - secretRef:
                 name: {{ .Spec.Dbs.Redis.CredsRef }}
             {{- end }}
-            {{- if isTrue .Spec.Monitoring.Prometheus.Enabled }}
+            {{- if .Spec.Monitoring.Prometheus.Enabled }}
             - secretRef:
                 name: {{ .Spec.Monitoring.Prometheus.CredsRef }}
             {{- end }}
-            {{- if isTrue .Spec.Networking.Proxy.Enabled }}
+            {{- if .Spec.Networking.Proxy.Enabled }}
             - configMapRef:
                 name: {{ .Spec.Networking.Proxy.ConfigRef }}
             {{- end }}
@@ -0,0 +1,46 @@
+package logging
+
+import (
+	mlopsv1 "github.com/AccessibleAI/cnvrg-operator/api/v1"
+	"github.com/AccessibleAI/cnvrg-operator/pkg/desired"
+)
+
+// ElastCredsData is the input of the elastalert creds secret template
+type ElastCredsData struct {
+	Namespace     string
+	Annotations   map[string]string
+	Labels        map[string]string
+	CredsRef      string
+	User          string
+	Pass          string
+	Htpasswd      string
+	ElastAlertUrl string
+}
+
+// KibanaConfData is the input of the kibana config secret template
+type KibanaConfData struct {
+	Namespace   string
+	Annotations map[string]string
+	Labels      map[string]string
+	Host        string
+	Port        string
+	EsHost      string
+	EsUser      string
+	EsPass      string
+}
+
+// FluentbitData is the input of the fluentbit config template
+type FluentbitData struct {
+	Namespace             string
+	Annotations           map[string]string
+	Labels                map[string]string
+	AppInstance           []mlopsv1.AppInstance
+	ClusterInternalDomain string
+	CriType               mlopsv1.CriType
+}
+
+func init() {
+	desired.RegisterTemplateData(path+"/elastalert/credsec.tpl", ElastCredsData{})
+	desired.RegisterTemplateData(path+"/kibana/secret.tpl", KibanaConfData{})
+	desired.RegisterTemplateData(path+"/fluentbit/cm.tpl", FluentbitData{})
+}
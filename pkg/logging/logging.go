@@ -8,10 +8,11 @@ import (
 
 const path = "/pkg/logging/tmpl"
 
-func ElastCreds(data *desired.TemplateData) []*desired.State {
+func ElastCreds(data ElastCredsData) []*desired.State {
+	templateData := desired.TemplateData{Namespace: data.Namespace, Data: data}
 	return []*desired.State{
 		{
-			TemplateData:   data,
+			TemplateData:   templateData,
 			TemplatePath:   path + "/elastalert/credsec.tpl",
 			Template:       nil,
 			ParsedTemplate: "",
@@ -95,10 +96,11 @@ func ElastAlert() []*desired.State {
 	}
 }
 
-func KibanaConfSecret(data desired.TemplateData) []*desired.State {
+func KibanaConfSecret(data KibanaConfData) []*desired.State {
+	templateData := desired.TemplateData{Namespace: data.Namespace, Data: data}
 	return []*desired.State{
 		{
-			TemplateData:   data,
+			TemplateData:   templateData,
 			TemplatePath:   path + "/kibana/secret.tpl",
 			Template:       nil,
 			ParsedTemplate: "",
@@ -218,8 +220,9 @@ func fluentbitConfigState() []*desired.State {
 			Obj:            &unstructured.Unstructured{},
 			GVK:            desired.Kinds[desired.ConfigMapGVK],
 			Own:            true,
-			Override:       true,
 			Updatable:      true,
+			// the config is rendered from all the apps, manual edits are overridden
+			ConflictPolicy: desired.ConflictForce,
 		},
 	}
 }
@@ -328,8 +331,9 @@ func InfraLoggingState(infra *mlopsv1.CnvrgInfra) []*desired.State {
 	return state
 }
 
-func FluentbitConfigurationState(data interface{}) []*desired.State {
+func FluentbitConfigurationState(data FluentbitData) []*desired.State {
+	templateData := desired.TemplateData{Namespace: data.Namespace, Data: data}
 	fluentbitConfigState := fluentbitConfigState()
-	fluentbitConfigState[0].TemplateData = data
+	fluentbitConfigState[0].TemplateData = templateData
 	return fluentbitConfigState
 }
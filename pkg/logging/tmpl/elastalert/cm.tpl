@@ -19,7 +19,11 @@ data:
       "wsport": 3333,
       "elastalertPath": "/opt/elastalert",
       "verbose": true,
+      {{- if .Spec.Dbs.Es.External.Enabled }}
+      "use_ssl": {{ .Spec.Dbs.Es.External.TLS }},
+      {{- else }}
       "use_ssl": false,
+      {{- end }}
       "verify_certs": false,
       "run_every": {
         "minutes": 1
@@ -43,8 +47,13 @@ data:
         "relative": true,
         "path": "/server_data"
       },
+      {{- if .Spec.Dbs.Es.External.Enabled }}
+      "es_host": "{{ .Spec.Dbs.Es.External.Host }}",
+      "es_port": {{ .Spec.Dbs.Es.External.Port }},
+      {{- else }}
       "es_host": "{{ .Spec.Dbs.Es.SvcName }}",
       "es_port": {{ .Spec.Dbs.Es.Port }},
+      {{- end }}
       "writeback_index": "elastalert_status"
     }
   config.yaml: |
@@ -53,9 +62,15 @@ data:
       minutes: 1
     buffer_time:
       minutes: 15
+    {{- if .Spec.Dbs.Es.External.Enabled }}
+    es_host: {{ .Spec.Dbs.Es.External.Host }}
+    es_port: {{ .Spec.Dbs.Es.External.Port }}
+    use_ssl: {{ if .Spec.Dbs.Es.External.TLS }}True{{ else }}False{{ end }}
+    {{- else }}
     es_host: {{ .Spec.Dbs.Es.SvcName }}
     es_port: {{ .Spec.Dbs.Es.Port}}
     use_ssl: False
+    {{- end }}
     verify_certs: False
     writeback_index: elastalert_status
     writeback_alias: elastalert_alerts
@@ -1,3 +1,4 @@
+{{- $pod := .Spec.Logging.Fluentbit.PodSpecOverrides }}
 apiVersion: apps/v1
 kind: DaemonSet
 metadata:
@@ -28,16 +29,34 @@ spec:
         {{- range $k, $v := .Spec.Annotations }}
         {{$k}}: "{{$v}}"
         {{- end }}
+        {{- range $k, $v := $pod.PodAnnotations }}
+        {{$k}}: "{{$v}}"
+        {{- end }}
     spec:
       priorityClassName: {{ .Spec.CnvrgAppPriorityClass.Name }}
       nodeSelector:
         {{- range $key, $val := .Spec.Logging.Fluentbit.NodeSelector }}
         {{ $key }}: {{ $val }}
         {{- end }}
+      {{- with $pod.Affinity }}
+      affinity: {{ toJson . }}
+      {{- end }}
+      {{- with $pod.TopologySpreadConstraints }}
+      topologySpreadConstraints: {{ toJson . }}
+      {{- end }}
+      {{- with $pod.SecurityContext }}
+      securityContext: {{ toJson . }}
+      {{- end }}
       containers:
         - name: fluentbit
           image: {{ image .Spec.ImageHub .Spec.Logging.Fluentbit.Image }}
-          imagePullPolicy: Always
+          imagePullPolicy: {{ $pod.ImagePullPolicy | default "Always" }}
+          {{- with $pod.Env }}
+          env: {{ toJson . }}
+          {{- end }}
+          {{- with $pod.EnvFrom }}
+          envFrom: {{ toJson . }}
+          {{- end }}
           command:
             - /bin/bash
             - -c
@@ -52,6 +71,9 @@ spec:
             {{- end }}
             - name: fluent-bit-config
               mountPath: /opt/app-root/etc/
+            {{- range $pod.VolumeMounts }}
+            - {{ toJson . }}
+            {{- end }}
           resources:
             requests:
               cpu: {{.Spec.Logging.Fluentbit.Requests.Cpu }}
@@ -73,6 +95,13 @@ spec:
         - name: fluent-bit-config
           configMap:
             name: fluent-bit-config
+        {{- range $pod.Volumes }}
+        - {{ toJson . }}
+        {{- end }}
       serviceAccountName: cnvrg-fluentbit
+      {{- if $pod.Tolerations }}
+      tolerations: {{ toJson $pod.Tolerations }}
+      {{- else }}
       tolerations:
         - operator: Exists
+      {{- end }}
@@ -1,4 +1,4 @@
-
+{{- $pod := .Spec.Logging.Kibana.PodSpecOverrides }}
 apiVersion: apps/v1
 kind: Deployment
 metadata:
@@ -25,6 +25,12 @@ spec:
         {{- range $k, $v := .Spec.Annotations }}
         {{$k}}: "{{$v}}"
         {{- end }}
+        {{- range $k, $v := $pod.PodAnnotations }}
+        {{$k}}: "{{$v}}"
+        {{- end }}
+        {{- with .Spec.SecretRefsChecksum }}
+        mlops.cnvrg.io/secret-refs-checksum: "{{ . }}"
+        {{- end }}
       labels:
         app: {{ .Spec.Logging.Kibana.SvcName }}
         {{- range $k, $v := .Spec.Labels }}
@@ -32,30 +38,47 @@ spec:
         {{- end }}
     spec:
       priorityClassName: {{ .Spec.CnvrgAppPriorityClass.Name }}
-      {{- if isTrue .Spec.Tenancy.Enabled }}
+      {{- if .Spec.Tenancy.Enabled }}
       nodeSelector:
         "{{ .Spec.Tenancy.Key }}": "{{ .Spec.Tenancy.Value }}"
+      {{- end }}
+      {{- with $pod.Affinity }}
+      affinity: {{ toJson . }}
+      {{- end }}
+      {{- with $pod.TopologySpreadConstraints }}
+      topologySpreadConstraints: {{ toJson . }}
+      {{- end }}
+      {{- if $pod.Tolerations }}
+      tolerations: {{ toJson $pod.Tolerations }}
+      {{- else if .Spec.Tenancy.Enabled }}
       tolerations:
         - key: "{{ .Spec.Tenancy.Key }}"
           operator: "Equal"
           value: "{{ .Spec.Tenancy.Value }}"
           effect: "NoSchedule"
       {{- end }}
+      {{- if $pod.SecurityContext }}
+      securityContext: {{ toJson $pod.SecurityContext }}
+      {{- else }}
       securityContext:
         runAsUser: 1000
         fsGroup: 1000
+      {{- end }}
       serviceAccountName: {{ .Spec.Logging.Kibana.SvcName }}
       volumes:
         - name: "kibana-config"
           secret:
             secretName: "kibana-config"
-        {{- if isTrue .Spec.SSO.Enabled }}
+        {{- if .Spec.SSO.Enabled }}
         - name: "oauth-proxy-config"
           secret:
             secretName: "oauth-proxy-{{.Spec.Logging.Kibana.SvcName}}"
         {{- end }}
+        {{- range $pod.Volumes }}
+        - {{ toJson . }}
+        {{- end }}
       containers:
-        {{- if isTrue .Spec.SSO.Enabled }}
+        {{- if .Spec.SSO.Enabled }}
         - name: "cnvrg-oauth-proxy"
           image: {{image .Spec.ImageHub .Spec.SSO.Image }}
           command: [ "oauth2-proxy","--config", "/opt/app-root/conf/proxy-config/conf" ]
@@ -76,6 +99,12 @@ spec:
         {{- end }}
         - name: {{ .Spec.Logging.Kibana.SvcName }}
           image: {{image .Spec.ImageHub .Spec.Logging.Kibana.Image }}
+          {{- with $pod.ImagePullPolicy }}
+          imagePullPolicy: {{ . }}
+          {{- end }}
+          {{- with $pod.EnvFrom }}
+          envFrom: {{ toJson . }}
+          {{- end }}
           command:
             - /bin/bash
             - -lc
@@ -108,14 +137,20 @@ spec:
             - name: "kibana-config"
               mountPath: "/usr/share/kibana/config"
               readOnly: true
+            {{- range $pod.VolumeMounts }}
+            - {{ toJson . }}
+            {{- end }}
           env:
-          {{- if isTrue .Spec.SSO.Enabled }}
+          {{- if .Spec.SSO.Enabled }}
           - name: SERVER_PORT
             value: "3000"
           {{- else }}
           - name: SERVER_PORT
             value: "{{ .Spec.Logging.Kibana.Port }}"
           {{- end }}
+          {{- range $pod.Env }}
+          - {{ toJson . }}
+          {{- end }}
           ports:
           - containerPort: {{ .Spec.Logging.Kibana.Port }}
           resources:
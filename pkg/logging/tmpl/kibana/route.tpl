@@ -21,7 +21,7 @@ spec:
     kind: Service
     name: {{ .Spec.Logging.Kibana.SvcName }}
     weight: 100
-  {{- if isTrue .Spec.Networking.HTTPS.Enabled  }}
+  {{- if .Spec.Networking.HTTPS.Enabled  }}
   tls:
     termination: edge
     insecureEdgeTerminationPolicy: Redirect
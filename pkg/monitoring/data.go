@@ -0,0 +1,42 @@
+package monitoring
+
+import "github.com/AccessibleAI/cnvrg-operator/pkg/desired"
+
+// PromCredsData is the input of the prometheus creds secret template
+type PromCredsData struct {
+	Namespace   string
+	Annotations map[string]string
+	Labels      map[string]string
+	CredsRef    string
+	User        string
+	Pass        string
+	PassHash    string
+	PromUrl     string
+}
+
+// PromUpstreamData is the input of the prometheus upstream creds secret template
+type PromUpstreamData struct {
+	Namespace   string
+	Annotations map[string]string
+	Labels      map[string]string
+	CredsRef    string
+	User        string
+	Pass        string
+	Upstream    string
+}
+
+// GrafanaDSData is the input of the grafana datasource template
+type GrafanaDSData struct {
+	Namespace   string
+	Annotations map[string]string
+	Labels      map[string]string
+	Url         string
+	User        string
+	Pass        string
+}
+
+func init() {
+	desired.RegisterTemplateData(path+"/prometheus/instance/credsec.tpl", PromCredsData{})
+	desired.RegisterTemplateData(path+"/prometheus/instance/ccp/upstream.tpl", PromUpstreamData{})
+	desired.RegisterTemplateData(path+"/grafana/datasource.tpl", GrafanaDSData{})
+}
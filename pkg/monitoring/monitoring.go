@@ -11,7 +11,8 @@ import (
 
 const path = "/pkg/monitoring/tmpl"
 
-func PromCreds(data interface{}) []*desired.State {
+func PromCreds(data PromCredsData) []*desired.State {
+	templateData := desired.TemplateData{Namespace: data.Namespace, Data: data}
 	return []*desired.State{
 		{
 			TemplatePath:   path + "/prometheus/instance/credsec.tpl",
@@ -19,14 +20,15 @@ func PromCreds(data interface{}) []*desired.State {
 			ParsedTemplate: "",
 			Obj:            &unstructured.Unstructured{},
 			GVK:            desired.Kinds[desired.SecretGVK],
-			TemplateData:   data,
+			TemplateData:   templateData,
 			Own:            true,
 			Updatable:      false,
 		},
 	}
 }
 
-func PromUpstreamCreds(data interface{}) []*desired.State {
+func PromUpstreamCreds(data PromUpstreamData) []*desired.State {
+	templateData := desired.TemplateData{Namespace: data.Namespace, Data: data}
 	return []*desired.State{
 		{
 			TemplatePath:   path + "/prometheus/instance/ccp/upstream.tpl",
@@ -34,7 +36,7 @@ func PromUpstreamCreds(data interface{}) []*desired.State {
 			ParsedTemplate: "",
 			Obj:            &unstructured.Unstructured{},
 			GVK:            desired.Kinds[desired.SecretGVK],
-			TemplateData:   data,
+			TemplateData:   templateData,
 			Own:            true,
 			Updatable:      false,
 		},
@@ -209,10 +211,11 @@ func kubeStateMetricsState() []*desired.State {
 	}
 }
 
-func GrafanaDSState(data interface{}) []*desired.State {
+func GrafanaDSState(data GrafanaDSData) []*desired.State {
+	templateData := desired.TemplateData{Namespace: data.Namespace, Data: data}
 	return []*desired.State{
 		{
-			TemplateData:   data,
+			TemplateData:   templateData,
 			TemplatePath:   path + "/grafana/datasource.tpl",
 			Template:       nil,
 			ParsedTemplate: "",
@@ -681,6 +684,20 @@ func cnvrgIdleMetricsServiceMonitors() []*desired.State {
 	}
 }
 
+func operatorServiceMonitor() []*desired.State {
+	return []*desired.State{
+		{
+			TemplatePath:   path + "/cnvrg-servicemonitors/operator.tpl",
+			Template:       nil,
+			ParsedTemplate: "",
+			Obj:            &unstructured.Unstructured{},
+			GVK:            desired.Kinds[desired.ServiceMonitorGVK],
+			Own:            true,
+			Updatable:      true,
+		},
+	}
+}
+
 func AppMonitoringState(cnvrgApp *mlopsv1.CnvrgApp) []*desired.State {
 	var state []*desired.State
 
@@ -729,6 +746,7 @@ func InfraMonitoringState(infra *mlopsv1.CnvrgInfra) []*desired.State {
 	if infra.Spec.Monitoring.Prometheus.Enabled {
 		state = append(state, promOauthProxy()...)
 		state = append(state, infraPrometheusInstanceState()...)
+		state = append(state, operatorServiceMonitor()...)
 
 		switch infra.Spec.Networking.Ingress.Type {
 		case mlopsv1.IstioIngress:
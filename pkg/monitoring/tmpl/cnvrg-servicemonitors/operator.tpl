@@ -0,0 +1,27 @@
+apiVersion: monitoring.coreos.com/v1
+kind: ServiceMonitor
+metadata:
+  name: cnvrg-operator
+  namespace: {{ ns . }}
+  annotations:
+    {{- range $k, $v := .Spec.Annotations }}
+    {{$k}}: "{{$v}}"
+    {{- end }}
+  labels:
+    app: cnvrg-operator
+    cnvrg-infra-prometheus: {{ .Name }}-{{ ns .}}
+    {{- range $k, $v := .Spec.Labels }}
+    {{$k}}: "{{$v}}"
+    {{- end }}
+spec:
+  jobLabel: control-plane
+  selector:
+    matchLabels:
+      control-plane: cnvrg-operator
+  namespaceSelector:
+    any: true
+  endpoints:
+    - interval: 30s
+      scrapeTimeout: 10s
+      port: "metrics"
+      path: /metrics
@@ -1,3 +1,4 @@
+{{- $pod := .Spec.Monitoring.Grafana.PodSpecOverrides }}
 apiVersion: apps/v1
 kind: Deployment
 metadata:
@@ -24,6 +25,12 @@ spec:
         {{- range $k, $v := .Spec.Annotations }}
         {{$k}}: "{{$v}}"
         {{- end }}
+        {{- range $k, $v := $pod.PodAnnotations }}
+        {{$k}}: "{{$v}}"
+        {{- end }}
+        {{- with .Spec.SecretRefsChecksum }}
+        mlops.cnvrg.io/secret-refs-checksum: "{{ . }}"
+        {{- end }}
       labels:
         app: {{ .Spec.Monitoring.Grafana.SvcName }}
         {{- range $k, $v := .Spec.Labels }}
@@ -31,21 +38,35 @@ spec:
         {{- end }}
     spec:
       priorityClassName: {{ .Spec.CnvrgAppPriorityClass.Name }}
-      {{- if isTrue .Spec.Tenancy.Enabled }}
+      {{- if .Spec.Tenancy.Enabled }}
       nodeSelector:
         "{{ .Spec.Tenancy.Key }}": "{{ .Spec.Tenancy.Value }}"
+      {{- end }}
+      {{- with $pod.Affinity }}
+      affinity: {{ toJson . }}
+      {{- end }}
+      {{- with $pod.TopologySpreadConstraints }}
+      topologySpreadConstraints: {{ toJson . }}
+      {{- end }}
+      {{- if $pod.Tolerations }}
+      tolerations: {{ toJson $pod.Tolerations }}
+      {{- else if .Spec.Tenancy.Enabled }}
       tolerations:
         - key: "{{ .Spec.Tenancy.Key }}"
           operator: "Equal"
           value: "{{ .Spec.Tenancy.Value }}"
           effect: "NoSchedule"
       {{- end }}
+      {{- if $pod.SecurityContext }}
+      securityContext: {{ toJson $pod.SecurityContext }}
+      {{- else }}
       securityContext:
         runAsNonRoot: true
         runAsUser: 65534
+      {{- end }}
       serviceAccountName: {{ .Spec.Monitoring.Grafana.SvcName }}
       containers:
-      {{- if isTrue .Spec.SSO.Enabled }}
+      {{- if .Spec.SSO.Enabled }}
       - name: "cnvrg-oauth-proxy"
         image: {{image .Spec.ImageHub .Spec.SSO.Image }}
         command: [ "oauth2-proxy","--config", "/opt/app-root/conf/proxy-config/conf" ]
@@ -66,6 +87,12 @@ spec:
       {{- end }}
       - image: {{image .Spec.ImageHub .Spec.Monitoring.Grafana.Image }}
         name: grafana
+        {{- with $pod.ImagePullPolicy }}
+        imagePullPolicy: {{ . }}
+        {{- end }}
+        {{- with $pod.EnvFrom }}
+        envFrom: {{ toJson . }}
+        {{- end }}
         env:
           - name: GF_AUTH_BASIC_ENABLED
             value: "false"
@@ -75,7 +102,7 @@ spec:
             value: Admin
           - name: GF_SECURITY_ALLOW_EMBEDDING
             value: "true"
-          {{- if isTrue .Spec.SSO.Enabled }}
+          {{- if .Spec.SSO.Enabled }}
           - name: GF_SERVER_HTTP_ADDR
             value: "127.0.0.1"
           - name: GF_SERVER_HTTP_PORT
@@ -86,6 +113,9 @@ spec:
           - name: GF_SERVER_HTTP_PORT
             value: "8080"
           {{- end }}
+          {{- range $pod.Env }}
+          - {{ toJson . }}
+          {{- end }}
         ports:
         - containerPort: 8080
           name: http
@@ -115,8 +145,11 @@ spec:
           name: {{ trimSuffix ".json" $dashboard }}
           readOnly: false
         {{- end }}
+        {{- range $pod.VolumeMounts }}
+        - {{ toJson . }}
+        {{- end }}
       volumes:
-      {{- if isTrue .Spec.SSO.Enabled }}
+      {{- if .Spec.SSO.Enabled }}
       - name: "oauth-proxy-config"
         secret:
           secretName: "oauth-proxy-grafana"
@@ -134,3 +167,6 @@ spec:
           name: {{ trimSuffix ".json" $dashboard }}
         name: {{ trimSuffix ".json" $dashboard }}
       {{- end }}
+      {{- range $pod.Volumes }}
+      - {{ toJson . }}
+      {{- end }}
@@ -22,7 +22,7 @@ spec:
     kind: Service
     name: {{ .Spec.Monitoring.Grafana.SvcName }}
     weight: 100
-  {{- if isTrue .Spec.Networking.HTTPS.Enabled  }}
+  {{- if .Spec.Networking.HTTPS.Enabled  }}
   tls:
     termination: edge
     insecureEdgeTerminationPolicy: Redirect
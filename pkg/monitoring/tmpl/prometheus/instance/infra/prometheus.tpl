@@ -1,3 +1,4 @@
+{{- $pod := .Spec.Monitoring.Prometheus.PodSpecOverrides }}
 apiVersion: monitoring.coreos.com/v1
 kind: Prometheus
 metadata:
@@ -13,6 +14,9 @@ metadata:
     {{$k}}: "{{$v}}"
     {{- end }}
 spec:
+  {{- if $pod.Affinity }}
+  affinity: {{ toJson $pod.Affinity }}
+  {{- else }}
   affinity:
     podAntiAffinity:
       preferredDuringSchedulingIgnoredDuringExecution:
@@ -25,6 +29,7 @@ spec:
               operator: In
               values:
               - {{ .Spec.Monitoring.Prometheus.SvcName }}
+  {{- end }}
   storage:
     disableMountSubPath: true
     volumeClaimTemplate:
@@ -47,11 +52,14 @@ spec:
       cpu: {{ .Spec.Monitoring.Prometheus.Limits.Cpu }}
       memory: {{ .Spec.Monitoring.Prometheus.Limits.Memory }}
   podMetadata:
-    {{- if .Spec.Annotations }}
+    {{- if or .Spec.Annotations $pod.PodAnnotations }}
     annotations:
       {{- range $k, $v := .Spec.Annotations }}
       {{$k}}: "{{$v}}"
       {{- end }}
+      {{- range $k, $v := $pod.PodAnnotations }}
+      {{$k}}: "{{$v}}"
+      {{- end }}
     {{- end }}
     labels:
       cnvrg: {{ .Spec.Monitoring.Prometheus.SvcName }}
@@ -62,10 +70,14 @@ spec:
     matchLabels:
       app: cnvrg-infra-prometheus
       role: alert-rules
+  {{- if $pod.SecurityContext }}
+  securityContext: {{ toJson $pod.SecurityContext }}
+  {{- else }}
   securityContext:
     fsGroup: 2000
     runAsNonRoot: true
     runAsUser: 1000
+  {{- end }}
   serviceAccountName: cnvrg-infra-prometheus
   priorityClassName: {{ .Spec.CnvrgAppPriorityClass.Name }}
   podMonitorNamespaceSelector: {}
@@ -78,6 +90,18 @@ spec:
   version: v2.22.1
   listenLocal: true
   containers:
+  {{- if or $pod.Env $pod.EnvFrom $pod.ImagePullPolicy }}
+  - name: prometheus
+    {{- with $pod.ImagePullPolicy }}
+    imagePullPolicy: {{ . }}
+    {{- end }}
+    {{- with $pod.Env }}
+    env: {{ toJson . }}
+    {{- end }}
+    {{- with $pod.EnvFrom }}
+    envFrom: {{ toJson . }}
+    {{- end }}
+  {{- end }}
   - name: "prom-auth-proxy"
     image: {{ image .Spec.ImageHub .Spec.Monitoring.Prometheus.BasicAuthProxyImage }}
     resources:
@@ -104,17 +128,30 @@ spec:
   - name: "htpasswd"
     secret:
       secretName: {{ .Spec.Monitoring.Prometheus.CredsRef }}
-  {{- if isTrue .Spec.Tenancy.Enabled }}
+  {{- range $pod.Volumes }}
+  - {{ toJson . }}
+  {{- end }}
+  {{- with $pod.VolumeMounts }}
+  volumeMounts: {{ toJson . }}
+  {{- end }}
+  {{- if .Spec.Tenancy.Enabled }}
   nodeSelector:
     {{ .Spec.Tenancy.Key }}: {{ .Spec.Tenancy.Value }}
     {{- range $key, $val := .Spec.Monitoring.Prometheus.NodeSelector }}
     {{ $key }}: {{ $val }}
     {{- end }}
-  tolerations:
-    - operator: "Exists"
   {{- else if (gt (len .Spec.Monitoring.Prometheus.NodeSelector) 0) }}
   nodeSelector:
     {{- range $key, $val := .Spec.Monitoring.Prometheus.NodeSelector }}
     {{ $key }}: {{ $val }}
     {{- end }}
   {{- end }}
+  {{- with $pod.TopologySpreadConstraints }}
+  topologySpreadConstraints: {{ toJson . }}
+  {{- end }}
+  {{- if $pod.Tolerations }}
+  tolerations: {{ toJson $pod.Tolerations }}
+  {{- else if .Spec.Tenancy.Enabled }}
+  tolerations:
+    - operator: "Exists"
+  {{- end }}
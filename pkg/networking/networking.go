@@ -74,6 +74,8 @@ func istioInstanceState() []*desired.State {
 			GVK:            desired.Kinds[desired.IstioGVK],
 			Own:            false,
 			Updatable:      true,
+			// the istio operator and istioctl manage the instance as well, their changes are kept
+			ConflictPolicy: desired.ConflictSkip,
 		},
 	}
 }
@@ -1,4 +1,4 @@
-apiVersion: networking.istio.io/v1alpha3
+apiVersion: {{ apiVersion "Gateway" }}
 kind: Gateway
 metadata:
   name: {{ .Spec.Networking.Ingress.IstioGwName }}
@@ -17,7 +17,7 @@ spec:
         protocol: HTTP
       hosts:
         - "*.{{ .Spec.ClusterDomain }}"
-      {{- if and ( isTrue .Spec.Networking.HTTPS.Enabled ) (ne .Spec.Networking.HTTPS.CertSecret "") }}
+      {{- if and .Spec.Networking.HTTPS.Enabled (ne .Spec.Networking.HTTPS.CertSecret "") }}
       tls:
         httpsRedirect: true
     - hosts:
@@ -29,7 +29,7 @@ spec:
       tls:
         mode: SIMPLE
         credentialName: {{ .Spec.Networking.HTTPS.CertSecret }}
-      {{- else if isTrue .Spec.Networking.HTTPS.Enabled }}
+      {{- else if .Spec.Networking.HTTPS.Enabled }}
       tls:
         httpsRedirect: true
     - hosts:
@@ -32,7 +32,7 @@ spec:
         - name: {{ .Spec.Registry.Name }}
       priorityClassName: {{ .Spec.CnvrgAppPriorityClass.Name }}
       serviceAccountName: istio-operator
-      {{- if isTrue .Spec.Tenancy.Enabled }}
+      {{- if .Spec.Tenancy.Enabled }}
       nodeSelector:
         {{ .Spec.Tenancy.Key }}: "{{ .Spec.Tenancy.Value }}"
       tolerations:
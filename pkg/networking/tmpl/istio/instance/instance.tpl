@@ -37,7 +37,7 @@ spec:
           {{- range $k, $v := .Spec.Annotations }}
           {{$k}}: "{{$v}}"
           {{- end }}
-        {{- if isTrue .Spec.Tenancy.Enabled }}
+        {{- if .Spec.Tenancy.Enabled }}
         nodeSelector:
           {{ .Spec.Tenancy.Key }}: "{{ .Spec.Tenancy.Value }}"
         tolerations:
@@ -101,7 +101,7 @@ spec:
           {{- range $k, $v := .Spec.Annotations }}
           {{$k}}: "{{$v}}"
           {{- end }}
-        {{- if isTrue .Spec.Tenancy.Enabled }}
+        {{- if .Spec.Tenancy.Enabled }}
         nodeSelector:
           {{ .Spec.Tenancy.Key }}: "{{ .Spec.Tenancy.Value }}"
         tolerations:
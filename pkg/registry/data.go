@@ -0,0 +1,18 @@
+package registry
+
+import (
+	mlopsv1 "github.com/AccessibleAI/cnvrg-operator/api/v1"
+	"github.com/AccessibleAI/cnvrg-operator/pkg/desired"
+)
+
+// Data is the input of the registry secret template
+type Data struct {
+	Namespace   string
+	Annotations map[string]string
+	Labels      map[string]string
+	Registry    mlopsv1.Registry
+}
+
+func init() {
+	desired.RegisterTemplateData(path+"/secret.tpl", Data{})
+}
@@ -21,8 +21,9 @@ func registryState() []*desired.State {
 	}
 }
 
-func State(data interface{}) []*desired.State {
+func State(data Data) []*desired.State {
+	templateData := desired.TemplateData{Namespace: data.Namespace, Data: data}
 	registry := registryState()
-	registry[0].TemplateData = data
+	registry[0].TemplateData = templateData
 	return registry
 }
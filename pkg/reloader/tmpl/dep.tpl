@@ -34,7 +34,7 @@ spec:
       securityContext:
         runAsUser: 1000
         runAsGroup: 1000
-      {{- if isTrue .Spec.Tenancy.Enabled }}
+      {{- if .Spec.Tenancy.Enabled }}
       nodeSelector:
         {{ .Spec.Tenancy.Key }}: "{{ .Spec.Tenancy.Value }}"
       tolerations:
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	mlopsv1 "github.com/AccessibleAI/cnvrg-operator/api/v1"
+	"github.com/AccessibleAI/cnvrg-operator/controllers"
+	"github.com/go-logr/zapr"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"io"
+	"io/ioutil"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"os"
+	"path/filepath"
+	"sigs.k8s.io/yaml"
+	"strings"
+)
+
+// specParams are shared by render and diff commands
+var specParams = []param{
+	{name: "file", shorthand: "f", value: "", usage: "CnvrgApp/CnvrgInfra manifest file, - for stdin"},
+}
+
+var renderParams = []param{
+	{name: "output-dir", shorthand: "o", value: "", usage: "destination dir for rendered manifests, stdout if not set"},
+	{name: "cri", shorthand: "", value: string(mlopsv1.CriTypeContainerd), usage: "container runtime to use when not set in the spec (docker, containerd, cri-o)"},
+}
+
+var renderCmd = &cobra.Command{
+	Use:   "render",
+	Short: "Render CnvrgApp/CnvrgInfra manifests without cluster access",
+	PreRun: func(cmd *cobra.Command, args []string) {
+		bindParams(specParams, cmd)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := render(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	},
+}
+
+func render() error {
+	if viper.GetString("file") == "" {
+		return fmt.Errorf("--file is required")
+	}
+	apps, infra, err := readSpecs(viper.GetString("file"))
+	if err != nil {
+		return err
+	}
+
+	renderer := &controllers.Renderer{
+		Scheme: scheme,
+		Log:    zapr.NewLogger(initZapLog()),
+		Cri:    mlopsv1.CriType(viper.GetString("cri")),
+	}
+
+	ctx := context.Background()
+	var objs []*unstructured.Unstructured
+	if infra != nil {
+		infraObjs, err := renderer.RenderCnvrgInfra(ctx, infra)
+		if err != nil {
+			return err
+		}
+		objs = append(objs, infraObjs...)
+	}
+	for _, app := range apps {
+		appObjs, err := renderer.RenderCnvrgApp(ctx, app, infra)
+		if err != nil {
+			return err
+		}
+		objs = append(objs, appObjs...)
+	}
+
+	if outputDir := viper.GetString("output-dir"); outputDir != "" {
+		return writeManifests(objs, outputDir)
+	}
+	return printManifests(objs, os.Stdout)
+}
+
+// readSpecs reads multi document yaml with CnvrgApps and (at most one) CnvrgInfra
+func readSpecs(file string) (apps []*mlopsv1.CnvrgApp, infra *mlopsv1.CnvrgInfra, err error) {
+	var r io.Reader = os.Stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	decoder := serializer.NewCodecFactory(scheme).UniversalDeserializer()
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(r))
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, nil, err
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+		obj, gvk, err := decoder.Decode(doc, nil, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		switch o := obj.(type) {
+		case *mlopsv1.CnvrgApp:
+			apps = append(apps, o)
+		case *mlopsv1.CnvrgInfra:
+			if infra != nil {
+				return nil, nil, fmt.Errorf("only one CnvrgInfra is supported, found: %s, %s", infra.Name, o.Name)
+			}
+			infra = o
+		default:
+			return nil, nil, fmt.Errorf("unsupported kind: %s, expected CnvrgApp or CnvrgInfra", gvk.Kind)
+		}
+	}
+	if len(apps) == 0 && infra == nil {
+		return nil, nil, fmt.Errorf("no CnvrgApp or CnvrgInfra found in %s", file)
+	}
+	return apps, infra, nil
+}
+
+func printManifests(objs []*unstructured.Unstructured, w io.Writer) error {
+	for _, obj := range objs {
+		b, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "---\n%s", b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeManifests(objs []*unstructured.Unstructured, outputDir string) error {
+	if err := os.MkdirAll(outputDir, 0775); err != nil {
+		return err
+	}
+	for i, obj := range objs {
+		b, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return err
+		}
+		// prefix keeps the apply order
+		name := fmt.Sprintf("%03d-%s-%s.yaml", i, strings.ToLower(obj.GetKind()), obj.GetName())
+		if obj.GetNamespace() != "" {
+			name = fmt.Sprintf("%03d-%s-%s-%s.yaml", i, obj.GetNamespace(), strings.ToLower(obj.GetKind()), obj.GetName())
+		}
+		if err := ioutil.WriteFile(filepath.Join(outputDir, name), b, 0664); err != nil {
+			return err
+		}
+	}
+	return nil
+}